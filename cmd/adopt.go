@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/discovery"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+func newAdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Create the PROJECT file of an existing controller-runtime project",
+		Long: `Create the PROJECT file of an existing controller-runtime project, so that
+kubebuilder can be used to scaffold new APIs and controllers in it.
+
+The repo is read from go.mod. The domain and the resources are discovered from
+the API packages under api/ and apis/: the group comes from the +groupName
+marker of the package, the version from the package directory, and the kinds
+from the types with a +kubebuilder:object:root=true marker.
+
+Nothing is scaffolded: the existing files are left untouched.
+`,
+		Example: `	# in the root of the existing project
+	kubebuilder alpha adopt
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runAdopt(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+	return cmd
+}
+
+func runAdopt() error {
+	if util.ProjectExist() {
+		return fmt.Errorf("the project already has a PROJECT file: %w", scaffold.ErrProjectExists)
+	}
+
+	p, err := discovery.Discover(".")
+	if err != nil {
+		return fmt.Errorf("failed to discover the project: %v", err)
+	}
+	if p.Domain == "" {
+		return invalidInputf("no API group found, initialize the project with kubebuilder init instead")
+	}
+
+	projectFile := &input.ProjectFile{
+		Version:   project.Version2,
+		Domain:    p.Domain,
+		Repo:      p.Repo,
+		Resources: p.Resources,
+	}
+	if err := scaffold.CreateProjectFile("PROJECT", projectFile); err != nil {
+		return err
+	}
+
+	logging.Infof("Created PROJECT for %s with domain %s", p.Repo, p.Domain)
+	for _, r := range p.Resources {
+		logging.Infof("Adopted %s/%s, Kind=%s", r.Group, r.Version, r.Kind)
+	}
+	if !p.HasControllers {
+		logging.Warnf("no controllers directory found, controllers will be scaffolded in controllers/")
+	}
+	return nil
+}
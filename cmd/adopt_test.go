@@ -0,0 +1,40 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestAdoptExitCode(t *testing.T) {
+	defer chdirTemp(t)()
+
+	if err := ioutil.WriteFile("go.mod", []byte("module example.com/proj\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if code := exitCode(runAdopt()); code != exitCodeInvalidInput {
+		t.Errorf("expected exit code %d for a project without API, got %d", exitCodeInvalidInput, code)
+	}
+
+	if err := ioutil.WriteFile("PROJECT", []byte("version: \"2\"\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if code := exitCode(runAdopt()); code != exitCodeAlreadyExists {
+		t.Errorf("expected exit code %d for a project with a PROJECT file, got %d", exitCodeAlreadyExists, code)
+	}
+}
@@ -21,20 +21,53 @@ import (
 )
 
 // newAlphaCommand returns alpha subcommand which will be mounted
-// at the root command by the caller.
-func newAlphaCommand() *cobra.Command {
+// at the root command by the caller. The webhook subcommand is only
+// available for v1 projects.
+func newAlphaCommand(v1 bool) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "alpha",
 		Short: "Expose commands which are in experimental or early stages of development",
 		Long:  `Command group for commands which are either experimental or in early stages of development`,
 		Example: `
-# scaffolds webhook server
+# creates the PROJECT file of an existing project
+kubebuilder alpha adopt
+
+# checks the manifests of the project before applying them
+kubebuilder alpha verify
+
+# rewrites the license header of the Go files from hack/boilerplate.go.txt
+kubebuilder alpha update-license
+
+# adds the types of an API package of another module to the scheme
+kubebuilder alpha wire --package <module>/apis/v1
+
+# scaffolds the project again from its PROJECT file to compare it or upgrade it
+kubebuilder alpha regenerate --output-dir <dir>
+
+# lists the files scaffolded by older versions of their templates
+kubebuilder alpha audit-scaffolds
+
+# updates the dependencies of the project to those of a Kubernetes release
+kubebuilder alpha bump-deps --k8s-version <version>
+
+# scaffolds webhook server (v1 projects only)
 kubebuilder alpha webhook <params>
 `,
 	}
 
 	cmd.AddCommand(
-		newWebhookCmd(),
+		newAdoptCmd(),
+		newVerifyCmd(),
+		newUpdateLicenseCmd(),
+		newWireCmd(),
+		newRegenerateCmd(),
+		newAuditScaffoldsCmd(),
+		newBumpDepsCmd(),
 	)
+	if v1 {
+		cmd.AddCommand(
+			newWebhookCmd(),
+		)
+	}
 	return cmd
 }
@@ -17,20 +17,22 @@ limitations under the License.
 package main
 
 import (
-	"bufio"
 	"fmt"
-	"log"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 	"sigs.k8s.io/kubebuilder/plugins/addon"
+	"sigs.k8s.io/kubebuilder/plugins/clientstub"
 )
 
 type apiOptions struct {
@@ -38,30 +40,165 @@ type apiOptions struct {
 	resourceFlag, controllerFlag *flag.Flag
 
 	// runMake indicates whether to run make or not after scaffolding APIs
-	runMake bool
+	runMake  bool
+	makeFlag *flag.Flag
 
 	// pattern indicates that we should use a plugin to build according to a pattern
-	pattern string
+	pattern     string
+	patternFlag *flag.Flag
+
+	// clientStub is the language of the client stub to scaffold, if any
+	clientStub string
+
+	// overwrite are the artifacts to overwrite if they already exist
+	overwrite []string
+
+	// controllerOnly scaffolds the controller without the resource
+	controllerOnly bool
+
+	// watches are the group/version/kind of the secondary resources owned by
+	// the resource
+	watches []string
+
+	// printColumns are the additional printer columns of the resource, as
+	// name:jsonPath[:type]
+	printColumns []string
+
+	// interactiveFields prompts for the fields of the Spec of the types once
+	// the API is scaffolded
+	interactiveFields bool
+
+	// withWebhooks are the webhooks to scaffold along with the API,
+	// defaulting, validation and/or conversion
+	withWebhooks []string
+
+	// interactive is false to answer the questions with their defaults
+	// instead of prompting
+	interactive bool
+
+	// prompter asks the questions, it prompts on the standard input and
+	// output, or uses the defaults if not interactive, when nil
+	prompter util.Prompter
 }
 
 func (o *apiOptions) bindCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.runMake, "make", true,
 		"if true, run make after generating files")
+	o.makeFlag = cmd.Flag("make")
 	cmd.Flags().BoolVar(&o.apiScaffolder.DoResource, "resource", true,
 		"if set, generate the resource without prompting the user")
 	o.resourceFlag = cmd.Flag("resource")
 	cmd.Flags().BoolVar(&o.apiScaffolder.DoController, "controller", true,
 		"if set, generate the controller without prompting the user")
 	o.controllerFlag = cmd.Flag("controller")
+	cmd.Flags().StringSliceVar(&o.withWebhooks, "with-webhooks", nil,
+		"webhooks of the resource to scaffold along with the API, instead of running create webhook next, "+
+			"among defaulting, validation and conversion (project version 2 only)")
+	cmd.Flags().BoolVar(&o.interactive, "interactive", true,
+		"if false, do not prompt and generate the resource and the controller unless --resource or --controller "+
+			"say otherwise")
+	cmd.Flags().StringVar(&o.pattern, "pattern", "",
+		"generates an API following an extension pattern (e.g. addon), which is recorded in the PROJECT file and "+
+			"used by default for the next APIs. Use none to generate a plain API in such a project")
+	o.patternFlag = cmd.Flag("pattern")
 	if os.Getenv("KUBEBUILDER_ENABLE_PLUGINS") != "" {
-		cmd.Flags().StringVar(&o.pattern, "pattern", "",
-			"generates an API following an extension pattern (addon)")
+		cmd.Flags().StringVar(&o.clientStub, "client-stub", "",
+			fmt.Sprintf("if specified, also scaffold the OpenAPI schema and a typed model of the API for "+
+				"non-Go clients, in one of %s, %s (project version 2 only)", clientstub.TypeScript, clientstub.Python))
 	}
 	cmd.Flags().BoolVar(&o.apiScaffolder.Force, "force", false,
-		"attempt to create resource even if it already exists")
+		"attempt to create resource even if it already exists, overwriting all its files")
+	if err := cmd.Flags().MarkDeprecated("force", "use the overwrite flag instead"); err != nil {
+		logging.Warnf("error to mark force flag as deprecated: %v", err)
+	}
+	cmd.Flags().StringSliceVar(&o.overwrite, "overwrite", nil, fmt.Sprintf(
+		"artifacts to overwrite if the resource already exists. May be any of %v", scaffold.APIArtifacts))
+	cmd.Flags().BoolVar(&o.controllerOnly, "controller-only", false,
+		"if set, only generate the controller, for a resource which already exists")
+	cmd.Flags().BoolVar(&o.apiScaffolder.GenerateOnly, "generate-only", false,
+		"if set, only generate the types of the resource, without its controller, sample, RBAC roles and "+
+			"main.go wiring, which create controller generates later (project version 2 only)")
+	cmd.Flags().StringVar((*string)(&o.apiScaffolder.ImportsStyle), "sort-imports-style", "", fmt.Sprintf(
+		"if specified, how to group the imports of the scaffolded Go files, which is recorded in the PROJECT file. "+
+			"May be one of %s (standard library, then the others) or %s (also the imports of the project "+
+			"apart, like goimports -local)", scaffold.ImportsStyleGoimports, scaffold.ImportsStyleLocal))
+	cmd.Flags().StringSliceVar(&o.watches, "watches", nil,
+		"group/version/kind of the resources owned by the resource, e.g. apps/v1/Deployment,core/v1/ConfigMap. "+
+			"The controller watches them and gets the RBAC permissions to manage them")
+	cmd.Flags().IntVar(&o.apiScaffolder.MaxConcurrentReconciles, "max-concurrent-reconciles", 0,
+		"if set, the maximum number of objects the controller reconciles concurrently, instead of 1 "+
+			"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.apiScaffolder.GenerationPredicate, "with-generation-predicate", false,
+		"if set, the controller ignores the updates which do not change the generation of the objects, "+
+			"e.g. the status updates (project version 2 only)")
+	cmd.Flags().BoolVar(&o.apiScaffolder.Events, "with-events", false,
+		"if set, the controller records the events of the objects it reconciles with the typed reasons of "+
+			"controllers/events.go (project version 2 only)")
+	cmd.Flags().StringVar((*string)(&o.apiScaffolder.Layout), "layout", string(scaffoldv2.ControllerLayoutFlat),
+		"layout of the package of the controller. May be one of flat (in controllers/) or per-kind (in its own "+
+			"package internal/controller/<kind>/, with its helpers and test) (project version 2 only)")
+	cmd.Flags().StringVar(&o.apiScaffolder.Schema, "schema", "",
+		"if set, an OpenAPI or JSON schema file, e.g. openapi.yaml, the Spec and Status fields of the types and "+
+			"their validation markers are generated from (project version 2 only)")
+	cmd.Flags().StringArrayVar(&o.apiScaffolder.Fields, "field", nil,
+		"field of the Spec of the types as name:type[:options], e.g. Replicas:int32:min=1,max=10,default=3, "+
+			"instead of an example field. The type is string, int32, int64, bool, quantity or time, or a slice "+
+			"or map[string] of them. The options are required, min, max, minLength, maxLength, pattern, format, "+
+			"enum (values separated by ;), minItems, maxItems and default, whose marker needs controller-gen v0.3.0 "+
+			"or later. The object type is an arbitrary object whose unknown fields are not pruned. May be repeated "+
+			"(project version 2 only)")
+	cmd.Flags().StringSliceVar(&o.apiScaffolder.PreserveUnknownFields, "preserve-unknown-fields", nil,
+		"fields of the kind, spec and/or status, whose unknown fields are not pruned by the API server, with the "+
+			"+kubebuilder:pruning:PreserveUnknownFields marker (project version 2 only)")
+	cmd.Flags().StringSliceVar(&o.apiScaffolder.EmbeddedResources, "embedded-resources", nil,
+		"object fields defined with --field which embed a Kubernetes object, e.g. a pod template, whose apiVersion, "+
+			"kind and metadata are validated, with the +kubebuilder:validation:EmbeddedResource marker "+
+			"(project version 2 only)")
+	cmd.Flags().StringVar(&o.apiScaffolder.SampleValues, "sample-values", "",
+		"if set, a YAML file with the values of the spec of the sample in config/samples, which are merged into "+
+			"the example field of the types (project version 2 only)")
+	cmd.Flags().BoolVar(&o.interactiveFields, "interactive-fields", false,
+		"if set, prompt for the name, type and validation options of the fields of the Spec once the API is "+
+			"scaffolded, and generate the types again with them. The fields are recorded in the PROJECT file "+
+			"(project version 2 only)")
+	cmd.Flags().StringArrayVar(&o.printColumns, "printer-column", nil,
+		"additional printer column of the resource as name:jsonPath[:type], e.g. Age:.metadata.creationTimestamp. "+
+			"The type defaults to date for the creation timestamp and to string otherwise. May be repeated "+
+			"(project version 2 only)")
 	o.apiScaffolder.Resource = resourceForFlags(cmd.Flags())
 }
 
+// parseWebhooks parses the webhooks of --with-webhooks
+func parseWebhooks(webhooks []string) (*scaffold.Webhook, error) {
+	w := &scaffold.Webhook{}
+	for _, kind := range webhooks {
+		switch kind {
+		case "defaulting":
+			w.Defaulting = true
+		case "validation":
+			w.Validating = true
+		case "conversion":
+			w.Conversion = true
+		default:
+			return nil, fmt.Errorf("unknown webhook %q, should be one of defaulting, validation, conversion", kind)
+		}
+	}
+	return w, nil
+}
+
+// parseWatches parses the group/version/kind of the watched resources
+func parseWatches(watches []string) ([]*resource.Resource, error) {
+	resources := make([]*resource.Resource, 0, len(watches))
+	for _, w := range watches {
+		gvk := strings.Split(w, "/")
+		if len(gvk) != 3 {
+			return nil, fmt.Errorf("watched resource %q should be group/version/kind", w)
+		}
+		resources = append(resources, &resource.Resource{Group: gvk[0], Version: gvk[1], Kind: gvk[2]})
+	}
+	return resources, nil
+}
+
 // resourceForFlags registers flags for Resource fields and returns the Resource
 func resourceForFlags(f *flag.FlagSet) *resource.Resource {
 	r := &resource.Resource{}
@@ -69,59 +206,197 @@ func resourceForFlags(f *flag.FlagSet) *resource.Resource {
 	f.StringVar(&r.Group, "group", "", "resource Group")
 	f.StringVar(&r.Version, "version", "", "resource Version")
 	f.BoolVar(&r.Namespaced, "namespaced", true, "resource is namespaced")
+	f.StringVar(&r.Resource, "plural", "",
+		"resource plural, if the plural computed from the kind is wrong (e.g. for domain terms)")
+	f.StringSliceVar(&r.ShortNames, "short-name", nil,
+		"short names of the resource for kubectl, e.g. fr (project version 2 only)")
+	f.StringSliceVar(&r.Categories, "categories", nil,
+		"categories the resource belongs to, e.g. all to be listed by kubectl get all (project version 2 only)")
 	f.BoolVar(&r.CreateExampleReconcileBody, "example", true,
 		"if true an example reconcile body should be written while scaffolding a resource.")
 	return r
 }
 
 // APICmd represents the resource command
-func (o *apiOptions) runAddAPI() {
-	dieIfNoProject()
+func (o *apiOptions) runAddAPI() error {
+	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
+		return scaffold.ErrProjectNotInitialized
+	}
+	projectInfo, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
 
-	switch strings.ToLower(o.pattern) {
-	case "":
-		// Default pattern
+	pattern, err := o.selectPattern()
+	if err != nil {
+		return err
+	}
+	if pattern.NewPlugins != nil {
+		o.apiScaffolder.Plugins = append(o.apiScaffolder.Plugins, pattern.NewPlugins()...)
+	}
 
-	case "addon":
-		o.apiScaffolder.Plugins = append(o.apiScaffolder.Plugins, &addon.Plugin{})
+	if o.clientStub != "" {
+		language := clientstub.Language(o.clientStub)
+		if err := language.Validate(); err != nil {
+			return invalidInput(err)
+		}
+		if projectInfo.Version != project.Version2 {
+			return invalidInputf("--client-stub is only supported for project version %s", project.Version2)
+		}
+		o.apiScaffolder.Plugins = append(o.apiScaffolder.Plugins, &clientstub.Plugin{Language: language})
+	}
+
+	if offline && o.makeFlag.Changed && o.runMake {
+		return invalidInputf("--make cannot be enabled in --offline mode")
+	}
+
+	for _, artifact := range o.overwrite {
+		o.apiScaffolder.Overwrite = append(o.apiScaffolder.Overwrite, scaffold.APIArtifact(artifact))
+	}
+
+	if o.controllerOnly {
+		if (o.resourceFlag.Changed && o.apiScaffolder.DoResource) ||
+			(o.controllerFlag.Changed && !o.apiScaffolder.DoController) {
+			return invalidInputf("--controller-only cannot be used with --resource or --controller=false")
+		}
+		o.apiScaffolder.DoResource = false
+		o.apiScaffolder.DoController = true
+	}
+
+	if o.apiScaffolder.GenerateOnly {
+		if o.controllerOnly || (o.resourceFlag.Changed && !o.apiScaffolder.DoResource) ||
+			(o.controllerFlag.Changed && o.apiScaffolder.DoController) {
+			return invalidInputf("--generate-only cannot be used with --controller-only, --resource=false or --controller")
+		}
+		o.apiScaffolder.DoResource = true
+		o.apiScaffolder.DoController = false
+	}
+
+	watches, err := parseWatches(o.watches)
+	if err != nil {
+		return invalidInput(err)
+	}
+	if len(watches) > 0 && o.controllerFlag.Changed && !o.apiScaffolder.DoController {
+		return invalidInputf("--watches requires the controller to be generated")
+	}
+	if (o.apiScaffolder.MaxConcurrentReconciles != 0 || o.apiScaffolder.GenerationPredicate) &&
+		o.controllerFlag.Changed && !o.apiScaffolder.DoController {
+		return invalidInputf("--max-concurrent-reconciles and --with-generation-predicate require the controller to be generated")
+	}
+	if o.apiScaffolder.Events && o.controllerFlag.Changed && !o.apiScaffolder.DoController {
+		return invalidInputf("--with-events requires the controller to be generated")
+	}
+	if o.apiScaffolder.Layout != scaffoldv2.ControllerLayoutFlat && o.controllerFlag.Changed &&
+		!o.apiScaffolder.DoController {
+		return invalidInputf("--layout requires the controller to be generated")
+	}
+	if o.apiScaffolder.Schema != "" && o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+		return invalidInputf("--schema requires the resource to be generated")
+	}
+	if len(o.apiScaffolder.Fields) > 0 && o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+		return invalidInputf("--field requires the resource to be generated")
+	}
+	if o.apiScaffolder.SampleValues != "" && o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+		return invalidInputf("--sample-values requires the resource to be generated")
+	}
+	if len(o.withWebhooks) > 0 {
+		if o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+			return invalidInputf("--with-webhooks requires the resource to be generated")
+		}
+		webhooks, err := parseWebhooks(o.withWebhooks)
+		if err != nil {
+			return invalidInput(err)
+		}
+		o.apiScaffolder.Webhooks = webhooks
+	}
+	if (len(o.apiScaffolder.PreserveUnknownFields) > 0 || len(o.apiScaffolder.EmbeddedResources) > 0) &&
+		o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+		return invalidInputf("--preserve-unknown-fields and --embedded-resources require the resource to be generated")
+	}
+	if o.interactiveFields {
+		if !o.interactive {
+			return invalidInputf("--interactive-fields cannot be used with --interactive=false")
+		}
+		if len(o.apiScaffolder.Fields) > 0 || o.apiScaffolder.Schema != "" {
+			return invalidInputf("--interactive-fields cannot be used with --field or --schema")
+		}
+		if o.resourceFlag.Changed && !o.apiScaffolder.DoResource {
+			return invalidInputf("--interactive-fields requires the resource to be generated")
+		}
+		if projectInfo.Version != project.Version2 {
+			return invalidInputf("--interactive-fields is only supported for project version %s", project.Version2)
+		}
+	}
+	o.apiScaffolder.Watches = watches
 
-	default:
-		log.Fatalf("unknown pattern %q", o.pattern)
+	for _, c := range o.printColumns {
+		column, err := resource.ParsePrintColumn(c)
+		if err != nil {
+			return invalidInput(err)
+		}
+		o.apiScaffolder.Resource.PrintColumns = append(o.apiScaffolder.Resource.PrintColumns, column)
 	}
 
 	if err := o.apiScaffolder.Validate(); err != nil {
-		log.Fatalln(err)
+		return err
 	}
 
-	reader := bufio.NewReader(os.Stdin)
-	if !o.resourceFlag.Changed {
-		fmt.Println("Create Resource [y/n]")
-		o.apiScaffolder.DoResource = util.Yesno(reader)
+	if o.prompter == nil {
+		o.prompter = util.NewPrompter(os.Stdin, os.Stdout)
+		if !o.interactive {
+			o.prompter = util.NewDefaultsPrompter()
+		}
+	}
+	if !o.resourceFlag.Changed && !o.controllerOnly && !o.apiScaffolder.GenerateOnly {
+		o.apiScaffolder.DoResource = o.prompter.Confirm("Create Resource", true)
 	}
 
-	if !o.controllerFlag.Changed {
-		fmt.Println("Create Controller [y/n]")
-		o.apiScaffolder.DoController = util.Yesno(reader)
+	if !o.controllerFlag.Changed && !o.controllerOnly && !o.apiScaffolder.GenerateOnly {
+		o.apiScaffolder.DoController = o.prompter.Confirm("Create Controller", true)
 	}
 
-	fmt.Println("Writing scaffold for you to edit...")
+	logging.Infof("Writing scaffold for you to edit...")
 
 	if err := o.apiScaffolder.Scaffold(); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	if o.interactiveFields && o.apiScaffolder.DoResource {
+		if fields := promptFields(o.prompter); len(fields) > 0 {
+			if err := o.apiScaffolder.RegenerateTypes(fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := scaffold.RecordPattern("PROJECT", pattern); err != nil {
+		return fmt.Errorf("error recording the %s pattern in the PROJECT file: %v", pattern.Name, err)
+	}
+	printDecisions(o.prompter)
+
 	if err := o.postScaffold(); err != nil {
-		log.Fatal(err)
+		return err
 	}
+
+	if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateAPI, hookExecutor()); err != nil {
+		return err
+	}
+	if o.apiScaffolder.Webhooks != nil {
+		if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateWebhook, hookExecutor()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (o *apiOptions) postScaffold() error {
+	if o.runMake && offline {
+		logging.Infof("Skipping make in offline mode.")
+		printSkippedCommands("make")
+		return nil
+	}
 	if o.runMake {
-		fmt.Println("Running make...")
-		cm := exec.Command("make") // #nosec
-		cm.Stderr = os.Stderr
-		cm.Stdout = os.Stdout
-		if err := cm.Run(); err != nil {
+		if err := runMake(); err != nil {
 			return fmt.Errorf("error running make: %v", err)
 		}
 	}
@@ -141,12 +416,64 @@ func newAPICommand() *cobra.Command {
 create resource will prompt the user for if it should scaffold the Resource and / or Controller.  To only
 scaffold a Controller for an existing Resource, select "n" for Resource.  To only define
 the schema for a Resource without writing a Controller, select "n" for Controller.
+With --interactive=false, nothing is prompted and both are scaffolded unless
+--resource or --controller say otherwise. The decisions are listed once the
+scaffold is written.
+
+When the API already exists, --overwrite scaffolds the given artifacts again.
+The code between "+kubebuilder:scaffold:user-code-begin" and
+"+kubebuilder:scaffold:user-code-end" markers of the overwritten files is kept.
 
 After the scaffold is written, api will run make on the project.
 `,
 		Example: `	# Create a frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
 	kubebuilder create api --group ship --version v1beta1 --kind Frigate
-	
+
+	# Regenerate the controller and the sample of the existing frigates API
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --resource --controller \
+		--overwrite=controller,sample
+
+	# Create a frigates API listed by kubectl get fr and kubectl get all, with the phase and age columns
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --short-name fr --categories all \
+		--printer-column "Phase:.status.phase" --printer-column "Age:.metadata.creationTimestamp"
+
+	# Create a frigates API along with its defaulting and validating webhooks
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --with-webhooks defaulting,validation
+
+	# Create a frigates API whose controller reconciles 5 frigates at once and ignores their status updates
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --max-concurrent-reconciles 5 \
+		--with-generation-predicate
+
+	# Create a frigates API whose controller records the events of the frigates it reconciles
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --with-events
+
+	# Create a frigates API whose controller is in its own package, internal/controller/frigate
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --layout per-kind
+
+	# Create a frigates API whose spec has 1 to 10 replicas, 3 by default, and a required image
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate \
+		--field "Replicas:int32:min=1,max=10,default=3" --field "Image:string:required,minLength=1"
+
+	# Create a frigates API whose spec embeds the template of the pods it runs, and whose status keeps
+	# the fields the API does not know
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --field "Template:object:required" \
+		--embedded-resources Template --preserve-unknown-fields status
+
+	# Create a frigates API whose sample has the spec of frigate.yaml
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate \
+		--field "Replicas:int32:min=1" --sample-values frigate.yaml
+
+	# Create a frigates API, and enter the fields of its spec when prompted
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --interactive-fields
+
+	# Create only the types of a frigates API, and complete it with its controller later
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --generate-only
+	kubebuilder create controller --group ship --version v1beta1 --kind Frigate
+
+	# Create a controller for the existing frigates API which owns Deployments and ConfigMaps
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --controller-only \
+		--watches=apps/v1/Deployment,core/v1/ConfigMap
+
 	# Edit the API Scheme
 	nano api/v1beta1/frigate_types.go
 
@@ -163,7 +490,9 @@ After the scaffold is written, api will run make on the project.
 	make run
 `,
 		Run: func(cmd *cobra.Command, args []string) {
-			options.runAddAPI()
+			if err := options.runAddAPI(); err != nil {
+				fatal(err)
+			}
 		},
 	}
 
@@ -172,9 +501,37 @@ After the scaffold is written, api will run make on the project.
 	return apiCmd
 }
 
+// registerPatterns registers the patterns of the APIs provided by kubebuilder
+func registerPatterns() error {
+	return scaffold.RegisterPattern(addon.Pattern())
+}
+
+// selectPattern returns the pattern of the API: the one passed with --pattern
+// or else the one recorded in the PROJECT file. The plain pattern is returned
+// as a Pattern without plugins.
+func (o *apiOptions) selectPattern() (scaffold.Pattern, error) {
+	if !o.patternFlag.Changed {
+		if p, err := scaffold.LoadProjectFile("PROJECT"); err == nil {
+			if pattern, found := scaffold.RecordedPattern(p); found {
+				return pattern, nil
+			}
+		}
+		return scaffold.Pattern{Name: scaffold.PatternNone}, nil
+	}
+	if o.pattern == "" || strings.ToLower(o.pattern) == scaffold.PatternNone {
+		return scaffold.Pattern{Name: scaffold.PatternNone}, nil
+	}
+	pattern, found := scaffold.LookupPattern(o.pattern)
+	if !found {
+		return scaffold.Pattern{}, fmt.Errorf("%w %q, must be one of %s", scaffold.ErrUnknownPattern,
+			o.pattern, strings.Join(append(scaffold.PatternNames(), scaffold.PatternNone), ", "))
+	}
+	return pattern, nil
+}
+
 // dieIfNoProject checks to make sure the command is run from a directory containing a project file.
 func dieIfNoProject() {
 	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
-		log.Fatalf("Command must be run from a directory containing %s", "PROJECT")
+		fatal(scaffold.ErrProjectNotInitialized)
 	}
 }
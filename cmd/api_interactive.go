@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/schema"
+)
+
+// promptFields walks the user through the fields of the Spec of the types,
+// until an empty name is entered. The fields are returned with the syntax of
+// the --field flag, e.g. Replicas:int32:min=1,max=10.
+func promptFields(prompter util.Prompter) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for {
+		name := prompter.Ask("Field name, e.g. Replicas (empty to finish)", "", func(name string) error {
+			if name == "" {
+				return nil
+			}
+			if seen[name] {
+				return fmt.Errorf("field %s is already defined", name)
+			}
+			_, err := schema.ParseField(name + ":string")
+			return err
+		})
+		if name == "" {
+			return fields
+		}
+
+		typ := prompter.Ask("Type (string, int32, int64, bool, quantity, time, or a []<type> or "+
+			"map[string]<type> of them)", "string", func(typ string) error {
+			_, err := schema.ParseField(name + ":" + typ)
+			return err
+		})
+
+		field := name + ":" + typ
+		options := prompter.Ask("Validation options, e.g. required,min=1,max=10 (empty for none)", "",
+			func(options string) error {
+				_, err := schema.ParseField(field + ":" + options)
+				return err
+			})
+		if options != "" {
+			field += ":" + options
+		}
+
+		seen[name] = true
+		fields = append(fields, field)
+	}
+}
+
+// printDecisions prints the questions asked by the prompter and their
+// answers, telling apart the ones which were prompted from the defaults.
+func printDecisions(prompter util.Prompter) {
+	decisions := prompter.Decisions()
+	if len(decisions) == 0 {
+		return
+	}
+	logging.Infof("Decisions:")
+	for _, d := range decisions {
+		how := "default"
+		if d.Prompted {
+			how = "prompted"
+		}
+		logging.Infof("  %s: %s (%s)", d.Question, d.Answer, how)
+	}
+}
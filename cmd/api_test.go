@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+// chdirTemp changes the working directory to a new temporary directory, and
+// returns the function restoring it
+func chdirTemp(t *testing.T) func() {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	dir, err := ioutil.TempDir("", "kubebuilder-api")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	return func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Errorf("error %v", err)
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+// scaffoldTestProject scaffolds a project in the working directory
+func scaffoldTestProject(t *testing.T) {
+	p := &scaffold.V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version: project.Version2,
+			Domain:  "example.com",
+			Repo:    "example.com/proj",
+		}},
+		Boilerplate: project.Boilerplate{License: "none"},
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := p.Scaffold(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+}
+
+// newAPIOptions returns the options of create api parsed from args
+func newAPIOptions(t *testing.T, args ...string) *apiOptions {
+	o := &apiOptions{}
+	cmd := &cobra.Command{}
+	o.bindCmdFlags(cmd)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	return o
+}
+
+func TestRunAddAPIErrors(t *testing.T) {
+	defer chdirTemp(t)()
+
+	o := newAPIOptions(t, "--group", "ship", "--version", "v1", "--kind", "Frigate")
+	if err := o.runAddAPI(); !errors.Is(err, scaffold.ErrProjectNotInitialized) {
+		t.Errorf("expected a project not initialized error, got %v", err)
+	}
+
+	project := "version: \"2\"\ndomain: example.com\nrepo: example.com/proj\n"
+	if err := ioutil.WriteFile("PROJECT", []byte(project), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	tests := [][]string{
+		{"--controller-only", "--resource"},
+		{"--generate-only", "--controller"},
+		{"--controller=false", "--watches", "apps/v1/Deployment"},
+		{"--watches", "apps/Deployment"},
+		{"--with-webhooks", "mutating"},
+		{"--printer-column", "Phase"},
+		{"--interactive-fields", "--interactive=false"},
+		{"--overwrite", "readme"},
+		{"--max-concurrent-reconciles", "-1"},
+	}
+	for _, args := range tests {
+		o := newAPIOptions(t, append([]string{"--group", "ship", "--version", "v1", "--kind", "Frigate"},
+			args...)...)
+		err := o.runAddAPI()
+		if !errors.Is(err, scaffold.ErrInvalidInput) {
+			t.Errorf("expected an invalid input error for %v, got %v", args, err)
+		}
+		if code := exitCode(err); code != exitCodeInvalidInput {
+			t.Errorf("expected exit code %d for %v, got %d", exitCodeInvalidInput, args, code)
+		}
+	}
+}
+
+func TestRunAddAPIDefaults(t *testing.T) {
+	defer chdirTemp(t)()
+	scaffoldTestProject(t)
+
+	o := newAPIOptions(t, "--group", "ship", "--version", "v1", "--kind", "Frigate", "--make=false")
+	o.prompter = util.NewDefaultsPrompter()
+	if err := o.runAddAPI(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	for _, path := range []string{"api/v1/frigate_types.go", "controllers/frigate_controller.go"} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be scaffolded: %v", path, err)
+		}
+	}
+	expected := []util.Decision{
+		{Question: "Create Resource", Answer: "yes"},
+		{Question: "Create Controller", Answer: "yes"},
+	}
+	decisions := o.prompter.Decisions()
+	if len(decisions) != len(expected) {
+		t.Fatalf("got decisions %v and wanted %v", decisions, expected)
+	}
+	for i, decision := range decisions {
+		if decision != expected[i] {
+			t.Errorf("got decision %v and wanted %v", decision, expected[i])
+		}
+	}
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/schema"
+)
+
+// projectManifest is the declarative description of a project read by apply.
+type projectManifest struct {
+	Domain  string `json:"domain,omitempty"`
+	Repo    string `json:"repo,omitempty"`
+	License string `json:"license,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+
+	Resources []resourceManifest `json:"resources,omitempty"`
+}
+
+// resourceManifest describes what to scaffold for a single GVK.
+type resourceManifest struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+
+	// Namespaced defaults to true
+	Namespaced *bool `json:"namespaced,omitempty"`
+	// Plural is only needed when the plural computed from the kind is wrong
+	Plural string `json:"plural,omitempty"`
+	// ShortNames and Categories are the short names of the resource and the
+	// categories it belongs to, see create api --short-name and --categories
+	ShortNames []string `json:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+
+	// Resource indicates whether to scaffold the API types
+	Resource bool `json:"resource,omitempty"`
+	// Controller indicates whether to scaffold the controller
+	Controller bool `json:"controller,omitempty"`
+	// Layout is the layout of the package of the controller, defaults to
+	// flat
+	Layout string `json:"layout,omitempty"`
+	// GenerateOnly indicates whether to scaffold only the API types, see
+	// create api --generate-only
+	GenerateOnly bool `json:"generateOnly,omitempty"`
+	// Fields are the fields of the Spec of the types, with the syntax of
+	// create api --field
+	Fields []string `json:"fields,omitempty"`
+	// PreserveUnknownFields and EmbeddedResources are the pruning options of
+	// the types, see create api --preserve-unknown-fields and
+	// --embedded-resources
+	PreserveUnknownFields []string `json:"preserveUnknownFields,omitempty"`
+	EmbeddedResources     []string `json:"embeddedResources,omitempty"`
+	// Webhook holds the webhooks to scaffold, if any
+	Webhook *webhookManifest `json:"webhook,omitempty"`
+}
+
+type webhookManifest struct {
+	Defaulting bool `json:"defaulting,omitempty"`
+	Validation bool `json:"validation,omitempty"`
+	Conversion bool `json:"conversion,omitempty"`
+}
+
+type applyOptions struct {
+	file string
+
+	// flags of the initialization of the project, like the ones of init
+	fetchDeps          bool
+	fetchDepsFlag      *flag.Flag
+	skipGoVersionCheck bool
+
+	// runMake indicates whether to run make once everything is scaffolded
+	runMake  bool
+	makeFlag *flag.Flag
+}
+
+func newApplyCmd() *cobra.Command {
+	o := applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Scaffold a project from a declarative manifest",
+		Long: `Scaffold a project from a declarative manifest.
+
+apply initializes the project if there is no PROJECT file yet, then creates the
+APIs, controllers and webhooks listed in the manifest. Anything that is already
+scaffolded is left untouched, so apply can be run again after adding resources
+to the manifest.
+`,
+		Example: `	# project.yaml
+	domain: example.org
+	repo: example.org/guestbook
+	resources:
+	- group: webapp
+	  version: v1
+	  kind: Guestbook
+	  resource: true
+	  controller: true
+	  webhook:
+	    defaulting: true
+	    validation: true
+
+	kubebuilder apply -f project.yaml
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "path to the project manifest")
+	cmd.Flags().BoolVar(&o.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded when initializing the project")
+	o.fetchDepsFlag = cmd.Flag("fetch-deps")
+	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check", false, "if specified, skip checking the Go version")
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+	o.makeFlag = cmd.Flag("make")
+
+	return cmd
+}
+
+func (o *applyOptions) run() error {
+	if o.file == "" {
+		return fmt.Errorf("a project manifest is required, use -f to pass one")
+	}
+	if offline && o.makeFlag.Changed && o.runMake {
+		return fmt.Errorf("--make cannot be enabled in --offline mode")
+	}
+	if offline && o.fetchDepsFlag.Changed && o.fetchDeps {
+		return fmt.Errorf("--fetch-deps cannot be enabled in --offline mode")
+	}
+
+	b, err := ioutil.ReadFile(o.file)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", o.file, err)
+	}
+	m := projectManifest{}
+	if err := yaml.UnmarshalStrict(b, &m); err != nil {
+		return fmt.Errorf("error parsing %s: %v", o.file, err)
+	}
+	if err := m.validate(); err != nil {
+		return fmt.Errorf("invalid manifest %s: %v", o.file, err)
+	}
+
+	scaffolded, err := o.applyProject(m)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range m.Resources {
+		done, err := applyResource(r)
+		if err != nil {
+			return err
+		}
+		scaffolded = scaffolded || done
+	}
+
+	if !scaffolded || !o.runMake {
+		return nil
+	}
+	if offline {
+		logging.Infof("Skipping make in offline mode.")
+		printSkippedCommands("make")
+		return nil
+	}
+	if err := runMake(); err != nil {
+		return fmt.Errorf("error running make: %v", err)
+	}
+	return nil
+}
+
+func (m projectManifest) validate() error {
+	for i, r := range m.Resources {
+		if r.Version == "" || r.Kind == "" {
+			return fmt.Errorf("resources[%d] must set version and kind", i)
+		}
+		if !r.Resource && !r.Controller && r.Webhook == nil {
+			return fmt.Errorf("resources[%d] (%s) has nothing to scaffold", i, r.Kind)
+		}
+		if r.GenerateOnly && (!r.Resource || r.Controller) {
+			return fmt.Errorf("resources[%d] (%s) generateOnly requires resource and no controller", i, r.Kind)
+		}
+		if r.Layout != "" {
+			if err := scaffoldv2.ControllerLayout(r.Layout).Validate(); err != nil {
+				return fmt.Errorf("resources[%d] (%s): %v", i, r.Kind, err)
+			}
+			if !r.Controller {
+				return fmt.Errorf("resources[%d] (%s) layout requires controller", i, r.Kind)
+			}
+		}
+		if len(r.Fields) > 0 && !r.Resource {
+			return fmt.Errorf("resources[%d] (%s) fields requires resource", i, r.Kind)
+		}
+		if (len(r.PreserveUnknownFields) > 0 || len(r.EmbeddedResources) > 0) && !r.Resource {
+			return fmt.Errorf("resources[%d] (%s) preserveUnknownFields and embeddedResources require resource",
+				i, r.Kind)
+		}
+		for _, f := range r.Fields {
+			if _, err := schema.ParseField(f); err != nil {
+				return fmt.Errorf("resources[%d] (%s): %v", i, r.Kind, err)
+			}
+		}
+		if r.Webhook != nil && !r.Webhook.Defaulting && !r.Webhook.Validation && !r.Webhook.Conversion {
+			return fmt.Errorf("resources[%d] (%s) webhook requires at least one of defaulting, validation and conversion", i, r.Kind)
+		}
+	}
+	return nil
+}
+
+// applyProject initializes the project, or checks that the existing one
+// matches the manifest, and returns true if the project was initialized.
+func (o *applyOptions) applyProject(m projectManifest) (bool, error) {
+	if util.ProjectExist() {
+		p, err := scaffold.LoadProjectFile("PROJECT")
+		if err != nil {
+			return false, fmt.Errorf("failed to read the PROJECT file: %v", err)
+		}
+		if m.Domain != "" && m.Domain != p.Domain {
+			return false, fmt.Errorf("manifest domain %q does not match the project domain %q", m.Domain, p.Domain)
+		}
+		if m.Repo != "" && m.Repo != p.Repo {
+			return false, fmt.Errorf("manifest repo %q does not match the project repo %q", m.Repo, p.Repo)
+		}
+		return false, nil
+	}
+
+	domain := m.Domain
+	if domain == "" {
+		domain = "my.domain"
+	}
+	license := m.License
+	if license == "" {
+		license = "apache2"
+	}
+	p := &scaffold.V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version: project.Version2,
+			Domain:  domain,
+			Repo:    m.Repo,
+		}},
+		Boilerplate: project.Boilerplate{License: license, Owner: m.Owner},
+		Executor:    commandExecutor(),
+	}
+	if err := initProject(p, o.fetchDeps && !offline, o.skipGoVersionCheck); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// initProject validates and scaffolds the project p in the working
+// directory, which names the project, like init does. The dependencies are
+// fetched if fetchDeps is set, make is left to the caller.
+func initProject(p *scaffold.V2Project, fetchDeps, skipGoVersionCheck bool) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if !skipGoVersionCheck {
+		if err := validateGoVersion(p.GoVersion); err != nil {
+			return err
+		}
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error to get the current path: %v", err)
+	}
+	if err := util.IsValidName(strings.ToLower(filepath.Base(dir))); err != nil {
+		return fmt.Errorf("project name (%v) is invalid: (%v)", filepath.Base(dir), err)
+	}
+	if p.Project.Domain != "" {
+		if errs := resource.IsDNS1123Subdomain(p.Project.Domain); len(errs) > 0 {
+			return fmt.Errorf("domain %q is invalid: (%s)", p.Project.Domain, strings.Join(errs, ", "))
+		}
+	}
+	if p.Project.Repo == "" {
+		if p.Project.Repo, err = findCurrentRepo(); err != nil {
+			return fmt.Errorf("error finding current repository: %v", err)
+		}
+	}
+	if err := validateRepo(p.Project.Repo); err != nil {
+		return err
+	}
+
+	if err := p.Scaffold(); err != nil {
+		return fmt.Errorf("error scaffolding project: %v", err)
+	}
+	if !fetchDeps {
+		logging.Infof("Skipping fetching dependencies.")
+		printSkippedCommands(p.DependencyCommands()...)
+		return nil
+	}
+	_, err = p.EnsureDependencies()
+	return err
+}
+
+// applyResource scaffolds the parts of the resource which do not exist yet,
+// and returns true if anything was scaffolded.
+func applyResource(r resourceManifest) (bool, error) {
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return false, fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+
+	layout := scaffoldv2.ControllerLayout(r.Layout)
+	if layout == "" {
+		layout = scaffoldv2.ControllerLayoutFlat
+	}
+	doResource := r.Resource && !resourceTracked(p, r)
+	doController := r.Controller && !fileExists(layout.ControllerPath(r.Kind))
+	doWebhook := r.Webhook != nil && !fileExists(filepath.Join("api", r.Version,
+		fmt.Sprintf("%s_webhook.go", strings.ToLower(r.Kind))))
+
+	if doResource || doController {
+		api := &scaffold.API{
+			Resource: &resource.Resource{
+				Group:                      r.Group,
+				Version:                    r.Version,
+				Kind:                       r.Kind,
+				Resource:                   r.Plural,
+				Namespaced:                 r.Namespaced == nil || *r.Namespaced,
+				ShortNames:                 r.ShortNames,
+				Categories:                 r.Categories,
+				CreateExampleReconcileBody: true,
+			},
+			DoResource:   doResource,
+			DoController: doController,
+			GenerateOnly: r.GenerateOnly,
+			Layout:       layout,
+		}
+		if doResource {
+			api.Fields = r.Fields
+			api.PreserveUnknownFields = r.PreserveUnknownFields
+			api.EmbeddedResources = r.EmbeddedResources
+		}
+		if pattern, found := scaffold.RecordedPattern(p); found && pattern.NewPlugins != nil {
+			api.Plugins = pattern.NewPlugins()
+		}
+		if err := api.Validate(); err != nil {
+			return false, err
+		}
+		logging.Infof("Writing scaffold for you to edit...")
+		if err := api.Scaffold(); err != nil {
+			return false, err
+		}
+		if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateAPI, hookExecutor()); err != nil {
+			return false, err
+		}
+	}
+
+	if doWebhook {
+		w := &scaffold.Webhook{
+			Resource:   &resource.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind},
+			Defaulting: r.Webhook.Defaulting,
+			Validating: r.Webhook.Validation,
+			Conversion: r.Webhook.Conversion,
+		}
+		if err := w.Validate(); err != nil {
+			return false, err
+		}
+		logging.Infof("Writing scaffold for you to edit...")
+		if err := w.Scaffold(); err != nil {
+			return false, err
+		}
+		if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateWebhook, hookExecutor()); err != nil {
+			return false, err
+		}
+	}
+
+	return doResource || doController || doWebhook, nil
+}
+
+func resourceTracked(p input.ProjectFile, r resourceManifest) bool {
+	for _, res := range p.Resources {
+		if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+func TestProjectManifestValidate(t *testing.T) {
+	tests := []struct {
+		resource  resourceManifest
+		isInvalid bool
+	}{
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true, Controller: true}, false},
+		{resourceManifest{Version: "v1", Kind: "Pod", Webhook: &webhookManifest{Defaulting: true}}, false},
+		{resourceManifest{Group: "ship", Kind: "Frigate", Resource: true}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Resource: true}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate"}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true, Controller: true,
+			GenerateOnly: true}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Controller: true, Layout: "nested"}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true, Layout: "per-kind"}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Controller: true,
+			Fields: []string{"Replicas:int32"}}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true,
+			Fields: []string{"Replicas:float"}}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Controller: true,
+			PreserveUnknownFields: []string{"spec"}}, true},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Webhook: &webhookManifest{}}, true},
+	}
+
+	for _, test := range tests {
+		err := projectManifest{Resources: []resourceManifest{test.resource}}.validate()
+		if (err != nil) != test.isInvalid {
+			t.Errorf("validate(%+v) = %v, expected invalid: %v", test.resource, err, test.isInvalid)
+		}
+	}
+}
+
+func TestResourceTracked(t *testing.T) {
+	p := input.ProjectFile{Resources: []input.Resource{{Group: "ship", Version: "v1", Kind: "Frigate"}}}
+
+	tests := []struct {
+		resource resourceManifest
+		tracked  bool
+	}{
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate"}, true},
+		{resourceManifest{Group: "ship", Version: "v2", Kind: "Frigate"}, false},
+		{resourceManifest{Group: "sea", Version: "v1", Kind: "Frigate"}, false},
+		{resourceManifest{Group: "ship", Version: "v1", Kind: "Sloop"}, false},
+	}
+
+	for _, test := range tests {
+		if tracked := resourceTracked(p, test.resource); tracked != test.tracked {
+			t.Errorf("resourceTracked(%+v) = %v, expected %v", test.resource, tracked, test.tracked)
+		}
+	}
+}
+
+func TestApplyResource(t *testing.T) {
+	defer chdirTemp(t)()
+	scaffoldTestProject(t)
+
+	r := resourceManifest{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true, Controller: true}
+	if scaffolded, err := applyResource(r); err != nil || !scaffolded {
+		t.Fatalf("applyResource(%+v) = %v, %v, expected the API to be scaffolded", r, scaffolded, err)
+	}
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if !resourceTracked(p, r) {
+		t.Errorf("expected the resource to be tracked in the PROJECT file")
+	}
+
+	// the resource and the controller are already scaffolded
+	if scaffolded, err := applyResource(r); err != nil || scaffolded {
+		t.Errorf("applyResource(%+v) = %v, %v, expected nothing to be scaffolded again", r, scaffolded, err)
+	}
+
+	// only the webhook is new
+	r.Webhook = &webhookManifest{Defaulting: true}
+	if scaffolded, err := applyResource(r); err != nil || !scaffolded {
+		t.Errorf("applyResource(%+v) = %v, %v, expected the webhook to be scaffolded", r, scaffolded, err)
+	}
+	if !fileExists("api/v1/frigate_webhook.go") {
+		t.Errorf("expected the webhook to be scaffolded")
+	}
+	if scaffolded, err := applyResource(r); err != nil || scaffolded {
+		t.Errorf("applyResource(%+v) = %v, %v, expected nothing to be scaffolded again", r, scaffolded, err)
+	}
+
+	// the errors of the scaffolding are returned
+	invalid := resourceManifest{Group: "ship", Version: "v1", Kind: "sloop", Resource: true}
+	if _, err := applyResource(invalid); err == nil {
+		t.Errorf("applyResource(%+v) expected an error for the lowercase kind", invalid)
+	}
+}
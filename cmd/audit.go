@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+func newAuditScaffoldsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit-scaffolds",
+		Short: "List the files scaffolded by older versions of the templates",
+		Long: `List the files of the project scaffolded by older versions of their templates,
+with the changes to make to upgrade them.
+
+The versioned templates stamp the files they scaffold with the version of the
+template, in a "+kubebuilder:scaffold:version=<version>" marker. The files
+without the marker were scaffolded before their template was versioned, they
+can be compared with the files scaffolded by kubebuilder alpha regenerate.
+
+audit-scaffolds exits with an error if any file is outdated.
+`,
+		Example: `	# list the outdated files of the project
+	kubebuilder alpha audit-scaffolds
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := auditScaffolds(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+	return cmd
+}
+
+// outdatedFile is a file scaffolded by an older version of its template
+type outdatedFile struct {
+	path     string
+	stamped  int
+	template input.TemplateVersion
+}
+
+func auditScaffolds() error {
+	dieIfNoProject()
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	if p.Version != project.Version2 {
+		return fmt.Errorf("kubebuilder alpha audit-scaffolds is only supported for project version %s",
+			project.Version2)
+	}
+
+	var outdated []outdatedFile
+	for _, t := range scaffoldv2.TemplateVersions {
+		paths, err := filepath.Glob(t.Path)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			v, err := scaffold.ScaffoldVersion(path)
+			if err != nil {
+				return err
+			}
+			if v < t.Version {
+				outdated = append(outdated, outdatedFile{path: path, stamped: v, template: t})
+			}
+		}
+	}
+	if len(outdated) == 0 {
+		logging.Infof("The files of the project are scaffolded by the current versions of their templates.")
+		return nil
+	}
+
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].path < outdated[j].path })
+	for _, f := range outdated {
+		if f.stamped == 0 {
+			logging.Errorf("%s: scaffolded before its template was versioned, the current version is %d",
+				f.path, f.template.Version)
+			logging.Infof("  - compare it with the file scaffolded by kubebuilder alpha regenerate, which is stamped")
+		} else {
+			logging.Errorf("%s: scaffolded by version %d of its template, the current version is %d",
+				f.path, f.stamped, f.template.Version)
+		}
+		for v := f.stamped + 1; v <= f.template.Version; v++ {
+			if m, found := f.template.Migrations[v]; found {
+				logging.Infof("  - version %d: %s", v, m)
+			}
+		}
+	}
+	return fmt.Errorf("%d files are scaffolded by older versions of their templates", len(outdated))
+}
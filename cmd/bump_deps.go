@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+func newBumpDepsCmd() *cobra.Command {
+	k8sVersion := ""
+
+	cmd := &cobra.Command{
+		Use:   "bump-deps",
+		Short: "Update the dependencies of the project to a Kubernetes release",
+		Long: fmt.Sprintf(`Update the dependencies of the project to the versions of a Kubernetes
+release, all together so that they stay compatible: the controller-runtime and
+k8s.io requirements of go.mod, the tools of the tools module in hack/tools, the
+versions of controller-gen, conversion-gen and defaulter-gen installed by the
+Makefile and the Kubernetes version of its envtest binaries.
+
+go mod tidy is run afterwards to update go.sum, unless --offline is set.
+
+The supported Kubernetes versions are: %s.
+`, strings.Join(scaffold.K8sVersions(), ", ")),
+		Example: fmt.Sprintf(`	# update the dependencies to Kubernetes %s
+	kubebuilder alpha bump-deps --k8s-version %s
+`, scaffold.K8sVersions()[0], scaffold.K8sVersions()[0]),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runBumpDeps(k8sVersion); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&k8sVersion, "k8s-version", "",
+		fmt.Sprintf("the Kubernetes release to update the dependencies to, one of %s",
+			strings.Join(scaffold.K8sVersions(), ", ")))
+
+	return cmd
+}
+
+func runBumpDeps(k8sVersion string) error {
+	dieIfNoProject()
+
+	if k8sVersion == "" {
+		return fmt.Errorf("--k8s-version is required, it must be one of %s",
+			strings.Join(scaffold.K8sVersions(), ", "))
+	}
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	if p.Version != project.Version2 {
+		return fmt.Errorf("kubebuilder alpha bump-deps is for project version: 2, the version of this "+
+			"project is: %s", p.Version)
+	}
+
+	changed, err := scaffold.BumpDeps(k8sVersion)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		logging.Infof("The dependencies are already those of Kubernetes %s.", k8sVersion)
+		return nil
+	}
+	for _, path := range changed {
+		logging.Infof("%s", path)
+		if path == filepath.Join("hack", "tools", "go.mod") {
+			logging.Infof("Run go mod tidy in hack/tools to update its go.sum.")
+		}
+	}
+	if offline {
+		logging.Infof("Run go mod tidy to update go.sum.")
+		return nil
+	}
+	done := logging.StartProgress("Running go mod tidy")
+	err = commandExecutor().Run("go", "mod", "tidy")
+	done(err)
+	return err
+}
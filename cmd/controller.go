@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+type controllerOptions struct {
+	apiScaffolder scaffold.API
+
+	// runMake indicates whether to run make after scaffolding the controller
+	runMake  bool
+	makeFlag *flag.Flag
+
+	// watches are the group/version/kind of the secondary resources owned by
+	// the resource
+	watches []string
+}
+
+func newControllerCmd() *cobra.Command {
+	o := controllerOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Short: "Complete an API created with create api --generate-only",
+		Long: `Complete an API created with create api --generate-only, by scaffolding its
+controller, sample, RBAC roles and e2e test, and wiring it in main.go.
+
+The types of the API are left untouched. The API is scaffolded with the pattern
+recorded in the PROJECT file, if any.
+`,
+		Example: `	# Complete the frigates API, with a controller which owns Deployments
+	kubebuilder create api --group ship --version v1beta1 --kind Frigate --generate-only
+	kubebuilder create controller --group ship --version v1beta1 --kind Frigate --watches=apps/v1/Deployment
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	r := &resource.Resource{Namespaced: true}
+	cmd.Flags().StringVar(&r.Group, "group", "", "resource Group")
+	cmd.Flags().StringVar(&r.Version, "version", "", "resource Version")
+	cmd.Flags().StringVar(&r.Kind, "kind", "", "resource Kind")
+	cmd.Flags().BoolVar(&r.CreateExampleReconcileBody, "example", true,
+		"if true an example reconcile body should be written while scaffolding the controller.")
+	o.apiScaffolder.Resource = r
+
+	cmd.Flags().BoolVar(&o.runMake, "make", true, "if true, run make after generating files")
+	o.makeFlag = cmd.Flag("make")
+	cmd.Flags().StringSliceVar(&o.watches, "watches", nil,
+		"group/version/kind of the resources owned by the resource, e.g. apps/v1/Deployment,core/v1/ConfigMap. "+
+			"The controller watches them and gets the RBAC permissions to manage them")
+	cmd.Flags().IntVar(&o.apiScaffolder.MaxConcurrentReconciles, "max-concurrent-reconciles", 0,
+		"if set, the maximum number of objects the controller reconciles concurrently, instead of 1")
+	cmd.Flags().BoolVar(&o.apiScaffolder.GenerationPredicate, "with-generation-predicate", false,
+		"if set, the controller ignores the updates which do not change the generation of the objects, "+
+			"e.g. the status updates")
+	cmd.Flags().BoolVar(&o.apiScaffolder.Events, "with-events", false,
+		"if set, the controller records the events of the objects it reconciles with the typed reasons of "+
+			"controllers/events.go")
+	cmd.Flags().StringVar((*string)(&o.apiScaffolder.Layout), "layout", string(scaffoldv2.ControllerLayoutFlat),
+		"layout of the package of the controller. May be one of flat (in controllers/) or per-kind (in its own "+
+			"package internal/controller/<kind>/, with its helpers and test)")
+
+	return cmd
+}
+
+func (o *controllerOptions) run() error {
+	dieIfNoProject()
+
+	if offline && o.makeFlag.Changed && o.runMake {
+		return fmt.Errorf("--make cannot be enabled in --offline mode")
+	}
+
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	// the API is completed with the plugins of the pattern it was created with
+	if pattern, found := scaffold.RecordedPattern(p); found && pattern.NewPlugins != nil {
+		o.apiScaffolder.Plugins = pattern.NewPlugins()
+	}
+
+	if o.apiScaffolder.Watches, err = parseWatches(o.watches); err != nil {
+		return err
+	}
+	o.apiScaffolder.DoResource = true
+	o.apiScaffolder.DoController = true
+	o.apiScaffolder.CompleteGenerated = true
+	if err := o.apiScaffolder.Validate(); err != nil {
+		return err
+	}
+
+	logging.Infof("Writing scaffold for you to edit...")
+	if err := o.apiScaffolder.Scaffold(); err != nil {
+		return err
+	}
+
+	if o.runMake && offline {
+		logging.Infof("Skipping make in offline mode.")
+		printSkippedCommands("make")
+	} else if o.runMake {
+		if err := runMake(); err != nil {
+			return fmt.Errorf("error running make: %v", err)
+		}
+	}
+
+	return scaffold.RunHooks("PROJECT", input.HookPhaseCreateAPI, hookExecutor())
+}
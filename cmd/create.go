@@ -36,12 +36,14 @@ func newCreateCmd() *cobra.Command {
 	)
 
 	foundProject, version := getProjectVersion()
-	// It add webhook v2 command in the following 2 cases:
+	// It add webhook v2 and controller commands in the following 2 cases:
 	// - There are no PROJECT file found.
 	// - version == 2 is found in the PROJECT file.
 	if !foundProject || version == "2" {
 		cmd.AddCommand(
 			newWebhookV2Cmd(),
+			newControllerCmd(),
+			newQuotaCmd(),
 		)
 	}
 
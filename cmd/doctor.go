@@ -0,0 +1,295 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	scaffoldutil "sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+// controllerGenVersionRegex matches the controller-gen version pinned by the
+// Makefile of the project.
+var controllerGenVersionRegex = regexp.MustCompile(`controller-gen@(v[0-9][0-9A-Za-z.\-]*)`)
+
+// diagnosis is the result of one of the checks of doctor.
+type diagnosis struct {
+	// check is what was checked, e.g. the name of a tool
+	check string
+	// message describes what was found
+	message string
+	// problem is true if the check failed, and warning is true if it found
+	// something which may be a problem
+	problem, warning bool
+	// fix tells how to fix the problem, if any
+	fix string
+}
+
+func (d diagnosis) String() string {
+	status := "ok"
+	switch {
+	case d.problem:
+		status = "error"
+	case d.warning:
+		status = "warning"
+	}
+	return fmt.Sprintf("[%s] %s", status, d.report())
+}
+
+// report returns the check, its message and the fix if any
+func (d diagnosis) report() string {
+	s := fmt.Sprintf("%s: %s", d.check, d.message)
+	if d.fix != "" {
+		s += "\n    " + d.fix
+	}
+	return s
+}
+
+type doctorOptions struct {
+	// lookPath and output run the tools, they are replaced by the tests
+	lookPath func(file string) (string, error)
+	output   func(name string, args ...string) ([]byte, error)
+}
+
+func newDoctorCmd() *cobra.Command {
+	o := doctorOptions{
+		lookPath: exec.LookPath,
+		output: func(name string, args ...string) ([]byte, error) {
+			return exec.Command(name, args...).CombinedOutput() // #nosec
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the development environment and the project",
+		Long: `Check the development environment and the project.
+
+doctor checks that the tools used by the scaffolded Makefile are installed: go,
+controller-gen, kustomize, kubectl and docker. The version of controller-gen is
+compared with the version pinned by the Makefile of the project.
+
+When run in a project, doctor also checks that the resources recorded in the
+PROJECT file match the API types on disk.
+
+Each problem is printed with how to fix it, and doctor exits with an error if
+any is found.
+`,
+		Example: `	# check the environment and the project in the current directory
+	kubebuilder doctor
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func (o *doctorOptions) run() error {
+	diagnoses := []diagnosis{
+		o.checkGo(),
+		o.checkControllerGen(),
+		o.checkTool("kustomize", "version"),
+		o.checkTool("kubectl", "version", "--client", "--short"),
+		o.checkTool("docker", "version", "--format", "{{.Client.Version}}"),
+	}
+	if util.ProjectExist() {
+		diagnoses = append(diagnoses, checkProject()...)
+	}
+
+	problems := 0
+	for _, d := range diagnoses {
+		switch {
+		case d.problem:
+			problems++
+			logging.Errorf("%s", d.report())
+		case d.warning:
+			logging.Warnf("%s", d.report())
+		default:
+			logging.Infof("%s", d)
+		}
+	}
+	if problems > 0 {
+		return fmt.Errorf("found %d problems", problems)
+	}
+	logging.Infof("No problem found.")
+	return nil
+}
+
+func (o *doctorOptions) checkGo() diagnosis {
+	d := diagnosis{check: "go"}
+	out, err := o.output("go", "version")
+	if err != nil {
+		d.problem = true
+		d.message = fmt.Sprintf("failed to run go version: %v", err)
+		d.fix = "install Go from https://golang.org/dl/"
+		return d
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 {
+		d.problem = true
+		d.message = fmt.Sprintf("found invalid Go version: %q", strings.TrimSpace(string(out)))
+		return d
+	}
+	d.message = fields[2]
+	if err := checkGoVersion(fields[2]); err != nil {
+		d.problem = true
+		d.message = fmt.Sprintf("%s is incompatible because %v", fields[2], err)
+		d.fix = "install a newer Go from https://golang.org/dl/"
+	}
+	return d
+}
+
+func (o *doctorOptions) checkControllerGen() diagnosis {
+	d := o.checkTool("controller-gen", "--version")
+	if d.problem {
+		d.fix = "run make controller-gen to install the version pinned by the Makefile"
+		return d
+	}
+
+	b, err := ioutil.ReadFile("Makefile")
+	if err != nil {
+		return d
+	}
+	m := controllerGenVersionRegex.FindSubmatch(b)
+	if m == nil {
+		return d
+	}
+	if pinned := string(m[1]); !strings.Contains(d.message, pinned) {
+		d.warning = true
+		d.message = fmt.Sprintf("%s, but the Makefile pins %s", d.message, pinned)
+		path, _ := o.lookPath("controller-gen")
+		d.fix = fmt.Sprintf("remove %s and run make controller-gen to install %s", path, pinned)
+	}
+	return d
+}
+
+// checkTool checks that the tool is in the PATH, and reports its version.
+func (o *doctorOptions) checkTool(name string, versionArgs ...string) diagnosis {
+	d := diagnosis{check: name}
+	if _, err := o.lookPath(name); err != nil {
+		d.problem = true
+		d.message = "not found in the PATH"
+		d.fix = fmt.Sprintf("install %s, see %s", name, toolURLs[name])
+		return d
+	}
+	out, err := o.output(name, versionArgs...)
+	if err != nil {
+		d.warning = true
+		d.message = fmt.Sprintf("failed to get the version: %v", err)
+		return d
+	}
+	d.message = strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	return d
+}
+
+// toolURLs are the installation instructions of the tools.
+var toolURLs = map[string]string{
+	"controller-gen": "https://github.com/kubernetes-sigs/controller-tools",
+	"kustomize":      "https://github.com/kubernetes-sigs/kustomize/blob/master/docs/INSTALL.md",
+	"kubectl":        "https://kubernetes.io/docs/tasks/tools/install-kubectl/",
+	"docker":         "https://docs.docker.com/install/",
+}
+
+// checkProject checks that the resources of the PROJECT file match the API
+// types on disk.
+func checkProject() []diagnosis {
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return []diagnosis{{
+			check:   "PROJECT",
+			problem: true,
+			message: fmt.Sprintf("failed to read the PROJECT file: %v", err),
+		}}
+	}
+	if p.Version != project.Version2 {
+		return []diagnosis{{check: "PROJECT", message: fmt.Sprintf("version %s, resources not checked", p.Version)}}
+	}
+	return checkResources(p, typesFiles())
+}
+
+// typesFiles returns the types files of the project, by path.
+func typesFiles() map[string]bool {
+	files := map[string]bool{}
+	matches, _ := filepath.Glob(filepath.Join("api", "*", "*_types.go"))
+	for _, m := range matches {
+		files[filepath.ToSlash(m)] = true
+	}
+	return files
+}
+
+// checkResources checks that each resource of the PROJECT file which is not a
+// core type has a types file, and that each types file has a resource.
+func checkResources(p input.ProjectFile, files map[string]bool) []diagnosis {
+	diagnoses := []diagnosis{}
+	tracked := map[string]bool{}
+	for _, r := range p.Resources {
+		if scaffoldutil.IsCoreGroup(r.Group) {
+			continue
+		}
+		path := fmt.Sprintf("api/%s/%s_types.go", r.Version, strings.ToLower(r.Kind))
+		tracked[path] = true
+		if !files[path] {
+			diagnoses = append(diagnoses, diagnosis{
+				check:   "PROJECT",
+				problem: true,
+				message: fmt.Sprintf("resource %s/%s, Kind=%s has no types file %s", r.Group, r.Version, r.Kind, path),
+				fix: fmt.Sprintf("restore %s, or remove the resource from the PROJECT file if it was deleted on purpose",
+					path),
+			})
+		}
+	}
+
+	untracked := []string{}
+	for path := range files {
+		if !tracked[path] {
+			untracked = append(untracked, path)
+		}
+	}
+	sort.Strings(untracked)
+	for _, path := range untracked {
+		diagnoses = append(diagnoses, diagnosis{
+			check:   "PROJECT",
+			warning: true,
+			message: fmt.Sprintf("%s is not a resource of the PROJECT file", path),
+			fix:     "add the resource to the PROJECT file, so that the create commands know about it",
+		})
+	}
+
+	if len(diagnoses) == 0 {
+		diagnoses = append(diagnoses, diagnosis{
+			check:   "PROJECT",
+			message: fmt.Sprintf("%d resources match the API types", len(tracked)),
+		})
+	}
+	return diagnoses
+}
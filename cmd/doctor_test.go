@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+func TestCheckTool(t *testing.T) {
+	o := doctorOptions{
+		lookPath: func(file string) (string, error) {
+			if file == "kubectl" {
+				return "/usr/bin/kubectl", nil
+			}
+			return "", errors.New("not found")
+		},
+		output: func(name string, args ...string) ([]byte, error) {
+			return []byte("Client Version: v1.16.2\n"), nil
+		},
+	}
+
+	if d := o.checkTool("kubectl", "version"); d.problem || d.warning || d.message != "Client Version: v1.16.2" {
+		t.Errorf("expected kubectl to be found with its version, got %+v", d)
+	}
+	if d := o.checkTool("docker", "version"); !d.problem || d.fix == "" {
+		t.Errorf("expected a missing docker to be a problem with a fix, got %+v", d)
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	p := input.ProjectFile{
+		Resources: []input.Resource{
+			{Group: "crew", Version: "v1", Kind: "Captain"},
+			{Group: "crew", Version: "v1", Kind: "Admiral"},
+			{Group: "core", Version: "v1", Kind: "Namespace"},
+		},
+	}
+
+	tests := []struct {
+		files              map[string]bool
+		problems, warnings int
+	}{
+		{
+			files: map[string]bool{"api/v1/captain_types.go": true, "api/v1/admiral_types.go": true},
+		},
+		{
+			files:    map[string]bool{"api/v1/captain_types.go": true},
+			problems: 1,
+		},
+		{
+			files: map[string]bool{"api/v1/captain_types.go": true, "api/v1/admiral_types.go": true,
+				"api/v1/sailor_types.go": true},
+			warnings: 1,
+		},
+	}
+	for _, test := range tests {
+		problems, warnings := 0, 0
+		for _, d := range checkResources(p, test.files) {
+			if d.problem {
+				problems++
+			}
+			if d.warning {
+				warnings++
+			}
+		}
+		if problems != test.problems || warnings != test.warnings {
+			t.Errorf("files %v: expected %d problems and %d warnings, got %d and %d",
+				test.files, test.problems, test.warnings, problems, warnings)
+		}
+	}
+}
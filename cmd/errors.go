@@ -0,0 +1,68 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// The exit codes of kubebuilder, so that the scripts wrapping it can tell
+// why a command failed
+const (
+	exitCodeError                 = 1
+	exitCodeUnknownPattern        = 2
+	exitCodeProjectNotInitialized = 3
+	exitCodeAlreadyExists         = 4
+	exitCodeInvalidInput          = 5
+)
+
+// exitCode returns the exit code of the error
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, scaffold.ErrUnknownPattern):
+		return exitCodeUnknownPattern
+	case errors.Is(err, scaffold.ErrProjectNotInitialized):
+		return exitCodeProjectNotInitialized
+	case errors.Is(err, scaffold.ErrProjectExists), errors.Is(err, scaffold.ErrResourceExists):
+		return exitCodeAlreadyExists
+	case errors.Is(err, scaffold.ErrInvalidInput):
+		return exitCodeInvalidInput
+	default:
+		return exitCodeError
+	}
+}
+
+// invalidInput wraps err as an ErrInvalidInput error
+func invalidInput(err error) error {
+	return fmt.Errorf("%w: %v", scaffold.ErrInvalidInput, err)
+}
+
+// invalidInputf returns an ErrInvalidInput error with the formatted message
+func invalidInputf(format string, a ...interface{}) error {
+	return invalidInput(fmt.Errorf(format, a...))
+}
+
+// fatal prints the error and exits with its exit code
+func fatal(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
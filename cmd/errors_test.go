@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("error running make"), exitCodeError},
+		{scaffold.ErrProjectNotInitialized, exitCodeProjectNotInitialized},
+		{fmt.Errorf("failed to initialize project: %w", scaffold.ErrProjectExists), exitCodeAlreadyExists},
+		{scaffold.ErrResourceExists, exitCodeAlreadyExists},
+		{fmt.Errorf("%w %q", scaffold.ErrUnknownPattern, "gitops"), exitCodeUnknownPattern},
+		{invalidInputf("--make cannot be enabled in --offline mode"), exitCodeInvalidInput},
+	}
+	for _, tt := range tests {
+		if code := exitCode(tt.err); code != tt.code {
+			t.Errorf("expected exit code %d for %q, got %d", tt.code, tt.err, code)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// promptOptions walks the user through the init options. The values of the
+// flags are used as the defaults of the prompts.
+func (o *projectOptions) promptOptions(prompter util.Prompter) {
+	o.project.Version = prompter.Ask("Project version", o.project.Version, func(v string) error {
+		if v != project.Version1 && v != project.Version2 {
+			return fmt.Errorf("should be one of %s, %s", project.Version1, project.Version2)
+		}
+		return nil
+	})
+
+	if !o.noDomain {
+		o.project.Domain = prompter.Ask("Domain of the API groups", o.project.Domain, func(domain string) error {
+			if errs := resource.IsDNS1123Subdomain(domain); len(errs) > 0 {
+				return fmt.Errorf("%s", strings.Join(errs, ", "))
+			}
+			return nil
+		})
+	}
+
+	o.project.Repo = prompter.Ask("Go module of the project (empty to detect it)", o.project.Repo,
+		func(repo string) error {
+			if repo == "" {
+				return nil
+			}
+			return validateRepo(repo)
+		})
+
+	o.boilerplate.License = prompter.Ask("License (apache2, none)", o.boilerplate.License,
+		func(license string) error {
+			if license != "apache2" && license != "none" {
+				return fmt.Errorf("should be one of apache2, none")
+			}
+			return nil
+		})
+	if o.boilerplate.License != "none" {
+		o.boilerplate.Owner = prompter.Ask("Copyright owner", o.boilerplate.Owner, nil)
+	}
+
+	if o.project.Version != project.Version2 {
+		return
+	}
+
+	o.goVersion = prompter.Ask("Go version of go.mod", o.goVersion, scaffold.ValidateGoVersion)
+
+	o.certSource = prompter.Ask("Webhook certificate source (cert-manager, webhook-bootstrap, manual, generated)",
+		o.certSource, func(source string) error {
+			return webhook.CertSource(source).Validate()
+		})
+	if o.certSource == string(webhook.CertSourceCertManager) {
+		o.certIssuer = prompter.Ask("Existing cert-manager Issuer (empty to scaffold a self-signed one)",
+			o.certIssuer, nil)
+	}
+
+	o.namespacedManager = prompter.Confirm("Restrict the manager to its namespace", o.namespacedManager)
+	o.secureDefaults = prompter.Confirm("Harden the manager security context and network policies",
+		o.secureDefaults)
+	o.leaderElection = prompter.Confirm("Enable leader election for the manager", o.leaderElection)
+	o.envOverlays = prompter.Confirm("Scaffold dev, staging and prod overlays", o.envOverlays)
+	o.metricsBindAddress = prompter.Ask("Bind address of the manager metrics", o.metricsBindAddress,
+		func(address string) error {
+			_, err := managerv2.BindAddressPort(address)
+			return err
+		})
+	healthProbePort := prompter.Ask("Port of the manager health probes (0 to not scaffold them)",
+		strconv.Itoa(o.healthProbePort), func(port string) error {
+			p, err := strconv.Atoi(port)
+			if err != nil {
+				return fmt.Errorf("should be a port number")
+			}
+			if p == 0 {
+				return nil
+			}
+			return managerv2.ValidatePort(p)
+		})
+	o.healthProbePort, _ = strconv.Atoi(healthProbePort)
+	o.grafana = prompter.Confirm("Scaffold Grafana dashboards and Prometheus rules", o.grafana)
+	o.profiling = prompter.Confirm("Serve pprof endpoints and export traces from the manager", o.profiling)
+	o.envtestK8sVersion = prompter.Ask(
+		"Kubernetes version of the envtest binaries to download (empty to use the installed ones)",
+		o.envtestK8sVersion, nil)
+	o.codeGenerators = prompter.Confirm("Run conversion-gen and defaulter-gen on the APIs", o.codeGenerators)
+	o.pinnedTools = prompter.Confirm("Install the Makefile tools from pinned versions", o.pinnedTools)
+	o.apiDocs = prompter.Confirm("Generate the API reference docs with crd-ref-docs", o.apiDocs)
+	o.e2e = prompter.Confirm("Scaffold an e2e test suite running on kind", o.e2e)
+	o.devTooling = prompter.Ask("Tool of the local development loop (tilt, skaffold, none)",
+		o.devTooling, func(tooling string) error {
+			return scaffoldv2.DevTooling(tooling).Validate()
+		})
+	o.olm = prompter.Confirm("Scaffold an OLM bundle", o.olm)
+	o.multiArch = prompter.Confirm("Build a multi-arch manager image with buildx", o.multiArch)
+	o.baseImage = prompter.Ask("Base image of the manager image (distroless, scratch, ubi8)",
+		o.baseImage, func(image string) error {
+			return managerv2.BaseImage(image).Validate()
+		})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+)
+
+func TestPromptOptions(t *testing.T) {
+	o := &projectOptions{}
+	cmd := &cobra.Command{}
+	o.bindCmdlineFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--license", "apache2"}); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// the answers end after the namespaced manager, the next questions
+	// select their defaults
+	answers := strings.Join([]string{
+		"",                    // project version
+		"example.org",         // domain
+		"",                    // repo
+		"",                    // license
+		"The Frigate authors", // owner
+		"1.15",                // go version
+		"",                    // webhook certificate source
+		"",                    // cert-manager issuer
+		"yes",                 // namespaced manager
+	}, "\n")
+	prompter := util.NewPrompter(strings.NewReader(answers), &strings.Builder{})
+	o.promptOptions(prompter)
+
+	if o.project.Version != "2" || o.project.Domain != "example.org" || o.boilerplate.License != "apache2" ||
+		o.boilerplate.Owner != "The Frigate authors" || o.goVersion != "1.15" {
+		t.Errorf("unexpected project options: %+v, %+v, go version %s", o.project, o.boilerplate, o.goVersion)
+	}
+	if !o.namespacedManager || !o.leaderElection || o.profiling {
+		t.Errorf("unexpected manager options: namespaced %v, leader election %v, profiling %v",
+			o.namespacedManager, o.leaderElection, o.profiling)
+	}
+
+	prompted := 0
+	for _, decision := range prompter.Decisions() {
+		if decision.Prompted {
+			prompted++
+		}
+	}
+	if prompted != 4 {
+		t.Errorf("expected 4 prompted answers, got %d in %v", prompted, prompter.Decisions())
+	}
+}
@@ -18,7 +18,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -30,8 +29,13 @@ import (
 	flag "github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 func newInitProjectCmd() *cobra.Command {
@@ -56,9 +60,14 @@ project will prompt the user to run 'dep ensure' after writing the project files
 `,
 		Example: `# Scaffold a project using the apache2 license with "The Kubernetes authors" as owners
 kubebuilder init --domain example.org --license apache2 --owner "The Kubernetes authors"
+
+# Scaffold a project in a new my-operator directory
+kubebuilder init --domain example.org --output-dir my-operator
 `,
 		Run: func(cmd *cobra.Command, args []string) {
-			o.initializeProject()
+			if err := o.initializeProject(); err != nil {
+				fatal(err)
+			}
 		},
 	}
 
@@ -71,11 +80,43 @@ type projectOptions struct {
 
 	// flags
 	fetchDeps          bool
+	fetchDepsFlag      *flag.Flag
 	skipGoVersionCheck bool
+	interactive        bool
+	grafana            bool
+	e2e                bool
+	olm                bool
+	multiArch          bool
+	baseImage          string
+	envtestK8sVersion  string
+	namespacedManager  bool
+	leaderElection     bool
+	healthProbePort    int
+	metricsBindAddress string
+	certSource         string
+	certIssuer         string
+	secureDefaults     bool
+	codeGenerators     bool
+	pinnedTools        bool
+	apiDocs            bool
+	noDomain           bool
+	devTooling         string
+	profiling          bool
+	envOverlays        bool
+	goVersion          string
+	webhookPort        int
+	webhookCertDir     string
+	outputDir          string
+	domainFlag         *flag.Flag
+	goVersionFlag      *flag.Flag
 
 	boilerplate project.Boilerplate
 	project     project.Project
 
+	// prompter asks the questions of --interactive and whether to run dep
+	// ensure, it prompts on the standard input and output when nil
+	prompter util.Prompter
+
 	// deprecated flags
 	dep     bool
 	depFlag *flag.Flag
@@ -88,9 +129,14 @@ type projectOptions struct {
 func (o *projectOptions) bindCmdlineFlags(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check", false, "if specified, skip checking the Go version")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "if specified, the directory to scaffold the project in, "+
+		"which is created if needed. The project name is the name of the directory")
+	cmd.Flags().BoolVar(&o.interactive, "interactive", false, "if specified, prompt for the project options, "+
+		"using the values of the other flags as defaults")
 
 	// dependency args
 	cmd.Flags().BoolVar(&o.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded")
+	o.fetchDepsFlag = cmd.Flag("fetch-deps")
 
 	// deprecated dependency args
 	cmd.Flags().BoolVar(&o.dep, "dep", true, "if specified, determines whether dep will be used.")
@@ -98,10 +144,10 @@ func (o *projectOptions) bindCmdlineFlags(cmd *cobra.Command) {
 	cmd.Flags().StringArrayVar(&o.depArgs, "depArgs", nil, "Additional arguments for dep")
 
 	if err := cmd.Flags().MarkDeprecated("dep", "use the fetch-deps flag instead"); err != nil {
-		log.Printf("error to mark dep flag as deprecated: %v", err)
+		logging.Warnf("error to mark dep flag as deprecated: %v", err)
 	}
 	if err := cmd.Flags().MarkDeprecated("depArgs", "will be removed with version 1 scaffolding"); err != nil {
-		log.Printf("error to mark dep flag as deprecated: %v", err)
+		logging.Warnf("error to mark dep flag as deprecated: %v", err)
 	}
 
 	// boilerplate args
@@ -111,14 +157,117 @@ func (o *projectOptions) bindCmdlineFlags(cmd *cobra.Command) {
 
 	// project args
 	cmd.Flags().StringVar(&o.project.Repo, "repo", "", "name to use for go module, e.g. github.com/user/repo.  "+
-		"defaults to the go package of the current working directory.")
+		"defaults to the go package of the current working directory, or to the path derived from the remote "+
+		"of its git or mercurial repository.")
 	cmd.Flags().StringVar(&o.project.Domain, "domain", "my.domain", "domain for groups")
+	o.domainFlag = cmd.Flag("domain")
+	cmd.Flags().BoolVar(&o.noDomain, "no-domain", false, "if specified, the project has no domain and the groups "+
+		"of its APIs must be fully qualified, e.g. ship.example.com (project version 2 only)")
 	cmd.Flags().StringVar(&o.project.Version, "project-version", project.Version2, "project version")
+	cmd.Flags().StringVar(&o.goVersion, "go-version", scaffold.DefaultGoVersion, "the Go release of the go "+
+		"directive of go.mod and of the builder image, which determines the pinned controller-runtime version. "+
+		"The local Go toolchain must support it. Defaults to "+scaffoldv2.OpenTelemetryMinGoVersion+
+		" with --with-profiling (project version 2 only)")
+	o.goVersionFlag = cmd.Flag("go-version")
+
+	// manager args
+	cmd.Flags().BoolVar(&o.namespacedManager, "namespaced-manager", false, "if specified, restrict the manager "+
+		"and its RBAC permissions to the namespace it is deployed in (project version 2 only)")
+	cmd.Flags().BoolVar(&o.leaderElection, "leader-election", true, "if true, the deployed manager runs "+
+		"with leader election enabled (project version 2 only)")
+	cmd.Flags().IntVar(&o.healthProbePort, "health-probe-port", 0, "if specified, the port of the health and "+
+		"readiness probes of the manager, which are scaffolded in main.go and in the manager Deployment "+
+		"(project version 2 only)")
+	cmd.Flags().StringVar(&o.metricsBindAddress, "metrics-bind-address", managerv2.DefaultMetricsBindAddress,
+		"the address the metrics endpoint of the manager binds to, the auth proxy forwards to its port "+
+			"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.secureDefaults, "secure-defaults", false, "if specified, harden the security context "+
+		"of the manager, restrict its traffic with network policies and scaffold a config/dev overlay relaxing "+
+		"both for development (project version 2 only)")
+	cmd.Flags().StringVar(&o.devTooling, "dev-tooling", string(scaffoldv2.DevToolingNone), "the tool of the local "+
+		"development loop, which rebuilds and redeploys the manager when the code changes. May be one of "+
+		"tilt,skaffold,none (project version 2 only)")
+
+	cmd.Flags().BoolVar(&o.profiling, "with-profiling", false, "if specified, serve the pprof endpoints of the "+
+		"deployed manager behind a Service and a pprof-reader ClusterRole, and set up an OpenTelemetry trace "+
+		"exporter enabled by OTEL_EXPORTER_OTLP_ENDPOINT (project version 2 only)")
+
+	cmd.Flags().BoolVar(&o.envOverlays, "env-overlays", false, "if specified, scaffold the config/overlays/dev, "+
+		"staging and prod overlays setting the replicas and resource limits of the manager, with deploy-dev, "+
+		"deploy-staging and deploy-prod Makefile targets deploying their own image (project version 2 only)")
+
+	// webhook args
+	cmd.Flags().StringVar(&o.certSource, "cert-source", string(webhook.CertSourceCertManager),
+		"where the webhook server certificates come from. May be one of cert-manager,webhook-bootstrap,manual,generated "+
+			"(project version 2 only)")
+	cmd.Flags().StringVar(&o.certIssuer, "cert-issuer", "", "name of an existing cert-manager Issuer to use "+
+		"instead of scaffolding a self-signed one (cert-manager certificate source only)")
+	cmd.Flags().IntVar(&o.webhookPort, "webhook-port", webhook.DefaultServerPort, "the port the webhook server "+
+		"of the manager listens on, used by main.go, the webhook Service and the manager patches "+
+		"(project version 2 only)")
+	cmd.Flags().StringVar(&o.webhookCertDir, "webhook-cert-dir", webhook.DefaultCertDir, "the directory the "+
+		"webhook server of the manager reads its certificates from, used by main.go and the manager patches "+
+		"(project version 2 only)")
+
+	// monitoring args
+	cmd.Flags().BoolVar(&o.grafana, "with-grafana", false, "if specified, scaffold Grafana dashboards and "+
+		"Prometheus alerting rules for the controller-runtime metrics (project version 2 only)")
+
+	// test args
+	cmd.Flags().StringVar(&o.envtestK8sVersion, "envtest-k8s-version", "", "if specified, the Kubernetes version "+
+		"of the envtest binaries downloaded by the setup-envtest Makefile target for the tests, e.g. 1.16.4 "+
+		"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.codeGenerators, "with-code-generators", false, "if specified, run conversion-gen and "+
+		"defaulter-gen on the API packages in the generate Makefile target, for APIs with internal types "+
+		"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.pinnedTools, "pinned-tools", false, "if specified, install the tools run by the "+
+		"Makefile from the versions pinned in a tools module under hack/tools, and verify the checksums of "+
+		"the envtest binaries. These checksums are not shipped, make envtest-checksum records the one of "+
+		"the binaries it downloads (trust on first use), which must be checked and committed "+
+		"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.apiDocs, "with-api-docs", false, "if specified, scaffold a docsgen Makefile target "+
+		"generating the API reference docs with crd-ref-docs, and its configuration under docs "+
+		"(project version 2 only)")
+	cmd.Flags().BoolVar(&o.e2e, "with-e2e", false, "if specified, scaffold an e2e test suite under test/e2e "+
+		"which deploys the project on a kind cluster (project version 2 only)")
+
+	// packaging args
+	cmd.Flags().BoolVar(&o.olm, "with-olm", false, "if specified, scaffold an Operator Lifecycle Manager bundle "+
+		"under bundle/ and the bundle Makefile targets (project version 2 only)")
+	cmd.Flags().BoolVar(&o.multiArch, "multi-arch", false, "if specified, scaffold a Dockerfile which cross-compiles "+
+		"the manager and a docker-buildx Makefile target to build a multi-arch image (project version 2 only)")
+	cmd.Flags().StringVar(&o.baseImage, "base-image", string(managerv2.BaseImageDistroless),
+		fmt.Sprintf("base image of the manager image, one of %s, %s, %s (project version 2 only)",
+			managerv2.BaseImageDistroless, managerv2.BaseImageScratch, managerv2.BaseImageUBI8))
 }
 
-func (o *projectOptions) initializeProject() {
+func (o *projectOptions) initializeProject() error {
+	if o.outputDir != "" {
+		// check the project name before creating the directory
+		abs, err := filepath.Abs(o.outputDir)
+		if err != nil {
+			return fmt.Errorf("error resolving the output directory %s: %v", o.outputDir, err)
+		}
+		if err := util.IsValidName(strings.ToLower(filepath.Base(abs))); err != nil {
+			return invalidInputf("project name (%v) is invalid: (%v)", filepath.Base(abs), err)
+		}
+		if err := os.MkdirAll(o.outputDir, 0755); err != nil {
+			return fmt.Errorf("error creating the output directory %s: %v", o.outputDir, err)
+		}
+		if err := os.Chdir(o.outputDir); err != nil {
+			return fmt.Errorf("error changing to the output directory %s: %v", o.outputDir, err)
+		}
+	}
+
+	if o.prompter == nil {
+		o.prompter = util.NewPrompter(os.Stdin, os.Stdout)
+	}
+	if o.interactive {
+		o.promptOptions(o.prompter)
+	}
+
 	if err := o.validate(); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	if o.project.Version == project.Version1 {
@@ -126,20 +275,40 @@ func (o *projectOptions) initializeProject() {
 	}
 
 	if err := o.scaffolder.Scaffold(); err != nil {
-		log.Fatalf("error scaffolding project: %v", err)
+		return fmt.Errorf("error scaffolding project: %v", err)
 	}
 
 	if err := o.postScaffold(); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	fmt.Printf("Next: Define a resource with:\n" +
-		"$ kubebuilder create api\n")
+	logging.Infof("Next: Define a resource with:\n" +
+		"$ kubebuilder create api")
+	return nil
 }
 
 func (o *projectOptions) validate() error {
+	if offline {
+		if o.fetchDepsFlag.Changed && o.fetchDeps {
+			return invalidInputf("--fetch-deps cannot be enabled in --offline mode")
+		}
+		if o.depFlag.Changed && o.dep {
+			return invalidInputf("--dep cannot be enabled in --offline mode")
+		}
+		o.fetchDeps = false
+	}
+
+	// the OpenTelemetry modules need a newer Go release than the default one
+	if o.profiling && o.project.Version == project.Version2 && !o.goVersionFlag.Changed {
+		o.goVersion = scaffoldv2.OpenTelemetryMinGoVersion
+	}
+
 	if !o.skipGoVersionCheck {
-		if err := validateGoVersion(); err != nil {
+		minGoVersion := ""
+		if o.project.Version == project.Version2 {
+			minGoVersion = o.goVersion
+		}
+		if err := validateGoVersion(minGoVersion); err != nil {
 			return err
 		}
 	}
@@ -154,7 +323,16 @@ func (o *projectOptions) validate() error {
 	// it will be used to create the namespace
 	projectName := filepath.Base(dir)
 	if err := util.IsValidName(strings.ToLower(projectName)); err != nil {
-		return fmt.Errorf("project name (%v) is invalid: (%v)", projectName, err)
+		return invalidInputf("project name (%v) is invalid: (%v)", projectName, err)
+	}
+
+	if o.noDomain {
+		if o.domainFlag.Changed {
+			return invalidInputf("--domain and --no-domain cannot be used together")
+		}
+		o.project.Domain = ""
+	} else if errs := resource.IsDNS1123Subdomain(o.project.Domain); len(errs) > 0 {
+		return invalidInputf("domain %q is invalid: (%s)", o.project.Domain, strings.Join(errs, ", "))
 	}
 
 	if o.project.Repo == "" {
@@ -164,9 +342,72 @@ func (o *projectOptions) validate() error {
 		}
 		o.project.Repo = repoPath
 	}
+	if err := validateRepo(o.project.Repo); err != nil {
+		return invalidInput(err)
+	}
 
 	switch o.project.Version {
 	case project.Version1:
+		if o.grafana {
+			return invalidInputf("--with-grafana is only supported for project version %s", project.Version2)
+		}
+		if o.e2e {
+			return invalidInputf("--with-e2e is only supported for project version %s", project.Version2)
+		}
+		if o.olm {
+			return invalidInputf("--with-olm is only supported for project version %s", project.Version2)
+		}
+		if o.multiArch {
+			return invalidInputf("--multi-arch is only supported for project version %s", project.Version2)
+		}
+		if o.baseImage != string(managerv2.BaseImageDistroless) {
+			return invalidInputf("--base-image is only supported for project version %s", project.Version2)
+		}
+		if o.envtestK8sVersion != "" {
+			return invalidInputf("--envtest-k8s-version is only supported for project version %s", project.Version2)
+		}
+		if o.namespacedManager {
+			return invalidInputf("--namespaced-manager is only supported for project version %s", project.Version2)
+		}
+		if !o.leaderElection || o.healthProbePort != 0 ||
+			o.metricsBindAddress != managerv2.DefaultMetricsBindAddress {
+			return invalidInputf("--leader-election, --health-probe-port and --metrics-bind-address are only "+
+				"supported for project version %s", project.Version2)
+		}
+		if o.codeGenerators {
+			return invalidInputf("--with-code-generators is only supported for project version %s", project.Version2)
+		}
+		if o.pinnedTools {
+			return invalidInputf("--pinned-tools is only supported for project version %s", project.Version2)
+		}
+		if o.apiDocs {
+			return invalidInputf("--with-api-docs is only supported for project version %s", project.Version2)
+		}
+		if o.noDomain {
+			return invalidInputf("--no-domain is only supported for project version %s", project.Version2)
+		}
+		if o.devTooling != string(scaffoldv2.DevToolingNone) {
+			return invalidInputf("--dev-tooling is only supported for project version %s", project.Version2)
+		}
+		if o.profiling {
+			return invalidInputf("--with-profiling is only supported for project version %s", project.Version2)
+		}
+		if o.secureDefaults {
+			return invalidInputf("--secure-defaults is only supported for project version %s", project.Version2)
+		}
+		if o.envOverlays {
+			return invalidInputf("--env-overlays is only supported for project version %s", project.Version2)
+		}
+		if o.goVersion != scaffold.DefaultGoVersion {
+			return invalidInputf("--go-version is only supported for project version %s", project.Version2)
+		}
+		if o.certSource != string(webhook.CertSourceCertManager) || o.certIssuer != "" {
+			return invalidInputf("--cert-source and --cert-issuer are only supported for project version %s", project.Version2)
+		}
+		if o.webhookPort != webhook.DefaultServerPort || o.webhookCertDir != webhook.DefaultCertDir {
+			return invalidInputf("--webhook-port and --webhook-cert-dir are only supported for project version %s",
+				project.Version2)
+		}
 		var defEnsure *bool
 		if o.depFlag.Changed {
 			defEnsure = &o.dep
@@ -177,36 +418,64 @@ func (o *projectOptions) validate() error {
 
 			DepArgs:          o.depArgs,
 			DefinitelyEnsure: defEnsure,
+			Executor:         commandExecutor(),
+			Prompter:         o.prompter,
 		}
 	case project.Version2:
 		o.scaffolder = &scaffold.V2Project{
-			Project:     o.project,
-			Boilerplate: o.boilerplate,
+			Project:           o.project,
+			Boilerplate:       o.boilerplate,
+			Grafana:           o.grafana,
+			E2E:               o.e2e,
+			OLM:               o.olm,
+			MultiArch:         o.multiArch,
+			BaseImage:         managerv2.BaseImage(o.baseImage),
+			EnvtestK8sVersion: o.envtestK8sVersion,
+			NamespacedManager: o.namespacedManager,
+
+			DisableLeaderElection: !o.leaderElection,
+			HealthProbePort:       o.healthProbePort,
+			MetricsBindAddress:    o.metricsBindAddress,
+			CertSource:            webhook.CertSource(o.certSource),
+			CertIssuer:            o.certIssuer,
+			SecureDefaults:        o.secureDefaults,
+			CodeGenerators:        o.codeGenerators,
+			PinnedTools:           o.pinnedTools,
+			APIDocs:               o.apiDocs,
+			DevTooling:            scaffoldv2.DevTooling(o.devTooling),
+			Profiling:             o.profiling,
+			EnvOverlays:           o.envOverlays,
+			GoVersion:             o.goVersion,
+			WebhookPort:           o.webhookPort,
+			WebhookCertDir:        o.webhookCertDir,
+			Executor:              commandExecutor(),
 		}
 	default:
-		return fmt.Errorf("unknown project version %v", o.project.Version)
+		return invalidInputf("unknown project version %v", o.project.Version)
 	}
 
 	if err := o.scaffolder.Validate(); err != nil {
-		return err
+		return invalidInput(err)
 	}
 
 	if util.ProjectExist() {
-		return fmt.Errorf("failed to initialize project because project is already initialized")
+		return fmt.Errorf("failed to initialize project: %w", scaffold.ErrProjectExists)
 	}
 
 	return nil
 }
 
-func validateGoVersion() error {
-	err := fetchAndCheckGoVersion()
+// validateGoVersion checks the version of the local Go toolchain, which must
+// also support the go directive of the project if minGoVersion is set.
+func validateGoVersion(minGoVersion string) error {
+	err := fetchAndCheckGoVersion(minGoVersion)
 	if err != nil {
 		return fmt.Errorf("%s. You can skip this check using the --skip-go-version-check flag", err)
 	}
 	return nil
 }
 
-func fetchAndCheckGoVersion() error {
+func fetchAndCheckGoVersion(minGoVersion string) error {
 	cmd := exec.Command("go", "version")
 	out, err := cmd.Output()
 	if err != nil {
@@ -221,6 +490,11 @@ func fetchAndCheckGoVersion() error {
 	if err := checkGoVersion(goVer); err != nil {
 		return fmt.Errorf("go version '%s' is incompatible because '%s'", goVer, err)
 	}
+	if minGoVersion != "" {
+		if err := scaffold.CheckGoToolchain(goVer, minGoVersion); err != nil {
+			return fmt.Errorf("go version '%s' is incompatible because '%s'", goVer, err)
+		}
+	}
 	return nil
 }
 
@@ -252,7 +526,8 @@ func (o *projectOptions) postScaffold() error {
 	// preserve old "ask if not explicitly set" behavior for the `--dep` flag
 	// (asking is handled by the v1 scaffolder)
 	if (o.depFlag.Changed && !o.dep) || !o.fetchDeps {
-		fmt.Println("Skipping fetching dependencies.")
+		logging.Infof("Skipping fetching dependencies.")
+		printSkippedCommands(append(o.scaffolder.DependencyCommands(), "make")...)
 		return nil
 	}
 
@@ -265,10 +540,5 @@ func (o *projectOptions) postScaffold() error {
 		return nil
 	}
 
-	fmt.Println("Running make...")
-	c := exec.Command("make") // #nosec
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	return c.Run()
+	return runMake()
 }
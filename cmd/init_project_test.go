@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newProjectOptions(t *testing.T, args ...string) *projectOptions {
+	o := &projectOptions{}
+	cmd := &cobra.Command{}
+	o.bindCmdlineFlags(cmd)
+	if err := cmd.Flags().Parse(append([]string{"--skip-go-version-check", "--repo", "example.com/proj"},
+		args...)); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	return o
+}
+
+func TestInitProjectExitCode(t *testing.T) {
+	defer chdirTemp(t)()
+
+	defer func(o bool) { offline = o }(offline)
+	offline = true
+	if code := exitCode(newProjectOptions(t, "--fetch-deps").validate()); code != exitCodeInvalidInput {
+		t.Errorf("expected exit code %d for --offline --fetch-deps, got %d", exitCodeInvalidInput, code)
+	}
+	offline = false
+
+	tests := [][]string{
+		{"--domain", "example.org", "--no-domain"},
+		{"--domain", "Example_org"},
+		{"--project-version", "1", "--with-e2e"},
+		{"--project-version", "3"},
+		{"--cert-source", "vault"},
+	}
+	for _, args := range tests {
+		if code := exitCode(newProjectOptions(t, args...).validate()); code != exitCodeInvalidInput {
+			t.Errorf("expected exit code %d for %v, got %d", exitCodeInvalidInput, args, code)
+		}
+	}
+
+	if err := ioutil.WriteFile("PROJECT", []byte("version: \"2\"\n"), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if code := exitCode(newProjectOptions(t).validate()); code != exitCodeAlreadyExists {
+		t.Errorf("expected exit code %d for an initialized project, got %d", exitCodeAlreadyExists, code)
+	}
+}
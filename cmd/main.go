@@ -17,111 +17,66 @@ limitations under the License.
 package main
 
 import (
-	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/tools/go/packages"
+	flag "github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/cmd/version"
+	"sigs.k8s.io/kubebuilder/pkg/executor"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 )
 
-const (
-	NoticeColor = "\033[1;36m%s\033[0m"
-)
+// offline is set by the --offline flag, which skips every step that requires
+// network access, e.g. fetching dependencies, running make and the hooks.
+var offline bool
 
-// module and goMod arg just enough of the output of `go mod edit -json` for our purposes
-type goMod struct {
-	Module module
-}
-type module struct {
-	Path string
-}
+// skipPostCommands is set by the --skip-post-commands flag, which skips every
+// external command run after scaffolding, e.g. make, go get and the hooks.
+var skipPostCommands bool
 
-// findGoModulePath finds the path of the current module, if present.
-func findGoModulePath(forceModules bool) (string, error) {
-	cmd := exec.Command("go", "mod", "edit", "-json")
-	cmd.Env = append(cmd.Env, os.Environ()...)
-	if forceModules {
-		cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
-	}
-	out, err := cmd.Output()
-	if err != nil {
-		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
-			err = fmt.Errorf("%s", string(exitErr.Stderr))
-		}
-		return "", err
-	}
-	mod := goMod{}
-	if err := json.Unmarshal(out, &mod); err != nil {
-		return "", err
-	}
-	return mod.Module.Path, nil
-}
+// projectDir is set by the --project-dir flag, the directory of the project
+// to run the command in.
+var projectDir string
 
-// findCurrentRepo attempts to determine the current repository
-// though a combination of go/packages and `go mod` commands/tricks.
-func findCurrentRepo() (string, error) {
-	// easiest case: project file already exists
-	projFile, err := scaffold.LoadProjectFile("PROJECT")
-	if err == nil {
-		return projFile.Repo, nil
-	}
+// verbose and quiet are set by the --verbose and --quiet flags, which change
+// how much of the progress of the commands is printed.
+var verbose, quiet bool
 
-	// next easy case: existing go module
-	path, err := findGoModulePath(false)
-	if err == nil {
-		return path, nil
-	}
+// noColor is set by the --no-color flag, which disables the colors of the
+// output. Colors are only enabled by default when the output is a terminal.
+var noColor bool
 
-	// next, check if we've got a package in the current directory
-	pkgCfg := &packages.Config{
-		Mode: packages.NeedName, // name gives us path as well
+func main() {
+	if err := applyGlobalFlags(os.Args[1:]); err != nil {
+		log.Fatal(err)
 	}
-	pkgs, err := packages.Load(pkgCfg, ".")
-	// NB(directxman12): when go modules are off and we're outside GOPATH and
-	// we don't otherwise have a good guess packages.Load will fabricate a path
-	// that consists of `_/absolute/path/to/current/directory`.  We shouldn't
-	// use that when it happens.
-	if err == nil && len(pkgs) > 0 && len(pkgs[0].PkgPath) > 0 && pkgs[0].PkgPath[0] != '_' {
-		return pkgs[0].PkgPath, nil
+	if err := registerPatterns(); err != nil {
+		log.Fatal(err)
 	}
 
-	// otherwise, try to get `go mod init` to guess for us -- it's pretty good
-	cmd := exec.Command("go", "mod", "init")
-	cmd.Env = append(cmd.Env, os.Environ()...)
-	cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
-	if _, err := cmd.Output(); err != nil {
-		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
-			err = fmt.Errorf("%s", string(exitErr.Stderr))
-		}
-		// give up, let the user figure it out
-		return "", fmt.Errorf("could not determine repository path from module data, package data, or by initializing a module: %v", err)
-	}
-	defer os.Remove("go.mod") // clean up after ourselves
-	return findGoModulePath(true)
-}
-
-func main() {
 	rootCmd := defaultCommand()
 
 	rootCmd.AddCommand(
 		newInitProjectCmd(),
 		newCreateCmd(),
+		newApplyCmd(),
+		newDoctorCmd(),
 		version.NewVersionCmd(),
 	)
 
 	foundProject, projectVersion := getProjectVersion()
-	if foundProject && projectVersion == project.Version1 {
+	isV1 := foundProject && projectVersion == project.Version1
+	rootCmd.AddCommand(newAlphaCommand(isV1))
+	if isV1 {
 		printV1DeprecationWarning()
 
 		rootCmd.AddCommand(
-			newAlphaCommand(),
 			newVendorUpdateCmd(),
 		)
 	}
@@ -132,7 +87,7 @@ func main() {
 }
 
 func defaultCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "kubebuilder",
 		Short: "Development kit for building Kubernetes extensions and tools.",
 		Long: `
@@ -156,6 +111,14 @@ scaffold a Controller for an existing Resource, select "n" for Resource. To only
 the schema for a Resource without writing a Controller, select "n" for Controller.
 
 After the scaffold is written, api will run make on the project.
+
+Exit codes:
+
+  1  the command failed
+  2  the pattern of create api is unknown
+  3  the command must be run in a project, and no PROJECT file was found
+  4  the project or the API resource already exists
+  5  the flags of create api are invalid or conflict with each other
 `,
 		Example: `
 	# Initialize your project
@@ -183,6 +146,99 @@ After the scaffold is written, api will run make on the project.
 			}
 		},
 	}
+
+	cmd.PersistentFlags().BoolVar(&offline, "offline", false,
+		"if specified, skip every step that may require network access (fetching dependencies, running make "+
+			"and the hooks of the project) and print the commands to run later instead")
+	cmd.PersistentFlags().BoolVar(&skipPostCommands, "skip-post-commands", false,
+		"if specified, do not run any external command after scaffolding (make, fetching dependencies, "+
+			"hooks) and print them instead")
+	cmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
+		"if specified, print the details of what the command does, e.g. the commands run and the files skipped")
+	cmd.PersistentFlags().BoolVar(&quiet, "quiet", false,
+		"if specified, only print the warnings and errors")
+	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false,
+		"if specified, do not color the output, which is only colored when printed to a terminal")
+	cmd.PersistentFlags().StringVar(&projectDir, "project-dir", "",
+		"if specified, the directory of the project to run the command in, instead of the current directory. "+
+			"The relative paths passed to the other flags are relative to it")
+
+	return cmd
+}
+
+// applyGlobalFlags sets the output verbosity and changes the working
+// directory to the one passed with the --project-dir flag, if any. The flags
+// are parsed before the commands are built, since which commands are
+// available depends on the PROJECT file, and every scaffolder and the make
+// invocations use paths relative to the project root.
+func applyGlobalFlags(args []string) error {
+	fs := flag.NewFlagSet("kubebuilder", flag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(ioutil.Discard)
+	fs.StringVar(&projectDir, "project-dir", "", "")
+	fs.BoolVarP(&verbose, "verbose", "v", false, "")
+	fs.BoolVar(&quiet, "quiet", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	// every other flag is unknown here, errors are reported by the command
+	_ = fs.Parse(args)
+
+	switch {
+	case verbose && quiet:
+		return fmt.Errorf("--verbose and --quiet cannot be used together")
+	case verbose:
+		logging.SetLevel(logging.LevelDebug)
+	case quiet:
+		logging.SetLevel(logging.LevelQuiet)
+	}
+	if noColor {
+		logging.SetColor(false)
+	}
+
+	if projectDir == "" {
+		return nil
+	}
+	logging.Debugf("using %s as project directory", projectDir)
+	if err := os.Chdir(projectDir); err != nil {
+		return fmt.Errorf("failed to use %s as project directory: %v", projectDir, err)
+	}
+	return nil
+}
+
+// commandExecutor returns the executor of the external commands run after
+// scaffolding, which only prints them with --skip-post-commands.
+func commandExecutor() executor.Executor {
+	if skipPostCommands {
+		return executor.Skip{}
+	}
+	return executor.Default
+}
+
+// hookExecutor returns the executor of the hooks of the project, which only
+// prints them with --offline, since they commonly run make or fetch
+// dependencies, as well as with --skip-post-commands.
+func hookExecutor() executor.Executor {
+	if offline {
+		return executor.Skip{}
+	}
+	return commandExecutor()
+}
+
+// runMake runs make with the executor of the external commands, reporting its
+// progress.
+func runMake() error {
+	done := logging.StartProgress("Running make")
+	err := commandExecutor().Run("make")
+	done(err)
+	return err
+}
+
+// printSkippedCommands prints the commands that were skipped and must be run
+// by the user to complete the scaffolding.
+func printSkippedCommands(commands ...string) {
+	logging.Infof("Run the following commands to complete the setup:")
+	for _, c := range commands {
+		logging.Infof("$ %s", c)
+	}
 }
 
 // getProjectVersion tries to load PROJECT file and returns if the file exist
@@ -199,5 +255,5 @@ func getProjectVersion() (bool, string) {
 }
 
 func printV1DeprecationWarning() {
-	fmt.Printf(NoticeColor, "[Deprecation Notice] The v1 projects are deprecated and will not be supported beyond Feb 1, 2020.\nSee how to upgrade your project to v2: https://book.kubebuilder.io/migration/guide.html\n")
+	logging.Warnf("%s", logging.Highlight("[Deprecation Notice] The v1 projects are deprecated and will not be supported beyond Feb 1, 2020.\nSee how to upgrade your project to v2: https://book.kubebuilder.io/migration/guide.html"))
 }
@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+func TestHookExecutorOffline(t *testing.T) {
+	defer chdirTemp(t)()
+	project := "version: \"2\"\nplugins:\n  hooks.kubebuilder.io:\n    hooks:\n    - command: touch ran\n"
+	if err := ioutil.WriteFile("PROJECT", []byte(project), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(o bool) { offline = o }(offline)
+	offline = true
+	if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateAPI, hookExecutor()); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if _, err := os.Stat("ran"); !os.IsNotExist(err) {
+		t.Errorf("expected the hook not to run in offline mode")
+	}
+
+	offline = false
+	if err := scaffold.RunHooks("PROJECT", input.HookPhaseCreateAPI, hookExecutor()); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if _, err := os.Stat("ran"); err != nil {
+		t.Errorf("expected the hook to run: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/quota"
+)
+
+type quotaOptions struct {
+	settings quota.Settings
+
+	// force overwrites the manifests if they already exist
+	force bool
+}
+
+func newQuotaCmd() *cobra.Command {
+	o := quotaOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "resource-quota",
+		Aliases: []string{"limits"},
+		Short:   "Scaffold the ResourceQuota and LimitRange of the manager namespace",
+		Long: `Scaffold the ResourceQuota and LimitRange of the namespace of the manager under
+config/quota, and add them to the bases of config/default/kustomization.yaml.
+
+The ResourceQuota caps the requests, limits and number of the pods of the
+namespace. The LimitRange sets the default requests and limits of the
+containers which do not set theirs, which the quota would reject otherwise.
+`,
+		Example: `	# Scaffold the default quota and limits of the manager namespace
+	kubebuilder create resource-quota
+
+	# Allow 4 CPUs and 8Gi of memory of limits to 20 pods, without default limits
+	kubebuilder create resource-quota --limits-cpu 4 --limits-memory 8Gi --requests-cpu "" \
+		--requests-memory "" --pods 20 --limit-range=false
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.settings.RequestsCPU, "requests-cpu", "2",
+		"maximum sum of the CPU requests of the pods of the namespace, unlimited if empty")
+	cmd.Flags().StringVar(&o.settings.RequestsMemory, "requests-memory", "2Gi",
+		"maximum sum of the memory requests of the pods of the namespace, unlimited if empty")
+	cmd.Flags().StringVar(&o.settings.LimitsCPU, "limits-cpu", "4",
+		"maximum sum of the CPU limits of the pods of the namespace, unlimited if empty")
+	cmd.Flags().StringVar(&o.settings.LimitsMemory, "limits-memory", "4Gi",
+		"maximum sum of the memory limits of the pods of the namespace, unlimited if empty")
+	cmd.Flags().IntVar(&o.settings.Pods, "pods", 10,
+		"maximum number of pods of the namespace, unlimited if 0")
+	cmd.Flags().BoolVar(&o.settings.LimitRange, "limit-range", true,
+		"if set, scaffold the LimitRange setting the default requests and limits of the containers")
+	cmd.Flags().StringVar(&o.settings.DefaultCPU, "default-cpu", "500m",
+		"default CPU limit of the containers")
+	cmd.Flags().StringVar(&o.settings.DefaultMemory, "default-memory", "128Mi",
+		"default memory limit of the containers")
+	cmd.Flags().StringVar(&o.settings.DefaultRequestCPU, "default-request-cpu", "100m",
+		"default CPU request of the containers")
+	cmd.Flags().StringVar(&o.settings.DefaultRequestMemory, "default-request-memory", "64Mi",
+		"default memory request of the containers")
+	cmd.Flags().BoolVar(&o.force, "force", false,
+		"if set, overwrite the manifests of config/quota if they already exist")
+
+	return cmd
+}
+
+func (o *quotaOptions) run() error {
+	dieIfNoProject()
+
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	if p.Version != project.Version2 {
+		return fmt.Errorf("kubebuilder create resource-quota is for project version: 2, the version of this "+
+			"project is: %s", p.Version)
+	}
+	if err := o.settings.Validate(); err != nil {
+		return err
+	}
+
+	files := []input.File{
+		&quota.ResourceQuota{Settings: o.settings, Force: o.force},
+		&quota.Kustomization{LimitRange: o.settings.LimitRange, Force: o.force},
+	}
+	if o.settings.LimitRange {
+		files = append(files, &quota.LimitRange{Settings: o.settings, Force: o.force})
+	}
+	logging.Infof("Writing scaffold for you to edit...")
+	if err := (&scaffold.Scaffold{}).Execute(&model.Universe{}, input.Options{}, files...); err != nil {
+		return fmt.Errorf("error scaffolding the quota: %v", err)
+	}
+
+	kustomization := filepath.Join("config", "default", "kustomization.yaml")
+	added, err := quota.AddToDefault(kustomization)
+	if err != nil {
+		return fmt.Errorf("error adding the quota to %s: %v", kustomization, err)
+	}
+	if !added {
+		logging.Warnf("Add ../quota to the bases of %s to deploy the quota.", kustomization)
+	}
+	return nil
+}
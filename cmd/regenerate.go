@@ -0,0 +1,332 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+type regenerateOptions struct {
+	outputDir string
+
+	// flags of the initialization of the project, like the ones of init
+	fetchDeps          bool
+	fetchDepsFlag      *flag.Flag
+	skipGoVersionCheck bool
+}
+
+func newRegenerateCmd() *cobra.Command {
+	o := regenerateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "regenerate",
+		Short: "Scaffold the project again from its PROJECT file in a new directory",
+		Long: `Scaffold the project again from its PROJECT file in a new directory, with the
+scaffolding of this version of kubebuilder.
+
+regenerate initializes a project with the domain and repo of the PROJECT file,
+the go directive of go.mod, and the tools module of hack/tools and the API
+docs configuration of docs if the project has them, then creates the APIs and
+the external schemes recorded in the PROJECT file, with the fields of their
+types recorded there and the scope, short names and categories of their
++kubebuilder:resource markers. The controllers and webhooks of the APIs are created if
+the project has them. The license header comes from hack/boilerplate.go.txt.
+
+The result is the code a new user would get, which can be compared with the
+project to see what changed in the scaffolding, or to upgrade the project by
+moving its code over. The project itself is left untouched.
+`,
+		Example: `	# scaffold the project again in ../my-operator-regenerated, and compare
+	kubebuilder alpha regenerate --output-dir ../my-operator-regenerated
+	diff -r . ../my-operator-regenerated
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+	o.bindFlags(cmd)
+
+	return cmd
+}
+
+func (o *regenerateOptions) bindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "the directory to scaffold the project in, which "+
+		"must not exist or be empty. Its name is the project name")
+	_ = cmd.MarkFlagRequired("output-dir")
+	cmd.Flags().BoolVar(&o.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded when initializing the project")
+	o.fetchDepsFlag = cmd.Flag("fetch-deps")
+	cmd.Flags().BoolVar(&o.skipGoVersionCheck, "skip-go-version-check", false, "if specified, skip checking the Go version")
+}
+
+func (o *regenerateOptions) run() error {
+	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
+		return scaffold.ErrProjectNotInitialized
+	}
+	p, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+	if p.Version != project.Version2 {
+		return fmt.Errorf("kubebuilder alpha regenerate is only supported for project version %s", project.Version2)
+	}
+	if offline && o.fetchDepsFlag.Changed && o.fetchDeps {
+		return fmt.Errorf("--fetch-deps cannot be enabled in --offline mode")
+	}
+
+	projectDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if entries, err := ioutil.ReadDir(o.outputDir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("the output directory %s is not empty", o.outputDir)
+	}
+	abs, err := filepath.Abs(o.outputDir)
+	if err != nil {
+		return fmt.Errorf("error resolving the output directory %s: %v", o.outputDir, err)
+	}
+	if err := util.IsValidName(strings.ToLower(filepath.Base(abs))); err != nil {
+		return fmt.Errorf("project name (%v) is invalid: (%v)", filepath.Base(abs), err)
+	}
+	if err := os.MkdirAll(o.outputDir, 0755); err != nil {
+		return fmt.Errorf("error creating the output directory %s: %v", o.outputDir, err)
+	}
+
+	// the project is scaffolded in the output directory, the files of the
+	// project are read from projectDir from then on
+	if err := os.Chdir(o.outputDir); err != nil {
+		return fmt.Errorf("error changing to the output directory %s: %v", o.outputDir, err)
+	}
+	if err := initProject(regeneratedProject(p, projectDir), o.fetchDeps && !offline,
+		o.skipGoVersionCheck); err != nil {
+		return err
+	}
+
+	boilerplatePath := filepath.Join("hack", "boilerplate.go.txt")
+	if b, err := ioutil.ReadFile(filepath.Join(projectDir, boilerplatePath)); err == nil {
+		if err := ioutil.WriteFile(boilerplatePath, b, 0644); err != nil {
+			return err
+		}
+		if _, err := scaffold.UpdateLicense(".", boilerplatePath); err != nil {
+			return fmt.Errorf("error updating the license headers: %v", err)
+		}
+	}
+
+	for _, r := range p.Resources {
+		m, err := regeneratedResource(projectDir, r)
+		if err != nil {
+			return err
+		}
+		fields := scaffold.RecordedResourceFields(p, &resource.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind})
+		m.Fields = fields.Fields
+		m.PreserveUnknownFields, m.EmbeddedResources = fields.PreserveUnknownFields, fields.EmbeddedResources
+		if _, err := applyResource(m); err != nil {
+			return err
+		}
+	}
+	for _, s := range p.ExternalSchemes {
+		if err := scaffold.WireScheme(s); err != nil {
+			return fmt.Errorf("error wiring %s: %v", s.Package, err)
+		}
+	}
+
+	logging.Infof("Scaffolded the project again in %s.", o.outputDir)
+	return nil
+}
+
+// regeneratedProject returns the project scaffolding the project at
+// projectDir again, with its domain, repo and go directive, and its tools
+// module and API docs configuration if it has them
+func regeneratedProject(p input.ProjectFile, projectDir string) *scaffold.V2Project {
+	return &scaffold.V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version: project.Version2,
+			Domain:  p.Domain,
+			Repo:    p.Repo,
+		}},
+		Boilerplate: project.Boilerplate{License: "apache2"},
+		GoVersion:   goDirective(filepath.Join(projectDir, "go.mod")),
+		PinnedTools: fileExists(filepath.Join(projectDir, "hack", "tools", "go.mod")),
+		APIDocs:     fileExists(filepath.Join(projectDir, "docs", "config.yaml")),
+		Executor:    commandExecutor(),
+	}
+}
+
+// regeneratedResource returns what to scaffold for a resource of the project
+// at projectDir: its API, with the scope, short names and categories of the
+// +kubebuilder:resource marker of its types, and its controller and webhooks
+// if the project has them.
+func regeneratedResource(projectDir string, r input.Resource) (resourceManifest, error) {
+	m := resourceManifest{Group: r.Group, Version: r.Version, Kind: r.Kind, Plural: r.Plural,
+		Resource: true, GenerateOnly: r.GenerateOnly}
+
+	typesPath := filepath.Join(projectDir, "api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
+	args, err := resourceMarkerArgs(typesPath, r.Kind)
+	if err != nil && !os.IsNotExist(err) {
+		return m, err
+	}
+	if args["scope"] == "Cluster" {
+		namespaced := false
+		m.Namespaced = &namespaced
+	}
+	if v := args["shortName"]; v != "" {
+		m.ShortNames = strings.Split(v, ";")
+	}
+	if v := args["categories"]; v != "" {
+		m.Categories = strings.Split(v, ";")
+	}
+
+	m.Controller = fileExists(filepath.Join(projectDir, scaffoldv2.ControllerLayoutFlat.ControllerPath(r.Kind)))
+	perKind := scaffoldv2.ControllerLayoutPerKind.ControllerPath(r.Kind)
+	if fileExists(filepath.Join(projectDir, perKind)) {
+		m.Controller = true
+		m.Layout = string(scaffoldv2.ControllerLayoutPerKind)
+	}
+
+	webhookPath := filepath.Join(projectDir, "api", r.Version, fmt.Sprintf("%s_webhook.go", strings.ToLower(r.Kind)))
+	interfaces, err := implementedInterfaces(webhookPath, r.Kind)
+	if os.IsNotExist(err) {
+		return m, nil
+	} else if err != nil {
+		return m, err
+	}
+	m.Webhook = &webhookManifest{
+		Defaulting: interfaces["webhook.Defaulter"],
+		Validation: interfaces["webhook.Validator"],
+	}
+	// the webhook file of a conversion webhook only sets up the webhook
+	m.Webhook.Conversion = !m.Webhook.Defaulting && !m.Webhook.Validation
+	return m, nil
+}
+
+// resourceMarkerArgs returns the arguments of the +kubebuilder:resource marker
+// of the kind type declared in the Go file at path, e.g. scope: Cluster. Like
+// controller-gen, the marker is looked for in the comments between the
+// previous declaration and the type.
+func resourceMarkerArgs(path, kind string) (map[string]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]string{}
+	prevEnd := f.Name.End()
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE || len(gen.Specs) != 1 || gen.Specs[0].(*ast.TypeSpec).Name.Name != kind {
+			prevEnd = decl.End()
+			continue
+		}
+		for _, group := range f.Comments {
+			// the doc of the type is one of these groups
+			if group.Pos() <= prevEnd || group.End() > decl.Pos() {
+				continue
+			}
+			for _, c := range group.List {
+				text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+				if !strings.HasPrefix(text, "+kubebuilder:resource:") {
+					continue
+				}
+				for _, arg := range strings.Split(strings.TrimPrefix(text, "+kubebuilder:resource:"), ",") {
+					if kv := strings.SplitN(arg, "=", 2); len(kv) == 2 {
+						args[kv[0]] = kv[1]
+					}
+				}
+			}
+		}
+		break
+	}
+	return args, nil
+}
+
+// implementedInterfaces returns the interfaces the kind type is asserted to
+// implement in the Go file at path with var _ <interface> = &<kind>{}
+// declarations, e.g. webhook.Defaulter
+func implementedInterfaces(path, kind string) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	interfaces := map[string]bool{}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			v := spec.(*ast.ValueSpec)
+			if len(v.Names) != 1 || v.Names[0].Name != "_" || len(v.Values) != 1 {
+				continue
+			}
+			iface, ok := v.Type.(*ast.SelectorExpr)
+			if !ok || !isAddressOf(v.Values[0], kind) {
+				continue
+			}
+			if pkg, ok := iface.X.(*ast.Ident); ok {
+				interfaces[pkg.Name+"."+iface.Sel.Name] = true
+			}
+		}
+	}
+	return interfaces, nil
+}
+
+// isAddressOf returns true if the expression is &<kind>{}
+func isAddressOf(expr ast.Expr, kind string) bool {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return false
+	}
+	lit, ok := unary.X.(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	ident, ok := lit.Type.(*ast.Ident)
+	return ok && ident.Name == kind
+}
+
+// goDirective returns the Go release of the go directive of the go.mod file
+// at path, if any
+func goDirective(path string) string {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return ""
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		if f := strings.Fields(l); len(f) == 2 && f[0] == "go" {
+			return f[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRegenerate(t *testing.T) {
+	defer chdirTemp(t)()
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// the fixture and the regenerated project have the same name, which
+	// prefixes their resources
+	fixture := filepath.Join(root, "fixture", "proj")
+	regenerated := filepath.Join(root, "regenerated", "proj")
+	if err := os.MkdirAll(fixture, 0755); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := os.Chdir(fixture); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	scaffoldTestProject(t)
+	namespaced := false
+	for _, r := range []resourceManifest{
+		{Group: "ship", Version: "v1", Kind: "Frigate", Resource: true, Controller: true,
+			Fields:  []string{"Replicas:int32:min=1"},
+			Webhook: &webhookManifest{Defaulting: true, Validation: true}},
+		{Group: "ship", Version: "v1", Kind: "Harbor", Plural: "harbours", Namespaced: &namespaced,
+			ShortNames: []string{"hb"}, Categories: []string{"all"}, Resource: true, Controller: true,
+			Layout: "per-kind"},
+		{Group: "ship", Version: "v1", Kind: "Sloop", Resource: true, Webhook: &webhookManifest{Conversion: true}},
+	} {
+		if _, err := applyResource(r); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	o := &regenerateOptions{}
+	cmd := &cobra.Command{}
+	o.bindFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--output-dir", regenerated, "--fetch-deps=false",
+		"--skip-go-version-check"}); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := o.run(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// the fixture is regenerated as it was scaffolded
+	err = filepath.Walk(fixture, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(fixture, path)
+		if err != nil {
+			return err
+		}
+		want, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		got, err := ioutil.ReadFile(filepath.Join(regenerated, rel)) // nolint: gosec
+		if err != nil {
+			t.Errorf("%s was not regenerated: %v", rel, err)
+			return nil
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs, got:\n%s\nwanted:\n%s", rel, got, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	err = filepath.Walk(regenerated, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(regenerated, path)
+		if err != nil {
+			return err
+		}
+		if !fileExists(filepath.Join(fixture, rel)) {
+			t.Errorf("%s was regenerated but is not in the fixture", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+// goModule is just enough of the output of `go list -m -json` and `go mod
+// edit -json` for our purposes
+type goModule struct {
+	Path string
+	// Dir is the directory of the module, only set by go list
+	Dir string
+}
+
+// findGoModulePath finds the path of the current module, if present.
+func findGoModulePath(forceModules bool) (string, error) {
+	cmd := exec.Command("go", "mod", "edit", "-json")
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	if forceModules {
+		cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
+			err = fmt.Errorf("%s", string(exitErr.Stderr))
+		}
+		return "", err
+	}
+	mod := struct{ Module goModule }{}
+	if err := json.Unmarshal(out, &mod); err != nil {
+		return "", err
+	}
+	return mod.Module.Path, nil
+}
+
+// findCurrentRepo attempts to determine the repository of the current
+// directory, from the first of:
+//   - the PROJECT file
+//   - the Go module the directory is in, whose path may be a vanity import
+//     path unrelated to where the code is hosted
+//   - the GOPATH the directory is in
+//   - the remote of the git or mercurial repository the directory is in
+//   - the path guessed by go mod init
+func findCurrentRepo() (string, error) {
+	// easiest case: project file already exists
+	projFile, err := scaffold.LoadProjectFile("PROJECT")
+	if err == nil {
+		return projFile.Repo, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	if repo, found := findModuleRepo(dir); found {
+		return repo, nil
+	}
+	if repo, found := findGOPATHRepo(dir, filepath.SplitList(build.Default.GOPATH)); found {
+		return repo, nil
+	}
+	if repo, found := findVCSRepo(dir); found {
+		return repo, nil
+	}
+
+	// otherwise, try to get `go mod init` to guess for us -- it's pretty good
+	cmd := exec.Command("go", "mod", "init")
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
+	if _, err := cmd.Output(); err != nil {
+		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
+			err = fmt.Errorf("%s", string(exitErr.Stderr))
+		}
+		// give up, let the user figure it out
+		return "", fmt.Errorf("could not determine repository path from module, GOPATH or VCS data, or by "+
+			"initializing a module, use --repo to set it: %v", err)
+	}
+	defer os.Remove("go.mod") // clean up after ourselves
+	return findGoModulePath(true)
+}
+
+// findModuleRepo returns the import path of the directory in the Go module it
+// is in, if any. A go.work workspace may list several modules, the innermost
+// one holding the directory is used.
+func findModuleRepo(dir string) (string, bool) {
+	cmd := exec.Command("go", "list", "-m", "-json")
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	var repo, moduleDir string
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		mod := goModule{}
+		if err := dec.Decode(&mod); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", false
+		}
+		if mod.Path == "" || len(mod.Dir) <= len(moduleDir) {
+			continue
+		}
+		if p, found := importPathIn(mod.Path, mod.Dir, dir); found {
+			repo, moduleDir = p, mod.Dir
+		}
+	}
+	return repo, repo != ""
+}
+
+// findGOPATHRepo returns the import path of the directory if it is in the src
+// directory of one of the gopaths. Outside of them, go/packages would
+// fabricate an _/absolute/path import path, which is not used.
+func findGOPATHRepo(dir string, gopaths []string) (string, bool) {
+	for _, gopath := range gopaths {
+		if gopath == "" {
+			continue
+		}
+		if p, found := importPathIn("", filepath.Join(gopath, "src"), dir); found && p != "." {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// findVCSRepo returns the import path of the directory derived from the
+// remote of the git or mercurial repository it is in, e.g.
+// github.com/user/repo/operator for the operator directory of a repository
+// cloned from git@github.com:user/repo.git. The origin remote of git is
+// preferred to the others.
+func findVCSRepo(dir string) (string, bool) {
+	for d := dir; ; d = filepath.Dir(d) {
+		var remote string
+		if info, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			remote = gitRemote(filepath.Join(d, ".git"), info.IsDir())
+		} else if _, err := os.Stat(filepath.Join(d, ".hg")); err == nil {
+			remote = iniValue(filepath.Join(d, ".hg", "hgrc"), func(section, key string) bool {
+				return section == "paths" && key == "default"
+			})
+		} else {
+			if filepath.Dir(d) == d {
+				return "", false
+			}
+			continue
+		}
+		// the repository the directory is in has been found, whether its
+		// remote can be used or not
+		root, ok := remoteImportPath(remote)
+		if !ok {
+			return "", false
+		}
+		return importPathIn(root, d, dir)
+	}
+}
+
+// gitRemote returns the URL of the preferred remote of the git repository,
+// whose .git is a directory or, for worktrees and submodules, a file pointing
+// to the git directory
+func gitRemote(dotGit string, isDir bool) string {
+	gitDir := dotGit
+	if !isDir {
+		b, err := ioutil.ReadFile(dotGit) // nolint: gosec
+		if err != nil {
+			return ""
+		}
+		gitDir = strings.TrimSpace(strings.TrimPrefix(string(b), "gitdir:"))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(filepath.Dir(dotGit), gitDir)
+		}
+		// the config of a worktree is in the common directory of the repository
+		if b, err := ioutil.ReadFile(filepath.Join(gitDir, "commondir")); err == nil { // nolint: gosec
+			common := strings.TrimSpace(string(b))
+			if !filepath.IsAbs(common) {
+				common = filepath.Join(gitDir, common)
+			}
+			gitDir = common
+		}
+	}
+
+	config := filepath.Join(gitDir, "config")
+	if origin := iniValue(config, func(section, key string) bool {
+		return section == `remote "origin"` && key == "url"
+	}); origin != "" {
+		return origin
+	}
+	return iniValue(config, func(section, key string) bool {
+		return strings.HasPrefix(section, "remote ") && key == "url"
+	})
+}
+
+// iniValue returns the first value of the ini file at path, e.g. a git config
+// or a mercurial hgrc, whose section and key match
+func iniValue(path string, match func(section, key string) bool) string {
+	f, err := os.Open(path) // nolint: gosec
+	if err != nil {
+		return ""
+	}
+	defer f.Close() // nolint: errcheck
+
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+		default:
+			kv := strings.SplitN(line, "=", 2)
+			if len(kv) == 2 && match(section, strings.TrimSpace(kv[0])) {
+				return strings.TrimSpace(kv[1])
+			}
+		}
+	}
+	return ""
+}
+
+// remoteImportPath returns the import path of the root of the repository
+// cloned from the remote URL, e.g. github.com/user/repo for
+// https://github.com/user/repo.git, ssh://git@github.com/user/repo or
+// git@github.com:user/repo.git. Local remotes have no import path.
+func remoteImportPath(remote string) (string, bool) {
+	var host, p string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil || u.Scheme == "file" {
+			return "", false
+		}
+		host, p = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax of git, [user@]host:path
+		i := strings.Index(remote, ":")
+		if i < 0 || strings.ContainsAny(remote[:i], `/\`) {
+			return "", false
+		}
+		host, p = remote[:i], remote[i+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	}
+	if host == "" {
+		return "", false
+	}
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	repo := path.Join(strings.ToLower(host), p)
+	if validateRepo(repo) != nil {
+		return "", false
+	}
+	return repo, true
+}
+
+// importPathIn returns the import path of dir, inside of root whose import
+// path is rootPath, e.g. example.com/repo/operator for the operator
+// directory of the example.com/repo module
+func importPathIn(rootPath, root, dir string) (string, bool) {
+	// the directories may be reached through symbolic links, e.g. /tmp on
+	// macOS
+	if r, err := filepath.EvalSymlinks(root); err == nil {
+		root = r
+	}
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return path.Join(rootPath, filepath.ToSlash(rel)), true
+}
+
+// validateRepo checks that the repo is a valid Go import path, which can be
+// used as the path of the module of the project
+func validateRepo(repo string) error {
+	if repo == "" {
+		return fmt.Errorf("repo cannot be empty")
+	}
+	if strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") {
+		return fmt.Errorf("invalid repo %q: cannot start or end with a slash", repo)
+	}
+	for _, elem := range strings.Split(repo, "/") {
+		if err := validateRepoElement(elem); err != nil {
+			return fmt.Errorf("invalid repo %q: %v", repo, err)
+		}
+	}
+	if strings.HasPrefix(repo, "-") {
+		return fmt.Errorf("invalid repo %q: cannot start with a dash", repo)
+	}
+	return nil
+}
+
+// validateRepoElement checks an element of an import path, between slashes
+func validateRepoElement(elem string) error {
+	switch {
+	case elem == "":
+		return fmt.Errorf("empty path element")
+	case elem == "." || elem == "..":
+		return fmt.Errorf("path element %q is not allowed", elem)
+	case strings.HasPrefix(elem, ".") || strings.HasSuffix(elem, "."):
+		return fmt.Errorf("path element %q cannot start or end with a dot", elem)
+	}
+	for _, r := range elem {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("-._~+", r)) {
+			return fmt.Errorf("path element %q contains the invalid character %q", elem, r)
+		}
+	}
+	return nil
+}
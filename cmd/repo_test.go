@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoteImportPath(t *testing.T) {
+	tests := []struct {
+		remote string
+		repo   string
+	}{
+		{"https://github.com/user/repo.git", "github.com/user/repo"},
+		{"https://github.com/user/repo/", "github.com/user/repo"},
+		{"ssh://git@github.com:22/user/repo.git", "github.com/user/repo"},
+		{"git@github.com:user/repo.git", "github.com/user/repo"},
+		{"GitLab.com:group/sub/repo", "gitlab.com/group/sub/repo"},
+		{"https://hg.example.org/repo", "hg.example.org/repo"},
+		{"file:///srv/git/repo.git", ""},
+		{"/srv/git/repo.git", ""},
+		{"../repo", ""},
+	}
+
+	for _, test := range tests {
+		repo, ok := remoteImportPath(test.remote)
+		if repo != test.repo || ok != (test.repo != "") {
+			t.Errorf("remoteImportPath(%q) = %q, %v, expected %q", test.remote, repo, ok, test.repo)
+		}
+	}
+}
+
+func TestValidateRepo(t *testing.T) {
+	tests := []struct {
+		repo      string
+		isInvalid bool
+	}{
+		{"github.com/user/repo", false},
+		{"example.com/my-operator/v2", false},
+		{"go.example.io/op_er~ator+x", false},
+		{"operator", false},
+		{"", true},
+		{"/github.com/user/repo", true},
+		{"github.com/user/repo/", true},
+		{"github.com//repo", true},
+		{"github.com/user/../repo", true},
+		{"github.com/user/.repo", true},
+		{"github.com/user/my repo", true},
+		{"-example.com/repo", true},
+		{`github.com\user\repo`, true},
+	}
+
+	for _, test := range tests {
+		err := validateRepo(test.repo)
+		if (err != nil) != test.isInvalid {
+			t.Errorf("validateRepo(%q) = %v, expected invalid: %v", test.repo, err, test.isInvalid)
+		}
+	}
+}
+
+func TestFindVCSRepo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubebuilder-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("git/.git/config", `[core]
+	bare = false
+[remote "upstream"]
+	url = https://github.com/upstream/repo.git
+[remote "origin"]
+	url = git@github.com:user/repo.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`)
+	write("git/operator/main.go", "package main\n")
+	write("worktree/.git", "gitdir: ../git/.git/worktrees/wt\n")
+	write("git/.git/worktrees/wt/commondir", "../..\n")
+	write("hg/.hg/hgrc", "[paths]\ndefault = https://hg.example.org/repo\n")
+	write("local/.git/config", "[remote \"origin\"]\n\turl = /srv/git/repo.git\n")
+
+	tests := []struct {
+		dir  string
+		repo string
+	}{
+		{"git", "github.com/user/repo"},
+		{"git/operator", "github.com/user/repo/operator"},
+		{"worktree", "github.com/user/repo"},
+		{"hg", "hg.example.org/repo"},
+		{"local", ""},
+	}
+
+	for _, test := range tests {
+		repo, ok := findVCSRepo(filepath.Join(dir, test.dir))
+		if repo != test.repo || ok != (test.repo != "") {
+			t.Errorf("findVCSRepo(%s) = %q, %v, expected %q", test.dir, repo, ok, test.repo)
+		}
+	}
+}
+
+func TestFindGOPATHRepo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubebuilder-gopath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	gopath := filepath.Join(dir, "gopath")
+	project := filepath.Join(gopath, "src", "example.com", "operator")
+	outside := filepath.Join(dir, "operator")
+	for _, d := range []string{project, outside} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if repo, ok := findGOPATHRepo(project, []string{"", filepath.Join(dir, "other"), gopath}); repo != "example.com/operator" || !ok {
+		t.Errorf("findGOPATHRepo in GOPATH = %q, %v, expected example.com/operator", repo, ok)
+	}
+	if repo, ok := findGOPATHRepo(outside, []string{gopath}); ok {
+		t.Errorf("findGOPATHRepo outside of GOPATH = %q, expected none", repo)
+	}
+	if repo, ok := findGOPATHRepo(filepath.Join(gopath, "src"), []string{gopath}); ok {
+		t.Errorf("findGOPATHRepo in GOPATH/src = %q, expected none", repo)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+func newUpdateLicenseCmd() *cobra.Command {
+	boilerplatePath := ""
+
+	cmd := &cobra.Command{
+		Use:   "update-license",
+		Short: "Rewrite the license header of the Go files from the boilerplate file",
+		Long: `Rewrite the license header of the Go files of the project from the boilerplate
+file, e.g. after changing the year or the owner in hack/boilerplate.go.txt.
+
+The license header of a file is the block comment it starts with, if that
+comment contains a copyright or a license. The files without such a header are
+left untouched, as are the vendored files, the files under bin/ and testbin/,
+and the zz_generated files. Run make generate to regenerate the latter with
+the new header.
+`,
+		Example: `	# after editing hack/boilerplate.go.txt
+	kubebuilder alpha update-license
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			updated, err := scaffold.UpdateLicense(".", boilerplatePath)
+			if err != nil {
+				fatal(fmt.Errorf("error updating the license headers: %w", err))
+			}
+			for _, path := range updated {
+				logging.Infof("%s", path)
+			}
+			logging.Infof("Updated the license header of %d files.", len(updated))
+		},
+	}
+
+	cmd.Flags().StringVar(&boilerplatePath, "boilerplate", filepath.Join("hack", "boilerplate.go.txt"),
+		"the boilerplate file holding the license header")
+
+	return cmd
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// Prompter asks the questions of the commands, e.g. whether to scaffold the
+// resource of an API, and records the decisions it made
+type Prompter interface {
+	// Confirm asks a yes/no question and returns the answer, or the
+	// default value if there is none
+	Confirm(question string, defaultValue bool) bool
+
+	// Ask asks a question until validate, if not nil, accepts the answer and
+	// returns it, or the default value if there is none
+	Ask(question, defaultValue string, validate func(string) error) string
+
+	// Decisions returns the questions asked so far with their answers
+	Decisions() []Decision
+}
+
+// Decision is a question asked by a Prompter and its answer
+type Decision struct {
+	Question string
+	Answer   string
+
+	// Prompted is true if the answer was entered, false if it is the
+	// default value
+	Prompted bool
+}
+
+// decisions records the decisions of a Prompter
+type decisions []Decision
+
+func (d *decisions) record(question, answer string, prompted bool) {
+	*d = append(*d, Decision{Question: question, Answer: answer, Prompted: prompted})
+}
+
+// Decisions implements Prompter
+func (d decisions) Decisions() []Decision {
+	return d
+}
+
+// NewPrompter returns a Prompter writing the questions to out and reading the
+// answers from in. An empty answer, or the end of in, selects the default
+// value.
+func NewPrompter(in io.Reader, out io.Writer) Prompter {
+	return &readerPrompter{in: bufio.NewReader(in), out: out}
+}
+
+type readerPrompter struct {
+	decisions
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// Confirm implements Prompter
+func (p *readerPrompter) Confirm(question string, defaultValue bool) bool {
+	choices := "[y/N]"
+	if defaultValue {
+		choices = "[Y/n]"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s %s: ", question, choices)
+		text, eof := p.read()
+		answer := defaultValue
+		switch text {
+		case "":
+		case "y", "yes":
+			answer = true
+		case "n", "no":
+			answer = false
+		default:
+			if eof {
+				log.Fatalf("invalid input %q, should be [y/n]", text)
+			}
+			fmt.Fprintf(p.out, "invalid input %q, should be [y/n]\n", text)
+			continue
+		}
+		p.record(question, yesno(answer), text != "")
+		return answer
+	}
+}
+
+// Ask implements Prompter
+func (p *readerPrompter) Ask(question, defaultValue string, validate func(string) error) string {
+	for {
+		if defaultValue != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		text, eof := p.read()
+		answer := text
+		if answer == "" {
+			answer = defaultValue
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				if eof {
+					log.Fatalf("invalid input %q: %v", answer, err)
+				}
+				fmt.Fprintf(p.out, "invalid input %q: %v\n", answer, err)
+				continue
+			}
+		}
+		p.record(question, answer, text != "")
+		return answer
+	}
+}
+
+// read reads a line of in trimming spaces, and returns true at the end of
+// in. log.Fatal's if there is an error.
+func (p *readerPrompter) read() (string, bool) {
+	text, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		log.Fatalf("Error when reading input: %v", err)
+	}
+	return strings.TrimSpace(text), err == io.EOF
+}
+
+// NewDefaultsPrompter returns a Prompter which does not prompt, and answers
+// the questions with their default value, e.g. to run the commands
+// non-interactively
+func NewDefaultsPrompter() Prompter {
+	return &defaultsPrompter{}
+}
+
+type defaultsPrompter struct {
+	decisions
+}
+
+// Confirm implements Prompter
+func (p *defaultsPrompter) Confirm(question string, defaultValue bool) bool {
+	p.record(question, yesno(defaultValue), false)
+	return defaultValue
+}
+
+// Ask implements Prompter
+func (p *defaultsPrompter) Ask(question, defaultValue string, _ func(string) error) string {
+	p.record(question, defaultValue, false)
+	return defaultValue
+}
+
+func yesno(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
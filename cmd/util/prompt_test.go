@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrompter(t *testing.T) {
+	p := NewPrompter(strings.NewReader("maybe\nn\n\nx\nfrigate\n"), ioutil.Discard)
+	if p.Confirm("Create Resource", true) {
+		t.Errorf("Confirm returned yes for n")
+	}
+	if !p.Confirm("Create Controller", true) {
+		t.Errorf("Confirm returned no for the default yes")
+	}
+	kind := p.Ask("Kind", "", func(kind string) error {
+		if kind != "frigate" {
+			return errors.New("not a frigate")
+		}
+		return nil
+	})
+	if kind != "frigate" {
+		t.Errorf("Ask returned %q", kind)
+	}
+	// the end of the input selects the defaults
+	if p.Ask("Version", "v1", nil) != "v1" {
+		t.Errorf("Ask did not return the default at the end of the input")
+	}
+
+	expected := []Decision{
+		{Question: "Create Resource", Answer: "no", Prompted: true},
+		{Question: "Create Controller", Answer: "yes"},
+		{Question: "Kind", Answer: "frigate", Prompted: true},
+		{Question: "Version", Answer: "v1"},
+	}
+	if !reflect.DeepEqual(p.Decisions(), expected) {
+		t.Errorf("got decisions %+v, expected %+v", p.Decisions(), expected)
+	}
+}
+
+func TestDefaultsPrompter(t *testing.T) {
+	p := NewDefaultsPrompter()
+	if !p.Confirm("Create Resource", true) || p.Ask("Version", "v1", nil) != "v1" {
+		t.Errorf("the defaults prompter did not return the defaults")
+	}
+	expected := []Decision{{Question: "Create Resource", Answer: "yes"}, {Question: "Version", Answer: "v1"}}
+	if !reflect.DeepEqual(p.Decisions(), expected) {
+		t.Errorf("got decisions %+v, expected %+v", p.Decisions(), expected)
+	}
+}
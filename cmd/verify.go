@@ -0,0 +1,126 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/verify"
+)
+
+type verifyOptions struct {
+	// dir is the kustomization to render
+	dir string
+	// file holds already rendered manifests, - for stdin
+	file string
+	// kustomize is the kustomize binary
+	kustomize string
+}
+
+func newVerifyCmd() *cobra.Command {
+	o := verifyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the manifests of the project before applying them",
+		Long: `Check the manifests of the project before applying them to a cluster.
+
+verify renders the kustomization of the project with kustomize, or reads already
+rendered manifests, and reports:
+- the CRD schemas which are not structural
+- the webhooks and conversion webhooks whose service is not in the manifests or
+  does not select the manager
+- the webhooks whose CA is injected from a certificate which is missing, not
+  valid for the webhook service, or whose secret is not mounted by the manager
+
+verify exits with an error if any problem is found. The webhooks without a CA
+bundle are only reported as warnings, since the CA bundle may be set at runtime.
+`,
+		Example: `	# check the manifests deployed by make deploy
+	kubebuilder alpha verify
+
+	# check manifests rendered by another tool
+	kustomize build config/overlays/prod | kubebuilder alpha verify -f -
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := o.run(); err != nil {
+				fatal(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&o.dir, "kustomize-dir", "config/default", "the kustomization to render and check")
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "if specified, the rendered manifests to check "+
+		"instead of rendering the kustomization, - for stdin")
+	cmd.Flags().StringVar(&o.kustomize, "kustomize", "kustomize", "the kustomize binary")
+
+	return cmd
+}
+
+func (o *verifyOptions) run() error {
+	manifests, err := o.manifests()
+	if err != nil {
+		return err
+	}
+	objects, err := verify.Parse(manifests)
+	if err != nil {
+		return fmt.Errorf("error parsing the manifests: %v", err)
+	}
+
+	errors := 0
+	for _, p := range verify.Verify(objects) {
+		if p.Warning {
+			logging.Warnf("%s: %s", p.Object, p.Message)
+			continue
+		}
+		errors++
+		logging.Errorf("%s: %s", p.Object, p.Message)
+	}
+	if errors > 0 {
+		return fmt.Errorf("found %d problems in the manifests", errors)
+	}
+	logging.Infof("Checked %d objects, no problem found.", len(objects))
+	return nil
+}
+
+// manifests returns the manifests to check.
+func (o *verifyOptions) manifests() ([]byte, error) {
+	switch o.file {
+	case "":
+	case "-":
+		return ioutil.ReadAll(os.Stdin)
+	default:
+		return ioutil.ReadFile(o.file)
+	}
+
+	logging.Debugf("%s build %s", o.kustomize, o.dir)
+	out := &bytes.Buffer{}
+	c := exec.Command(o.kustomize, "build", o.dir) // #nosec
+	c.Stdout = out
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("error rendering %s with %s: %v", o.dir, o.kustomize, err)
+	}
+	return out.Bytes(), nil
+}
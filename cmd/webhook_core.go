@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// isCoreWebhook returns true if the webhook is for a type of the Kubernetes
+// API, i.e. the group is a Kubernetes group, or empty for the core group,
+// and the resource is not one of the project.
+func isCoreWebhook(p *input.ProjectFile, r *resource.Resource) bool {
+	// an empty group is the core group, unless the project has an API of the
+	// kind in its domain
+	if r.Group == "" && !resourceTracked(*p, resourceManifest{Version: r.Version, Kind: r.Kind}) {
+		r.Group = "core"
+	}
+	if !util.IsCoreGroup(r.Group) {
+		return false
+	}
+	for _, res := range p.Resources {
+		if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+			return false
+		}
+	}
+	return true
+}
+
+// runCoreWebhook scaffolds the admission webhooks of a type of the Kubernetes
+// API under webhook/, and registers them in main.go.
+func runCoreWebhook(p *input.ProjectFile, o *webhookV2Options) error {
+	if o.conversion {
+		return invalidInputf("conversion webhooks are not supported for %s, which is not a type of the project", o.res.Kind)
+	}
+
+	logging.Infof("Writing scaffold for you to edit...")
+	logging.Infof("%s", filepath.Join(webhook.CoreDir, fmt.Sprintf("%s_webhook.go", strings.ToLower(o.res.Kind))))
+	err := (&scaffold.Scaffold{}).Execute(
+		&model.Universe{},
+		input.Options{},
+		&webhook.CoreWebhook{
+			Resource:   o.res,
+			Defaulting: o.defaulting,
+			Validating: o.validation,
+			Settings:   o.settings,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("error scaffolding webhook: %v", err)
+	}
+	if err := scaffold.WebhookSettingsPatch(o.res, o.settings, o.defaulting, o.validation); err != nil {
+		return err
+	}
+
+	err = (&scaffoldv2.Main{}).Update(
+		&scaffoldv2.MainUpdateOptions{
+			Project:         p,
+			WireCoreWebhook: true,
+			Resource:        o.res,
+		})
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+	if err := scaffold.UpdateWebhookServer(o.server); err != nil {
+		return err
+	}
+
+	return scaffold.RunHooks("PROJECT", input.HookPhaseCreateWebhook, hookExecutor())
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/policy"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// policyCEL is the --policy of the ValidatingAdmissionPolicy with CEL rules
+const policyCEL = "cel"
+
+// runPolicy scaffolds the ValidatingAdmissionPolicy of the resource and its
+// binding under config/policy, instead of the webhooks.
+func runPolicy(p *input.ProjectFile, o *webhookV2Options) error {
+	if o.policy != policyCEL {
+		return invalidInputf("unknown policy %q, must be %s", o.policy, policyCEL)
+	}
+	if o.defaulting || o.validation || o.conversion {
+		return invalidInputf("--policy replaces the webhooks, it cannot be used with --defaulting, " +
+			"--programmatic-validation or --conversion")
+	}
+	if o.settings.SideEffects != "" || o.settings.TimeoutSeconds != 0 {
+		return invalidInputf("--side-effects and --timeout-seconds are not supported with --policy")
+	}
+	if err := o.settings.Validate(); err != nil {
+		return invalidInput(err)
+	}
+
+	// the rules of the types of the project are stubs checking their spec
+	// fields, the types of the Kubernetes API get a single stub
+	var fields []webhook.SpecField
+	if !isCoreWebhook(p, o.res) {
+		if len(o.res.Resource) == 0 {
+			o.res.Resource = scaffold.RecordedPlural(p, o.res)
+		}
+		typesPath := filepath.Join("api", o.res.Version, fmt.Sprintf("%s_types.go", strings.ToLower(o.res.Kind)))
+		if _, err := os.Stat(typesPath); err == nil {
+			if fields, err = webhook.ParseSpecFields(typesPath, o.res.Kind); err != nil {
+				return fmt.Errorf("error reading the spec fields of %s: %v", o.res.Kind, err)
+			}
+		}
+	}
+	if len(o.res.Resource) == 0 {
+		o.res.Resource = resource.Pluralize(o.res.Kind)
+	}
+
+	logging.Infof("Writing scaffold for you to edit...")
+	pol := &policy.Policy{Resource: o.res, SpecFields: fields, FailurePolicy: o.settings.FailurePolicy}
+	logging.Infof("%s", filepath.Join(policy.Dir, pol.FileName()))
+	err := (&scaffold.Scaffold{}).Execute(&model.Universe{}, input.Options{}, pol)
+	if err != nil {
+		return fmt.Errorf("error scaffolding the policy: %v", err)
+	}
+
+	// the kustomization files are shared by the policies of all the resources
+	kustomization := &policy.Kustomization{}
+	err = (&scaffold.Scaffold{SkipExisting: true}).Execute(&model.Universe{}, input.Options{},
+		kustomization, &policy.KustomizeConfig{})
+	if err != nil {
+		return fmt.Errorf("error scaffolding the policy kustomization: %v", err)
+	}
+	if err := kustomization.AddPolicy(pol); err != nil {
+		return fmt.Errorf("error adding the policy to %s: %v", filepath.Join(policy.Dir, "kustomization.yaml"), err)
+	}
+	logging.Infof(`The policy has been set up for you.
+Add ../policy to the bases of config/default/kustomization.yaml to deploy it.`)
+
+	return scaffold.RunHooks("PROJECT", input.HookPhaseCreateWebhook, hookExecutor())
+}
@@ -18,15 +18,11 @@ package main
 
 import (
 	"fmt"
-	"log"
-	"os"
-	"os/exec"
-	"strings"
 
-	"github.com/gobuffalo/flect"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
@@ -55,18 +51,18 @@ This command is only available for v1 scaffolding project.
 
 			projectInfo, err := scaffold.LoadProjectFile("PROJECT")
 			if err != nil {
-				log.Fatalf("failed to read the PROJECT file: %v", err)
+				fatal(fmt.Errorf("failed to read the PROJECT file: %v", err))
 			}
 
 			if projectInfo.Version != project.Version1 {
-				fmt.Printf("webhook scaffolding is not supported for this project version: %s \n", projectInfo.Version)
-				os.Exit(1)
+				fatal(invalidInputf("webhook scaffolding is not supported for this project version: %s",
+					projectInfo.Version))
 			}
 
-			fmt.Println("Writing scaffold for you to edit...")
+			logging.Infof("Writing scaffold for you to edit...")
 
 			if len(o.res.Resource) == 0 {
-				o.res.Resource = flect.Pluralize(strings.ToLower(o.res.Kind))
+				o.res.Resource = resource.Pluralize(o.res.Kind)
 			}
 
 			err = (&scaffold.Scaffold{}).Execute(
@@ -81,16 +77,12 @@ This command is only available for v1 scaffolding project.
 				&webhook.AddServer{Config: webhook.Config{Server: o.server, Type: o.webhookType, Operations: o.operations}},
 			)
 			if err != nil {
-				log.Fatal(err)
+				fatal(err)
 			}
 
 			if o.doMake {
-				fmt.Println("Running make...")
-				cm := exec.Command("make") // #nosec
-				cm.Stderr = os.Stderr
-				cm.Stdout = os.Stdout
-				if err := cm.Run(); err != nil {
-					log.Fatal(err)
+				if err := runMake(); err != nil {
+					fatal(err)
 				}
 			}
 		},
@@ -18,20 +18,16 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
-	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
 	"github.com/spf13/cobra"
 
-	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
-	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
@@ -41,72 +37,65 @@ func newWebhookV2Cmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "webhook",
 		Short: "Scaffold a webhook for an API resource.",
-		Long:  `Scaffold a webhook for an API resource. You can choose to scaffold defaulting, validating and (or) conversion webhooks.`,
+		Long: `Scaffold a webhook for an API resource. You can choose to scaffold defaulting, validating and (or) conversion webhooks.
+
+The validating webhook of a CRD gets a fuzz test, which feeds it random objects
+to catch the panics of the validation logic. It is run by make test-fuzz.
+`,
 		Example: `	# Create defaulting and validating webhooks for CRD of group crew, version v1 and kind FirstMate.
 	kubebuilder create webhook --group crew --version v1 --kind FirstMate --defaulting --programmatic-validation
 
 	# Create conversion webhook for CRD of group crew, version v1 and kind FirstMate.
 	kubebuilder create webhook --group crew --version v1 --kind FirstMate --conversion
+
+	# Create a validating webhook for CRD of group crew, version v1 and kind FirstMate, which is skipped
+	# when it fails, has no side effects and times out after 5 seconds.
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --programmatic-validation \
+		--failure-policy ignore --side-effects None --timeout-seconds 5
+
+	# Create defaulting and validating webhooks for the Pods of the core group.
+	kubebuilder create webhook --group "" --version v1 --kind Pod --defaulting --programmatic-validation
+
+	# Create a ValidatingAdmissionPolicy with CEL rules for CRD of group crew, version v1 and kind FirstMate,
+	# which needs no webhook server.
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --policy cel
+
+	# Create a validating webhook for CRD of group crew, version v1 and kind FirstMate, served on port 9444
+	# behind the Service firstmate-webhooks.
+	kubebuilder create webhook --group crew --version v1 --kind FirstMate --programmatic-validation \
+		--webhook-port 9444 --webhook-service-name firstmate-webhooks
 `,
 		Run: func(cmd *cobra.Command, args []string) {
-			dieIfNoProject()
-
-			projectInfo, err := scaffold.LoadProjectFile("PROJECT")
-			if err != nil {
-				log.Fatalf("failed to read the PROJECT file: %v", err)
+			if err := o.run(); err != nil {
+				fatal(err)
 			}
+		},
+	}
+	o.bindCmdFlags(cmd)
 
-			if projectInfo.Version != project.Version2 {
-				fmt.Printf("kubebuilder webhook is for project version: 2, the version of this project is: %s \n", projectInfo.Version)
-				os.Exit(1)
-			}
+	return cmd
+}
 
-			if !o.defaulting && !o.validation && !o.conversion {
-				fmt.Printf("kubebuilder webhook requires at least one of --defaulting, --programmatic-validation and --conversion to be true")
-				os.Exit(1)
-			}
+// webhookOptions represents commandline options for scaffolding a webhook.
+type webhookV2Options struct {
+	res        *resource.Resource
+	defaulting bool
+	validation bool
+	conversion bool
 
-			if len(o.res.Resource) == 0 {
-				o.res.Resource = flect.Pluralize(strings.ToLower(o.res.Kind))
-			}
+	// policy is the kind of admission policy to scaffold instead of the
+	// webhooks, if any
+	policy string
 
-			fmt.Println("Writing scaffold for you to edit...")
-			fmt.Println(filepath.Join("api", o.res.Version,
-				fmt.Sprintf("%s_webhook.go", strings.ToLower(o.res.Kind))))
-			if o.conversion {
-				fmt.Println(`Webhook server has been set up for you.
-You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
-			}
-			webhookScaffolder := &webhook.Webhook{
-				Resource:   o.res,
-				Defaulting: o.defaulting,
-				Validating: o.validation,
-			}
-			err = (&scaffold.Scaffold{}).Execute(
-				&model.Universe{},
-				input.Options{},
-				webhookScaffolder,
-			)
-			if err != nil {
-				fmt.Printf("error scaffolding webhook: %v", err)
-				os.Exit(1)
-			}
+	// settings are the settings of the defaulting and validating webhooks
+	settings webhook.Settings
 
-			err = (&scaffoldv2.Main{}).Update(
-				&scaffoldv2.MainUpdateOptions{
-					Project:        &projectInfo,
-					WireResource:   false,
-					WireController: false,
-					WireWebhook:    true,
-					Resource:       o.res,
-				})
-			if err != nil {
-				fmt.Printf("error updating main.go: %v", err)
-				os.Exit(1)
-			}
+	// server are the settings of the webhook server and of its Service to
+	// change
+	server webhook.Server
+}
 
-		},
-	}
+func (o *webhookV2Options) bindCmdFlags(cmd *cobra.Command) {
 	o.res = gvkForFlags(cmd.Flags())
 	cmd.Flags().BoolVar(&o.defaulting, "defaulting", false,
 		"if set, scaffold the defaulting webhook")
@@ -114,14 +103,87 @@ You need to implement the conversion.Hub and conversion.Convertible interfaces f
 		"if set, scaffold the validating webhook")
 	cmd.Flags().BoolVar(&o.conversion, "conversion", false,
 		"if set, scaffold the conversion webhook")
-
-	return cmd
+	cmd.Flags().StringVar(&o.policy, "policy", "", fmt.Sprintf(
+		"if set to %s, scaffold a ValidatingAdmissionPolicy with CEL rules under config/policy instead of "+
+			"the webhooks", policyCEL))
+	cmd.Flags().StringVar(&o.settings.FailurePolicy, "failure-policy", "fail", fmt.Sprintf(
+		"how the API server handles the errors calling the defaulting and validating webhooks, or evaluating "+
+			"the policy, one of %s",
+		strings.Join(webhook.FailurePolicies, ", ")))
+	cmd.Flags().StringVar(&o.settings.SideEffects, "side-effects", "", fmt.Sprintf(
+		"if set, the side effects class of the defaulting and validating webhooks, one of %s",
+		strings.Join(webhook.SideEffectsClasses, ", ")))
+	cmd.Flags().IntVar(&o.settings.TimeoutSeconds, "timeout-seconds", 0, fmt.Sprintf(
+		"if set, how long the API server waits for the defaulting and validating webhooks, "+
+			"at most %d seconds", webhook.MaxTimeoutSeconds))
+	cmd.Flags().IntVar(&o.server.Port, "webhook-port", 0, "if set, the port the webhook server of the manager "+
+		"listens on, changed in main.go, the manager patches and the webhook Service")
+	cmd.Flags().StringVar(&o.server.CertDir, "webhook-cert-dir", "", "if set, the directory the webhook server "+
+		"of the manager reads its certificates from, changed in main.go and the manager patches")
+	cmd.Flags().StringVar(&o.server.ServiceName, "webhook-service-name", "", "if set, the name of the webhook "+
+		"Service, renamed by a patch in config/webhook and replaced by kustomize in the webhook configurations")
+	cmd.Flags().StringVar(&o.server.ServiceNamespace, "webhook-service-namespace", "", "if set, the namespace "+
+		"of the webhook Service, which is the namespace of the manager set in config/default")
 }
 
-// webhookOptions represents commandline options for scaffolding a webhook.
-type webhookV2Options struct {
-	res        *resource.Resource
-	defaulting bool
-	validation bool
-	conversion bool
+// run scaffolds the webhooks, or the admission policy, of the resource
+func (o *webhookV2Options) run() error {
+	if _, err := os.Stat("PROJECT"); os.IsNotExist(err) {
+		return scaffold.ErrProjectNotInitialized
+	}
+
+	projectInfo, err := scaffold.LoadProjectFile("PROJECT")
+	if err != nil {
+		return fmt.Errorf("failed to read the PROJECT file: %v", err)
+	}
+
+	if projectInfo.Version != project.Version2 {
+		return invalidInputf("kubebuilder webhook is for project version: 2, the version of this project is: %s",
+			projectInfo.Version)
+	}
+
+	if o.policy != "" {
+		if o.server.IsSet() {
+			return invalidInputf("the --webhook-* flags do not apply to --policy, which needs no webhook server")
+		}
+		return runPolicy(&projectInfo, o)
+	}
+
+	if !o.defaulting && !o.validation && !o.conversion {
+		return invalidInputf("kubebuilder webhook requires at least one of --defaulting, " +
+			"--programmatic-validation and --conversion to be true")
+	}
+
+	if err := o.settings.Validate(); err != nil {
+		return invalidInput(err)
+	}
+	if o.settings.NeedsPatch() && !o.defaulting && !o.validation {
+		return invalidInputf("--side-effects and --timeout-seconds require --defaulting or --programmatic-validation")
+	}
+	if err := o.server.Validate(); err != nil {
+		return invalidInput(err)
+	}
+
+	if isCoreWebhook(&projectInfo, o.res) {
+		return runCoreWebhook(&projectInfo, o)
+	}
+
+	webhookScaffolder := &scaffold.Webhook{
+		Resource:   o.res,
+		Defaulting: o.defaulting,
+		Validating: o.validation,
+		Conversion: o.conversion,
+		Settings:   o.settings,
+		Server:     o.server,
+	}
+	if err := webhookScaffolder.Validate(); err != nil {
+		return err
+	}
+
+	logging.Infof("Writing scaffold for you to edit...")
+	if err := webhookScaffolder.Scaffold(); err != nil {
+		return err
+	}
+
+	return scaffold.RunHooks("PROJECT", input.HookPhaseCreateWebhook, hookExecutor())
 }
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newWebhookV2Options(t *testing.T, args ...string) *webhookV2Options {
+	o := &webhookV2Options{}
+	cmd := &cobra.Command{}
+	o.bindCmdFlags(cmd)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	return o
+}
+
+func TestWebhookV2ExitCode(t *testing.T) {
+	defer chdirTemp(t)()
+
+	gvk := []string{"--group", "ship", "--version", "v1", "--kind", "Frigate"}
+	err := newWebhookV2Options(t, append(gvk, "--defaulting")...).run()
+	if code := exitCode(err); code != exitCodeProjectNotInitialized {
+		t.Errorf("expected exit code %d outside of a project, got %d", exitCodeProjectNotInitialized, code)
+	}
+
+	scaffoldTestProject(t)
+	tests := [][]string{
+		{},
+		{"--defaulting", "--failure-policy", "retry"},
+		{"--conversion", "--timeout-seconds", "5"},
+		{"--defaulting", "--webhook-port", "8443"},
+		{"--policy", "cel", "--webhook-port", "9444"},
+		{"--policy", "rego"},
+		{"--policy", "cel", "--defaulting"},
+		{"--defaulting", "--kind", "frigate"},
+		{"--conversion", "--group", "apps", "--kind", "Deployment"},
+	}
+	for _, args := range tests {
+		if code := exitCode(newWebhookV2Options(t, append(gvk, args...)...).run()); code != exitCodeInvalidInput {
+			t.Errorf("expected exit code %d for %v, got %d", exitCodeInvalidInput, args, code)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+func newWireCmd() *cobra.Command {
+	s := input.ExternalScheme{}
+
+	cmd := &cobra.Command{
+		Use:   "wire",
+		Short: "Add the types of an API package of another module to the scheme of the manager",
+		Long: `Add the types of an API package of another module to the scheme of the manager,
+e.g. to watch or own the resources of another operator in a controller.
+
+The package is imported in main.go, where its AddToScheme function is called,
+and it is recorded in the PROJECT file. The module of the package must be
+required in go.mod, e.g. with go get.
+
+The package is imported with the name of its group and its version, e.g.
+certmanagerv1alpha2, unless an alias is set.
+`,
+		Example: `	# add the types of cert-manager to the scheme
+	go get github.com/jetstack/cert-manager@v0.11.0
+	kubebuilder alpha wire --package github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dieIfNoProject()
+			p, err := scaffold.LoadProjectFile("PROJECT")
+			if err != nil {
+				fatal(err)
+			}
+			if p.Version != project.Version2 {
+				fatal(invalidInputf("kubebuilder alpha wire is only supported for project version %s", project.Version2))
+			}
+			if s.Alias == "" {
+				s.Alias = scaffold.DefaultSchemeAlias(s.Package)
+			}
+			if err := scaffold.WireScheme(s); err != nil {
+				fatal(fmt.Errorf("error wiring %s: %w", s.Package, err))
+			}
+			logging.Infof("Added %s to the scheme of the manager as %s.", s.Package, s.Alias)
+		},
+	}
+
+	cmd.Flags().StringVar(&s.Package, "package", "", "the Go import path of the API package, e.g. example.com/module/apis/v1")
+	cmd.Flags().StringVar(&s.Alias, "alias", "", "the name the package is imported as in main.go")
+	_ = cmd.MarkFlagRequired("package")
+
+	return cmd
+}
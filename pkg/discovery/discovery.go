@@ -0,0 +1,231 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package discovery finds out how an existing controller-runtime project is
+// laid out, so that kubebuilder can start managing it.
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+const (
+	rootMarker  = "+kubebuilder:object:root=true"
+	groupMarker = "+groupName="
+)
+
+// APIDirs are the directories, relative to the project root, which contain
+// the API types
+var APIDirs = []string{"api", "apis"}
+
+// Project is what was discovered of an existing project
+type Project struct {
+	// Repo is the path of the go module of the project
+	Repo string
+
+	// Domain is the domain of the API groups
+	Domain string
+
+	// Resources are the kinds with a +kubebuilder:object:root marker
+	Resources []input.Resource
+
+	// HasControllers is true if the project has a controllers directory
+	HasControllers bool
+}
+
+// Discover discovers the project rooted at the given directory.
+func Discover(dir string) (*Project, error) {
+	repo, err := modulePath(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	p := &Project{Repo: repo}
+
+	if info, err := os.Stat(filepath.Join(dir, "controllers")); err == nil && info.IsDir() {
+		p.HasControllers = true
+	}
+
+	domains := map[string]bool{}
+	for _, apiDir := range APIDirs {
+		err := filepath.Walk(filepath.Join(dir, apiDir), func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			if err != nil || !info.IsDir() {
+				return err
+			}
+			group, domain, resources, err := discoverPackage(path)
+			if err != nil {
+				return err
+			}
+			if len(resources) == 0 {
+				return nil
+			}
+			if group == "" {
+				return fmt.Errorf("%s has root types but no %s marker", path, groupMarker)
+			}
+			domains[domain] = true
+			p.Resources = append(p.Resources, resources...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(domains) > 1 {
+		found := make([]string, 0, len(domains))
+		for d := range domains {
+			found = append(found, d)
+		}
+		sort.Strings(found)
+		return nil, fmt.Errorf("the API groups use several domains (%s), a project has a single domain",
+			strings.Join(found, ", "))
+	}
+	for d := range domains {
+		p.Domain = d
+	}
+
+	sort.Slice(p.Resources, func(i, j int) bool {
+		a, b := p.Resources[i], p.Resources[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Kind < b.Kind
+	})
+	return p, nil
+}
+
+var moduleRegexp = regexp.MustCompile(`^module\s+"?([^"\s]+)"?`)
+
+// modulePath returns the module path declared in the go.mod file.
+func modulePath(path string) (string, error) {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return "", fmt.Errorf("a go module is required: %v", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		if m := moduleRegexp.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			return m[1], nil
+		}
+	}
+	return "", fmt.Errorf("no module declared in %s", path)
+}
+
+// discoverPackage returns the group and domain of the +groupName marker of
+// the package in the given directory, along with its root types.
+func discoverPackage(dir string) (group, domain string, resources []input.Resource, err error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	version := filepath.Base(dir)
+	for _, pkg := range pkgs {
+		kinds := map[string]bool{}
+		for _, f := range pkg.Files {
+			for _, c := range f.Comments {
+				for _, l := range c.List {
+					text := strings.TrimSpace(strings.TrimPrefix(l.Text, "//"))
+					if strings.HasPrefix(text, groupMarker) {
+						groupName := strings.TrimPrefix(text, groupMarker)
+						parts := strings.SplitN(groupName, ".", 2)
+						group = parts[0]
+						if len(parts) == 2 {
+							domain = parts[1]
+						}
+					}
+				}
+			}
+			for _, kind := range rootTypes(f) {
+				kinds[kind] = true
+			}
+		}
+		for kind := range kinds {
+			// the list of a kind is a root type too
+			if strings.HasSuffix(kind, "List") && kinds[strings.TrimSuffix(kind, "List")] {
+				continue
+			}
+			resources = append(resources, input.Resource{Group: group, Version: version, Kind: kind})
+		}
+	}
+	return group, domain, resources, nil
+}
+
+// rootTypes returns the types of the file marked as root objects.
+func rootTypes(f *ast.File) []string {
+	var kinds []string
+	prevEnd := f.Name.End()
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		after := prevEnd
+		prevEnd = decl.End()
+		if !ok || gen.Tok != token.TYPE || len(gen.Specs) != 1 {
+			continue
+		}
+		if hasRootMarker(f, gen, after) {
+			kinds = append(kinds, gen.Specs[0].(*ast.TypeSpec).Name.Name)
+		}
+	}
+	return kinds
+}
+
+// hasRootMarker returns true if the root marker is in the doc of the type, or
+// in a comment group between it and the previous declaration, which is how
+// kubebuilder scaffolds it.
+func hasRootMarker(f *ast.File, gen *ast.GenDecl, after token.Pos) bool {
+	start := gen.Pos()
+	if gen.Doc != nil {
+		start = gen.Doc.Pos()
+	}
+	groups := []*ast.CommentGroup{gen.Doc}
+	for _, c := range f.Comments {
+		if c.Pos() > after && c.End() < start {
+			groups = append(groups, c)
+		}
+	}
+
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, l := range g.List {
+			if strings.TrimSpace(strings.TrimPrefix(l.Text, "//")) == rootMarker {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+const groupVersionInfo = `// +kubebuilder:object:generate=true
+// +groupName=crew.example.org
+package v1
+`
+
+const types = `package v1
+
+type ShipSpec struct {
+	// +kubebuilder:object:root=true
+	Name string
+}
+
+// +kubebuilder:object:root=true
+
+// Ship is a ship
+type Ship struct{}
+
+// +kubebuilder:object:root=true
+
+// ShipList is a list of ships
+type ShipList struct{}
+
+// Crew is not a root type
+type Crew struct{}
+
+// +kubebuilder:object:root=true
+type Harbor struct{}
+`
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubebuilder-discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                          "module example.org/ships\n\ngo 1.13\n",
+		"api/v1/groupversion_info.go":     groupVersionInfo,
+		"api/v1/ship_types.go":            types,
+		"controllers/ship_controller.go":  "package controllers\n",
+		"api/v1/zz_generated.deepcopy.go": "package v1\n",
+	})
+
+	p, err := Discover(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &Project{
+		Repo:   "example.org/ships",
+		Domain: "example.org",
+		Resources: []input.Resource{
+			{Group: "crew", Version: "v1", Kind: "Harbor"},
+			{Group: "crew", Version: "v1", Kind: "Ship"},
+		},
+		HasControllers: true,
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("expected %+v, got %+v", expected, p)
+	}
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	for name, files := range map[string]map[string]string{
+		"no go module": {
+			"api/v1/groupversion_info.go": groupVersionInfo,
+		},
+		"no group marker": {
+			"go.mod":               "module example.org/ships\n",
+			"api/v1/ship_types.go": types,
+		},
+		"several domains": {
+			"go.mod":                       "module example.org/ships\n",
+			"api/v1/groupversion_info.go":  groupVersionInfo,
+			"api/v1/ship_types.go":         types,
+			"apis/v1/groupversion_info.go": "// +groupName=crew.example.com\npackage v1\n",
+			"apis/v1/ship_types.go":        types,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kubebuilder-discovery")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir) // nolint: errcheck
+
+			writeFiles(t, dir, files)
+			if _, err := Discover(dir); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
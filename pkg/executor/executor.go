@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package executor runs the external commands of the scaffolding commands,
+// e.g. make, go get or the hooks of the project, so that they can be skipped
+// or faked in tests.
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+)
+
+// Executor runs external commands
+type Executor interface {
+	// Run runs the command with the given arguments in the current
+	// directory, its output goes to the output of kubebuilder
+	Run(name string, args ...string) error
+}
+
+// Default is the Executor running the commands with os/exec
+var Default Executor = execExecutor{}
+
+type execExecutor struct{}
+
+// Run implements Executor
+func (execExecutor) Run(name string, args ...string) error {
+	c := exec.Command(name, args...) // #nosec
+	c.Stderr = os.Stderr
+	c.Stdout = os.Stdout
+	return c.Run()
+}
+
+// Skip is an Executor which prints the commands instead of running them
+type Skip struct{}
+
+// Run implements Executor
+func (Skip) Run(name string, args ...string) error {
+	logging.Infof("Skipping: %s", strings.Join(append([]string{name}, args...), " "))
+	return nil
+}
+
+// Fake is an Executor for tests, which records the commands instead of
+// running them
+type Fake struct {
+	// Commands are the commands run, with their arguments
+	Commands [][]string
+
+	// Err is returned by Run, if set
+	Err error
+}
+
+// Run implements Executor
+func (f *Fake) Run(name string, args ...string) error {
+	f.Commands = append(f.Commands, append([]string{name}, args...))
+	return f.Err
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging prints the output of the kubebuilder commands according to
+// the verbosity chosen by the user.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Level is the verbosity of the output
+type Level int
+
+const (
+	// LevelQuiet only prints the warnings and the problems found, errors are
+	// returned to the commands
+	LevelQuiet Level = iota
+	// LevelInfo also prints the progress of the commands, e.g. the files
+	// written and the next steps
+	LevelInfo
+	// LevelDebug also prints the details of what the commands do, e.g. the
+	// commands run and the files skipped
+	LevelDebug
+)
+
+var (
+	level Level = LevelInfo
+
+	out io.Writer = os.Stdout
+	// errOut is kept separate so that the warnings are seen in quiet mode
+	errOut io.Writer = os.Stderr
+
+	// color is only enabled by default when the output is a terminal, see
+	// https://no-color.org for NO_COLOR
+	color = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+)
+
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorNotice = "\033[1;36m"
+	colorReset  = "\033[0m"
+)
+
+// SetLevel sets the verbosity of the output
+func SetLevel(l Level) {
+	level = l
+}
+
+// SetOutput sets the writers of the output and of the warnings
+func SetOutput(stdout, stderr io.Writer) {
+	out, errOut = stdout, stderr
+}
+
+// SetColor enables or disables the colors of the output
+func SetColor(enabled bool) {
+	color = enabled
+}
+
+// Enabled returns true if the messages of the given level are printed
+func Enabled(l Level) bool {
+	return level >= l
+}
+
+// Infof prints the progress of a command
+func Infof(format string, args ...interface{}) {
+	if Enabled(LevelInfo) {
+		fmt.Fprintf(out, format+"\n", args...)
+	}
+}
+
+// Debugf prints the details of what a command does
+func Debugf(format string, args ...interface{}) {
+	if Enabled(LevelDebug) {
+		fmt.Fprintf(out, "DEBUG "+format+"\n", args...)
+	}
+}
+
+// Warnf prints a warning, whatever the level
+func Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(errOut, colorize(colorYellow, "WARNING")+" "+format+"\n", args...)
+}
+
+// Errorf prints a problem found by a command, whatever the level
+func Errorf(format string, args ...interface{}) {
+	fmt.Fprintf(errOut, colorize(colorRed, "ERROR")+" "+format+"\n", args...)
+}
+
+// Highlight returns the message highlighted if colors are enabled, e.g. for
+// notices which must not be missed
+func Highlight(msg string) string {
+	return colorize(colorNotice, msg)
+}
+
+func colorize(c, msg string) string {
+	if !color {
+		return msg
+	}
+	return c + msg + colorReset
+}
+
+// isTerminal returns true if w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	defer SetLevel(LevelInfo)
+	defer SetOutput(out, errOut)
+	defer SetColor(color)
+	SetColor(false)
+
+	tests := []struct {
+		level  Level
+		stdout string
+	}{
+		{level: LevelQuiet, stdout: ""},
+		{level: LevelInfo, stdout: "info\n"},
+		{level: LevelDebug, stdout: "info\nDEBUG debug\n"},
+	}
+	for _, test := range tests {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		SetOutput(stdout, stderr)
+		SetLevel(test.level)
+
+		Infof("info")
+		Debugf("debug")
+		Warnf("warning")
+		Errorf("error")
+
+		if stdout.String() != test.stdout {
+			t.Errorf("level %d: expected output %q, got %q", test.level, test.stdout, stdout.String())
+		}
+		if expected := "WARNING warning\nERROR error\n"; stderr.String() != expected {
+			t.Errorf("level %d: expected warnings and errors %q, got %q", test.level, expected, stderr.String())
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	defer SetOutput(out, errOut)
+	defer SetColor(color)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	SetOutput(stdout, stderr)
+
+	SetColor(true)
+	Warnf("warning")
+	if expected := "\033[33mWARNING\033[0m warning\n"; stderr.String() != expected {
+		t.Errorf("expected colored warning %q, got %q", expected, stderr.String())
+	}
+	stderr.Reset()
+	Errorf("error")
+	if expected := "\033[31mERROR\033[0m error\n"; stderr.String() != expected {
+		t.Errorf("expected colored error %q, got %q", expected, stderr.String())
+	}
+	if expected := "\033[1;36mnotice\033[0m"; Highlight("notice") != expected {
+		t.Errorf("expected highlighted message %q, got %q", expected, Highlight("notice"))
+	}
+
+	SetColor(false)
+	if Highlight("notice") != "notice" {
+		t.Errorf("expected message not to be highlighted, got %q", Highlight("notice"))
+	}
+}
+
+func TestProgress(t *testing.T) {
+	defer SetOutput(out, errOut)
+	defer SetColor(color)
+	SetColor(false)
+
+	tests := []struct {
+		err    error
+		stdout *regexp.Regexp
+	}{
+		{err: nil, stdout: regexp.MustCompile(`^Running make\.\.\.\n✓ Running make done in [0-9.]+m?s\n$`)},
+		{err: errors.New("exit status 2"), stdout: regexp.MustCompile(`^Running make\.\.\.\n✗ Running make failed after [0-9.]+m?s\n$`)},
+	}
+	for _, test := range tests {
+		stdout := &bytes.Buffer{}
+		SetOutput(stdout, &bytes.Buffer{})
+
+		done := StartProgress("Running make")
+		done(test.err)
+
+		if !test.stdout.MatchString(stdout.String()) {
+			t.Errorf("error %v: expected output matching %q, got %q", test.err, test.stdout, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"time"
+)
+
+// Progress reports the progress of the long steps of the commands, e.g.
+// fetching the dependencies or running make
+type Progress interface {
+	// Start reports that the step starts, and returns the function to call
+	// with the result of the step when it ends
+	Start(step string) (done func(err error))
+}
+
+var progress Progress = lineProgress{}
+
+// SetProgress sets how the progress of the long steps is reported
+func SetProgress(p Progress) {
+	progress = p
+}
+
+// StartProgress reports that the step starts, and returns the function to
+// call with the result of the step when it ends
+func StartProgress(step string) (done func(err error)) {
+	return progress.Start(step)
+}
+
+// lineProgress prints a line when a step starts, and one when it ends with
+// the time it took. The output of the step, e.g. the output of make, is
+// printed between them.
+type lineProgress struct{}
+
+// Start implements Progress
+func (lineProgress) Start(step string) func(err error) {
+	Infof("%s...", step)
+	start := time.Now()
+	return func(err error) {
+		elapsed := time.Since(start).Round(100 * time.Millisecond)
+		if err != nil {
+			Infof("%s %s failed after %s", colorize(colorRed, "✗"), step, elapsed)
+			return
+		}
+		Infof("%s %s done in %s", colorize(colorGreen, "✓"), step, elapsed)
+	}
+}
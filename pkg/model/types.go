@@ -11,6 +11,9 @@ type Universe struct {
 	Resource *Resource `json:"resource,omitempty"`
 
 	Files []*File `json:"files,omitempty"`
+
+	// Values are the values shared between the plugins, by key
+	Values map[string]Value `json:"values,omitempty"`
 }
 
 // Resource describes the resource currently being generated
@@ -52,4 +55,7 @@ type File struct {
 	// TODO: Move input.IfExistsAction into model
 	// IfExistsAction determines what to do if the file exists
 	IfExistsAction input.IfExistsAction `json:"ifExistsAction,omitempty"`
+
+	// Static is true for the files written as is, see input.Static
+	Static bool `json:"static,omitempty"`
 }
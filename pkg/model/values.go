@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+)
+
+// Value is a value set by a plugin for the plugins which run after it
+type Value struct {
+	// Owner is the name of the plugin which set the value
+	Owner string `json:"owner"`
+
+	// Data is the value, it must be serializable to JSON
+	Data interface{} `json:"data"`
+}
+
+// ValueConflictError is returned when a plugin sets a value already set by
+// another plugin
+type ValueConflictError struct {
+	Key   string
+	Owner string
+	Other string
+}
+
+func (e *ValueConflictError) Error() string {
+	return fmt.Sprintf("plugin %q cannot set value %q, it is already set by plugin %q", e.Other, e.Key, e.Owner)
+}
+
+// SetValue sets the value of the key on behalf of the owner plugin. A plugin
+// can replace its own values but not the values of other plugins.
+func (u *Universe) SetValue(owner, key string, data interface{}) error {
+	if v, found := u.Values[key]; found && v.Owner != owner {
+		return &ValueConflictError{Key: key, Owner: v.Owner, Other: owner}
+	}
+	if u.Values == nil {
+		u.Values = map[string]Value{}
+	}
+	u.Values[key] = Value{Owner: owner, Data: data}
+	return nil
+}
+
+// GetValue returns the value of the key, if any
+func (u *Universe) GetValue(key string) (interface{}, bool) {
+	v, found := u.Values[key]
+	return v.Data, found
+}
+
+// GetString returns the value of the key, if any, and fails if it is not a
+// string
+func (u *Universe) GetString(key string) (string, bool, error) {
+	data, found := u.GetValue(key)
+	if !found {
+		return "", false, nil
+	}
+	s, ok := data.(string)
+	if !ok {
+		return "", true, fmt.Errorf("value %q is a %T, not a string", key, data)
+	}
+	return s, true, nil
+}
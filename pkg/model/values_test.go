@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestSetValue(t *testing.T) {
+	u := &Universe{}
+
+	if err := u.SetValue("golang", "image", "controller:latest"); err != nil {
+		t.Fatalf("unexpected error setting a new value: %v", err)
+	}
+	if err := u.SetValue("golang", "image", "controller:v1"); err != nil {
+		t.Fatalf("unexpected error replacing a value of the same plugin: %v", err)
+	}
+	err := u.SetValue("kustomize", "image", "other:latest")
+	if _, ok := err.(*ValueConflictError); !ok {
+		t.Fatalf("expected a ValueConflictError setting a value of another plugin, got %v", err)
+	}
+
+	image, found, err := u.GetString("image")
+	if err != nil || !found || image != "controller:v1" {
+		t.Errorf("expected image to be controller:v1, got %q (found: %v, err: %v)", image, found, err)
+	}
+}
+
+func TestGetString(t *testing.T) {
+	u := &Universe{}
+	if err := u.SetValue("golang", "replicas", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, err := u.GetString("missing"); found || err != nil {
+		t.Errorf("expected a missing value not to be found, got found: %v, err: %v", found, err)
+	}
+	if _, found, err := u.GetString("replicas"); !found || err == nil {
+		t.Errorf("expected an error getting a non string value, got found: %v, err: %v", found, err)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugintest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// UpdateGoldenEnv is the environment variable which, when set, makes
+// AssertGolden write the golden files instead of comparing them
+const UpdateGoldenEnv = "KUBEBUILDER_UPDATE_GOLDEN"
+
+// TestingT is the part of testing.T used by AssertGolden, also implemented
+// by ginkgo.GinkgoT()
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// AssertGolden checks that the files, by path, have the contents of the
+// golden files of the same paths under goldenDir, e.g. the Files of a
+// Result. The golden files are written instead if the UpdateGoldenEnv
+// environment variable is set.
+func AssertGolden(t TestingT, goldenDir string, files map[string]string) {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := writeFiles(goldenDir, files); err != nil {
+			t.Fatalf("error updating the golden files: %v", err)
+		}
+		return
+	}
+
+	for _, path := range sortedPaths(files) {
+		golden, err := ioutil.ReadFile(filepath.Join(goldenDir, path)) // nolint: gosec
+		if err != nil {
+			t.Errorf("error reading the golden file of %s, set %s=1 to write it: %v", path, UpdateGoldenEnv, err)
+			continue
+		}
+		if string(golden) != files[path] {
+			t.Errorf("%s does not match its golden file %s, set %s=1 to update it:\n%s",
+				path, filepath.Join(goldenDir, path), UpdateGoldenEnv, files[path])
+		}
+	}
+}
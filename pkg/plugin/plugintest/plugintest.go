@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugintest helps testing the plugins of kubebuilder: it runs a
+// plugin on a fake universe like the scaffolding commands do, in a temporary
+// project directory, and compares the files it produces with golden files.
+package plugintest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// NewProject returns a fake project file of project version 2
+func NewProject(repo, domain string) input.ProjectFile {
+	return input.ProjectFile{Version: project.Version2, Repo: repo, Domain: domain}
+}
+
+// NewResource returns the model of the resource of the given project, like
+// the one passed to the plugins by create api
+func NewResource(p input.ProjectFile, group, version, kind string) (*model.Resource, error) {
+	r := &resource.Resource{Group: group, Version: version, Kind: kind, Namespaced: true}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &model.Resource{
+		Namespaced:  r.Namespaced,
+		Group:       r.Group,
+		Version:     r.Version,
+		Kind:        r.Kind,
+		Resource:    r.Resource,
+		Plural:      r.Plural(),
+		GoPackage:   path.Join(p.Repo, "api"),
+		GroupDomain: resource.QualifiedGroup(group, p.Domain),
+	}, nil
+}
+
+// Runner runs a plugin like the scaffolding commands do: its PreScaffold hook,
+// then Pipe on the universe, then its PostScaffold and UpdateResource hooks.
+// The hooks run in a temporary project directory, which is the working
+// directory while the plugin runs, so the runners of a test binary must not
+// run in parallel.
+type Runner struct {
+	// Plugin is the plugin to run
+	Plugin scaffold.Plugin
+
+	// Project is written as the PROJECT file of the project directory
+	Project input.ProjectFile
+
+	// Resource is the resource of the universe, see NewResource
+	Resource *model.Resource
+
+	// Boilerplate is the boilerplate of the universe
+	Boilerplate string
+
+	// Files are the files scaffolded before the plugin runs, by path
+	Files map[string]string
+
+	// ProjectFiles are the files of the project directory before the plugin
+	// runs, by path, e.g. a Makefile updated by the PostScaffold hook
+	ProjectFiles map[string]string
+}
+
+// Result is the outcome of running a plugin
+type Result struct {
+	// Universe is the universe after the plugin ran
+	Universe *model.Universe
+
+	// Dir is the project directory
+	Dir string
+
+	// Resource is the resource updated by the UpdateResource hook of the
+	// plugin, if it implements it
+	Resource *model.Resource
+}
+
+// Run runs the plugin. The project directory of the returned Result has to
+// be removed with Cleanup, even if Run fails.
+func (r *Runner) Run() (*Result, error) {
+	dir, err := ioutil.TempDir("", "plugintest-")
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Dir: dir, Universe: r.universe()}
+
+	projectFile, err := yaml.Marshal(r.Project)
+	if err != nil {
+		return res, err
+	}
+	files := map[string]string{"PROJECT": string(projectFile)}
+	for p, contents := range r.ProjectFiles {
+		files[p] = contents
+	}
+	if err := writeFiles(dir, files); err != nil {
+		return res, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return res, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return res, err
+	}
+	defer os.Chdir(wd) // nolint: errcheck
+
+	if pre, ok := r.Plugin.(scaffold.PreScaffolder); ok {
+		if err := pre.PreScaffold(res.Universe); err != nil {
+			return res, fmt.Errorf("PreScaffold failed: %v", err)
+		}
+	}
+	if err := r.Plugin.Pipe(res.Universe); err != nil {
+		return res, fmt.Errorf("Pipe failed: %v", err)
+	}
+	if post, ok := r.Plugin.(scaffold.PostScaffolder); ok {
+		if err := post.PostScaffold(res.Universe); err != nil {
+			return res, fmt.Errorf("PostScaffold failed: %v", err)
+		}
+	}
+	if updater, ok := r.Plugin.(scaffold.ResourceUpdater); ok && res.Universe.Resource != nil {
+		updated := *res.Universe.Resource
+		if err := updater.UpdateResource(res.Universe, &updated); err != nil {
+			return res, fmt.Errorf("UpdateResource failed: %v", err)
+		}
+		res.Resource = &updated
+	}
+	return res, nil
+}
+
+// universe returns the universe passed to the plugin, with the files sorted
+// by path
+func (r *Runner) universe() *model.Universe {
+	u := &model.Universe{
+		Boilerplate: r.Boilerplate,
+		Resource:    r.Resource,
+		Values:      map[string]model.Value{},
+	}
+	for _, p := range sortedPaths(r.Files) {
+		u.Files = append(u.Files, &model.File{Path: p, Contents: r.Files[p], IfExistsAction: input.Error})
+	}
+	return u
+}
+
+// Files returns the contents of the files of the universe, by path
+func (r *Result) Files() map[string]string {
+	files := map[string]string{}
+	for _, f := range r.Universe.Files {
+		files[f.Path] = f.Contents
+	}
+	return files
+}
+
+// ReadFile returns the contents of a file of the project directory
+func (r *Result) ReadFile(p string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(r.Dir, p)) // nolint: gosec
+	return string(b), err
+}
+
+// Cleanup removes the project directory
+func (r *Result) Cleanup() error {
+	if r == nil || r.Dir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.Dir)
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	for p, contents := range files {
+		p = filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedPaths(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugintest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// readmePlugin adds a README for the resource, and a target printing it to
+// the Makefile of the project
+type readmePlugin struct {
+	preErr error
+	calls  []string
+}
+
+func (p *readmePlugin) PreScaffold(u *model.Universe) error {
+	p.calls = append(p.calls, "PreScaffold")
+	return p.preErr
+}
+
+func (p *readmePlugin) Pipe(u *model.Universe) error {
+	p.calls = append(p.calls, "Pipe")
+	u.Files = append(u.Files, &model.File{
+		Path:     "docs/" + u.Resource.Plural + ".md",
+		Contents: fmt.Sprintf("# %s\n\nThe %s of %s/%s.\n", u.Resource.Kind, u.Resource.Plural, u.Resource.GroupDomain, u.Resource.Version),
+	})
+	return nil
+}
+
+func (p *readmePlugin) PostScaffold(u *model.Universe) error {
+	p.calls = append(p.calls, "PostScaffold")
+	b, err := ioutil.ReadFile("Makefile")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile("Makefile", append(b, []byte("docs:\n\tcat docs/*.md\n")...), 0644)
+}
+
+func newRunner(t *testing.T, plugin *readmePlugin) *Runner {
+	project := NewProject("example.com/project", "example.com")
+	r, err := NewResource(project, "ship", "v1", "Frigate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Runner{
+		Plugin:       plugin,
+		Project:      project,
+		Resource:     r,
+		Files:        map[string]string{"api/v1/frigate_types.go": "package v1\n"},
+		ProjectFiles: map[string]string{"Makefile": "all: manager\n"},
+	}
+}
+
+func TestRunner(t *testing.T) {
+	plugin := &readmePlugin{}
+	res, err := newRunner(t, plugin).Run()
+	defer res.Cleanup() // nolint: errcheck
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := strings.Join(plugin.calls, ","); calls != "PreScaffold,Pipe,PostScaffold" {
+		t.Errorf("expected the hooks and Pipe to be called in order, got %s", calls)
+	}
+	AssertGolden(t, "testdata/golden", res.Files())
+
+	makefile, err := res.ReadFile("Makefile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if makefile != "all: manager\ndocs:\n\tcat docs/*.md\n" {
+		t.Errorf("unexpected Makefile after PostScaffold:\n%s", makefile)
+	}
+	if _, err := res.ReadFile("PROJECT"); err != nil {
+		t.Errorf("expected the project file to be written: %v", err)
+	}
+	if wd, _ := os.Getwd(); strings.HasPrefix(wd, res.Dir) {
+		t.Errorf("expected the working directory to be restored, got %s", wd)
+	}
+}
+
+func TestRunnerPreScaffoldError(t *testing.T) {
+	plugin := &readmePlugin{preErr: fmt.Errorf("project not supported")}
+	res, err := newRunner(t, plugin).Run()
+	defer res.Cleanup() // nolint: errcheck
+	if err == nil || !strings.Contains(err.Error(), "project not supported") {
+		t.Fatalf("expected the PreScaffold error, got %v", err)
+	}
+	if len(plugin.calls) != 1 {
+		t.Errorf("expected Pipe not to be called after PreScaffold failed, got %v", plugin.calls)
+	}
+}
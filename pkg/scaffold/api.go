@@ -17,12 +17,19 @@ limitations under the License.
 package scaffold
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
+	"sigs.k8s.io/yaml"
 
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
@@ -32,6 +39,46 @@ import (
 	crdv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/crd"
 	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/e2e"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/grafana"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/olm"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/schema"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// APIArtifact is a part of the scaffolding of an API which can be overwritten
+// when scaffolding the API again
+type APIArtifact string
+
+const (
+	// APITypes is the <kind>_types.go file
+	APITypes APIArtifact = "types"
+	// APIGroup is the groupversion_info.go file
+	APIGroup APIArtifact = "group"
+	// APIController is the <kind>_controller.go file
+	APIController APIArtifact = "controller"
+	// APISample is the sample custom resource
+	APISample APIArtifact = "sample"
+	// APIRBAC are the editor and viewer roles of the resource
+	APIRBAC APIArtifact = "rbac"
+	// APICRDPatches are the webhook and CA injection patches of the CRD
+	APICRDPatches APIArtifact = "crd-patches"
+)
+
+// APIArtifacts are all the artifacts which can be overwritten
+var APIArtifacts = []APIArtifact{APITypes, APIGroup, APIController, APISample, APIRBAC, APICRDPatches}
+
+// ImportsStyle is how the imports of the scaffolded Go files are grouped
+type ImportsStyle string
+
+const (
+	// ImportsStyleGoimports groups the standard library imports apart from
+	// the others, like goimports
+	ImportsStyleGoimports ImportsStyle = "goimports"
+	// ImportsStyleLocal also groups the imports of the project apart from the
+	// third-party ones, like goimports -local with the repo of the project
+	ImportsStyleLocal ImportsStyle = "local"
 )
 
 // API contains configuration for generating scaffolding for Go type
@@ -44,14 +91,89 @@ type API struct {
 
 	project *input.ProjectFile
 
+	// values are the plugin values, shared by all the universes of the API so
+	// that plugins can read the values set while scaffolding the other files
+	values map[string]model.Value
+
 	// DoResource indicates whether to scaffold API Resource or not
 	DoResource bool
 
 	// DoController indicates whether to scaffold controller files or not
 	DoController bool
 
-	// Force indicates that the resource should be created even if it already exists.
+	// Force indicates that the resource should be created even if it already
+	// exists, overwriting all its artifacts.
 	Force bool
+
+	// Overwrite are the artifacts to overwrite if they already exist.
+	Overwrite []APIArtifact
+
+	// Watches are the secondary resources owned by the Resource, the
+	// controller watches them and gets the RBAC permissions to manage them
+	Watches []*resource.Resource
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles
+	// of the controller, the controller-runtime default is used if 0
+	MaxConcurrentReconciles int
+
+	// GenerationPredicate filters out the updates of the watched objects which
+	// do not change their generation, e.g. the status updates
+	GenerationPredicate bool
+
+	// Events adds an EventRecorder to the controller, with the typed event
+	// reasons of controllers/events.go, and records an event when the example
+	// reconcile succeeds
+	Events bool
+
+	// Layout is the layout of the package of the controller, defaults to
+	// flat, i.e. the controllers package
+	Layout scaffoldv2.ControllerLayout
+
+	// Schema is the path of an OpenAPI or JSON schema file the Spec and Status
+	// of the types are generated from, instead of an example field
+	Schema string
+
+	// Fields are the fields of the Spec of the types, defined with the syntax
+	// of schema.ParseField, instead of an example field
+	Fields []string
+
+	// schemaTypes are the types generated from Schema or Fields
+	schemaTypes *schema.Types
+
+	// PreserveUnknownFields are the fields of the kind, spec and/or status,
+	// whose unknown fields are not pruned by the API server
+	PreserveUnknownFields []string
+
+	// EmbeddedResources are the names of the object Fields which embed a
+	// Kubernetes object, whose apiVersion, kind and metadata are validated
+	EmbeddedResources []string
+
+	// SampleValues is the path of a YAML file with the values of the spec of
+	// the sample, instead of the example field
+	SampleValues string
+
+	// sampleValues are the values read from SampleValues
+	sampleValues map[string]interface{}
+
+	// Webhooks are the webhooks of the resource to scaffold along with the
+	// API, which are wired in main.go with it. Their resource is the one of
+	// the API.
+	Webhooks *Webhook
+
+	// ImportsStyle changes how the imports of the scaffolded Go files are
+	// grouped, and is recorded in the PROJECT file. The recorded style is
+	// kept if empty.
+	ImportsStyle ImportsStyle
+
+	// GenerateOnly scaffolds only the types of the resource, without its
+	// sample, RBAC roles, e2e test and main.go wiring. The resource is
+	// recorded as such in the PROJECT file until CompleteGenerated
+	// scaffolds the rest.
+	GenerateOnly bool
+
+	// CompleteGenerated scaffolds what GenerateOnly skipped for an existing
+	// resource, along with its controller if DoController
+	CompleteGenerated bool
 }
 
 // Validate validates whether API scaffold has correct bits to generate
@@ -60,17 +182,356 @@ func (api *API) Validate() error {
 	if err := api.setDefaults(); err != nil {
 		return err
 	}
+	if err := api.validate(); err != nil {
+		return invalidInput(err)
+	}
+	return nil
+}
+
+// validate checks the settings of the API against the project, whose errors
+// are invalid inputs
+func (api *API) validate() error {
+	if err := api.validatePlural(); err != nil {
+		return err
+	}
 	if err := api.Resource.Validate(); err != nil {
 		return err
 	}
+	// an empty group is the domain of the project, which v1 projects do not
+	// support and projects without a domain do not have
+	if api.Resource.Group == "" {
+		if api.project.Version != project.Version2 {
+			return fmt.Errorf("group cannot be empty for project version %s", api.project.Version)
+		}
+		if api.project.Domain == "" {
+			return fmt.Errorf("the project has no domain, the group cannot be empty")
+		}
+	}
+	// the API group of a CRD must contain a dot, so the groups of a project
+	// without a domain must be fully qualified
+	if api.project.Domain == "" && api.project.Version == project.Version2 &&
+		!util.IsCoreGroup(api.Resource.Group) && !strings.Contains(api.Resource.Group, ".") {
+		return fmt.Errorf("the project has no domain, the group must be fully qualified, e.g. %s.example.com",
+			api.Resource.Group)
+	}
+
+	for _, artifact := range api.Overwrite {
+		if !artifact.valid() {
+			return fmt.Errorf("unknown artifact %q to overwrite, should be one of %v", artifact, APIArtifacts)
+		}
+	}
+
+	if api.GenerateOnly || api.CompleteGenerated {
+		if err := api.validateGenerateOnly(); err != nil {
+			return err
+		}
+	}
+
+	if api.DoResource && api.resourceExists() && !api.Force && len(api.Overwrite) == 0 && !api.CompleteGenerated {
+		return ErrResourceExists
+	}
+
+	switch api.ImportsStyle {
+	case "", ImportsStyleGoimports, ImportsStyleLocal:
+	default:
+		return fmt.Errorf("unknown imports style %q, should be one of %s, %s",
+			api.ImportsStyle, ImportsStyleGoimports, ImportsStyleLocal)
+	}
+	if api.ImportsStyle != "" && api.project.Version != project.Version2 {
+		return fmt.Errorf("imports styles are only supported for project version %s", project.Version2)
+	}
+
+	r := api.Resource
+	if (len(r.ShortNames) > 0 || len(r.Categories) > 0 || len(r.PrintColumns) > 0) &&
+		api.project.Version != project.Version2 {
+		return fmt.Errorf("short names, categories and printer columns are only supported for project version %s",
+			project.Version2)
+	}
+
+	if len(api.Watches) > 0 && api.project.Version != project.Version2 {
+		return fmt.Errorf("watches are only supported for project version %s", project.Version2)
+	}
+	for _, w := range api.Watches {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid watched resource %s/%s/%s: %v", w.Group, w.Version, w.Kind, err)
+		}
+	}
+
+	if api.MaxConcurrentReconciles < 0 {
+		return fmt.Errorf("max concurrent reconciles must be positive (was %d)", api.MaxConcurrentReconciles)
+	}
+	if (api.MaxConcurrentReconciles > 0 || api.GenerationPredicate || api.Events) &&
+		api.project.Version != project.Version2 {
+		return fmt.Errorf("controller options are only supported for project version %s", project.Version2)
+	}
+
+	if api.Layout == "" {
+		api.Layout = scaffoldv2.ControllerLayoutFlat
+	}
+	if err := api.Layout.Validate(); err != nil {
+		return err
+	}
+	if api.Layout != scaffoldv2.ControllerLayoutFlat && api.project.Version != project.Version2 {
+		return fmt.Errorf("controller layouts are only supported for project version %s", project.Version2)
+	}
+
+	if api.Schema != "" {
+		if !api.DoResource {
+			return fmt.Errorf("generating the types from a schema requires the resource to be generated")
+		}
+		if api.project.Version != project.Version2 {
+			return fmt.Errorf("generating the types from a schema is only supported for project version %s",
+				project.Version2)
+		}
+		doc, err := schema.Load(api.Schema)
+		if err != nil {
+			return err
+		}
+		if api.schemaTypes, err = schema.Generate(api.Resource.Kind, doc); err != nil {
+			return fmt.Errorf("error generating the types from %s: %v", api.Schema, err)
+		}
+		if err := api.checkSchemaTypeNames(); err != nil {
+			return err
+		}
+	}
+
+	if len(api.Fields) > 0 {
+		if !api.DoResource {
+			return fmt.Errorf("generating the fields of the types requires the resource to be generated")
+		}
+		if api.Schema != "" {
+			return fmt.Errorf("the fields of the types cannot be both defined and generated from a schema")
+		}
+		if api.project.Version != project.Version2 {
+			return fmt.Errorf("generating the fields of the types is only supported for project version %s",
+				project.Version2)
+		}
+		if err := api.parseFields(); err != nil {
+			return err
+		}
+	}
+
+	if len(api.PreserveUnknownFields) > 0 || len(api.EmbeddedResources) > 0 {
+		if err := api.validatePruning(); err != nil {
+			return err
+		}
+	}
+
+	if api.SampleValues != "" {
+		if err := api.loadSampleValues(); err != nil {
+			return err
+		}
+	}
+
+	if api.Webhooks != nil {
+		if err := api.validateWebhooks(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSchemaTypeNames checks that the types generated from the schema are not
+// declared by the other files of the API package, e.g. by the types of
+// another kind. The types file of the kind is replaced.
+func (api *API) checkSchemaTypeNames() error {
+	dir := filepath.Join("api", api.Resource.Version)
+	typesFile := fmt.Sprintf("%s_types.go", strings.ToLower(api.Resource.Kind))
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(info os.FileInfo) bool {
+		return info.Name() != typesFile && !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error reading the types of %s: %v", dir, err)
+	}
+
+	generated := map[string]bool{}
+	for _, name := range api.schemaTypes.Names {
+		generated[name] = true
+	}
+	for _, pkg := range pkgs {
+		for path, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					if name := spec.(*ast.TypeSpec).Name.Name; generated[name] {
+						return fmt.Errorf("the type %s generated from %s is already declared in %s", name,
+							api.Schema, path)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateWebhooks checks that the Webhooks can be scaffolded with the
+// resource
+func (api *API) validateWebhooks() error {
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("webhooks are only supported for project version %s", project.Version2)
+	}
+	if !api.DoResource || api.GenerateOnly {
+		return fmt.Errorf("scaffolding the webhooks with the API requires the resource to be generated and " +
+			"wired in main.go")
+	}
+	api.Webhooks.Resource = api.Resource
+	api.Webhooks.project = api.project
+	return api.Webhooks.Validate()
+}
+
+// loadSampleValues reads the values of the spec of the sample
+func (api *API) loadSampleValues() error {
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("the values of the sample are only supported for project version %s", project.Version2)
+	}
+	if !api.DoResource || api.GenerateOnly {
+		return fmt.Errorf("the values of the sample require the sample of the resource to be generated")
+	}
+	b, err := ioutil.ReadFile(api.SampleValues)
+	if err != nil {
+		return fmt.Errorf("error reading the values of the sample: %v", err)
+	}
+	api.sampleValues = map[string]interface{}{}
+	if err := yaml.Unmarshal(b, &api.sampleValues); err != nil {
+		return fmt.Errorf("the values of the sample in %s must be the fields of the spec: %v", api.SampleValues, err)
+	}
+	if len(api.sampleValues) == 0 {
+		return fmt.Errorf("%s has no values for the spec of the sample", api.SampleValues)
+	}
+	return nil
+}
+
+// parseFields generates the types of the Fields
+func (api *API) parseFields() error {
+	fields := make([]*schema.Field, 0, len(api.Fields))
+	for _, f := range api.Fields {
+		field, err := schema.ParseField(f)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, field)
+	}
+	for _, name := range api.EmbeddedResources {
+		found := false
+		for _, f := range fields {
+			if f.Name == name {
+				if err := f.EmbedResource(); err != nil {
+					return err
+				}
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("embedded resource %s is not a field of the Spec", name)
+		}
+	}
+	var err error
+	api.schemaTypes, err = schema.FieldTypes(fields)
+	return err
+}
+
+// addSample adds the sample of the resource to config/samples/kustomization.yaml,
+// which is scaffolded with the samples of the other resources if it does not
+// exist yet.
+func (api *API) addSample() error {
+	var samples []*resource.Resource
+	err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{}, &scaffoldv2.SamplesKustomization{})
+	if err != nil && !isAlreadyExistsError(err) {
+		return fmt.Errorf("error scaffolding the kustomization of the samples: %v", err)
+	}
+	if err == nil {
+		for _, res := range api.project.Resources {
+			r := &resource.Resource{Group: res.Group, Version: res.Version, Kind: res.Kind}
+			if _, err := os.Stat(filepath.Join("config", "samples", r.SampleFileName())); err == nil {
+				samples = append(samples, r)
+			}
+		}
+	}
+	samples = append(samples, api.Resource)
+	for _, r := range samples {
+		if err := (&scaffoldv2.SamplesKustomization{Resource: r}).Update(); err != nil {
+			return fmt.Errorf("error updating config/samples/kustomization.yaml: %v", err)
+		}
+	}
+	return nil
+}
 
-	if api.resourceExists() && !api.Force {
-		return fmt.Errorf("API resource already exists")
+// validateGenerateOnly checks that the types of a resource are scaffolded
+// alone, or that the resource to complete was scaffolded that way.
+func (api *API) validateGenerateOnly() error {
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("generating only the types is only supported for project version %s", project.Version2)
+	}
+	if api.GenerateOnly {
+		if api.CompleteGenerated || !api.DoResource || api.DoController {
+			return fmt.Errorf("generating only the types requires the resource without the controller")
+		}
+		return nil
+	}
+	if !api.DoResource {
+		return fmt.Errorf("completing the API requires the resource to be scaffolded")
+	}
+	res, found := api.trackedResource()
+	if !found {
+		return fmt.Errorf("resource %s/%s/%s not found in the PROJECT file, create it with create api first",
+			api.Resource.Group, api.Resource.Version, api.Resource.Kind)
+	}
+	if !res.GenerateOnly {
+		return fmt.Errorf("the API of %s is already complete, use create api --controller-only to add "+
+			"its controller", api.Resource.Kind)
 	}
+	return nil
+}
 
+// validatePlural checks the plural passed for the resource, or else defaults
+// it to the plural recorded in the project file.
+func (api *API) validatePlural() error {
+	recorded := RecordedPlural(api.project, api.Resource)
+	if api.Resource.Resource == "" {
+		api.Resource.Resource = recorded
+		return nil
+	}
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("the plural can only be set for project version %s", project.Version2)
+	}
+	if recorded == "" {
+		recorded = resource.Pluralize(api.Resource.Kind)
+	}
+	if api.resourceExists() && api.Resource.Resource != recorded {
+		return fmt.Errorf("the resource already exists with the plural %q", recorded)
+	}
 	return nil
 }
 
+func (a APIArtifact) valid() bool {
+	for _, artifact := range APIArtifacts {
+		if a == artifact {
+			return true
+		}
+	}
+	return false
+}
+
+// overwrites returns true if the given artifact must be overwritten
+func (api *API) overwrites(artifact APIArtifact) bool {
+	if api.Force {
+		return true
+	}
+	for _, a := range api.Overwrite {
+		if a == artifact {
+			return true
+		}
+	}
+	return false
+}
+
 func (api *API) setDefaults() error {
 	if api.project == nil {
 		p, err := LoadProjectFile("PROJECT")
@@ -87,14 +548,75 @@ func (api *API) Scaffold() error {
 		return err
 	}
 
+	// the files of the command are written in several steps, which are all
+	// undone if one of them fails
+	snapshot, err := takeSnapshot(".")
+	if err != nil {
+		return err
+	}
+	if err := api.scaffold(); err != nil {
+		return snapshot.restore(err)
+	}
+	return nil
+}
+
+func (api *API) scaffold() error {
+	// the hooks of all the plugins run around the files of the whole
+	// command, which are scaffolded in several steps
+	if err := runPreScaffold(api.Plugins, api.buildUniverse()); err != nil {
+		return err
+	}
+
+	var err error
 	switch ver := api.project.Version; ver {
 	case project.Version1:
-		return api.scaffoldV1()
+		err = api.scaffoldV1()
 	case project.Version2:
-		return api.scaffoldV2()
+		err = api.scaffoldV2()
 	default:
-		return fmt.Errorf("")
+		err = fmt.Errorf("")
 	}
+	if err != nil {
+		return err
+	}
+
+	u := api.buildUniverse()
+	if err := runPostScaffold(api.Plugins, u); err != nil {
+		return err
+	}
+	return api.updateResource(u)
+}
+
+// updateResource merges the updates of the resource by the ResourceUpdater
+// plugins in the Resource, and in the resource tracked in the PROJECT file.
+func (api *API) updateResource(u *model.Universe) error {
+	updated, changed, err := runUpdateResource(api.Plugins, u)
+	if err != nil || !changed {
+		return err
+	}
+	r := api.Resource
+	r.Resource = updated.Resource
+
+	// v1 projects do not track their resources
+	if api.project.Version != project.Version2 || !api.resourceExists() {
+		return nil
+	}
+	plural := ""
+	if r.HasCustomPlural() {
+		plural = r.Resource
+	}
+	p, err := updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+		for i, res := range p.Resources {
+			if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+				p.Resources[i].Plural = plural
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	api.project = p
+	return nil
 }
 
 func (api *API) buildUniverse() *model.Universe {
@@ -104,13 +626,18 @@ func (api *API) buildUniverse() *model.Universe {
 		Version:    api.Resource.Version,
 		Kind:       api.Resource.Kind,
 		Resource:   api.Resource.Resource,
-		Plural:     flect.Pluralize(strings.ToLower(api.Resource.Kind)),
+		Plural:     api.Resource.Plural(),
 	}
 
 	resourceModel.GoPackage, resourceModel.GroupDomain = util.GetResourceInfo(api.Resource, api.project.Repo, api.project.Domain)
 
+	if api.values == nil {
+		api.values = map[string]model.Value{}
+	}
+
 	return &model.Universe{
 		Resource: resourceModel,
+		Values:   api.values,
 	}
 }
 
@@ -118,9 +645,9 @@ func (api *API) scaffoldV1() error {
 	r := api.Resource
 
 	if api.DoResource {
-		fmt.Println(filepath.Join("pkg", "apis", r.Group, r.Version,
+		logging.Infof("%s", filepath.Join("pkg", "apis", r.Group, r.Version,
 			fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))))
-		fmt.Println(filepath.Join("pkg", "apis", r.Group, r.Version,
+		logging.Infof("%s", filepath.Join("pkg", "apis", r.Group, r.Version,
 			fmt.Sprintf("%s_types_test.go", strings.ToLower(r.Kind))))
 
 		err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{},
@@ -145,9 +672,9 @@ func (api *API) scaffoldV1() error {
 	}
 
 	if api.DoController {
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
+		logging.Infof("%s", filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
 			fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))))
-		fmt.Println(filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
+		logging.Infof("%s", filepath.Join("pkg", "controller", strings.ToLower(r.Kind),
 			fmt.Sprintf("%s_controller_test.go", strings.ToLower(r.Kind))))
 
 		err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{},
@@ -167,12 +694,24 @@ func (api *API) scaffoldV1() error {
 func (api *API) scaffoldV2() error {
 	r := api.Resource
 
+	if err := api.recordImportsStyle(); err != nil {
+		return err
+	}
+
+	exists := api.resourceExists()
+	// when scaffolding an existing resource again, only the artifacts to
+	// overwrite replace the existing files
+	rescaffold := exists && (api.Force || len(api.Overwrite) > 0 || api.CompleteGenerated)
+	// the version of the kind the new version is converted to, if the kind
+	// already has other versions
+	hub := api.hubVersion()
+
 	if api.DoResource {
 		if err := api.validateResourceGroup(r); err != nil {
 			return err
 		}
 
-		fmt.Println(filepath.Join("api", r.Version,
+		logging.Infof("%s", filepath.Join("api", r.Version,
 			fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))))
 
 		files := []input.File{
@@ -180,46 +719,116 @@ func (api *API) scaffoldV2() error {
 				Input: input.Input{
 					Path: filepath.Join("api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind))),
 				},
-				Resource: r},
-			&scaffoldv2.Group{Resource: r},
-			&scaffoldv2.CRDSample{Resource: r},
-			&scaffoldv2.CRDEditorRole{Resource: r},
-			&scaffoldv2.CRDViewerRole{Resource: r},
-			&crdv2.EnableWebhookPatch{Resource: r},
-			&crdv2.EnableCAInjectionPatch{Resource: r},
+				Resource:              r,
+				Force:                 api.overwrites(APITypes),
+				Schema:                api.schemaTypes,
+				PreserveUnknownFields: api.PreserveUnknownFields},
+			&scaffoldv2.Group{Resource: r, Force: api.overwrites(APIGroup), CodeGenerators: codeGeneratorsEnabled()},
+			&crdv2.EnableWebhookPatch{Resource: r, Force: api.overwrites(APICRDPatches)},
+			&crdv2.EnableCAInjectionPatch{Resource: r, Force: api.overwrites(APICRDPatches)},
+		}
+		if !api.GenerateOnly {
+			files = append(files,
+				&scaffoldv2.CRDSample{Resource: r, Force: api.overwrites(APISample), Values: api.sampleValues,
+					ExampleField: api.schemaTypes == nil},
+				&scaffoldv2.CRDEditorRole{Resource: r, Force: api.overwrites(APIRBAC)},
+				&scaffoldv2.CRDViewerRole{Resource: r, Force: api.overwrites(APIRBAC)},
+			)
 		}
 
 		scaffold := &Scaffold{
-			Plugins: api.Plugins,
+			Plugins:      api.Plugins,
+			SkipExisting: rescaffold,
 		}
 
 		if err := scaffold.Execute(api.buildUniverse(), input.Options{}, files...); err != nil {
 			return fmt.Errorf("error scaffolding APIs: %v", err)
 		}
 
+		// the kustomization files are scaffolded separately, as either of them
+		// may exist already
 		crdKustomization := &crdv2.Kustomization{Resource: r}
-		err := (&Scaffold{}).Execute(api.buildUniverse(),
-			input.Options{},
-			crdKustomization,
-			&crdv2.KustomizeConfig{},
-		)
-		if err != nil && !isAlreadyExistsError(err) {
-			return fmt.Errorf("error scaffolding kustomization: %v", err)
+		for _, f := range []input.File{crdKustomization, &crdv2.KustomizeConfig{}} {
+			err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{}, f)
+			if err != nil && !isAlreadyExistsError(err) {
+				return fmt.Errorf("error scaffolding kustomization: %v", err)
+			}
 		}
 
-		err = crdKustomization.Update()
+		err := crdKustomization.Update()
 		if err != nil {
 			return fmt.Errorf("error updating kustomization.yaml: %v", err)
 		}
 
-		if !api.resourceExists() {
+		if !api.GenerateOnly {
+			if err := api.addSample(); err != nil {
+				return err
+			}
+		}
+
+		if !exists && hub != "" {
+			if err := api.scaffoldConversion(hub); err != nil {
+				return err
+			}
+		}
+
+		if olmEnabled() {
+			csv := &olm.CSV{Input: input.Input{Domain: api.project.Domain}, Resource: r}
+			if err := csv.Update(); err != nil {
+				return fmt.Errorf("error updating the ClusterServiceVersion: %v", err)
+			}
+		}
+
+		if apiDocsEnabled() {
+			if err := docs.AddAPIPackage("Makefile", r); err != nil {
+				return fmt.Errorf("error updating the API docs packages of the Makefile: %v", err)
+			}
+		}
+
+		if e2eEnabled() && !api.GenerateOnly {
+			resourceTest := &e2e.ResourceTest{Resource: r}
+			logging.Infof("%s", filepath.Join(e2e.Dir, fmt.Sprintf("%s_test.go", strings.ToLower(r.Kind))))
+			if err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{}, resourceTest); err != nil && !isAlreadyExistsError(err) {
+				return fmt.Errorf("error scaffolding e2e test: %v", err)
+			}
+		}
+
+		if !exists {
 			// update scaffolded resource in project file
-			api.project.Resources = append(api.project.Resources,
-				input.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind})
-			err = saveProjectFile("PROJECT", api.project)
+			p, err := updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+				res := input.Resource{Group: r.Group, Version: r.Version, Kind: r.Kind, GenerateOnly: api.GenerateOnly}
+				if r.HasCustomPlural() {
+					res.Plural = r.Resource
+				}
+				p.Resources = append(p.Resources, res)
+				if hub != "" {
+					markStorageVersion(p, r.Group, r.Kind, hub)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			api.project = p
+		}
+
+		if len(api.Fields) > 0 || len(api.PreserveUnknownFields) > 0 {
+			if err := api.recordFields(); err != nil {
+				return err
+			}
+		}
+
+		if api.CompleteGenerated {
+			p, err := updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+				for i, res := range p.Resources {
+					if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+						p.Resources[i].GenerateOnly = false
+					}
+				}
+			})
 			if err != nil {
-				fmt.Printf("error updating project file with resource information : %v \n", err)
+				return err
 			}
+			api.project = p
 		}
 
 	} else {
@@ -231,19 +840,42 @@ func (api *API) scaffoldV2() error {
 	}
 
 	if api.DoController {
-		fmt.Println(filepath.Join("controllers", fmt.Sprintf("%s_controller.go", strings.ToLower(r.Kind))))
+		logging.Infof("%s", api.Layout.ControllerPath(r.Kind))
 
 		scaffold := &Scaffold{
-			Plugins: api.Plugins,
+			Plugins:      api.Plugins,
+			SkipExisting: rescaffold,
 		}
 
-		ctrlScaffolder := &scaffoldv2.Controller{Resource: r}
-		testsuiteScaffolder := &scaffoldv2.ControllerSuiteTest{Resource: r}
+		ctrlScaffolder := &scaffoldv2.Controller{
+			Resource: r,
+			Force:    api.overwrites(APIController),
+			Watches:  api.Watches,
+
+			MaxConcurrentReconciles: api.MaxConcurrentReconciles,
+			GenerationPredicate:     api.GenerationPredicate,
+			Events:                  api.Events,
+			Layout:                  api.Layout,
+		}
+		testsuiteScaffolder := &scaffoldv2.ControllerSuiteTest{Resource: r, EnvtestAssets: envtestEnabled(),
+			Layout: api.Layout}
+		files := []input.File{testsuiteScaffolder, ctrlScaffolder}
+		if api.Layout == scaffoldv2.ControllerLayoutPerKind {
+			files = append(files,
+				&scaffoldv2.ControllerHelpers{Resource: r},
+				&scaffoldv2.ControllerTest{Resource: r, Events: api.Events},
+			)
+		}
+		if api.Events {
+			files = append(files, &scaffoldv2.Events{
+				Input:   input.Input{Path: filepath.Join(api.Layout.Dir(r.Kind), "events.go")},
+				Package: api.Layout.Package(r.Kind),
+			})
+		}
 		err := scaffold.Execute(
 			api.buildUniverse(),
 			input.Options{},
-			testsuiteScaffolder,
-			ctrlScaffolder,
+			files...,
 		)
 		if err != nil {
 			return fmt.Errorf("error scaffolding controller: %v", err)
@@ -251,16 +883,41 @@ func (api *API) scaffoldV2() error {
 
 		err = testsuiteScaffolder.Update()
 		if err != nil {
-			return fmt.Errorf("error updating suite_test.go under controllers pkg: %v", err)
+			return fmt.Errorf("error updating %s: %v", testsuiteScaffolder.Path, err)
+		}
+
+		// the Dockerfile only copies the controllers package when the project
+		// is initialized
+		if api.Layout == scaffoldv2.ControllerLayoutPerKind {
+			if err := scaffoldv2.AddDockerfileSource("Dockerfile", "internal"); err != nil {
+				logging.Warnf("error adding the internal directory to the Dockerfile: %v", err)
+			}
+		}
+
+		if grafanaEnabled() {
+			dashboard := &grafana.ControllerDashboard{Resource: r}
+			logging.Infof("%s", filepath.Join(grafana.Dir, fmt.Sprintf("%s_controller.json", strings.ToLower(r.Kind))))
+			if err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{}, dashboard); err != nil && !isAlreadyExistsError(err) {
+				return fmt.Errorf("error scaffolding grafana dashboard: %v", err)
+			}
+		}
+	}
+
+	if api.Webhooks != nil {
+		if err := api.Webhooks.scaffoldFiles(&Scaffold{Plugins: api.Plugins}, api.buildUniverse()); err != nil {
+			return err
 		}
 	}
 
 	err := (&scaffoldv2.Main{}).Update(
 		&scaffoldv2.MainUpdateOptions{
 			Project:        api.project,
-			WireResource:   api.DoResource,
+			WireResource:   api.DoResource && !api.GenerateOnly,
 			WireController: api.DoController,
+			WireWebhook:    api.Webhooks != nil,
 			Resource:       r,
+			Events:         api.Events,
+			Layout:         api.Layout,
 		})
 	if err != nil {
 		return fmt.Errorf("error updating main.go: %v", err)
@@ -269,6 +926,29 @@ func (api *API) scaffoldV2() error {
 	return nil
 }
 
+// recordImportsStyle records the local prefix of the imports style in the
+// PROJECT file, which the scaffolded Go files are formatted with.
+func (api *API) recordImportsStyle() error {
+	prefix := ""
+	switch api.ImportsStyle {
+	case "":
+		return nil
+	case ImportsStyleLocal:
+		prefix = api.project.Repo
+	}
+	if prefix == api.project.ImportsLocalPrefix {
+		return nil
+	}
+	p, err := updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+		p.ImportsLocalPrefix = prefix
+	})
+	if err != nil {
+		return err
+	}
+	api.project = p
+	return nil
+}
+
 // Since we support single group only in v2 scaffolding, validate if resource
 // being created belongs to existing group.
 func (api *API) validateResourceGroup(r *resource.Resource) error {
@@ -283,13 +963,81 @@ func (api *API) validateResourceGroup(r *resource.Resource) error {
 // resourceExists returns true if API resource is already tracked by the PROJECT file.
 // Note that this works only for v2, since in v1 resources are not tracked by the PROJECT file.
 func (api *API) resourceExists() bool {
+	_, found := api.trackedResource()
+	return found
+}
+
+// trackedResource returns the Resource recorded in the project file, if any
+func (api *API) trackedResource() (input.Resource, bool) {
 	for _, resource := range api.project.Resources {
 		if resource.Group == api.Resource.Group &&
 			resource.Version == api.Resource.Version &&
 			resource.Kind == api.Resource.Kind {
-			return true
+			return resource, true
 		}
 	}
 
-	return false
+	return input.Resource{}, false
+}
+
+// RecordedPlural returns the plural recorded in the project file for the
+// resource, if the resource does not use the plural computed from its kind.
+func RecordedPlural(p *input.ProjectFile, r *resource.Resource) string {
+	for _, res := range p.Resources {
+		if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+			return res.Plural
+		}
+	}
+	return ""
+}
+
+// grafanaEnabled returns true if the project was initialized with the Grafana
+// dashboards, i.e. with the --with-grafana flag.
+func grafanaEnabled() bool {
+	_, err := os.Stat(grafana.Dir)
+	return err == nil
+}
+
+// bootstrapCertsEnabled returns true if the webhook certificates are generated
+// by an init container of the manager, i.e. if the project was initialized
+// with --cert-source webhook-bootstrap.
+func bootstrapCertsEnabled() bool {
+	_, err := os.Stat(webhook.BootstrapRBACPath)
+	return err == nil
+}
+
+// e2eEnabled returns true if the project was initialized with the e2e test
+// suite, i.e. with the --with-e2e flag.
+func e2eEnabled() bool {
+	_, err := os.Stat(e2e.Dir)
+	return err == nil
+}
+
+// olmEnabled returns true if the project was initialized with an OLM bundle,
+// i.e. with the --with-olm flag.
+func olmEnabled() bool {
+	_, err := os.Stat(olm.Dir)
+	return err == nil
+}
+
+// codeGeneratorsEnabled returns true if the Makefile runs conversion-gen and
+// defaulter-gen, i.e. if the project was initialized with the
+// --with-code-generators flag.
+func codeGeneratorsEnabled() bool {
+	b, err := ioutil.ReadFile("Makefile")
+	return err == nil && bytes.Contains(b, []byte("\nconversion-gen:"))
+}
+
+// apiDocsEnabled returns true if the Makefile generates the API reference docs,
+// i.e. if the project was initialized with the --with-api-docs flag.
+func apiDocsEnabled() bool {
+	b, err := ioutil.ReadFile("Makefile")
+	return err == nil && bytes.Contains(b, []byte("\ndocsgen:"))
+}
+
+// envtestEnabled returns true if the Makefile downloads the envtest binaries,
+// i.e. if the project was initialized with the --envtest-k8s-version flag.
+func envtestEnabled() bool {
+	b, err := ioutil.ReadFile("Makefile")
+	return err == nil && bytes.Contains(b, []byte("\nsetup-envtest:"))
 }
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// k8sCompatibility is an entry of the compatibility table of the Kubernetes
+// releases and the versions of the dependencies of the projects
+type k8sCompatibility struct {
+	// k8sVersion is the Kubernetes release, e.g. 1.16
+	k8sVersion string
+
+	// controllerRuntimeVersion is the controller-runtime version required in
+	// go.mod
+	controllerRuntimeVersion string
+
+	// k8sModulesVersion is the version of the k8s.io modules required in
+	// go.mod, e.g. k8s.io/api, and of code-generator
+	k8sModulesVersion string
+
+	// controllerToolsVersion is the version of controller-gen
+	controllerToolsVersion string
+
+	// envtestVersion is the Kubernetes version of the envtest binaries
+	envtestVersion string
+}
+
+// k8sCompatibilities is the compatibility table, from the newest Kubernetes
+// release to the oldest. Like the Go compatibility table, an entry is only
+// added once the templates support its controller-runtime version.
+var k8sCompatibilities = []k8sCompatibility{
+	{
+		k8sVersion:               "1.17",
+		controllerRuntimeVersion: "v0.5.0",
+		k8sModulesVersion:        "v0.17.2",
+		controllerToolsVersion:   "v0.2.5",
+		envtestVersion:           "1.17.9",
+	},
+	{
+		k8sVersion:               "1.16",
+		controllerRuntimeVersion: "v0.4.0",
+		k8sModulesVersion:        codeGeneratorVersion,
+		controllerToolsVersion:   controllerToolsVersion,
+		envtestVersion:           "1.16.4",
+	},
+}
+
+// K8sVersions returns the Kubernetes releases the dependencies of the
+// projects can be bumped to, from the newest to the oldest
+func K8sVersions() []string {
+	versions := make([]string, 0, len(k8sCompatibilities))
+	for _, c := range k8sCompatibilities {
+		versions = append(versions, c.k8sVersion)
+	}
+	return versions
+}
+
+// k8sModules are the k8s.io modules of the Kubernetes release of the projects
+var k8sModules = []string{
+	"k8s.io/api",
+	"k8s.io/apiextensions-apiserver",
+	"k8s.io/apimachinery",
+	"k8s.io/client-go",
+	"k8s.io/code-generator",
+}
+
+// BumpDeps updates the dependencies of the project in the current directory
+// to the versions of the given Kubernetes release, all together: the
+// requirements of go.mod and of the tools module in hack/tools, the versions
+// of the tools installed by the Makefile and the version of its envtest
+// binaries. It returns the paths of the files it changed, go.sum is left to
+// go mod tidy.
+func BumpDeps(k8sVersion string) ([]string, error) {
+	var c *k8sCompatibility
+	for i := range k8sCompatibilities {
+		if k8sCompatibilities[i].k8sVersion == k8sVersion {
+			c = &k8sCompatibilities[i]
+		}
+	}
+	if c == nil {
+		return nil, fmt.Errorf("unsupported Kubernetes version %s, must be one of %s", k8sVersion,
+			strings.Join(K8sVersions(), ", "))
+	}
+
+	modules := map[string]string{"sigs.k8s.io/controller-runtime": c.controllerRuntimeVersion}
+	for _, m := range k8sModules {
+		modules[m] = c.k8sModulesVersion
+	}
+	tools := map[string]string{
+		"sigs.k8s.io/controller-tools": c.controllerToolsVersion,
+		"k8s.io/code-generator":        c.k8sModulesVersion,
+	}
+	makefile := []versionRewrite{
+		{regexp.MustCompile(`(controller-tools/cmd/controller-gen@)\S+`), c.controllerToolsVersion},
+		{regexp.MustCompile(`(code-generator/cmd/(?:conversion|defaulter)-gen@)\S+`), c.k8sModulesVersion},
+		{regexp.MustCompile(`(?m)^(ENVTEST_K8S_VERSION \?= )\S+`), c.envtestVersion},
+	}
+
+	// the files are read and rewritten before any of them is written, so
+	// that a failure leaves the versions as they were
+	type rewritten struct {
+		path    string
+		content []byte
+		mode    os.FileMode
+	}
+	var files []rewritten
+	for _, f := range []struct {
+		path     string
+		rewrites []versionRewrite
+	}{
+		{"go.mod", moduleRewrites(modules)},
+		{filepath.Join("hack", "tools", "go.mod"), moduleRewrites(tools)},
+		{"Makefile", makefile},
+	} {
+		info, err := os.Stat(f.path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadFile(f.path) // nolint: gosec
+		if err != nil {
+			return nil, err
+		}
+		content := string(b)
+		for _, r := range f.rewrites {
+			content = r.re.ReplaceAllString(content, "${1}"+r.version)
+		}
+		if content != string(b) {
+			files = append(files, rewritten{path: f.path, content: []byte(content), mode: info.Mode().Perm()})
+		}
+	}
+
+	var changed []string
+	for _, f := range files {
+		if err := ioutil.WriteFile(f.path, f.content, f.mode); err != nil {
+			return changed, fmt.Errorf("error updating the versions of %s: %v", f.path, err)
+		}
+		changed = append(changed, f.path)
+	}
+	return changed, nil
+}
+
+// versionRewrite replaces the version following the first group of the
+// matches of its regexp
+type versionRewrite struct {
+	re      *regexp.Regexp
+	version string
+}
+
+// moduleRewrites returns the rewrites of the versions of the modules in a
+// go.mod, in the require and replace directives
+func moduleRewrites(modules map[string]string) []versionRewrite {
+	var rewrites []versionRewrite
+	for m, v := range modules {
+		rewrites = append(rewrites, versionRewrite{
+			re:      regexp.MustCompile(`(?m)((?:^|\s)` + regexp.QuoteMeta(m) + `\s+)v\S+`),
+			version: v,
+		})
+	}
+	return rewrites
+}
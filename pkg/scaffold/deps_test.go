@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BumpDeps", func() {
+	var dir, wd string
+
+	const goMod = `module example.com/proj
+
+go 1.13
+
+require (
+	k8s.io/api v0.0.0-20190918155943-95b840bb6a1f
+	k8s.io/apimachinery v0.15.7
+	sigs.k8s.io/controller-runtime v0.2.2
+)
+
+replace k8s.io/client-go => k8s.io/client-go v0.15.7
+`
+	const makefile = `ENVTEST_K8S_VERSION ?= 1.15.5
+
+controller-gen:
+	go get sigs.k8s.io/controller-tools/cmd/controller-gen@v0.2.0 ;\
+`
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-deps")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("go.mod", []byte(goMod), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile("Makefile", []byte(makefile), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should update the versions of go.mod and the Makefile together", func() {
+		changed, err := BumpDeps("1.16")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(Equal([]string{"go.mod", "Makefile"}))
+
+		Expect(ioutil.ReadFile("go.mod")).To(Equal([]byte(`module example.com/proj
+
+go 1.13
+
+require (
+	k8s.io/api v0.16.4
+	k8s.io/apimachinery v0.16.4
+	sigs.k8s.io/controller-runtime v0.4.0
+)
+
+replace k8s.io/client-go => k8s.io/client-go v0.16.4
+`)))
+		Expect(ioutil.ReadFile("Makefile")).To(Equal([]byte(`ENVTEST_K8S_VERSION ?= 1.16.4
+
+controller-gen:
+	go get sigs.k8s.io/controller-tools/cmd/controller-gen@v0.2.4 ;\
+`)))
+
+		changed, err = BumpDeps("1.16")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeEmpty())
+	})
+
+	It("should update the versions of a project scaffolded for the oldest release", func() {
+		_, err := BumpDeps("1.16")
+		Expect(err).NotTo(HaveOccurred())
+
+		changed, err := BumpDeps("1.17")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(Equal([]string{"go.mod", "Makefile"}))
+		Expect(ioutil.ReadFile("go.mod")).To(ContainSubstring("\tsigs.k8s.io/controller-runtime v0.5.0\n"))
+		Expect(ioutil.ReadFile("Makefile")).To(Equal([]byte(`ENVTEST_K8S_VERSION ?= 1.17.9
+
+controller-gen:
+	go get sigs.k8s.io/controller-tools/cmd/controller-gen@v0.2.5 ;\
+`)))
+	})
+
+	It("should fail for the unsupported Kubernetes versions", func() {
+		_, err := BumpDeps("1.10")
+		Expect(err).To(MatchError(ContainSubstring("unsupported Kubernetes version 1.10")))
+		Expect(ioutil.ReadFile("go.mod")).To(Equal([]byte(goMod)))
+	})
+})
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The errors of the scaffolding commands which callers may handle, e.g. by
+// exiting with a specific code. They may be wrapped, so they have to be
+// checked with errors.Is.
+var (
+	// ErrProjectNotInitialized is returned when a command requiring a
+	// project is run outside of one
+	ErrProjectNotInitialized = errors.New("no PROJECT file found, the command must be run in a project " +
+		"initialized with kubebuilder init")
+
+	// ErrProjectExists is returned when initializing a project which is
+	// already initialized
+	ErrProjectExists = errors.New("project is already initialized")
+
+	// ErrResourceExists is returned when creating an API whose resource
+	// already exists, without overwriting it
+	ErrResourceExists = errors.New("API resource already exists")
+
+	// ErrUnknownPattern is returned when creating an API with a pattern which
+	// is not registered
+	ErrUnknownPattern = errors.New("unknown pattern")
+
+	// ErrInvalidInput is returned when the flags or the arguments of a
+	// command are invalid or conflict with each other
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// invalidInput wraps err as an ErrInvalidInput error, unless it is already
+// one of the errors above
+func invalidInput(err error) error {
+	for _, e := range []error{ErrProjectNotInitialized, ErrProjectExists, ErrResourceExists, ErrUnknownPattern,
+		ErrInvalidInput} {
+		if errors.Is(err, e) {
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+// FieldsPluginKey is the key of the plugins section of the PROJECT file the
+// fields of the Spec of the types are recorded under
+const FieldsPluginKey = "fields.kubebuilder.io"
+
+// FieldsConfig is the configuration recorded under FieldsPluginKey
+type FieldsConfig struct {
+	Resources []ResourceFields `json:"resources,omitempty"`
+}
+
+// ResourceFields are the fields of the Spec of the types of a resource, with
+// the syntax of create api --field, and their pruning options
+type ResourceFields struct {
+	Group   string   `json:"group"`
+	Version string   `json:"version"`
+	Kind    string   `json:"kind"`
+	Fields  []string `json:"fields,omitempty"`
+
+	// PreserveUnknownFields and EmbeddedResources are those of create api
+	PreserveUnknownFields []string `json:"preserveUnknownFields,omitempty"`
+	EmbeddedResources     []string `json:"embeddedResources,omitempty"`
+}
+
+// RecordedFields returns the fields of the resource recorded in the project
+// file, if any
+func RecordedFields(p input.ProjectFile, r *resource.Resource) []string {
+	return RecordedResourceFields(p, r).Fields
+}
+
+// RecordedResourceFields returns the fields of the resource and their pruning
+// options recorded in the project file, which are empty if none is
+func RecordedResourceFields(p input.ProjectFile, r *resource.Resource) ResourceFields {
+	cfg := FieldsConfig{}
+	if err := p.DecodePluginConfig(FieldsPluginKey, &cfg); err != nil {
+		return ResourceFields{}
+	}
+	for _, res := range cfg.Resources {
+		if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+			return res
+		}
+	}
+	return ResourceFields{}
+}
+
+// recordFields records the Fields of the resource and their pruning options in
+// the PROJECT file, replacing the fields recorded for it before
+func (api *API) recordFields() error {
+	r := api.Resource
+	var encodeErr error
+	p, err := updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+		cfg := FieldsConfig{}
+		if err := p.DecodePluginConfig(FieldsPluginKey, &cfg); err != nil {
+			if _, notFound := err.(input.PluginKeyNotFoundError); !notFound {
+				encodeErr = err
+				return
+			}
+		}
+		updated := ResourceFields{Group: r.Group, Version: r.Version, Kind: r.Kind, Fields: api.Fields,
+			PreserveUnknownFields: api.PreserveUnknownFields, EmbeddedResources: api.EmbeddedResources}
+		found := false
+		for i, res := range cfg.Resources {
+			if res.Group == r.Group && res.Version == r.Version && res.Kind == r.Kind {
+				cfg.Resources[i], found = updated, true
+			}
+		}
+		if !found {
+			cfg.Resources = append(cfg.Resources, updated)
+		}
+		encodeErr = p.EncodePluginConfig(FieldsPluginKey, cfg)
+	})
+	if err != nil {
+		return err
+	}
+	if encodeErr != nil {
+		return encodeErr
+	}
+	api.project = p
+	return nil
+}
+
+// RegenerateTypes scaffolds the types file of the resource again with the
+// given fields, defined with the syntax of create api --field, and records
+// them in the PROJECT file. It is used once the API is scaffolded, when its
+// fields are entered interactively.
+func (api *API) RegenerateTypes(fields []string) error {
+	if err := api.setDefaults(); err != nil {
+		return err
+	}
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("generating the fields of the types is only supported for project version %s",
+			project.Version2)
+	}
+	if !api.DoResource {
+		return fmt.Errorf("generating the fields of the types requires the resource to be generated")
+	}
+	api.Fields = fields
+	if err := api.parseFields(); err != nil {
+		return err
+	}
+
+	r := api.Resource
+	path := filepath.Join("api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
+	logging.Infof("%s", path)
+	err := (&Scaffold{Plugins: api.Plugins}).Execute(api.buildUniverse(), input.Options{},
+		&scaffoldv2.Types{
+			Input:                 input.Input{Path: path},
+			Resource:              r,
+			Force:                 true,
+			Schema:                api.schemaTypes,
+			PreserveUnknownFields: api.PreserveUnknownFields,
+		})
+	if err != nil {
+		return fmt.Errorf("error scaffolding the types: %v", err)
+	}
+	return api.recordFields()
+}
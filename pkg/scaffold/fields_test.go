@@ -0,0 +1,95 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ = Describe("Recorded fields", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-fields")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("PROJECT", []byte("version: \"2\"\nrepo: example.com/proj\n"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	record := func(r *resource.Resource, fields ...string) {
+		api := &API{Resource: r, Fields: fields}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.recordFields()).To(Succeed())
+	}
+
+	It("should record the fields of each resource", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		destroyer := &resource.Resource{Group: "ship", Version: "v1", Kind: "Destroyer"}
+		record(frigate, "Replicas:int32:min=1", "Image:string:required")
+		record(destroyer, "Guns:int32")
+
+		p, err := LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(RecordedFields(p, frigate)).To(Equal([]string{"Replicas:int32:min=1", "Image:string:required"}))
+		Expect(RecordedFields(p, destroyer)).To(Equal([]string{"Guns:int32"}))
+		Expect(RecordedFields(p, &resource.Resource{Group: "ship", Version: "v2", Kind: "Frigate"})).To(BeEmpty())
+	})
+
+	It("should replace the fields recorded for the resource", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		record(frigate, "Replicas:int32")
+		record(frigate, "Image:string")
+
+		p, err := LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(RecordedFields(p, frigate)).To(Equal([]string{"Image:string"}))
+	})
+
+	It("should record the pruning options of the fields", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		api := &API{Resource: frigate, Fields: []string{"Template:object"}, PreserveUnknownFields: []string{"status"},
+			EmbeddedResources: []string{"Template"}}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.recordFields()).To(Succeed())
+
+		p, err := LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(RecordedResourceFields(p, frigate)).To(Equal(ResourceFields{Group: "ship", Version: "v1",
+			Kind: "Frigate", Fields: []string{"Template:object"}, PreserveUnknownFields: []string{"status"},
+			EmbeddedResources: []string{"Template"}}))
+	})
+
+	It("should validate the pruning options", func() {
+		frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"}
+		for _, api := range []*API{
+			{Resource: frigate, DoResource: true, PreserveUnknownFields: []string{"metadata"}},
+			{Resource: frigate, DoResource: true, EmbeddedResources: []string{"Template"}},
+			{Resource: frigate, PreserveUnknownFields: []string{"spec"}},
+		} {
+			Expect(api.setDefaults()).To(Succeed())
+			Expect(api.validatePruning()).NotTo(Succeed())
+		}
+
+		api := &API{Resource: frigate, DoResource: true, Fields: []string{"Template:object", "Image:string"},
+			PreserveUnknownFields: []string{"spec", "status"}, EmbeddedResources: []string{"Template"}}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.validatePruning()).To(Succeed())
+		Expect(api.parseFields()).To(Succeed())
+
+		api.EmbeddedResources = []string{"Image"}
+		Expect(api.parseFields()).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,63 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ = Describe("go.mod", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-gomod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("PROJECT", []byte("version: \"2\"\ndomain: example.com\nrepo: example.com/proj\n"),
+			0600)).To(Succeed())
+		Expect(os.Mkdir("hack", 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join("hack", "boilerplate.go.txt"), nil, 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should only require the OpenTelemetry modules with profiling", func() {
+		gomod := &scaffoldv2.GoMod{GoVersion: "1.15", ControllerRuntimeVersion: "v0.4.0"}
+		Expect((&Scaffold{}).Execute(&model.Universe{}, input.Options{}, gomod)).To(Succeed())
+		Expect(ioutil.ReadFile("go.mod")).NotTo(ContainSubstring("go.opentelemetry.io"))
+
+		gomod = &scaffoldv2.GoMod{GoVersion: "1.15", ControllerRuntimeVersion: "v0.4.0", Profiling: true}
+		Expect((&Scaffold{}).Execute(&model.Universe{}, input.Options{}, gomod)).To(Succeed())
+		Expect(ioutil.ReadFile("go.mod")).To(Equal([]byte(`
+module example.com/proj
+
+go 1.15
+
+require (
+	go.opentelemetry.io/otel ` + scaffoldv2.OpenTelemetryVersion + `
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc ` + scaffoldv2.OpenTelemetryVersion + `
+	go.opentelemetry.io/otel/sdk ` + scaffoldv2.OpenTelemetryVersion + `
+	sigs.k8s.io/controller-runtime v0.4.0
+)
+`)))
+	})
+
+	It("should require a Go release supported by the OpenTelemetry modules with profiling", func() {
+		Expect((&V2Project{Profiling: true}).Validate()).NotTo(Succeed())
+		Expect((&V2Project{Profiling: true, GoVersion: scaffoldv2.OpenTelemetryMinGoVersion}).Validate()).To(Succeed())
+	})
+})
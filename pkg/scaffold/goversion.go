@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// DefaultGoVersion is the Go release of the go directive of the projects
+const DefaultGoVersion = "1.13"
+
+// goCompatibility is an entry of the compatibility table of the Go releases
+// and the controller-runtime versions the projects are scaffolded for
+type goCompatibility struct {
+	// minGoVersion is the oldest Go release the entry applies to
+	minGoVersion string
+
+	// controllerRuntimeVersion is the controller-runtime version pinned in
+	// the go.mod of the projects
+	controllerRuntimeVersion string
+}
+
+// goCompatibilities is the compatibility table, from the newest Go release to
+// the oldest. A Go release uses the first entry it is not older than. The
+// scaffolded code is written for the controller-runtime versions of the
+// table, so a version is only added once the templates support it.
+var goCompatibilities = []goCompatibility{
+	{minGoVersion: "1.13", controllerRuntimeVersion: "v0.4.0"},
+}
+
+var (
+	goVersionRegexp   = regexp.MustCompile(`^(?:go)?([0-9]+)\.([0-9]+)([\.0-9A-Za-z\-]+)?$`)
+	goDirectiveRegexp = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+)
+
+// ParseGoVersion returns the major and minor numbers of a Go release, e.g.
+// 1.13, or of the version printed by go version, e.g. go1.13.4.
+func ParseGoVersion(v string) (major, minor int, err error) {
+	m := goVersionRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return 0, 0, fmt.Errorf("invalid Go version %q", v)
+	}
+	if major, err = strconv.Atoi(m[1]); err != nil {
+		return 0, 0, fmt.Errorf("error parsing major version '%s': %s", m[1], err)
+	}
+	if minor, err = strconv.Atoi(m[2]); err != nil {
+		return 0, 0, fmt.Errorf("error parsing minor version '%s': %s", m[2], err)
+	}
+	return major, minor, nil
+}
+
+// compareGoVersions returns -1, 0 or 1 if the Go release a is older, the
+// same or newer than b. Both must be valid.
+func compareGoVersions(a, b string) int {
+	aMajor, aMinor, _ := ParseGoVersion(a)
+	bMajor, bMinor, _ := ParseGoVersion(b)
+	if aMajor != bMajor {
+		aMinor, bMinor = aMajor, bMajor
+	}
+	switch {
+	case aMinor < bMinor:
+		return -1
+	case aMinor > bMinor:
+		return 1
+	}
+	return 0
+}
+
+// ValidateGoVersion checks the Go release of the go directive, which must be
+// major.minor and supported by the compatibility table.
+func ValidateGoVersion(goVersion string) error {
+	if !goDirectiveRegexp.MatchString(goVersion) {
+		return fmt.Errorf("the Go version must be major.minor, e.g. %s (was %s)", DefaultGoVersion, goVersion)
+	}
+	_, err := ControllerRuntimeVersionFor(goVersion)
+	return err
+}
+
+// ControllerRuntimeVersionFor returns the controller-runtime version pinned in
+// the projects of the given Go release.
+func ControllerRuntimeVersionFor(goVersion string) (string, error) {
+	if _, _, err := ParseGoVersion(goVersion); err != nil {
+		return "", err
+	}
+	for _, c := range goCompatibilities {
+		if compareGoVersions(goVersion, c.minGoVersion) >= 0 {
+			return c.controllerRuntimeVersion, nil
+		}
+	}
+	oldest := goCompatibilities[len(goCompatibilities)-1]
+	return "", fmt.Errorf("unsupported Go version %s, the projects require Go %s or later", goVersion, oldest.minGoVersion)
+}
+
+// CheckGoToolchain checks that the Go toolchain, as printed by go version,
+// e.g. go1.13.4, can build a project with the given Go release.
+func CheckGoToolchain(toolchain, goVersion string) error {
+	if _, _, err := ParseGoVersion(toolchain); err != nil {
+		return err
+	}
+	if compareGoVersions(toolchain, goVersion) < 0 {
+		return fmt.Errorf("the go directive requires Go %s or later (was %s)", goVersion, toolchain)
+	}
+	return nil
+}
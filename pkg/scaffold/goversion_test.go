@@ -0,0 +1,36 @@
+package scaffold
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Go versions", func() {
+	It("should pin the controller-runtime version compatible with the Go release", func() {
+		v, err := ControllerRuntimeVersionFor("1.13")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("v0.4.0"))
+
+		v, err = ControllerRuntimeVersionFor("1.14")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal("v0.4.0"))
+
+		_, err = ControllerRuntimeVersionFor("1.12")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should only accept major.minor go directives", func() {
+		Expect(ValidateGoVersion("1.13")).To(Succeed())
+		Expect(ValidateGoVersion("1.13.4")).NotTo(Succeed())
+		Expect(ValidateGoVersion("go1.13")).NotTo(Succeed())
+		Expect(ValidateGoVersion("1.11")).NotTo(Succeed())
+	})
+
+	It("should check that the toolchain supports the go directive", func() {
+		Expect(CheckGoToolchain("go1.13.4", "1.13")).To(Succeed())
+		Expect(CheckGoToolchain("go1.14rc1", "1.13")).To(Succeed())
+		Expect(CheckGoToolchain("go2.0", "1.13")).To(Succeed())
+		Expect(CheckGoToolchain("go1.13", "1.14")).NotTo(Succeed())
+		Expect(CheckGoToolchain("devel", "1.13")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/executor"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// HooksPluginKey is the key of the plugins section of the PROJECT file the
+// hooks are declared under
+const HooksPluginKey = "hooks.kubebuilder.io"
+
+// HooksConfig is the configuration declared under HooksPluginKey
+type HooksConfig struct {
+	// Hooks are the commands to run after scaffolding, in the order they are
+	// declared.
+	Hooks []input.Hook `json:"hooks,omitempty"`
+}
+
+// RunHooks runs the hooks of the project file at the given path that apply
+// to the given phase, in the order they are declared, with the given executor.
+func RunHooks(path string, phase input.HookPhase, e executor.Executor) error {
+	p, err := LoadProjectFile(path)
+	if err != nil {
+		return err
+	}
+	cfg := HooksConfig{}
+	if err := p.DecodePluginConfig(HooksPluginKey, &cfg); err != nil {
+		if _, notFound := err.(input.PluginKeyNotFoundError); notFound {
+			return nil
+		}
+		return err
+	}
+	return runHooks(cfg.Hooks, phase, e)
+}
+
+func runHooks(hooks []input.Hook, phase input.HookPhase, e executor.Executor) error {
+	// validate all the hooks before running any of them
+	for _, hook := range hooks {
+		if err := validateHook(hook); err != nil {
+			return err
+		}
+	}
+
+	for _, hook := range hooks {
+		if !hook.RunsIn(phase) {
+			logging.Debugf("skipping hook %q, it does not run in the %s phase", hook.Command, phase)
+			continue
+		}
+
+		logging.Infof("Running %s hook: %s", phase, hook.Command)
+		if err := e.Run("sh", "-c", hook.Command); err != nil {
+			if hook.FailurePolicy == input.HookIgnore {
+				logging.Warnf("ignoring failed hook %q: %v", hook.Command, err)
+				continue
+			}
+			return fmt.Errorf("error running hook %q: %v", hook.Command, err)
+		}
+	}
+	return nil
+}
+
+func validateHook(hook input.Hook) error {
+	if hook.Command == "" {
+		return fmt.Errorf("hook command cannot be empty")
+	}
+
+	switch hook.FailurePolicy {
+	case "", input.HookFail, input.HookIgnore:
+	default:
+		return fmt.Errorf("hook %q has an unknown failure policy %q, should be one of %s, %s",
+			hook.Command, hook.FailurePolicy, input.HookFail, input.HookIgnore)
+	}
+
+	for _, phase := range hook.Phases {
+		switch phase {
+		case input.HookPhaseCreateAPI, input.HookPhaseCreateWebhook:
+		default:
+			return fmt.Errorf("hook %q has an unknown phase %q, should be one of %s, %s",
+				hook.Command, phase, input.HookPhaseCreateAPI, input.HookPhaseCreateWebhook)
+		}
+	}
+	return nil
+}
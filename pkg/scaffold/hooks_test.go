@@ -0,0 +1,104 @@
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/executor"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ = Describe("Hooks", func() {
+	var dir, projectPath, outPath string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubebuilder-hooks")
+		Expect(err).NotTo(HaveOccurred())
+		projectPath = filepath.Join(dir, "PROJECT")
+		outPath = filepath.Join(dir, "out")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeProject := func(hooks string) {
+		hooks = "    " + strings.ReplaceAll(strings.TrimSuffix(hooks, "\n"), "\n", "\n    ") + "\n"
+		content := "version: \"2\"\nrepo: example.com/project\nplugins:\n  hooks.kubebuilder.io:\n    hooks:\n" + hooks
+		Expect(ioutil.WriteFile(projectPath, []byte(content), 0600)).To(Succeed())
+	}
+
+	It("should run the hooks of the phase in order", func() {
+		writeProject(`- command: echo first >> ` + outPath + `
+- command: echo api-only >> ` + outPath + `
+  phases: [create-api]
+- command: echo second >> ` + outPath + `
+  phases: [create-webhook]
+`)
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateWebhook, executor.Default)).To(Succeed())
+
+		b, err := ioutil.ReadFile(outPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("first\nsecond\n"))
+	})
+
+	It("should stop at a failing hook by default", func() {
+		writeProject(`- command: exit 1
+- command: echo unreachable >> ` + outPath + `
+`)
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, executor.Default)).NotTo(Succeed())
+		_, err := os.Stat(outPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should continue after a failing hook with the Ignore policy", func() {
+		writeProject(`- command: exit 1
+  failurePolicy: Ignore
+- command: echo reached >> ` + outPath + `
+`)
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, executor.Default)).To(Succeed())
+		b, err := ioutil.ReadFile(outPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("reached\n"))
+	})
+
+	It("should not run any hook if one of them is invalid", func() {
+		writeProject(`- command: echo valid >> ` + outPath + `
+- command: echo invalid
+  phases: [create-controller]
+`)
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, executor.Default)).NotTo(Succeed())
+		_, err := os.Stat(outPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should reject the init phase, which runs before the PROJECT file exists", func() {
+		writeProject(`- command: echo init >> ` + outPath + `
+  phases: [init]
+`)
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, executor.Default)).NotTo(Succeed())
+	})
+
+	It("should not read hooks outside of the plugins section", func() {
+		content := "version: \"2\"\nrepo: example.com/project\nhooks:\n- command: make generate\n"
+		Expect(ioutil.WriteFile(projectPath, []byte(content), 0600)).To(Succeed())
+		fake := &executor.Fake{}
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, fake)).To(Succeed())
+		Expect(fake.Commands).To(BeEmpty())
+	})
+
+	It("should run the hooks with the given executor", func() {
+		writeProject(`- command: make generate
+`)
+		fake := &executor.Fake{}
+		Expect(scaffold.RunHooks(projectPath, input.HookPhaseCreateAPI, fake)).To(Succeed())
+		Expect(fake.Commands).To(Equal([][]string{{"sh", "-c", "make generate"}}))
+	})
+})
@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+// TemplateEngine renders the TemplateBody of a File into its contents. Files
+// without a TemplateEngine are rendered as Go text/template.
+type TemplateEngine interface {
+	// Render renders the body using the File as data
+	Render(body string, f File) ([]byte, error)
+}
+
+// RawEngine is a TemplateEngine which writes the body as is
+type RawEngine struct{}
+
+// Render implements TemplateEngine
+func (RawEngine) Render(body string, _ File) ([]byte, error) {
+	return []byte(body), nil
+}
+
+var _ File = &RawFile{}
+
+// RawFile is a File with static contents, written without templating
+type RawFile struct {
+	Input
+
+	// Contents are the contents of the file
+	Contents string
+}
+
+// GetInput implements input.File
+func (f *RawFile) GetInput() (Input, error) {
+	f.TemplateBody = f.Contents
+	f.TemplateEngine = RawEngine{}
+	return f.Input, nil
+}
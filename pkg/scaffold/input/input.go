@@ -41,6 +41,9 @@ type Input struct {
 	// TemplateBody is the template body to execute
 	TemplateBody string
 
+	// TemplateEngine renders the TemplateBody, defaults to Go text/template
+	TemplateEngine TemplateEngine
+
 	// Boilerplate is the contents of a Boilerplate go header file
 	Boilerplate string
 
@@ -58,6 +61,26 @@ type Input struct {
 
 	// ProjectPath is the relative path to the project root
 	ProjectPath string
+
+	// ScaffoldVersion is the version of the template, stamped at the end of
+	// the file with a marker so that the files scaffolded by its older
+	// versions can be found. The file is not stamped if 0.
+	ScaffoldVersion int
+}
+
+// TemplateVersion is the current version of a template stamping the files it
+// scaffolds, see Input.ScaffoldVersion
+type TemplateVersion struct {
+	// Path is the path of the files scaffolded by the template, a glob if
+	// it depends on the resource, e.g. controllers/*_controller.go
+	Path string
+
+	// Version is the current version of the template, from 1
+	Version int
+
+	// Migrations are the changes to make to the files scaffolded by the
+	// older versions of the template, by the version introducing them
+	Migrations map[int]string
 }
 
 // Domain allows a domain to be set on an object
@@ -173,6 +196,35 @@ type ProjectFile struct {
 	// Resources tracks scaffolded resources in the project. This info is
 	// tracked only in project with version 2.
 	Resources []Resource `json:"resources,omitempty"`
+
+	// ImportsLocalPrefix is a comma-separated list of import path prefixes,
+	// like goimports -local. The imports of the scaffolded Go files with
+	// these prefixes are grouped after the third-party ones.
+	ImportsLocalPrefix string `json:"importsLocalPrefix,omitempty"`
+
+	// Plugins holds the configuration of each plugin, keyed by plugin name.
+	// Use DecodePluginConfig and EncodePluginConfig to access it.
+	Plugins map[string]interface{} `json:"plugins,omitempty"`
+
+	// ExternalSchemes are the API packages of other modules added to the
+	// scheme of the manager with kubebuilder alpha wire.
+	ExternalSchemes []ExternalScheme `json:"externalSchemes,omitempty"`
+
+	// Checksums are the sha256 checksums of the static files scaffolded in the
+	// project, by slash separated path. A static file whose contents no
+	// longer match its checksum was modified by the user and is not
+	// overwritten.
+	Checksums map[string]string `json:"checksums,omitempty"`
+}
+
+// ExternalScheme is an API package of another module whose AddToScheme is
+// called in main.go
+type ExternalScheme struct {
+	// Package is the Go import path of the API package
+	Package string `json:"package"`
+
+	// Alias is the name the package is imported as in main.go
+	Alias string `json:"alias"`
 }
 
 // ResourceGroups returns unique groups of scaffolded resources in the project.
@@ -194,4 +246,66 @@ type Resource struct {
 	Group   string `json:"group,omitempty"`
 	Version string `json:"version,omitempty"`
 	Kind    string `json:"kind,omitempty"`
+
+	// Plural is only set when the resource does not use the plural computed
+	// from its kind, so that every command scaffolds it with the same plural.
+	Plural string `json:"plural,omitempty"`
+
+	// StorageVersion is true for the version of a kind with several versions
+	// which is stored, and which the other versions are converted to.
+	StorageVersion bool `json:"storageVersion,omitempty"`
+
+	// GenerateOnly is true for a resource whose types were scaffolded alone,
+	// with create api --generate-only, until create controller scaffolds
+	// the rest of its API.
+	GenerateOnly bool `json:"generateOnly,omitempty"`
+}
+
+// HookPhase is a scaffolding command after which hooks can run. There is no
+// phase for `kubebuilder init`, the hooks are read from the PROJECT file
+// which does not exist before it.
+type HookPhase string
+
+const (
+	// HookPhaseCreateAPI runs the hook after `kubebuilder create api`
+	HookPhaseCreateAPI HookPhase = "create-api"
+
+	// HookPhaseCreateWebhook runs the hook after `kubebuilder create webhook`
+	HookPhaseCreateWebhook HookPhase = "create-webhook"
+)
+
+// HookFailurePolicy determines what to do if a hook fails
+type HookFailurePolicy string
+
+const (
+	// HookFail stops and reports the error of the hook
+	HookFail HookFailurePolicy = "Fail"
+
+	// HookIgnore prints the error of the hook and runs the next one
+	HookIgnore HookFailurePolicy = "Ignore"
+)
+
+// Hook is a command run after scaffolding, e.g. `go vet ./...` or `make generate`.
+type Hook struct {
+	// Command is the command to run, it is interpreted by `sh -c`
+	Command string `json:"command"`
+
+	// Phases are the phases the hook runs after. It runs after all phases if empty.
+	Phases []HookPhase `json:"phases,omitempty"`
+
+	// FailurePolicy determines what to do if the command fails - defaults to Fail
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// RunsIn returns true if the hook runs after the given phase.
+func (h Hook) RunsIn(phase HookPhase) bool {
+	if len(h.Phases) == 0 {
+		return true
+	}
+	for _, p := range h.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
 }
@@ -2,8 +2,53 @@ package input_test
 
 import (
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
 var _ = Describe("Input", func() {
 
 })
+
+var _ = Describe("ProjectFile plugin configuration", func() {
+	type pluginConfig struct {
+		Enabled bool     `json:"enabled,omitempty"`
+		Names   []string `json:"names,omitempty"`
+	}
+
+	It("should return a PluginKeyNotFoundError for an unknown plugin", func() {
+		pf := &input.ProjectFile{}
+		err := pf.DecodePluginConfig("unknown.example.com", &pluginConfig{})
+		Expect(err).To(Equal(input.PluginKeyNotFoundError{Key: "unknown.example.com"}))
+	})
+
+	It("should decode the configuration encoded for the plugin", func() {
+		pf := &input.ProjectFile{}
+		in := pluginConfig{Enabled: true, Names: []string{"a", "b"}}
+		Expect(pf.EncodePluginConfig("sample.example.com", in)).To(Succeed())
+
+		out := pluginConfig{}
+		Expect(pf.DecodePluginConfig("sample.example.com", &out)).To(Succeed())
+		Expect(out).To(Equal(in))
+	})
+
+	It("should keep the configuration of each plugin when saved", func() {
+		pf := &input.ProjectFile{Version: "2"}
+		Expect(pf.EncodePluginConfig("a.example.com", pluginConfig{Enabled: true})).To(Succeed())
+		Expect(pf.EncodePluginConfig("b.example.com", pluginConfig{Names: []string{"x"}})).To(Succeed())
+
+		b, err := yaml.Marshal(pf)
+		Expect(err).NotTo(HaveOccurred())
+		loaded := &input.ProjectFile{}
+		Expect(yaml.Unmarshal(b, loaded)).To(Succeed())
+
+		out := pluginConfig{}
+		Expect(loaded.DecodePluginConfig("a.example.com", &out)).To(Succeed())
+		Expect(out).To(Equal(pluginConfig{Enabled: true}))
+		out = pluginConfig{}
+		Expect(loaded.DecodePluginConfig("b.example.com", &out)).To(Succeed())
+		Expect(out).To(Equal(pluginConfig{Names: []string{"x"}}))
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// PluginKeyNotFoundError is returned by DecodePluginConfig when the project
+// file holds no configuration for the plugin.
+type PluginKeyNotFoundError struct {
+	Key string
+}
+
+// Error implements error
+func (e PluginKeyNotFoundError) Error() string {
+	return fmt.Sprintf("plugin key %q not found in the project file", e.Key)
+}
+
+// DecodePluginConfig decodes the configuration of the plugin with the given
+// key into configObj, which must be a pointer. It returns a
+// PluginKeyNotFoundError if the plugin has no configuration.
+func (pf *ProjectFile) DecodePluginConfig(key string, configObj interface{}) error {
+	cfg, found := pf.Plugins[key]
+	if !found {
+		return PluginKeyNotFoundError{Key: key}
+	}
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("error marshalling the configuration of plugin %q: %v", key, err)
+	}
+	if err := yaml.Unmarshal(b, configObj); err != nil {
+		return fmt.Errorf("error decoding the configuration of plugin %q: %v", key, err)
+	}
+	return nil
+}
+
+// EncodePluginConfig stores configObj as the configuration of the plugin with
+// the given key, replacing its previous configuration.
+func (pf *ProjectFile) EncodePluginConfig(key string, configObj interface{}) error {
+	if key == "" {
+		return fmt.Errorf("plugin key cannot be empty")
+	}
+	b, err := yaml.Marshal(configObj)
+	if err != nil {
+		return fmt.Errorf("error encoding the configuration of plugin %q: %v", key, err)
+	}
+	var cfg interface{}
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return fmt.Errorf("error encoding the configuration of plugin %q: %v", key, err)
+	}
+	if pf.Plugins == nil {
+		pf.Plugins = map[string]interface{}{}
+	}
+	pf.Plugins[key] = cfg
+	return nil
+}
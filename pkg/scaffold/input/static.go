@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+)
+
+// Static is implemented by the files whose contents are written as is, e.g.
+// binary files. They are neither rendered nor formatted, and the checksums of
+// their contents are recorded in the project file so that the files modified
+// by the user are not overwritten.
+type Static interface {
+	// GetContents returns the contents of the file
+	GetContents() ([]byte, error)
+}
+
+var _ File = &StaticFile{}
+var _ Static = &StaticFile{}
+
+// StaticFile is a File whose contents are copied from an asset, e.g. an icon,
+// an example image or a pre-built schema
+type StaticFile struct {
+	Input
+
+	// Assets is the filesystem of the asset, e.g. the one generated by
+	// go-bindata or vfsgen, or http.Dir. The contents are used if nil.
+	Assets http.FileSystem
+
+	// Source is the path of the asset in Assets, defaults to the slash
+	// separated Path of the file
+	Source string
+
+	// Contents are the contents of the file when Assets is nil
+	Contents []byte
+}
+
+// GetInput implements input.File
+func (f *StaticFile) GetInput() (Input, error) {
+	if f.Path == "" {
+		return Input{}, fmt.Errorf("the path of the static file is required")
+	}
+	return f.Input, nil
+}
+
+// GetContents implements input.Static
+func (f *StaticFile) GetContents() ([]byte, error) {
+	if f.Assets == nil {
+		return f.Contents, nil
+	}
+	source := f.Source
+	if source == "" {
+		source = path.Clean("/" + filepath.ToSlash(f.Path))
+	}
+	asset, err := f.Assets.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("error opening the asset of %s: %v", f.Path, err)
+	}
+	defer asset.Close() // nolint: errcheck
+	b, err := ioutil.ReadAll(asset)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the asset of %s: %v", f.Path, err)
+	}
+	return b, nil
+}
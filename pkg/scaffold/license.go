@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedCode matches the comment of the Go files generated by other tools,
+// see https://golang.org/s/generatedcode
+var generatedCode = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// licenseSkipDirs are the directories whose Go files are not owned by the
+// project, or not scaffolded by kubebuilder.
+var licenseSkipDirs = map[string]bool{
+	"vendor":  true,
+	"bin":     true,
+	"testbin": true,
+}
+
+// UpdateLicense replaces the license header of the Go files under root with
+// the boilerplate file at boilerplatePath, and returns the paths of the files
+// which were updated.
+//
+// The license header is the block comment the file starts with, if it
+// contains a copyright or a license. The files without such a header, the
+// files generated by other tools, named zz_generated* or marked with the
+// standard "Code generated ... DO NOT EDIT." comment, and the vendored files
+// are left untouched.
+func UpdateLicense(root, boilerplatePath string) ([]string, error) {
+	boilerplate, err := getBoilerplate(boilerplatePath)
+	if err != nil {
+		return nil, err
+	}
+	header := []byte(strings.TrimSpace(boilerplate))
+
+	updated := []string{}
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (licenseSkipDirs[name] || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" || strings.HasPrefix(info.Name(), "zz_generated") {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		if generatedCode.Match(content) {
+			return nil
+		}
+		replaced, ok := replaceLicenseHeader(content, header)
+		if !ok || bytes.Equal(replaced, content) {
+			return nil
+		}
+		if err := ioutil.WriteFile(path, replaced, info.Mode()); err != nil {
+			return err
+		}
+		updated = append(updated, path)
+		return nil
+	})
+	return updated, err
+}
+
+// replaceLicenseHeader replaces the license header of the Go file content with
+// the given header, and returns false if the content has no license header.
+func replaceLicenseHeader(content, header []byte) ([]byte, bool) {
+	trimmed := bytes.TrimLeft(content, " \t\r\n")
+	if !bytes.HasPrefix(trimmed, []byte("/*")) {
+		return nil, false
+	}
+	end := bytes.Index(trimmed, []byte("*/"))
+	if end < 0 {
+		return nil, false
+	}
+	old := trimmed[:end+len("*/")]
+	if !bytes.Contains(old, []byte("Copyright")) && !bytes.Contains(old, []byte("License")) {
+		return nil, false
+	}
+
+	rest := trimmed[len(old):]
+	if len(header) == 0 {
+		return bytes.TrimLeft(rest, " \t\r\n"), true
+	}
+	out := make([]byte, 0, len(header)+len(rest))
+	out = append(out, header...)
+	return append(out, rest...), true
+}
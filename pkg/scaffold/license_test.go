@@ -0,0 +1,65 @@
+package scaffold_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+)
+
+var _ = Describe("UpdateLicense", func() {
+	var dir, boilerplatePath string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubebuilder-license")
+		Expect(err).NotTo(HaveOccurred())
+		boilerplatePath = filepath.Join(dir, "hack", "boilerplate.go.txt")
+		write := func(path, content string) {
+			path = filepath.Join(dir, path)
+			Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		}
+		write("hack/boilerplate.go.txt", "/*\nCopyright 2020 New Owner.\n*/\n")
+		write("main.go", "/*\nCopyright 2019 Old Owner.\n*/\n\npackage main\n")
+		write("api/v1/doc.go", "/* Package v1 has no license */\npackage v1\n")
+		write("api/v1/zz_generated.deepcopy.go", "/*\nCopyright 2019 Old Owner.\n*/\n\npackage v1\n")
+		write("pkg/client/clientset.go",
+			"/*\nCopyright 2019 Old Owner.\n*/\n\n// Code generated by client-gen. DO NOT EDIT.\n\npackage client\n")
+		write("vendor/example.com/lib/lib.go", "/*\nCopyright 2019 Someone Else.\n*/\n\npackage lib\n")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, path))
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("should only replace the license headers of the project files", func() {
+		updated, err := scaffold.UpdateLicense(dir, boilerplatePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(Equal([]string{filepath.Join(dir, "main.go")}))
+
+		Expect(read("main.go")).To(Equal("/*\nCopyright 2020 New Owner.\n*/\n\npackage main\n"))
+		Expect(read("api/v1/doc.go")).To(Equal("/* Package v1 has no license */\npackage v1\n"))
+		Expect(read("api/v1/zz_generated.deepcopy.go")).To(ContainSubstring("Old Owner"))
+		Expect(read("pkg/client/clientset.go")).To(ContainSubstring("Old Owner"))
+		Expect(read("vendor/example.com/lib/lib.go")).To(ContainSubstring("Someone Else"))
+	})
+
+	It("should not update the files again", func() {
+		_, err := scaffold.UpdateLicense(dir, boilerplatePath)
+		Expect(err).NotTo(HaveOccurred())
+		updated, err := scaffold.UpdateLicense(dir, boilerplatePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeEmpty())
+	})
+})
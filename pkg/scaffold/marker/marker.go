@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package marker defines the scaffold markers, the comments of the scaffolded
+// files before which the code of the new resources is inserted.
+package marker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prefix is the prefix of the markers, after the comment delimiter
+const prefix = "+kubebuilder:scaffold:"
+
+// commentDelimiters are the line comment delimiters of the file types which
+// can hold markers, keyed by extension, or by name for the files without one
+var commentDelimiters = map[string]string{
+	".go":        "//",
+	".yaml":      "#",
+	".yml":       "#",
+	"Makefile":   "#",
+	"Dockerfile": "#",
+}
+
+// commentDelimiter returns the line comment delimiter of the file at path
+func commentDelimiter(path string) (string, error) {
+	if c, found := commentDelimiters[filepath.Base(path)]; found {
+		return c, nil
+	}
+	if c, found := commentDelimiters[filepath.Ext(path)]; found {
+		return c, nil
+	}
+	return "", fmt.Errorf("%s does not support markers, only the Go, YAML, Makefile and Dockerfile files do", path)
+}
+
+// Supported returns true if the file at path can hold markers
+func Supported(path string) bool {
+	_, err := commentDelimiter(path)
+	return err == nil
+}
+
+// Marker is a scaffold marker of a file type
+type Marker struct {
+	comment string
+	value   string
+}
+
+// For returns the marker with the given value in the files of the type of
+// path, e.g. "main.go" or "config/crd/kustomization.yaml". The markers are
+// declared along the templates, it panics if the file type does not support
+// markers.
+func For(path, value string) Marker {
+	comment, err := commentDelimiter(path)
+	if err != nil {
+		panic(err)
+	}
+	return Marker{comment: comment, value: value}
+}
+
+// Value returns the value of the marker, e.g. "imports"
+func (m Marker) Value() string {
+	return m.value
+}
+
+// String returns the comment line of the marker, e.g.
+// "// +kubebuilder:scaffold:imports"
+func (m Marker) String() string {
+	return fmt.Sprintf("%s %s%s", m.comment, prefix, m.value)
+}
+
+// List returns the markers of the file at path, in order
+func List(path string) ([]Marker, error) {
+	comment, err := commentDelimiter(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var markers []Marker
+	start := comment + " " + prefix
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, start) {
+			markers = append(markers, Marker{comment: comment, value: strings.TrimPrefix(line, start)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return markers, nil
+}
+
+// Validate returns an error if a marker is found more than once in the file at
+// path, the code of the new resources would be inserted before each of them
+func Validate(path string) error {
+	markers, err := List(path)
+	if err != nil {
+		return err
+	}
+	seen := make(map[Marker]bool, len(markers))
+	for _, m := range markers {
+		if seen[m] {
+			return fmt.Errorf("marker %q is found more than once in %s", m, path)
+		}
+		seen[m] = true
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package marker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"main.go", "// +kubebuilder:scaffold:imports"},
+		{"config/crd/kustomization.yaml", "# +kubebuilder:scaffold:imports"},
+		{"config/crd/kustomization.yml", "# +kubebuilder:scaffold:imports"},
+		{"Makefile", "# +kubebuilder:scaffold:imports"},
+		{"build/Dockerfile", "# +kubebuilder:scaffold:imports"},
+	}
+	for _, test := range tests {
+		if m := For(test.path, "imports"); m.String() != test.expected {
+			t.Errorf("marker of %s: got %q, wanted %q", test.path, m, test.expected)
+		}
+	}
+
+	if Supported("PROJECT") {
+		t.Errorf("PROJECT should not support markers")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic for the marker of a PROJECT file")
+		}
+	}()
+	For("PROJECT", "imports")
+}
+
+func TestListAndValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "marker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "main.go")
+	content := `package main
+
+import (
+	// +kubebuilder:scaffold:imports
+)
+
+func main() {
+	# +kubebuilder:scaffold:yaml
+	// +kubebuilder:scaffold:builder
+}
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	markers, err := List(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Marker{For("main.go", "imports"), For("main.go", "builder")}
+	if !reflect.DeepEqual(markers, expected) {
+		t.Errorf("got %v, wanted %v", markers, expected)
+	}
+	if err := Validate(path); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	content += "// +kubebuilder:scaffold:imports\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(path); err == nil {
+		t.Errorf("expected an error for the duplicate imports marker")
+	}
+}
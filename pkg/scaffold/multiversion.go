@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/crd"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// hubVersion returns the version the other versions of the kind of the API
+// resource are converted to, or "" if the kind has no other version yet.
+// The storage version recorded in the PROJECT file is the hub, and the first
+// scaffolded version otherwise.
+func (api *API) hubVersion() string {
+	hub := ""
+	for _, res := range api.project.Resources {
+		if res.Group != api.Resource.Group || res.Kind != api.Resource.Kind ||
+			res.Version == api.Resource.Version {
+			continue
+		}
+		if res.StorageVersion {
+			return res.Version
+		}
+		if hub == "" {
+			hub = res.Version
+		}
+	}
+	return hub
+}
+
+// markStorageVersion marks the given version of the kind as the storage
+// version, unless another version of the kind is marked already.
+func markStorageVersion(p *input.ProjectFile, group, kind, version string) {
+	for _, res := range p.Resources {
+		if res.Group == group && res.Kind == kind && res.StorageVersion {
+			return
+		}
+	}
+	for i, res := range p.Resources {
+		if res.Group == group && res.Kind == kind && res.Version == version {
+			p.Resources[i].StorageVersion = true
+			return
+		}
+	}
+}
+
+// scaffoldConversion scaffolds the conversion of a new version of an existing
+// kind: the hub version becomes the storage version and gets a conversion
+// webhook, the new version gets the conversion functions to fill in, and the
+// conversion patches of the CRD are enabled.
+func (api *API) scaffoldConversion(hubVersion string) error {
+	r := api.Resource
+	hub := &resource.Resource{
+		Namespaced: r.Namespaced,
+		Group:      r.Group,
+		Version:    hubVersion,
+		Kind:       r.Kind,
+		Resource:   r.Resource,
+	}
+	if err := hub.Validate(); err != nil {
+		return fmt.Errorf("invalid hub version %s: %v", hubVersion, err)
+	}
+
+	kind := strings.ToLower(r.Kind)
+	logging.Infof("%s", filepath.Join("api", hub.Version, fmt.Sprintf("%s_conversion.go", kind)))
+	logging.Infof("%s", filepath.Join("api", r.Version, fmt.Sprintf("%s_conversion.go", kind)))
+	err := (&Scaffold{}).Execute(api.buildUniverse(), input.Options{},
+		&scaffoldv2.ConversionHub{Resource: hub},
+		&scaffoldv2.ConversionSpoke{Resource: r, Hub: hub},
+	)
+	if err != nil {
+		return fmt.Errorf("error scaffolding conversion: %v", err)
+	}
+
+	typesPath := filepath.Join("api", hub.Version, fmt.Sprintf("%s_types.go", kind))
+	marked, err := scaffoldv2.AddStorageVersionMarker(typesPath, hub.Kind)
+	if err != nil {
+		return fmt.Errorf("error adding the storage version marker to %s: %v", typesPath, err)
+	}
+	if !marked {
+		logging.Warnf("could not find the %s type in %s, add the +kubebuilder:storageversion marker to it by hand",
+			hub.Kind, typesPath)
+	}
+
+	// the conversion webhook is served by the hub version
+	webhookPath := filepath.Join("api", hub.Version, fmt.Sprintf("%s_webhook.go", kind))
+	if _, err := os.Stat(webhookPath); os.IsNotExist(err) {
+		logging.Infof("%s", webhookPath)
+		err := (&Scaffold{}).Execute(&model.Universe{}, input.Options{},
+			&webhook.Webhook{Resource: hub},
+		)
+		if err != nil {
+			return fmt.Errorf("error scaffolding the conversion webhook: %v", err)
+		}
+		err = (&scaffoldv2.Main{}).Update(
+			&scaffoldv2.MainUpdateOptions{
+				Project:     api.project,
+				WireWebhook: true,
+				Resource:    hub,
+			})
+		if err != nil {
+			return fmt.Errorf("error updating main.go: %v", err)
+		}
+	}
+
+	// the CA of the webhook-bootstrap certificates is injected in the CRDs by
+	// the manager, not by cert-manager
+	bootstrap := bootstrapCertsEnabled()
+	if err := (&crdv2.Kustomization{Resource: r}).EnableConversionPatches(!bootstrap); err != nil {
+		return fmt.Errorf("error enabling the conversion patches: %v", err)
+	}
+
+	conversionPath := filepath.Join("api", r.Version, fmt.Sprintf("%s_conversion.go", kind))
+	if bootstrap {
+		logging.Infof(`%s is converted from and to the storage version %s.
+Implement ConvertTo and ConvertFrom in %s. The CA of the conversion
+webhook is injected in the CRD by the cabundle-inject init container of the
+manager.`, r.Version, hub.Version, conversionPath)
+		return nil
+	}
+	logging.Infof(`%s is converted from and to the storage version %s.
+Implement ConvertTo and ConvertFrom in %s, and enable the [WEBHOOK] and
+[CERTMANAGER] sections of config/default/kustomization.yaml to serve the
+conversion webhook.`, r.Version, hub.Version, conversionPath)
+	return nil
+}
@@ -0,0 +1,106 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("Conversion", func() {
+	var dir, wd string
+
+	scaffoldProject := func(certSource webhook.CertSource) {
+		p := &V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Domain:  "example.com",
+				Repo:    "example.com/proj",
+			}},
+			Boilerplate: project.Boilerplate{License: "none"},
+			CertSource:  certSource,
+		}
+		Expect(p.Validate()).To(Succeed())
+		Expect(p.Scaffold()).To(Succeed())
+
+		for _, version := range []string{"v1", "v2"} {
+			api := &API{
+				Resource:   &resource.Resource{Group: "ship", Version: version, Kind: "Frigate", Namespaced: true},
+				DoResource: true,
+			}
+			Expect(api.Validate()).To(Succeed())
+			Expect(api.Scaffold()).To(Succeed())
+		}
+	}
+
+	read := func(path ...string) string {
+		b, err := ioutil.ReadFile(filepath.Join(path...))
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-conversion")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "proj"), 0750)).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "proj"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should inject the CA in the CRD with cert-manager", func() {
+		scaffoldProject(webhook.CertSourceCertManager)
+
+		kustomization := read("config", "crd", "kustomization.yaml")
+		Expect(kustomization).To(ContainSubstring("\n- patches/webhook_in_frigates.yaml\n"))
+		Expect(kustomization).To(ContainSubstring("\n- patches/cainjection_in_frigates.yaml\n"))
+	})
+
+	It("should inject the CA in the CRD with the init container of webhook-bootstrap", func() {
+		scaffoldProject(webhook.CertSourceBootstrap)
+
+		kustomization := read("config", "crd", "kustomization.yaml")
+		Expect(kustomization).To(ContainSubstring("\n- patches/webhook_in_frigates.yaml\n"))
+		Expect(kustomization).To(ContainSubstring("\n#- patches/cainjection_in_frigates.yaml\n"))
+		Expect(read("config", "default", "manager_webhook_patch.yaml")).To(
+			ContainSubstring(`\"path\": \"/spec/conversion/webhookClientConfig/caBundle\"`))
+		Expect(read(webhook.BootstrapRBACPath)).To(ContainSubstring("- customresourcedefinitions\n"))
+	})
+
+	It("should add the defaulting and validating webhooks to the conversion webhook of the hub", func() {
+		scaffoldProject(webhook.CertSourceCertManager)
+
+		hub := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+		w := &Webhook{Resource: hub, Defaulting: true}
+		Expect(w.Validate()).To(Succeed())
+		Expect(w.Scaffold()).To(Succeed())
+		w = &Webhook{Resource: hub, Validating: true}
+		Expect(w.Validate()).To(Succeed())
+		Expect(w.Scaffold()).To(Succeed())
+
+		webhookFile := read("api", "v1", "frigate_webhook.go")
+		Expect(strings.Count(webhookFile, "func (r *Frigate) SetupWebhookWithManager(")).To(Equal(1))
+		Expect(webhookFile).To(ContainSubstring("var _ webhook.Defaulter = &Frigate{}\n"))
+		Expect(webhookFile).To(ContainSubstring("var _ webhook.Validator = &Frigate{}\n"))
+		Expect(webhookFile).To(ContainSubstring("\t\"sigs.k8s.io/controller-runtime/pkg/webhook\"\n"))
+		Expect(strings.Count(read("main.go"), "(&shipv1.Frigate{}).SetupWebhookWithManager(mgr)")).To(Equal(1))
+
+		w = &Webhook{Resource: hub, Defaulting: true}
+		Expect(w.Validate()).To(Succeed())
+		Expect(w.Scaffold()).To(MatchError(ContainSubstring("the defaulting webhook of Frigate already exists")))
+	})
+})
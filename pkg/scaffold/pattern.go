@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// PatternNone is the name of the plain pattern, which scaffolds an API
+// without any plugin
+const PatternNone = "none"
+
+// Pattern is a scaffolding pattern of the APIs, selected with the --pattern
+// flag of create api
+type Pattern struct {
+	// Name is the name of the pattern, e.g. addon
+	Name string
+
+	// Key is the key the pattern is recorded under in the plugins section of
+	// the PROJECT file, so that the next APIs use it by default. The pattern
+	// is not recorded if Key is empty.
+	Key string
+
+	// NewPlugins returns the plugins scaffolding an API with the pattern
+	NewPlugins func() []Plugin
+}
+
+var (
+	patternsMu sync.RWMutex
+	// patterns are the registered patterns, by lower case name
+	patterns = map[string]Pattern{}
+)
+
+// RegisterPattern registers a scaffolding pattern of the APIs. Plugins can
+// call it to extend the patterns accepted by create api --pattern. It fails if
+// a pattern with the same name is already registered.
+func RegisterPattern(p Pattern) error {
+	name := strings.ToLower(p.Name)
+	if name == "" || name == PatternNone {
+		return fmt.Errorf("invalid pattern name %q", p.Name)
+	}
+	if p.NewPlugins == nil {
+		return fmt.Errorf("pattern %s has no plugins", p.Name)
+	}
+
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	if _, found := patterns[name]; found {
+		return fmt.Errorf("pattern %s is already registered", p.Name)
+	}
+	patterns[name] = p
+	return nil
+}
+
+// LookupPattern returns the registered pattern with the given name, ignoring
+// the case.
+func LookupPattern(name string) (Pattern, bool) {
+	patternsMu.RLock()
+	defer patternsMu.RUnlock()
+	p, found := patterns[strings.ToLower(name)]
+	return p, found
+}
+
+// PatternNames returns the sorted names of the registered patterns
+func PatternNames() []string {
+	patternsMu.RLock()
+	defer patternsMu.RUnlock()
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RecordedPattern returns the pattern recorded in the project file, if any.
+// When several are recorded, the first one by name is returned.
+func RecordedPattern(p input.ProjectFile) (Pattern, bool) {
+	for _, name := range PatternNames() {
+		pattern, _ := LookupPattern(name)
+		if pattern.Key == "" {
+			continue
+		}
+		if _, found := p.Plugins[pattern.Key]; found {
+			return pattern, true
+		}
+	}
+	return Pattern{}, false
+}
+
+// RecordPattern records the pattern in the plugins section of the project file
+// at the given path. The configuration already stored under the key of the
+// pattern, if any, is kept.
+func RecordPattern(path string, p Pattern) error {
+	if p.Key == "" {
+		return nil
+	}
+	_, err := updateProjectFile(path, func(pf *input.ProjectFile) {
+		if _, found := pf.Plugins[p.Key]; !found {
+			_ = pf.EncodePluginConfig(p.Key, struct{}{})
+		}
+	})
+	return err
+}
@@ -0,0 +1,44 @@
+package scaffold
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ = Describe("Patterns", func() {
+	newPlugins := func() []Plugin { return []Plugin{pipeOnlyPlugin{}} }
+
+	It("should look up the registered patterns ignoring the case", func() {
+		Expect(RegisterPattern(Pattern{Name: "GitOps", Key: "gitops.example.com", NewPlugins: newPlugins})).To(Succeed())
+
+		p, found := LookupPattern("gitops")
+		Expect(found).To(BeTrue())
+		Expect(p.Key).To(Equal("gitops.example.com"))
+		Expect(PatternNames()).To(ContainElement("gitops"))
+
+		_, found = LookupPattern("unknown")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should not register a pattern twice or the plain pattern", func() {
+		Expect(RegisterPattern(Pattern{Name: "claim", NewPlugins: newPlugins})).To(Succeed())
+		Expect(RegisterPattern(Pattern{Name: "Claim", NewPlugins: newPlugins})).NotTo(Succeed())
+		Expect(RegisterPattern(Pattern{Name: PatternNone, NewPlugins: newPlugins})).NotTo(Succeed())
+		Expect(RegisterPattern(Pattern{Name: "noplugins"})).NotTo(Succeed())
+	})
+
+	It("should return the pattern recorded in the project file", func() {
+		Expect(RegisterPattern(Pattern{Name: "provider", Key: "provider.example.com", NewPlugins: newPlugins})).To(Succeed())
+
+		pf := input.ProjectFile{}
+		_, found := RecordedPattern(pf)
+		Expect(found).To(BeFalse())
+
+		Expect(pf.EncodePluginConfig("provider.example.com", struct{}{})).To(Succeed())
+		p, found := RecordedPattern(pf)
+		Expect(found).To(BeTrue())
+		Expect(p.Name).To(Equal("provider"))
+	})
+})
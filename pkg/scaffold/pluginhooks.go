@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// PreScaffolder is implemented by the plugins which run before the files of a
+// command are scaffolded, e.g. to check the project or to set values read by
+// the other plugins.
+type PreScaffolder interface {
+	// PreScaffold is called before any file is written
+	PreScaffold(u *model.Universe) error
+}
+
+// PostScaffolder is implemented by the plugins which run after the files of a
+// command are scaffolded, e.g. to update files they do not own.
+type PostScaffolder interface {
+	// PostScaffold is called after all the files are written
+	PostScaffold(u *model.Universe) error
+}
+
+// ResourceUpdater is implemented by the plugins which update the resource of a
+// command once its files are scaffolded, e.g. to resolve its plural. The
+// updates are recorded in the PROJECT file, so that the next commands
+// scaffold the resource the same way.
+type ResourceUpdater interface {
+	// UpdateResource is called after the PostScaffold hooks, with a copy of
+	// the resource of the universe to update
+	UpdateResource(u *model.Universe, r *model.Resource) error
+}
+
+// updatableResourceFields are the fields of model.Resource which the
+// ResourceUpdater plugins can update, the ones recorded in the PROJECT file
+var updatableResourceFields = map[string]bool{"Resource": true}
+
+// ResourceConflictError is returned when a plugin updates a field of the
+// resource already updated to another value by another plugin
+type ResourceConflictError struct {
+	Field string
+	Owner string
+	Other string
+}
+
+func (e *ResourceConflictError) Error() string {
+	return fmt.Sprintf("plugin %q cannot update the %s of the resource, it is already updated by plugin %q",
+		e.Other, e.Field, e.Owner)
+}
+
+// PluginHookError is returned when the PreScaffold or PostScaffold hooks of
+// one or more plugins fail.
+type PluginHookError struct {
+	// Phase is the failed phase, PreScaffold or PostScaffold
+	Phase string
+
+	// Plugins are the types of the failed plugins, in the order they ran
+	Plugins []string
+
+	// Errors are the errors of the failed plugins, in the same order
+	Errors []error
+}
+
+func (e *PluginHookError) Error() string {
+	msgs := make([]string, 0, len(e.Plugins))
+	for i, p := range e.Plugins {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", p, e.Errors[i]))
+	}
+	return fmt.Sprintf("%s failed for %d plugins: %s", e.Phase, len(msgs), strings.Join(msgs, "; "))
+}
+
+// runPreScaffold calls the PreScaffold hook of the plugins implementing it, in
+// the order of the plugins. All the hooks are called even if some fail, so
+// that all the problems are reported at once, and their errors are returned
+// as a PluginHookError.
+func runPreScaffold(plugins []Plugin, u *model.Universe) error {
+	return runPluginHooks("PreScaffold", plugins, func(p Plugin) error {
+		if pre, ok := p.(PreScaffolder); ok {
+			return pre.PreScaffold(u)
+		}
+		return nil
+	})
+}
+
+// runPostScaffold calls the PostScaffold hook of the plugins implementing it,
+// in the order of the plugins, like runPreScaffold.
+func runPostScaffold(plugins []Plugin, u *model.Universe) error {
+	return runPluginHooks("PostScaffold", plugins, func(p Plugin) error {
+		if post, ok := p.(PostScaffolder); ok {
+			return post.PostScaffold(u)
+		}
+		return nil
+	})
+}
+
+// runUpdateResource calls the UpdateResource hook of the plugins implementing
+// it, in the order of the plugins, each with a copy of the resource of the
+// universe. It returns the resource with the updates of all the plugins, and
+// true if there are any. The plugins can only update the fields recorded in
+// the PROJECT file, and not a field already updated by another plugin.
+func runUpdateResource(plugins []Plugin, u *model.Universe) (*model.Resource, bool, error) {
+	if u.Resource == nil {
+		return nil, false, nil
+	}
+	updated := *u.Resource
+	// owners are the plugins which updated the fields
+	owners := map[string]string{}
+	err := runPluginHooks("UpdateResource", plugins, func(p Plugin) error {
+		updater, ok := p.(ResourceUpdater)
+		if !ok {
+			return nil
+		}
+		r := *u.Resource
+		if err := updater.UpdateResource(u, &r); err != nil {
+			return err
+		}
+		owner := fmt.Sprintf("%T", p)
+		original, old, updates := reflect.ValueOf(*u.Resource), reflect.ValueOf(&updated).Elem(), reflect.ValueOf(r)
+		for i := 0; i < original.NumField(); i++ {
+			if reflect.DeepEqual(updates.Field(i).Interface(), original.Field(i).Interface()) {
+				continue
+			}
+			field := original.Type().Field(i).Name
+			if !updatableResourceFields[field] {
+				return fmt.Errorf("the %s of the resource cannot be updated", field)
+			}
+			if other, found := owners[field]; found &&
+				!reflect.DeepEqual(updates.Field(i).Interface(), old.Field(i).Interface()) {
+				return &ResourceConflictError{Field: field, Owner: other, Other: owner}
+			}
+			owners[field] = owner
+			old.Field(i).Set(updates.Field(i))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &updated, len(owners) > 0, nil
+}
+
+func runPluginHooks(phase string, plugins []Plugin, hook func(Plugin) error) error {
+	var hookErr *PluginHookError
+	for _, p := range plugins {
+		err := hook(p)
+		if err == nil {
+			continue
+		}
+		if hookErr == nil {
+			hookErr = &PluginHookError{Phase: phase}
+		}
+		hookErr.Plugins = append(hookErr.Plugins, fmt.Sprintf("%T", p))
+		hookErr.Errors = append(hookErr.Errors, err)
+	}
+	if hookErr != nil {
+		return hookErr
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package scaffold
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+// hookPlugin records the calls of its hooks in calls
+type hookPlugin struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (p *hookPlugin) Pipe(u *model.Universe) error { return nil }
+
+func (p *hookPlugin) PreScaffold(u *model.Universe) error {
+	*p.calls = append(*p.calls, "pre "+p.name)
+	return p.err
+}
+
+func (p *hookPlugin) PostScaffold(u *model.Universe) error {
+	*p.calls = append(*p.calls, "post "+p.name)
+	return p.err
+}
+
+// pipeOnlyPlugin implements none of the hooks
+type pipeOnlyPlugin struct{}
+
+func (pipeOnlyPlugin) Pipe(u *model.Universe) error { return nil }
+
+// resourcePlugin updates the resource with update
+type resourcePlugin struct {
+	update func(r *model.Resource)
+}
+
+func (p *resourcePlugin) Pipe(u *model.Universe) error { return nil }
+
+func (p *resourcePlugin) UpdateResource(u *model.Universe, r *model.Resource) error {
+	p.update(r)
+	return nil
+}
+
+var _ = Describe("Plugin hooks", func() {
+	var calls []string
+
+	BeforeEach(func() {
+		calls = nil
+	})
+
+	It("should call the hooks of the plugins implementing them in order", func() {
+		plugins := []Plugin{
+			&hookPlugin{name: "first", calls: &calls},
+			pipeOnlyPlugin{},
+			&hookPlugin{name: "second", calls: &calls},
+		}
+		Expect(runPreScaffold(plugins, &model.Universe{})).To(Succeed())
+		Expect(runPostScaffold(plugins, &model.Universe{})).To(Succeed())
+		Expect(calls).To(Equal([]string{"pre first", "pre second", "post first", "post second"}))
+	})
+
+	It("should call all the hooks and return their errors together", func() {
+		plugins := []Plugin{
+			&hookPlugin{name: "first", calls: &calls, err: fmt.Errorf("first failed")},
+			&hookPlugin{name: "second", calls: &calls},
+			&hookPlugin{name: "third", calls: &calls, err: fmt.Errorf("third failed")},
+		}
+		err := runPreScaffold(plugins, &model.Universe{})
+		Expect(calls).To(Equal([]string{"pre first", "pre second", "pre third"}))
+
+		hookErr, ok := err.(*PluginHookError)
+		Expect(ok).To(BeTrue())
+		Expect(hookErr.Phase).To(Equal("PreScaffold"))
+		Expect(hookErr.Errors).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("first failed"))
+		Expect(err.Error()).To(ContainSubstring("third failed"))
+	})
+
+	Context("UpdateResource", func() {
+		var u *model.Universe
+
+		BeforeEach(func() {
+			u = &model.Universe{Resource: &model.Resource{Group: "ship", Version: "v1", Kind: "Frigate",
+				Resource: "frigates"}}
+		})
+
+		setPlural := func(plural string) Plugin {
+			return &resourcePlugin{update: func(r *model.Resource) { r.Resource = plural }}
+		}
+
+		It("should return the resource unchanged without updates", func() {
+			r, changed, err := runUpdateResource([]Plugin{pipeOnlyPlugin{}, setPlural("frigates")}, u)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(r).To(Equal(u.Resource))
+		})
+
+		It("should merge the updates of the plugins", func() {
+			r, changed, err := runUpdateResource([]Plugin{setPlural("frigatae"), setPlural("frigatae")}, u)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(r.Resource).To(Equal("frigatae"))
+			Expect(u.Resource.Resource).To(Equal("frigates"))
+		})
+
+		It("should fail if plugins update a field to different values", func() {
+			_, _, err := runUpdateResource([]Plugin{setPlural("frigatae"), setPlural("frigatoes")}, u)
+			hookErr, ok := err.(*PluginHookError)
+			Expect(ok).To(BeTrue())
+			_, ok = hookErr.Errors[0].(*ResourceConflictError)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should fail if a plugin updates a field which is not recorded", func() {
+			plugin := &resourcePlugin{update: func(r *model.Resource) { r.Kind = "Sloop" }}
+			_, _, err := runUpdateResource([]Plugin{plugin}, u)
+			Expect(err).To(MatchError(ContainSubstring("the Kind of the resource cannot be updated")))
+		})
+	})
+})
@@ -17,13 +17,15 @@ limitations under the License.
 package scaffold
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 
 	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/executor"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
@@ -32,31 +34,59 @@ import (
 	metricsauthv1 "sigs.k8s.io/kubebuilder/pkg/scaffold/v1/metricsauth"
 	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/certmanager"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/e2e"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/grafana"
 	managerv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
 	metricsauthv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2/metricsauth"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/networkpolicy"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/olm"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/prometheus"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 const (
-	// controller runtime version to be used in the project
-	controllerRuntimeVersion = "v0.4.0"
 	// ControllerTools version to be used in the project
 	controllerToolsVersion = "v0.2.4"
+	// version of conversion-gen and defaulter-gen, matching the Kubernetes
+	// version of controller-runtime
+	codeGeneratorVersion = "v0.16.4"
+	// version of kustomize pinned in the tools module
+	kustomizeVersion = "v3.5.4"
+	// version of crd-ref-docs generating the API reference docs
+	crdRefDocsVersion = "v0.0.8"
 )
 
 type ProjectScaffolder interface {
 	EnsureDependencies() (bool, error)
+	// DependencyCommands returns the commands run by EnsureDependencies,
+	// so they can be run later if fetching the dependencies is skipped
+	DependencyCommands() []string
 	Scaffold() error
 	Validate() error
 }
 
+// executorOrDefault returns e, or executor.Default if e is nil
+func executorOrDefault(e executor.Executor) executor.Executor {
+	if e == nil {
+		return executor.Default
+	}
+	return e
+}
+
 type V1Project struct {
 	Project     project.Project
 	Boilerplate project.Boilerplate
 
 	DepArgs          []string
 	DefinitelyEnsure *bool
+
+	// Executor runs dep, defaults to executor.Default
+	Executor executor.Executor
+
+	// Prompter asks whether to run dep ensure if DefinitelyEnsure is nil,
+	// defaults to a Prompter on the standard input and output
+	Prompter util.Prompter
 }
 
 func (p *V1Project) Validate() error {
@@ -69,21 +99,26 @@ func (p *V1Project) Validate() error {
 
 func (p *V1Project) EnsureDependencies() (bool, error) {
 	if p.DefinitelyEnsure == nil {
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Println("Run `dep ensure` to fetch dependencies (Recommended) [y/n]?")
-		if !util.Yesno(reader) {
+		prompter := p.Prompter
+		if prompter == nil {
+			prompter = util.NewPrompter(os.Stdin, os.Stdout)
+		}
+		if !prompter.Confirm("Run `dep ensure` to fetch dependencies (Recommended)", true) {
 			return false, nil
 		}
 	} else if !*p.DefinitelyEnsure {
 		return false, nil
 	}
 
-	c := exec.Command("dep", "ensure") // #nosec
-	c.Args = append(c.Args, p.DepArgs...)
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	return true, c.Run()
+	args := append([]string{"ensure"}, p.DepArgs...)
+	done := logging.StartProgress("dep " + strings.Join(args, " "))
+	err := executorOrDefault(p.Executor).Run("dep", args...)
+	done(err)
+	return true, err
+}
+
+func (p *V1Project) DependencyCommands() []string {
+	return []string{strings.Join(append([]string{"dep", "ensure"}, p.DepArgs...), " ")}
 }
 
 func (p *V1Project) buildUniverse() *model.Universe {
@@ -148,33 +183,240 @@ func (p *V1Project) Scaffold() error {
 type V2Project struct {
 	Project     project.Project
 	Boilerplate project.Boilerplate
+
+	// Grafana indicates whether to scaffold the Grafana dashboards and
+	// the Prometheus alerting rules for the controller-runtime metrics
+	Grafana bool
+
+	// E2E indicates whether to scaffold the e2e test suite which deploys
+	// the project on a kind cluster
+	E2E bool
+
+	// OLM indicates whether to scaffold an Operator Lifecycle Manager bundle
+	OLM bool
+
+	// MultiArch indicates whether to scaffold a Dockerfile and Makefile
+	// target to build the manager image for several platforms with buildx
+	MultiArch bool
+
+	// BaseImage is the base image of the manager image, defaults to distroless
+	BaseImage managerv2.BaseImage
+
+	// EnvtestK8sVersion is the Kubernetes version of the envtest binaries
+	// downloaded by the Makefile for the tests, the tests use the installed
+	// binaries if empty
+	EnvtestK8sVersion string
+
+	// NamespacedManager restricts the manager and its permissions to the
+	// namespace it is deployed in
+	NamespacedManager bool
+
+	// DisableLeaderElection runs the deployed manager without leader election
+	DisableLeaderElection bool
+
+	// HealthProbePort is the port of the health and readiness probes of the
+	// manager, there are no probes if it is 0
+	HealthProbePort int
+
+	// MetricsBindAddress is the address the metrics endpoint of the manager
+	// binds to, defaults to :8080
+	MetricsBindAddress string
+
+	// CodeGenerators indicates whether to run conversion-gen and
+	// defaulter-gen on the API packages
+	CodeGenerators bool
+
+	// SecureDefaults hardens the security context of the manager and
+	// restricts its traffic with network policies, the dev overlay relaxes
+	// both for development
+	SecureDefaults bool
+
+	// CertSource is where the webhook server certificates come from,
+	// defaults to cert-manager
+	CertSource webhook.CertSource
+
+	// DevTooling is the tool running the local development loop, defaults
+	// to none
+	DevTooling scaffoldv2.DevTooling
+
+	// WebhookPort is the port the webhook server of the manager listens on,
+	// defaults to 9443
+	WebhookPort int
+
+	// WebhookCertDir is the directory the webhook server of the manager
+	// reads its certificates from, defaults to
+	// /tmp/k8s-webhook-server/serving-certs
+	WebhookCertDir string
+
+	// Profiling serves the pprof endpoints of the manager and sets up its
+	// OpenTelemetry trace exporter, which is enabled by the environment
+	Profiling bool
+
+	// EnvOverlays indicates whether to scaffold the overlays of the dev,
+	// staging and prod environments, and their deploy targets
+	EnvOverlays bool
+
+	// CertIssuer is the name of an existing cert-manager Issuer to use
+	// instead of the scaffolded self-signed one
+	CertIssuer string
+
+	// GoVersion is the Go release of the go directive of go.mod and of the
+	// builder image, defaults to DefaultGoVersion. It determines the pinned
+	// controller-runtime version.
+	GoVersion string
+
+	// PinnedTools indicates whether to scaffold the tools module in
+	// hack/tools pinning the versions of the tools installed by the
+	// Makefile, which also verifies the checksums of the envtest binaries
+	// once they are recorded, on first use
+	PinnedTools bool
+
+	// APIDocs indicates whether to scaffold the docsgen Makefile target
+	// generating the API reference docs, and its configuration under docs
+	APIDocs bool
+
+	// Executor runs the commands fetching the dependencies, defaults to
+	// executor.Default
+	Executor executor.Executor
 }
 
+var envtestK8sVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
 func (p *V2Project) Validate() error {
-	return nil
+	if p.GoVersion == "" {
+		p.GoVersion = DefaultGoVersion
+	}
+	if err := ValidateGoVersion(p.GoVersion); err != nil {
+		return err
+	}
+	if p.Profiling && compareGoVersions(p.GoVersion, scaffoldv2.OpenTelemetryMinGoVersion) < 0 {
+		return fmt.Errorf("profiling requires Go %s or later for the OpenTelemetry modules (was %s)",
+			scaffoldv2.OpenTelemetryMinGoVersion, p.GoVersion)
+	}
+	if p.DevTooling == "" {
+		p.DevTooling = scaffoldv2.DevToolingNone
+	}
+	if err := p.DevTooling.Validate(); err != nil {
+		return err
+	}
+	if p.CertSource == "" {
+		p.CertSource = webhook.CertSourceCertManager
+	}
+	if err := p.CertSource.Validate(); err != nil {
+		return err
+	}
+	if p.CertIssuer != "" && p.CertSource != webhook.CertSourceCertManager {
+		return fmt.Errorf("a cert-manager issuer can only be set with the %s certificate source",
+			webhook.CertSourceCertManager)
+	}
+	if p.EnvtestK8sVersion != "" && !envtestK8sVersionRegexp.MatchString(p.EnvtestK8sVersion) {
+		return fmt.Errorf("envtest Kubernetes version must be major.minor.patch, e.g. 1.16.4 (was %s)",
+			p.EnvtestK8sVersion)
+	}
+	if p.MetricsBindAddress == "" {
+		p.MetricsBindAddress = managerv2.DefaultMetricsBindAddress
+	}
+	metricsPort, err := managerv2.BindAddressPort(p.MetricsBindAddress)
+	if err != nil {
+		return fmt.Errorf("invalid metrics bind address: %v", err)
+	}
+	if p.HealthProbePort != 0 {
+		if err := managerv2.ValidatePort(p.HealthProbePort); err != nil {
+			return fmt.Errorf("invalid health probe port: %v", err)
+		}
+		if p.HealthProbePort == metricsPort {
+			return fmt.Errorf("the health probes and the metrics cannot use the same port %d", metricsPort)
+		}
+	}
+	if p.WebhookPort != 0 {
+		if err := managerv2.ValidateWebhookPort(p.WebhookPort); err != nil {
+			return fmt.Errorf("invalid webhook port: %v", err)
+		}
+		switch p.WebhookPort {
+		case metricsPort:
+			return fmt.Errorf("the webhook server and the metrics cannot use the same port %d", metricsPort)
+		case p.HealthProbePort:
+			return fmt.Errorf("the webhook server and the health probes cannot use the same port %d", p.WebhookPort)
+		}
+		if p.Profiling && p.WebhookPort == managerv2.PprofPort {
+			return fmt.Errorf("port %d is already used by the pprof endpoints", managerv2.PprofPort)
+		}
+	}
+	if p.WebhookCertDir != "" {
+		if err := webhook.ValidateCertDir(p.WebhookCertDir); err != nil {
+			return err
+		}
+	}
+	if p.Profiling && (metricsPort == managerv2.PprofPort || p.HealthProbePort == managerv2.PprofPort) {
+		return fmt.Errorf("port %d is already used by the pprof endpoints", managerv2.PprofPort)
+	}
+	if p.BaseImage == "" {
+		p.BaseImage = managerv2.BaseImageDistroless
+	}
+	return p.BaseImage.Validate()
+}
+
+// codeGeneratorVersion returns the version of conversion-gen and defaulter-gen
+// run by the Makefile, if any
+func (p *V2Project) codeGeneratorVersion() string {
+	if !p.CodeGenerators {
+		return ""
+	}
+	return codeGeneratorVersion
+}
+
+// crdRefDocsVersion returns the version of crd-ref-docs run by the Makefile, if
+// any
+func (p *V2Project) crdRefDocsVersion() string {
+	if !p.APIDocs {
+		return ""
+	}
+	return crdRefDocsVersion
+}
+
+// controllerRuntimeVersion returns the controller-runtime version pinned in
+// go.mod, from the compatibility table of the Go version
+func (p *V2Project) controllerRuntimeVersion() string {
+	v, _ := ControllerRuntimeVersionFor(p.GoVersion)
+	return v
+}
+
+// environments returns the environments of the overlays, if any
+func (p *V2Project) environments() []scaffoldv2.Environment {
+	if !p.EnvOverlays {
+		return nil
+	}
+	return scaffoldv2.Environments
+}
+
+// dependencyArgs returns the commands to fetch the dependencies of the project
+func (p *V2Project) dependencyArgs() [][]string {
+	return [][]string{
+		// ensure that we are pinning controller-runtime version
+		// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
+		{"go", "get", "sigs.k8s.io/controller-runtime@" + p.controllerRuntimeVersion()},
+		{"go", "mod", "tidy"},
+	}
 }
 
 func (p *V2Project) EnsureDependencies() (bool, error) {
-	// ensure that we are pinning controller-runtime version
-	// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
-	c := exec.Command("go", "get", "sigs.k8s.io/controller-runtime@"+controllerRuntimeVersion) // #nosec
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	err := c.Run()
-	if err != nil {
-		return false, err
+	for _, args := range p.dependencyArgs() {
+		done := logging.StartProgress(strings.Join(args, " "))
+		err := executorOrDefault(p.Executor).Run(args[0], args[1:]...)
+		done(err)
+		if err != nil {
+			return false, err
+		}
 	}
+	return true, nil
+}
 
-	c = exec.Command("go", "mod", "tidy") // #nosec
-	c.Stderr = os.Stderr
-	c.Stdout = os.Stdout
-	fmt.Println(strings.Join(c.Args, " "))
-	err = c.Run()
-	if err != nil {
-		return false, err
+func (p *V2Project) DependencyCommands() []string {
+	var commands []string
+	for _, args := range p.dependencyArgs() {
+		commands = append(commands, strings.Join(args, " "))
 	}
-	return true, err
+	return commands
 }
 
 func (p *V2Project) buildUniverse() *model.Universe {
@@ -212,34 +454,168 @@ func (p *V2Project) Scaffold() error {
 	// default controller manager image name
 	imgName := "controller:latest"
 
-	s = &Scaffold{}
-	return s.Execute(
-		p.buildUniverse(),
-		input.Options{ProjectPath: projectInput.Path, BoilerplatePath: bpInput.Path},
+	// the port of the metrics endpoint for the auth proxy, already checked
+	// by Validate
+	metricsPort, err := managerv2.BindAddressPort(p.MetricsBindAddress)
+	if err != nil {
+		return err
+	}
+
+	// main.go only sets the certificate directory if it is not the
+	// controller-runtime default
+	mainCertDir := p.WebhookCertDir
+	if mainCertDir == webhook.DefaultCertDir {
+		mainCertDir = ""
+	}
+
+	files := []input.File{
 		&project.GitIgnore{},
-		&metricsauthv2.KustomizeAuthProxyPatch{},
+		&metricsauthv2.KustomizeAuthProxyPatch{MetricsPort: metricsPort, LeaderElection: !p.DisableLeaderElection},
 		&scaffoldv2.AuthProxyService{},
 		&project.AuthProxyRole{},
 		&project.AuthProxyRoleBinding{},
-		&managerv2.Config{Image: imgName},
-		&scaffoldv2.Main{},
-		&scaffoldv2.GoMod{ControllerRuntimeVersion: controllerRuntimeVersion},
-		&scaffoldv2.Makefile{Image: imgName, ControllerToolsVersion: controllerToolsVersion},
-		&scaffoldv2.Dockerfile{},
-		&scaffoldv2.Kustomize{},
-		&scaffoldv2.ManagerWebhookPatch{},
-		&scaffoldv2.ManagerRoleBinding{},
+		&managerv2.Config{Image: imgName, BaseImage: p.BaseImage,
+			LeaderElection: !p.DisableLeaderElection, HealthProbePort: p.HealthProbePort,
+			SecureDefaults: p.SecureDefaults},
+		&scaffoldv2.Main{WatchNamespace: p.NamespacedManager,
+			MetricsBindAddress: p.MetricsBindAddress, HealthProbePort: p.HealthProbePort, Profiling: p.Profiling,
+			WebhookPort: p.WebhookPort, WebhookCertDir: mainCertDir},
+		&scaffoldv2.GoMod{GoVersion: p.GoVersion, ControllerRuntimeVersion: p.controllerRuntimeVersion(),
+			Profiling: p.Profiling},
+		&scaffoldv2.Makefile{Image: imgName, ControllerToolsVersion: controllerToolsVersion,
+			E2E: p.E2E, OLM: p.OLM, MultiArch: p.MultiArch, EnvtestK8sVersion: p.EnvtestK8sVersion,
+			DevOverlay: p.SecureDefaults, Environments: p.environments(), CodeGeneratorVersion: p.codeGeneratorVersion(),
+			PinnedTools: p.PinnedTools, CRDRefDocsVersion: p.crdRefDocsVersion(),
+			GeneratedCerts: p.CertSource == webhook.CertSourceGenerated, WatchNamespace: p.NamespacedManager},
+		&scaffoldv2.Dockerfile{MultiArch: p.MultiArch, BaseImage: p.BaseImage, GoVersion: p.GoVersion},
+		&scaffoldv2.Kustomize{WatchNamespacePatch: p.NamespacedManager, CertSource: p.CertSource,
+			NetworkPolicy: p.SecureDefaults, ProfilingPatch: p.Profiling},
+		&scaffoldv2.ManagerWebhookPatch{CertSource: p.CertSource, Port: p.WebhookPort, CertDir: p.WebhookCertDir},
+		&scaffoldv2.ManagerRoleBinding{Namespaced: p.NamespacedManager},
 		&scaffoldv2.LeaderElectionRole{},
 		&scaffoldv2.LeaderElectionRoleBinding{},
-		&scaffoldv2.KustomizeRBAC{},
+		&scaffoldv2.KustomizeRBAC{Profiling: p.Profiling},
 		&managerv2.Kustomization{},
-		&webhook.Kustomization{},
+		&webhook.Kustomization{CertSource: p.CertSource},
 		&webhook.KustomizeConfigWebhook{},
-		&webhook.Service{},
-		&webhook.InjectCAPatch{},
-		&prometheus.Kustomization{},
+		&webhook.Service{Port: p.WebhookPort},
+		&prometheus.Kustomization{Rules: p.Grafana},
 		&prometheus.PrometheusServiceMonitor{},
-		&certmanager.CertManager{},
-		&certmanager.Kustomization{},
-		&certmanager.KustomizeConfig{})
+	}
+
+	switch p.CertSource {
+	case webhook.CertSourceCertManager:
+		files = append(files,
+			&webhook.InjectCAPatch{},
+			&certmanager.CertManager{Issuer: p.CertIssuer},
+			&certmanager.Kustomization{},
+			&certmanager.KustomizeConfig{},
+		)
+	case webhook.CertSourceBootstrap:
+		files = append(files, &webhook.BootstrapRBAC{})
+	case webhook.CertSourceGenerated:
+		files = append(files, &webhook.Certs{})
+	}
+
+	if p.Grafana {
+		files = append(files,
+			&prometheus.PrometheusRule{},
+			&grafana.RuntimeDashboard{},
+		)
+	}
+
+	if p.NamespacedManager {
+		files = append(files, &scaffoldv2.ManagerWatchNamespacePatch{})
+	}
+
+	if p.Profiling {
+		files = append(files,
+			&scaffoldv2.Profiling{},
+			&scaffoldv2.ManagerProfilingPatch{},
+			&scaffoldv2.PprofService{},
+			&scaffoldv2.PprofReaderRole{},
+		)
+	}
+
+	if p.SecureDefaults {
+		// the pprof endpoints are only allowed if there are some
+		pprofPort := 0
+		if p.Profiling {
+			pprofPort = managerv2.PprofPort
+		}
+		files = append(files,
+			&networkpolicy.ManagerPolicy{MetricsPort: metricsPort, HealthProbePort: p.HealthProbePort,
+				WebhookPort: p.WebhookPort, PprofPort: pprofPort},
+			&networkpolicy.Kustomization{},
+			&scaffoldv2.DevKustomization{},
+			&scaffoldv2.ManagerDevPatch{},
+			&scaffoldv2.NetworkPolicyDevPatch{},
+		)
+	}
+
+	// the dev environment builds on the dev overlay if there is one
+	devBase := "default"
+	if p.SecureDefaults {
+		devBase = "dev"
+	}
+	for _, env := range p.environments() {
+		base := "default"
+		if env.Name == "dev" {
+			base = devBase
+		}
+		files = append(files,
+			&scaffoldv2.EnvKustomization{Environment: env, Base: base},
+			&scaffoldv2.EnvManagerPatch{Environment: env, LeaderElection: !p.DisableLeaderElection},
+		)
+	}
+
+	// the development loop deploys the dev overlay if there is one
+	devOverlay := "config/" + devBase
+	if p.EnvOverlays {
+		devOverlay = "config/overlays/dev"
+	}
+	switch p.DevTooling {
+	case scaffoldv2.DevToolingTilt:
+		files = append(files, &scaffoldv2.Tiltfile{Overlay: devOverlay})
+	case scaffoldv2.DevToolingSkaffold:
+		files = append(files, &scaffoldv2.SkaffoldConfig{Overlay: devOverlay})
+	}
+
+	if p.PinnedTools {
+		files = append(files,
+			&scaffoldv2.ToolsGoMod{GoVersion: p.GoVersion, ControllerToolsVersion: controllerToolsVersion,
+				KustomizeVersion: kustomizeVersion, CodeGeneratorVersion: p.codeGeneratorVersion(),
+				CRDRefDocsVersion: p.crdRefDocsVersion()},
+			&scaffoldv2.ToolsGo{CodeGenerators: p.CodeGenerators, APIDocs: p.APIDocs},
+		)
+	}
+
+	if p.APIDocs {
+		files = append(files,
+			&docs.Config{},
+			&docs.Header{},
+			&docs.Footer{},
+		)
+	}
+
+	if p.E2E {
+		files = append(files,
+			&e2e.SuiteTest{},
+			&e2e.Utils{},
+		)
+	}
+
+	if p.OLM {
+		files = append(files,
+			&olm.CSV{},
+			&olm.Annotations{},
+			&olm.Dockerfile{},
+		)
+	}
+
+	s = &Scaffold{}
+	return s.Execute(
+		p.buildUniverse(),
+		input.Options{ProjectPath: projectInput.Path, BoilerplatePath: bpInput.Path},
+		files...)
 }
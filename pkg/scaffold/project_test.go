@@ -0,0 +1,76 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/cmd/util"
+	"sigs.k8s.io/kubebuilder/pkg/executor"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+var _ = Describe("V1Project", func() {
+	It("should ask whether to run dep ensure", func() {
+		fake := &executor.Fake{}
+		p := &V1Project{Executor: fake, Prompter: util.NewPrompter(strings.NewReader("n\n"), ioutil.Discard)}
+		Expect(p.EnsureDependencies()).To(BeFalse())
+		Expect(fake.Commands).To(BeEmpty())
+
+		p.Prompter = util.NewDefaultsPrompter()
+		Expect(p.EnsureDependencies()).To(BeTrue())
+		Expect(fake.Commands).To(Equal([][]string{{"dep", "ensure"}}))
+	})
+
+	It("should not ask whether to run dep ensure when it is decided", func() {
+		fake := &executor.Fake{}
+		ensure := false
+		p := &V1Project{Executor: fake, DefinitelyEnsure: &ensure, Prompter: util.NewPrompter(
+			strings.NewReader("y\n"), ioutil.Discard)}
+		Expect(p.EnsureDependencies()).To(BeFalse())
+		Expect(p.Prompter.Decisions()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("V2Project", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-project")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "proj"), 0750)).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "proj"))).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should set the namespace of a namespaced manager in make run", func() {
+		p := &V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Domain:  "example.com",
+				Repo:    "example.com/proj",
+			}},
+			Boilerplate:       project.Boilerplate{License: "none"},
+			NamespacedManager: true,
+		}
+		Expect(p.Validate()).To(Succeed())
+		Expect(p.Scaffold()).To(Succeed())
+
+		makefile, err := ioutil.ReadFile("Makefile")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(makefile)).To(ContainSubstring("\nWATCH_NAMESPACE ?= default\n"))
+		Expect(string(makefile)).To(ContainSubstring("\n\tWATCH_NAMESPACE=$(WATCH_NAMESPACE) go run ./main.go\n"))
+	})
+})
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// topLevelKeyRegexp matches the lines starting a top-level field of a YAML
+// document.
+var topLevelKeyRegexp = regexp.MustCompile(`^([^\s#\-][^:]*):(\s|$)`)
+
+// yamlField is a top-level field of a YAML document.
+type yamlField struct {
+	key string
+	// comments are the comment and blank lines before the field
+	comments []string
+	// lines are the lines of the field, including the comments in its value
+	lines []string
+}
+
+// splitFields splits a YAML document into its top-level fields, and returns
+// the comment and blank lines after the last field.
+func splitFields(content []byte) ([]yamlField, []string) {
+	fields := []yamlField{}
+	pending := []string{}
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		switch {
+		case topLevelKeyRegexp.MatchString(line):
+			key := topLevelKeyRegexp.FindStringSubmatch(line)[1]
+			fields = append(fields, yamlField{key: key, comments: pending, lines: []string{line}})
+			pending = []string{}
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#"):
+			pending = append(pending, line)
+		case len(fields) > 0:
+			// the comments between the lines of a value are part of it
+			last := &fields[len(fields)-1]
+			last.lines = append(last.lines, pending...)
+			last.lines = append(last.lines, line)
+			pending = []string{}
+		default:
+			pending = append(pending, line)
+		}
+	}
+	return fields, pending
+}
+
+// sameValue returns true if both YAML fields have the same value.
+func sameValue(a, b yamlField) bool {
+	var va, vb interface{}
+	if err := yaml.Unmarshal([]byte(strings.Join(a.lines, "\n")), &va); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(b.lines, "\n")), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// preserveLayout returns the marshalled content of a project file in the
+// layout of the existing file: the fields are kept in the same order with the
+// comments before them, and the fields whose value did not change are kept as
+// they are written. The new fields are added at the end.
+//
+// Only the comments of the changed fields and of the removed fields are lost.
+func preserveLayout(existing, content []byte) []byte {
+	if strings.TrimSpace(string(existing)) == "" {
+		return content
+	}
+	oldFields, footer := splitFields(existing)
+	newFields, _ := splitFields(content)
+
+	byKey := map[string]yamlField{}
+	for _, f := range newFields {
+		byKey[f.key] = f
+	}
+
+	out := []string{}
+	written := map[string]bool{}
+	for _, old := range oldFields {
+		f, found := byKey[old.key]
+		if !found {
+			continue
+		}
+		out = append(out, old.comments...)
+		if sameValue(old, f) {
+			out = append(out, old.lines...)
+		} else {
+			out = append(out, f.lines...)
+		}
+		written[old.key] = true
+	}
+	for _, f := range newFields {
+		if !written[f.key] {
+			out = append(out, f.lines...)
+		}
+	}
+	out = append(out, footer...)
+	return []byte(strings.Join(out, "\n") + "\n")
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// ProjectLockedError is returned when the project file is being updated by
+// another kubebuilder command.
+type ProjectLockedError struct {
+	// LockPath is the path of the lock file
+	LockPath string
+}
+
+func (e *ProjectLockedError) Error() string {
+	return fmt.Sprintf("the project file is being updated by another kubebuilder command, "+
+		"remove %s if no other command is running", e.LockPath)
+}
+
+// lockProjectFile takes the advisory lock of the project file at the given
+// path, and returns the function which releases it.
+func lockProjectFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if os.IsExist(err) {
+		return nil, &ProjectLockedError{LockPath: lockPath}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock project file at %s %v", path, err)
+	}
+	// the pid helps finding out which command holds a stale lock
+	_, err = fmt.Fprintf(f, "%d\n", os.Getpid())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(lockPath)
+		return nil, fmt.Errorf("failed to lock project file at %s %v", path, err)
+	}
+
+	return func() { _ = os.Remove(lockPath) }, nil
+}
+
+// updateProjectFile applies the update to the project file at the given path
+// while holding its lock, so that the changes of concurrent commands are not
+// lost, and returns the updated project file.
+func updateProjectFile(path string, update func(*input.ProjectFile)) (*input.ProjectFile, error) {
+	unlock, err := lockProjectFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	p, err := LoadProjectFile(path)
+	if err != nil {
+		return nil, err
+	}
+	update(&p)
+	if err := saveProjectFile(path, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// RecordPluginConfig stores the configuration of the plugin with the given key
+// in the project file at the given path, see ProjectFile.EncodePluginConfig.
+func RecordPluginConfig(path, key string, configObj interface{}) error {
+	var encodeErr error
+	_, err := updateProjectFile(path, func(p *input.ProjectFile) {
+		encodeErr = p.EncodePluginConfig(key, configObj)
+	})
+	if err != nil {
+		return err
+	}
+	return encodeErr
+}
+
+// CreateProjectFile saves a new project file at the given path, while holding
+// its lock. It fails if the project file already exists.
+func CreateProjectFile(path string, p *input.ProjectFile) error {
+	unlock, err := lockProjectFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("project file %s already exists: %w", path, ErrProjectExists)
+	}
+	return saveProjectFile(path, p)
+}
@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ = Describe("Project file", func() {
+	var dir, path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubebuilder-project")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "PROJECT")
+		Expect(ioutil.WriteFile(path, []byte("version: \"2\"\nrepo: example.com/project\n"), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	addResource := func(p *input.ProjectFile) {
+		p.Resources = append(p.Resources, input.Resource{Group: "ship", Version: "v1", Kind: "Frigate"})
+	}
+
+	It("should save the update and release the lock", func() {
+		p, err := updateProjectFile(path, addResource)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Resources).To(HaveLen(1))
+
+		saved, err := LoadProjectFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.Repo).To(Equal("example.com/project"))
+		Expect(saved.Resources).To(Equal(p.Resources))
+
+		files, err := ioutil.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("should fail with a ProjectLockedError while the lock is held", func() {
+		unlock, err := lockProjectFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = updateProjectFile(path, addResource)
+		Expect(err).To(BeAssignableToTypeOf(&ProjectLockedError{}))
+
+		unlock()
+		_, err = updateProjectFile(path, addResource)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should keep the order of the fields and the comments", func() {
+		existing := `# Project of the fleet
+version: "2"
+
+# the module of the project
+repo: example.com/project
+domain: 'testproject.org'
+`
+		Expect(ioutil.WriteFile(path, []byte(existing), 0600)).To(Succeed())
+
+		_, err := updateProjectFile(path, addResource)
+		Expect(err).NotTo(HaveOccurred())
+
+		b, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(existing + `resources:
+- group: ship
+  kind: Frigate
+  version: v1
+`))
+	})
+
+	It("should record the configuration of a plugin", func() {
+		Expect(RecordPluginConfig(path, "addon.kubebuilder.io", map[string]string{"channel": "stable"})).To(Succeed())
+
+		saved, err := LoadProjectFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		cfg := map[string]string{}
+		Expect(saved.DecodePluginConfig("addon.kubebuilder.io", &cfg)).To(Succeed())
+		Expect(cfg).To(Equal(map[string]string{"channel": "stable"}))
+		Expect(saved.Repo).To(Equal("example.com/project"))
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+)
+
+// PruningFields are the fields of a kind whose unknown fields can be
+// preserved, see API.PreserveUnknownFields
+var PruningFields = []string{"spec", "status"}
+
+var (
+	crdOptionsVar = regexp.MustCompile(`(?m)^CRD_OPTIONS\s*\??=\s*"?([^"\n]*)"?`)
+	crdV1         = regexp.MustCompile(`crdVersions=\{?v1\b`)
+)
+
+// validatePruning checks the pruning options of the types, and warns about the
+// ones the CRDs generated for the project do not honor
+func (api *API) validatePruning() error {
+	if api.project.Version != project.Version2 {
+		return fmt.Errorf("the pruning options are only supported for project version %s", project.Version2)
+	}
+	if !api.DoResource {
+		return fmt.Errorf("the pruning options require the resource to be generated")
+	}
+	for _, f := range api.PreserveUnknownFields {
+		valid := false
+		for _, field := range PruningFields {
+			valid = valid || f == field
+		}
+		if !valid {
+			return fmt.Errorf("unknown field %q to preserve the unknown fields of, should be one of %s",
+				f, strings.Join(PruningFields, ", "))
+		}
+	}
+	if len(api.EmbeddedResources) > 0 && len(api.Fields) == 0 {
+		return fmt.Errorf("the embedded resources must be object fields of the Spec defined with --field")
+	}
+
+	options := crdOptions()
+	if crdV1.MatchString(options) {
+		return nil
+	}
+	logging.Warnf("The CRDs are generated as apiextensions.k8s.io/v1beta1, whose x-kubernetes-preserve-unknown-fields " +
+		"and x-kubernetes-embedded-resource need Kubernetes 1.15 or later.")
+	if len(api.PreserveUnknownFields) > 0 && !strings.Contains(options, "preserveUnknownFields=false") {
+		logging.Warnf("The v1beta1 CRDs keep all the unknown fields unless the CRD_OPTIONS of the Makefile have "+
+			"crd:preserveUnknownFields=false, until then preserving the unknown fields of %s makes no difference.",
+			strings.Join(api.PreserveUnknownFields, " and "))
+	}
+	return nil
+}
+
+// crdOptions returns the options of controller-gen generating the CRDs, i.e.
+// the CRD_OPTIONS of the Makefile, which are empty if it has none
+func crdOptions() string {
+	b, err := ioutil.ReadFile("Makefile")
+	if err != nil {
+		return ""
+	}
+	if m := crdOptionsVar.FindSubmatch(b); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
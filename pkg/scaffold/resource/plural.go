@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gobuffalo/flect"
+)
+
+var (
+	pluralsMu sync.RWMutex
+	// plurals are the irregular plurals of lower case kinds, which flect
+	// does not pluralize correctly
+	plurals = map[string]string{}
+)
+
+// RegisterPlural registers the plural of a kind which is not pluralized
+// correctly, e.g. a domain term. Plugins can call it to extend the registry.
+func RegisterPlural(kind, plural string) {
+	pluralsMu.Lock()
+	defer pluralsMu.Unlock()
+	plurals[strings.ToLower(kind)] = strings.ToLower(plural)
+}
+
+// Pluralize returns the lower case plural of the kind, from the registered
+// plurals or else from flect.
+func Pluralize(kind string) string {
+	kind = strings.ToLower(kind)
+
+	pluralsMu.RLock()
+	defer pluralsMu.RUnlock()
+	if plural, found := plurals[kind]; found {
+		return plural
+	}
+	return flect.Pluralize(kind)
+}
+
+// Plural returns the plural of the resource, that is the resource name if
+// set or else the plural of the kind.
+func (r *Resource) Plural() string {
+	if r.Resource != "" {
+		return r.Resource
+	}
+	return Pluralize(r.Kind)
+}
+
+// HasCustomPlural returns true if the plural of the resource is not the one
+// computed by controller-gen, which then needs a marker to use it for the CRD.
+func (r *Resource) HasCustomPlural() bool {
+	return r.Plural() != flect.Pluralize(strings.ToLower(r.Kind))
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// printColumnTypes are the OpenAPI types of the additional printer columns
+var printColumnTypes = []string{"string", "integer", "number", "boolean", "date"}
+
+// PrintColumn is an additional printer column of the resource, printed by
+// kubectl get.
+type PrintColumn struct {
+	// Name is the header of the column
+	Name string
+
+	// JSONPath is the path of the printed field, e.g. .status.phase
+	JSONPath string
+
+	// Type is the OpenAPI type of the field
+	Type string
+}
+
+// ParsePrintColumn parses a printer column written as name:jsonPath or
+// name:jsonPath:type, e.g. Age:.metadata.creationTimestamp. The type defaults
+// to date for the creation timestamp and to string for the other fields.
+func ParsePrintColumn(s string) (PrintColumn, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return PrintColumn{}, fmt.Errorf("printer column %q should be name:jsonPath or name:jsonPath:type", s)
+	}
+	c := PrintColumn{Name: parts[0], JSONPath: parts[1], Type: "string"}
+	if c.JSONPath == ".metadata.creationTimestamp" {
+		c.Type = "date"
+	}
+	if len(parts) == 3 {
+		c.Type = parts[2]
+	}
+	return c, c.Validate()
+}
+
+// Validate checks the printer column values to make sure they are valid.
+func (c PrintColumn) Validate() error {
+	if !strings.HasPrefix(c.JSONPath, ".") {
+		return fmt.Errorf("JSON path of printer column %s must start with a dot (was %q)", c.Name, c.JSONPath)
+	}
+	if strings.ContainsAny(c.Name+c.JSONPath, `",`) {
+		return fmt.Errorf("printer column %s cannot contain quotes or commas", c.Name)
+	}
+	for _, t := range printColumnTypes {
+		if c.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("type of printer column %s must be one of %s (was %s)",
+		c.Name, strings.Join(printColumnTypes, ", "), c.Type)
+}
+
+// Marker returns the +kubebuilder:printcolumn marker of the column.
+func (c PrintColumn) Marker() string {
+	return fmt.Sprintf(`// +kubebuilder:printcolumn:name=%q,type=%q,JSONPath=%q`, c.Name, c.Type, c.JSONPath)
+}
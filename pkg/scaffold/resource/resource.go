@@ -29,7 +29,8 @@ type Resource struct {
 	// Namespaced is true if the resource is namespaced
 	Namespaced bool
 
-	// Group is the API Group.  Does not contain the domain.
+	// Group is the API Group.  Does not contain the domain.  It is empty for
+	// the APIs whose group is the domain itself.
 	Group string
 
 	// GroupImportSafe is the API Group.  Does not contain the domain and it the "-"
@@ -42,20 +43,27 @@ type Resource struct {
 	// Kind is the API Kind.
 	Kind string
 
-	// Resource is the API Resource.
+	// Resource is the API Resource, that is the plural of the Kind.
 	Resource string
 
 	// ShortNames is the list of resource shortnames.
 	ShortNames []string
 
+	// Categories are the groups of resources the resource belongs to, e.g.
+	// "all" for kubectl get all.
+	Categories []string
+
+	// PrintColumns are the additional printer columns of the resource.
+	PrintColumns []PrintColumn
+
 	// CreateExampleReconcileBody will create a Deployment in the Reconcile example
 	CreateExampleReconcileBody bool
 }
 
 // Validate checks the Resource values to make sure they are valid.
 func (r *Resource) Validate() error {
-	if r.isGroupEmpty() {
-		return fmt.Errorf("group cannot be empty")
+	if r.isGroupFlag() {
+		return fmt.Errorf("group cannot be %s", r.Group)
 	}
 	if r.isVersionEmpty() {
 		return fmt.Errorf("version cannot be empty")
@@ -63,9 +71,12 @@ func (r *Resource) Validate() error {
 	if r.isKindEmpty() {
 		return fmt.Errorf("kind cannot be empty")
 	}
-	// Check if the Group has a valid value for for it
-	if err := IsDNS1123Subdomain(r.Group); err != nil {
-		return fmt.Errorf("group name is invalid: (%v)", err)
+	// Check if the Group has a valid value for for it, an empty group is the
+	// domain of the project
+	if r.Group != "" {
+		if err := IsDNS1123Subdomain(r.Group); err != nil {
+			return fmt.Errorf("group name is invalid: (%v)", err)
+		}
 	}
 	// Check if the version is a valid value
 	versionMatch := regexp.MustCompile(`^v\d+(alpha\d+|beta\d+)?$`)
@@ -78,10 +89,33 @@ func (r *Resource) Validate() error {
 		return fmt.Errorf("kind must be PascalCase (expected %s was %s)", flect.Pascalize(r.Kind), r.Kind)
 	}
 
+	// Check if the plural is a valid value
+	if len(r.Resource) != 0 {
+		if err := IsDNS1123Label(r.Resource); err != nil {
+			return fmt.Errorf("plural is invalid: (%v)", err)
+		}
+	}
+
+	for _, name := range r.ShortNames {
+		if err := IsDNS1123Label(name); err != nil {
+			return fmt.Errorf("short name is invalid: (%v)", err)
+		}
+	}
+	for _, category := range r.Categories {
+		if err := IsDNS1123Label(category); err != nil {
+			return fmt.Errorf("category is invalid: (%v)", err)
+		}
+	}
+	for _, c := range r.PrintColumns {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// todo: move it for the proper place since they are not validations and then, should not be here
 	// Add in r.Resource the Kind plural
 	if len(r.Resource) == 0 {
-		r.Resource = flect.Pluralize(strings.ToLower(r.Kind))
+		r.Resource = Pluralize(r.Kind)
 	}
 	// Replace the caracter "-" for "" to allow scaffold the go imports
 	r.GroupImportSafe = strings.Replace(r.Group, "-", "", -1)
@@ -89,6 +123,29 @@ func (r *Resource) Validate() error {
 	return nil
 }
 
+// QualifiedGroup returns the API group of the group in the domain, which is
+// the group itself if the domain is empty, and the domain if the group is.
+func QualifiedGroup(group, domain string) string {
+	switch {
+	case domain == "":
+		return group
+	case group == "":
+		return domain
+	}
+	return group + "." + domain
+}
+
+// SampleFileName returns the name of the sample file of the resource in
+// config/samples, <group>_<version>_<kind>.yaml, or <version>_<kind>.yaml if
+// the group is empty.
+func (r *Resource) SampleFileName() string {
+	name := fmt.Sprintf("%s_%s.yaml", r.Version, strings.ToLower(r.Kind))
+	if r.Group == "" {
+		return name
+	}
+	return r.Group + "_" + name
+}
+
 // isKindEmpty will return true if the --kind flag do not be informed
 // NOTE: required check if the flags are assuming the other flags as value
 func (r *Resource) isKindEmpty() bool {
@@ -101,10 +158,10 @@ func (r *Resource) isVersionEmpty() bool {
 	return len(r.Version) == 0 || r.Version == "--group" || r.Version == "--kind"
 }
 
-// isVersionEmpty will return true if the --group flag do not be informed
-// NOTE: required check if the flags are assuming the other flags as value
-func (r *Resource) isGroupEmpty() bool {
-	return len(r.Group) == 0 || r.Group == "--version" || r.Group == "--kind"
+// isGroupFlag will return true if the --group flag took the next flag as value
+// NOTE: the group can be empty, for the APIs in the domain of the project
+func (r *Resource) isGroupFlag() bool {
+	return r.Group == "--version" || r.Group == "--kind"
 }
 
 // The following code came from "k8s.io/apimachinery/pkg/util/validation"
@@ -112,13 +169,31 @@ func (r *Resource) isGroupEmpty() bool {
 // ---------------------------------------
 const (
 	dns1123LabelFmt          string = "[a-z0-9]([-a-z0-9]*[a-z0-9])?"
+	dns1123LabelErrMsg       string = "a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character"
 	dns1123SubdomainFmt      string = dns1123LabelFmt + "(\\." + dns1123LabelFmt + ")*"
 	dns1123SubdomainErrorMsg string = "a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character"
 
+	// dns1123LabelMaxLength is a label's max length in DNS (RFC 1123)
+	dns1123LabelMaxLength int = 63
 	// dns1123SubdomainMaxLength is a subdomain's max length in DNS (RFC 1123)
 	dns1123SubdomainMaxLength int = 253
 )
 
+var dns1123LabelRegexp = regexp.MustCompile("^" + dns1123LabelFmt + "$")
+
+// IsDNS1123Label tests for a string that conforms to the definition of a
+// label in DNS (RFC 1123).
+func IsDNS1123Label(value string) []string {
+	var errs []string
+	if len(value) > dns1123LabelMaxLength {
+		errs = append(errs, maxLenError(dns1123LabelMaxLength))
+	}
+	if !dns1123LabelRegexp.MatchString(value) {
+		errs = append(errs, regexError(dns1123LabelErrMsg, dns1123LabelFmt, "my-name", "123-abc"))
+	}
+	return errs
+}
+
 var dns1123SubdomainRegexp = regexp.MustCompile("^" + dns1123SubdomainFmt + "$")
 
 // IsDNS1123Subdomain tests for a string that conforms to the definition of a
@@ -14,10 +14,16 @@ var _ = Describe("Resource", func() {
 			Expect(instance.Validate()).To(Succeed())
 		})
 
-		It("should fail if the Group is not specified", func() {
+		It("should succeed if the Group is empty", func() {
 			instance := &Resource{Version: "v1", Kind: "FirstMate"}
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.SampleFileName()).To(Equal("v1_firstmate.yaml"))
+		})
+
+		It("should fail if the Group flag took the next flag as value", func() {
+			instance := &Resource{Group: "--version", Version: "v1", Kind: "FirstMate"}
 			Expect(instance.Validate()).NotTo(Succeed())
-			Expect(instance.Validate().Error()).To(ContainSubstring("group cannot be empty"))
+			Expect(instance.Validate().Error()).To(ContainSubstring("group cannot be --version"))
 		})
 
 		It("should fail if the Group is not all lowercase", func() {
@@ -128,5 +134,76 @@ var _ = Describe("Resource", func() {
 			Expect(instance.Validate()).To(Succeed())
 			Expect(instance.Resource).To(Equal("myresource"))
 		})
+
+		It("should fail if the Resource is not a DNS-1123 label", func() {
+			instance := &Resource{Group: "crew", Kind: "FirstMate", Version: "v1", Resource: "First_Mates"}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("plural is invalid: ([a DNS-1123 label must consist of lower case alphanumeric characters"))
+		})
+
+		It("should default the Resource to the registered plural of the Kind", func() {
+			RegisterPlural("Octopus", "octopodes")
+			instance := &Resource{Group: "crew", Kind: "Octopus", Version: "v1"}
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.Resource).To(Equal("octopodes"))
+			Expect(instance.HasCustomPlural()).To(BeTrue())
+		})
+
+		It("should not have a custom plural when the Kind plural is computed", func() {
+			instance := &Resource{Group: "crew", Kind: "FirstMate", Version: "v1"}
+			Expect(instance.Validate()).To(Succeed())
+			Expect(instance.HasCustomPlural()).To(BeFalse())
+		})
+
+		It("should fail if a short name is not a DNS-1123 label", func() {
+			instance := &Resource{Group: "crew", Kind: "FirstMate", Version: "v1", ShortNames: []string{"FM"}}
+			Expect(instance.Validate()).NotTo(Succeed())
+			Expect(instance.Validate().Error()).To(ContainSubstring("short name is invalid"))
+		})
+	})
+
+	Describe("parsing printer columns", func() {
+		It("should default the type of the creation timestamp to date", func() {
+			c, err := ParsePrintColumn("Age:.metadata.creationTimestamp")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).To(Equal(PrintColumn{Name: "Age", JSONPath: ".metadata.creationTimestamp", Type: "date"}))
+			Expect(c.Marker()).To(Equal(
+				`// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"`))
+		})
+
+		It("should default the type of the other fields to string", func() {
+			c, err := ParsePrintColumn("Phase:.status.phase")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Type).To(Equal("string"))
+		})
+
+		It("should keep the given type", func() {
+			c, err := ParsePrintColumn("Replicas:.spec.replicas:integer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Type).To(Equal("integer"))
+		})
+
+		It("should fail without a JSON path or with an unknown type", func() {
+			_, err := ParsePrintColumn("Age")
+			Expect(err).To(HaveOccurred())
+			_, err = ParsePrintColumn("Phase:status.phase")
+			Expect(err).To(HaveOccurred())
+			_, err = ParsePrintColumn("Phase:.status.phase:text")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("qualifying the group", func() {
+		It("should append the domain to the group", func() {
+			Expect(QualifiedGroup("crew", "testproject.org")).To(Equal("crew.testproject.org"))
+		})
+
+		It("should keep the group as is without a domain", func() {
+			Expect(QualifiedGroup("ship.example.com", "")).To(Equal("ship.example.com"))
+		})
+
+		It("should use the domain as the group if the group is empty", func() {
+			Expect(QualifiedGroup("", "testproject.org")).To(Equal("testproject.org"))
+		})
 	})
 })
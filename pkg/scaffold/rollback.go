@@ -0,0 +1,273 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// RollbackError is returned by Execute when writing a file fails after other
+// files were written, and by API.Scaffold when a step of the command fails.
+// The files written by Execute, or the command, are restored to their
+// previous contents, or removed if they did not exist.
+type RollbackError struct {
+	// Err is the error which stopped the scaffolding
+	Err error
+
+	// Restored are the paths of the files which were restored or removed
+	Restored []string
+
+	// NotRestored are the errors restoring the other files, by path. These
+	// files are left as written by Execute.
+	NotRestored map[string]error
+}
+
+// Error implements error
+func (e *RollbackError) Error() string {
+	msg := e.Err.Error()
+	if len(e.Restored) > 0 {
+		msg += fmt.Sprintf("; restored %s", strings.Join(e.Restored, ", "))
+	}
+	if len(e.NotRestored) > 0 {
+		paths := make([]string, 0, len(e.NotRestored))
+		for path := range e.NotRestored {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		failures := make([]string, 0, len(paths))
+		for _, path := range paths {
+			failures = append(failures, fmt.Sprintf("%s (%v)", path, e.NotRestored[path]))
+		}
+		msg += fmt.Sprintf("; failed to restore %s", strings.Join(failures, ", "))
+	}
+	return msg
+}
+
+// Unwrap returns the error which stopped the scaffolding
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// originalFile is the state of a file before Execute writes it
+type originalFile struct {
+	path     string
+	existed  bool
+	contents []byte
+}
+
+// saveOriginal returns the state of the file before it is written, or nil if
+// the file is not going to be written.
+func (s *Scaffold) saveOriginal(file *model.File) (*originalFile, error) {
+	if !s.FileExists(file.Path) {
+		return &originalFile{path: file.Path}, nil
+	}
+	if file.IfExistsAction != input.Overwrite {
+		return nil, nil
+	}
+	contents, err := s.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &originalFile{path: file.Path, existed: true, contents: contents}, nil
+}
+
+// rollback restores the given files, in the reverse order they were written,
+// after Execute failed with cause.
+func (s *Scaffold) rollback(originals []*originalFile, cause error) error {
+	if len(originals) == 0 {
+		return cause
+	}
+
+	e := &RollbackError{Err: cause, NotRestored: map[string]error{}}
+	for i := len(originals) - 1; i >= 0; i-- {
+		o := originals[i]
+		if err := s.restore(o); err != nil {
+			e.NotRestored[o.path] = err
+			continue
+		}
+		logging.Debugf("restored %s", o.path)
+		e.Restored = append(e.Restored, o.path)
+	}
+	return e
+}
+
+func (s *Scaffold) restore(o *originalFile) error {
+	if !o.existed {
+		if err := s.RemoveFile(o.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	f, err := s.GetWriter(o.path)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(o.contents)
+	if c, ok := f.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// snapshot is the state of the files of a project before a command which
+// scaffolds them in several steps, e.g. several Executes and in place
+// updates. The command restores it if a step fails, so that the project is
+// left as it was, including the PROJECT file.
+type snapshot struct {
+	root  string
+	files map[string]snapshotFile
+	dirs  map[string]bool
+}
+
+type snapshotFile struct {
+	contents []byte
+	mode     os.FileMode
+}
+
+// snapshotSkippedDirs are the directories of a project which are never
+// scaffolded, along with the hidden ones
+var snapshotSkippedDirs = map[string]bool{"bin": true, "testbin": true, "vendor": true}
+
+// takeSnapshot saves the files of the project at root
+func takeSnapshot(root string) (*snapshot, error) {
+	s := &snapshot{root: root, files: map[string]snapshotFile{}, dirs: map[string]bool{}}
+	err := s.walk(func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			s.dirs[path] = true
+			return nil
+		}
+		contents, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		s.files[path] = snapshotFile{contents: contents, mode: info.Mode()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving the files of the project: %v", err)
+	}
+	return s, nil
+}
+
+// walk calls fn for the directories and the regular files of the project
+func (s *snapshot) walk(fn func(path string, info os.FileInfo) error) error {
+	return filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != s.root &&
+			(snapshotSkippedDirs[info.Name()] || strings.HasPrefix(info.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		return fn(path, info)
+	})
+}
+
+// restore restores the project after cause stopped the command: the files
+// changed or removed since the snapshot are written back, and the new files
+// and directories are removed.
+func (s *snapshot) restore(cause error) error {
+	e := &RollbackError{Err: cause, NotRestored: map[string]error{}}
+	seen := map[string]bool{}
+	var newDirs []string
+	err := s.walk(func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			if !s.dirs[path] {
+				newDirs = append(newDirs, path)
+			}
+			return nil
+		}
+		seen[path] = true
+		original, existed := s.files[path]
+		if !existed {
+			if err := os.Remove(path); err != nil {
+				e.NotRestored[path] = err
+				return nil
+			}
+			e.Restored = append(e.Restored, path)
+			return nil
+		}
+		contents, err := ioutil.ReadFile(path) // nolint: gosec
+		if err == nil && bytes.Equal(contents, original.contents) {
+			return nil
+		}
+		if err := ioutil.WriteFile(path, original.contents, original.mode); err != nil {
+			e.NotRestored[path] = err
+			return nil
+		}
+		e.Restored = append(e.Restored, path)
+		return nil
+	})
+	if err != nil {
+		e.NotRestored[s.root] = err
+	}
+
+	removed := []string{}
+	for path := range s.files {
+		if !seen[path] {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		original := s.files[path]
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			e.NotRestored[path] = err
+			continue
+		}
+		if err := ioutil.WriteFile(path, original.contents, original.mode); err != nil {
+			e.NotRestored[path] = err
+			continue
+		}
+		e.Restored = append(e.Restored, path)
+	}
+
+	// the new directories are empty once their files are removed, the
+	// deepest ones are removed first
+	sort.Sort(sort.Reverse(sort.StringSlice(newDirs)))
+	for _, dir := range newDirs {
+		if err := os.Remove(dir); err != nil {
+			e.NotRestored[dir] = err
+		}
+	}
+
+	if len(e.Restored) == 0 && len(e.NotRestored) == 0 {
+		return cause
+	}
+	sort.Strings(e.Restored)
+	for _, path := range e.Restored {
+		logging.Debugf("restored %s", path)
+	}
+	return e
+}
@@ -0,0 +1,77 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ = Describe("API rollback", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-rollback")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "proj"), 0750)).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "proj"))).To(Succeed())
+
+		p := &V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Domain:  "example.com",
+				Repo:    "example.com/proj",
+			}},
+			Boilerplate: project.Boilerplate{License: "none"},
+		}
+		Expect(p.Validate()).To(Succeed())
+		Expect(p.Scaffold()).To(Succeed())
+
+		api := &API{
+			Resource:     &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			DoResource:   true,
+			DoController: true,
+		}
+		Expect(api.Validate()).To(Succeed())
+		Expect(api.Scaffold()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should restore the project when a step of the command fails", func() {
+		before, err := takeSnapshot(".")
+		Expect(err).NotTo(HaveOccurred())
+
+		api := &API{
+			Resource:     &resource.Resource{Group: "ship", Version: "v2", Kind: "Frigate", Namespaced: true},
+			DoResource:   true,
+			DoController: true,
+		}
+		Expect(api.Validate()).To(Succeed())
+		err = api.Scaffold()
+		Expect(err).To(MatchError(ContainSubstring("error scaffolding controller")))
+		rollbackErr, ok := err.(*RollbackError)
+		Expect(ok).To(BeTrue())
+		Expect(rollbackErr.NotRestored).To(BeEmpty())
+		Expect(rollbackErr.Restored).To(ContainElement("PROJECT"))
+		Expect(rollbackErr.Restored).To(ContainElement(filepath.Join("api", "v2", "frigate_types.go")))
+
+		_, err = os.Stat(filepath.Join("api", "v2"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		after, err := takeSnapshot(".")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+})
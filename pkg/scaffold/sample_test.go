@@ -0,0 +1,87 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var _ = Describe("Samples", func() {
+	var dir, wd string
+
+	frigate := &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true}
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-samples")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("PROJECT", []byte("version: \"2\"\ndomain: example.com\nrepo: example.com/proj\n"+
+			"resources:\n- group: ship\n  version: v1\n  kind: Sloop\n"), 0600)).To(Succeed())
+		Expect(os.Mkdir("hack", 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join("hack", "boilerplate.go.txt"), nil, 0600)).To(Succeed())
+		Expect(ioutil.WriteFile("values.yaml", []byte("replicas: 3\nresources:\n  limits:\n    cpu: 500m\n"),
+			0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should merge the sample values into the example field", func() {
+		api := &API{Resource: frigate, DoResource: true, SampleValues: "values.yaml"}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.loadSampleValues()).To(Succeed())
+
+		sample := &scaffoldv2.CRDSample{Resource: frigate, Values: api.sampleValues, ExampleField: true}
+		Expect((&Scaffold{}).Execute(&model.Universe{}, input.Options{}, sample)).To(Succeed())
+		Expect(ioutil.ReadFile(filepath.Join("config", "samples", "ship_v1_frigate.yaml"))).To(Equal(
+			[]byte(`apiVersion: ship.example.com/v1
+kind: Frigate
+metadata:
+  name: frigate-sample
+spec:
+  foo: bar
+  replicas: 3
+  resources:
+    limits:
+      cpu: 500m
+`)))
+	})
+
+	It("should reject the sample values which are not an object", func() {
+		Expect(ioutil.WriteFile("values.yaml", []byte("- replicas\n"), 0600)).To(Succeed())
+		api := &API{Resource: frigate, DoResource: true, SampleValues: "values.yaml"}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.loadSampleValues()).NotTo(Succeed())
+	})
+
+	It("should aggregate the existing samples when scaffolding the kustomization", func() {
+		Expect(os.MkdirAll(filepath.Join("config", "samples"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join("config", "samples", "ship_v1_sloop.yaml"), nil, 0600)).To(Succeed())
+
+		api := &API{Resource: frigate}
+		Expect(api.setDefaults()).To(Succeed())
+		Expect(api.addSample()).To(Succeed())
+		Expect(api.addSample()).To(Succeed())
+		Expect(ioutil.ReadFile(filepath.Join("config", "samples", "kustomization.yaml"))).To(Equal(
+			[]byte(`# The samples of the resources of the project, which kubectl apply -k creates
+resources:
+- ship_v1_sloop.yaml
+- ship_v1_frigate.yaml
+# +kubebuilder:scaffold:samplekustomizeresource
+`)))
+	})
+})
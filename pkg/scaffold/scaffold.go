@@ -18,6 +18,7 @@ package scaffold
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -27,10 +28,12 @@ import (
 	"strings"
 	"text/template"
 
-	"golang.org/x/tools/imports"
+	"sigs.k8s.io/kubebuilder/pkg/logging"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/yaml"
 )
 
@@ -56,6 +59,15 @@ type Scaffold struct {
 
 	FileExists func(path string) bool
 
+	ReadFile func(path string) ([]byte, error)
+
+	// RemoveFile removes the files written by Execute when it fails midway
+	RemoveFile func(path string) error
+
+	// SkipExisting skips the existing files instead of returning an error,
+	// the files set to be overwritten are still overwritten
+	SkipExisting bool
+
 	// Plugins is the list of plugins we should allow to transform our generated scaffolding
 	Plugins []Plugin
 }
@@ -116,13 +128,40 @@ func LoadProjectFile(path string) (input.ProjectFile, error) {
 	return p, nil
 }
 
-// saveProjectFile saves the given ProjectFile at the given path.
+// saveProjectFile saves the given ProjectFile at the given path. The file is
+// written next to the project file and then renamed, so that it is never left
+// partially written. The order of the fields and the comments of an existing
+// file are kept, see preserveLayout.
 func saveProjectFile(path string, project *input.ProjectFile) error {
 	content, err := yaml.Marshal(project)
 	if err != nil {
 		return fmt.Errorf("error marshalling project info %v", err)
 	}
-	err = ioutil.WriteFile(path, content, os.ModePerm)
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if existing, err := ioutil.ReadFile(path); err == nil { // nolint: gosec
+		content = preserveLayout(existing, content)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+"-")
+	if err != nil {
+		return fmt.Errorf("failed to save project file at %s %v", path, err)
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to save project file at %s %v", path, err)
 	}
@@ -158,7 +197,6 @@ func (s *Scaffold) defaultOptions(options *input.Options) error {
 	if !s.ProjectOptional && err != nil {
 		return err
 	}
-
 	return nil
 }
 
@@ -173,6 +211,12 @@ func (s *Scaffold) Execute(u *model.Universe, options input.Options, files ...in
 			return err == nil
 		}
 	}
+	if s.ReadFile == nil {
+		s.ReadFile = ioutil.ReadFile
+	}
+	if s.RemoveFile == nil {
+		s.RemoveFile = os.Remove
+	}
 
 	if u.Boilerplate == "" {
 		u.Boilerplate = s.Boilerplate
@@ -195,11 +239,24 @@ func (s *Scaffold) Execute(u *model.Universe, options input.Options, files ...in
 		}
 	}
 
+	// the files are written all or none: if writing one fails, the files
+	// written before it are restored
+	originals := []*originalFile{}
 	for _, f := range u.Files {
+		original, err := s.saveOriginal(f)
+		if err != nil {
+			return s.rollback(originals, err)
+		}
+		if original != nil {
+			originals = append(originals, original)
+		}
 		if err := s.writeFile(f); err != nil {
-			return err
+			return s.rollback(originals, err)
 		}
 	}
+	if err := s.recordChecksums(options.ProjectPath, u.Files); err != nil {
+		return s.rollback(originals, err)
+	}
 
 	return nil
 }
@@ -213,8 +270,8 @@ func (e *errorAlreadyExists) Error() string {
 }
 
 func isAlreadyExistsError(e error) bool {
-	_, ok := e.(*errorAlreadyExists)
-	return ok
+	var exists *errorAlreadyExists
+	return errors.As(e, &exists)
 }
 
 // doFile scaffolds a single file
@@ -231,8 +288,24 @@ func (s *Scaffold) buildFileModel(e input.File) (*model.File, error) {
 		return nil, err
 	}
 
+	// the templates may use slashes, the files are written with the path
+	// separator of the OS
+	i.Path = filepath.Clean(filepath.FromSlash(i.Path))
+
 	m := &model.File{
-		Path: i.Path,
+		Path:           i.Path,
+		IfExistsAction: i.IfExistsAction,
+	}
+
+	// static files are written as is
+	if static, ok := e.(input.Static); ok {
+		b, err := static.GetContents()
+		if err != nil {
+			return nil, err
+		}
+		m.Contents = string(b)
+		m.Static = true
+		return m, nil
 	}
 
 	if b, err := s.doTemplate(i, e); err != nil {
@@ -249,11 +322,29 @@ func (s *Scaffold) writeFile(file *model.File) error {
 	if s.FileExists(file.Path) {
 		switch file.IfExistsAction {
 		case input.Overwrite:
+			if file.Static {
+				modified, err := s.staticModified(file)
+				if err != nil {
+					return err
+				}
+				if modified {
+					logging.Warnf("keeping %s, it was modified since it was scaffolded", file.Path)
+					return nil
+				}
+			}
+			logging.Debugf("overwriting %s", file.Path)
 		case input.Skip:
+			logging.Debugf("skipping %s, it already exists", file.Path)
 			return nil
 		case input.Error:
+			if s.SkipExisting {
+				logging.Debugf("keeping %s, it already exists", file.Path)
+				return nil
+			}
 			return &errorAlreadyExists{path: file.Path}
 		}
+	} else {
+		logging.Debugf("writing %s", file.Path)
 	}
 
 	f, err := s.GetWriter(file.Path)
@@ -275,34 +366,67 @@ func (s *Scaffold) writeFile(file *model.File) error {
 
 // doTemplate executes the template for a file using the input
 func (s *Scaffold) doTemplate(i input.Input, e input.File) ([]byte, error) {
-	temp, err := newTemplate(e).Parse(i.TemplateBody)
+	b, err := render(i, e)
 	if err != nil {
 		return nil, err
 	}
 
-	out := &bytes.Buffer{}
-	err = temp.Execute(out, e)
-	if err != nil {
-		return nil, err
+	// keep the user code regions of the file being overwritten
+	if i.IfExistsAction == input.Overwrite && s.FileExists(i.Path) {
+		existing, err := s.ReadFile(i.Path)
+		if err != nil {
+			return nil, err
+		}
+		if b, err = mergeUserRegions(b, existing); err != nil {
+			return nil, fmt.Errorf("error preserving user code of %s: %v", i.Path, err)
+		}
+	}
+
+	if i.ScaffoldVersion > 0 {
+		if b, err = stampVersion(i.Path, b, i.ScaffoldVersion); err != nil {
+			return nil, err
+		}
 	}
-	b := out.Bytes()
 
 	// gofmt the imports
 	if filepath.Ext(i.Path) == ".go" {
-		b, err = imports.Process(i.Path, b, nil)
+		// the imports are grouped with the local prefix of the project
+		formatted, err := util.FormatImports(i.Path, b, s.Project.ImportsLocalPrefix)
 		if err != nil {
-			fmt.Printf("%s\n", out.Bytes())
+			logging.Infof("%s", b)
 			return nil, err
 		}
+		b = formatted
 	}
 
 	return b, nil
 }
 
+// render renders the template body of a file with its template engine
+func render(i input.Input, e input.File) ([]byte, error) {
+	if i.TemplateEngine != nil {
+		logging.Debugf("rendering %s (%T) with %T", i.Path, e, i.TemplateEngine)
+		return i.TemplateEngine.Render(i.TemplateBody, e)
+	}
+
+	temp, err := newTemplate(e).Parse(i.TemplateBody)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	err = temp.Execute(out, e)
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // newTemplate a new template with common functions
 func newTemplate(t input.File) *template.Template {
 	return template.New(fmt.Sprintf("%T", t)).Funcs(template.FuncMap{
-		"title": strings.Title,
-		"lower": strings.ToLower,
+		"title":          strings.Title,
+		"lower":          strings.ToLower,
+		"qualifiedGroup": resource.QualifiedGroup,
 	})
 }
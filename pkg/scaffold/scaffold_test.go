@@ -1,9 +1,269 @@
 package scaffold_test
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/tools/imports"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 )
 
+// upperEngine is a TemplateEngine which uppercases the body
+type upperEngine struct{}
+
+func (upperEngine) Render(body string, _ input.File) ([]byte, error) {
+	return []byte(strings.ToUpper(body)), nil
+}
+
+type upperFile struct {
+	input.Input
+}
+
+func (f *upperFile) GetInput() (input.Input, error) {
+	f.Path = "upper.txt"
+	f.TemplateBody = "{{ .Path }}"
+	f.TemplateEngine = upperEngine{}
+	return f.Input, nil
+}
+
+type regionFile struct {
+	input.Input
+}
+
+func (f *regionFile) GetInput() (input.Input, error) {
+	f.Path = "region.txt"
+	f.TemplateBody = `header
+// +kubebuilder:scaffold:user-code-begin body
+scaffolded body
+// +kubebuilder:scaffold:user-code-end body
+footer
+`
+	f.IfExistsAction = input.Overwrite
+	return f.Input, nil
+}
+
 var _ = Describe("Scaffold", func() {
+	var s *scaffold.Scaffold
+	var out map[string]*bytes.Buffer
+
+	BeforeEach(func() {
+		out = map[string]*bytes.Buffer{}
+		s = &scaffold.Scaffold{
+			BoilerplateOptional: true,
+			ProjectOptional:     true,
+			GetWriter: func(path string) (io.Writer, error) {
+				out[path] = &bytes.Buffer{}
+				return out[path], nil
+			},
+			FileExists: func(string) bool { return false },
+		}
+	})
+
+	It("should write raw files as is", func() {
+		f := &input.RawFile{Input: input.Input{Path: "raw.json"}, Contents: `{"legend": "{{ name }}"}`}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		Expect(out["raw.json"].String()).To(Equal(`{"legend": "{{ name }}"}`))
+	})
+
+	It("should write static files as is", func() {
+		assets, err := ioutil.TempDir("", "kubebuilder-assets")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(assets)
+		icon := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, '{', '{'}
+		Expect(ioutil.WriteFile(filepath.Join(assets, "icon.png"), icon, 0600)).To(Succeed())
+
+		f := &input.StaticFile{Input: input.Input{Path: "docs/icon.go"}, Assets: http.Dir(assets), Source: "icon.png"}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		Expect(out[filepath.Join("docs", "icon.go")].Bytes()).To(Equal(icon))
+	})
+
+	It("should render files with their template engine", func() {
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &upperFile{})).To(Succeed())
+		Expect(out["upper.txt"].String()).To(Equal("{{ .PATH }}"))
+	})
+
+	It("should keep the user code regions of overwritten files", func() {
+		s.FileExists = func(string) bool { return true }
+		s.ReadFile = func(string) ([]byte, error) {
+			return []byte(`edited header
+// +kubebuilder:scaffold:user-code-begin body
+user body
+	more user body
+// +kubebuilder:scaffold:user-code-end body
+`), nil
+		}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &regionFile{})).To(Succeed())
+		Expect(out["region.txt"].String()).To(Equal(`header
+// +kubebuilder:scaffold:user-code-begin body
+user body
+	more user body
+// +kubebuilder:scaffold:user-code-end body
+footer
+`))
+	})
+
+	It("should keep the user code regions of overwritten files with CRLF line endings", func() {
+		s.FileExists = func(string) bool { return true }
+		s.ReadFile = func(string) ([]byte, error) {
+			return []byte("edited header\r\n// +kubebuilder:scaffold:user-code-begin body\r\n" +
+				"user body\r\n// +kubebuilder:scaffold:user-code-end body\r\n"), nil
+		}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &regionFile{})).To(Succeed())
+		Expect(out["region.txt"].String()).To(Equal(`header
+// +kubebuilder:scaffold:user-code-begin body
+user body
+// +kubebuilder:scaffold:user-code-end body
+footer
+`))
+	})
+
+	It("should write the files with the path separator of the OS", func() {
+		f := &input.RawFile{Input: input.Input{Path: "config/samples/../crd/raw.yaml"}, Contents: "raw"}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, f)).To(Succeed())
+		Expect(out).To(HaveKey(filepath.Join("config", "crd", "raw.yaml")))
+	})
+
+	It("should group the imports with the local prefix of the project", func() {
+		dir, err := ioutil.TempDir("", "kubebuilder-imports")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		projectPath := filepath.Join(dir, "PROJECT")
+		Expect(ioutil.WriteFile(projectPath,
+			[]byte("version: \"2\"\nrepo: example.com/proj\nimportsLocalPrefix: example.com/proj\n"), 0600)).To(Succeed())
+
+		f := &input.RawFile{Input: input.Input{Path: "main.go"}, Contents: `package main
+
+import (
+	"example.com/proj/api"
+	"fmt"
+	"github.com/go-logr/logr"
+)
+
+var _, _, _ = api.X, fmt.Sprint, logr.Logger(nil)
+`}
+		Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, f)).To(Succeed())
+		Expect(out["main.go"].String()).To(ContainSubstring(`import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	"example.com/proj/api"
+)`))
+
+		By("leaving the local prefix of the imports package to the next files")
+		Expect(imports.LocalPrefix).To(BeEmpty())
+	})
+
+	It("should fail to overwrite files with an unclosed user code region", func() {
+		s.FileExists = func(string) bool { return true }
+		s.ReadFile = func(string) ([]byte, error) {
+			return []byte("// +kubebuilder:scaffold:user-code-begin body\n"), nil
+		}
+		Expect(s.Execute(&model.Universe{}, input.Options{}, &regionFile{})).NotTo(Succeed())
+	})
+
+	Context("when overwriting static files", func() {
+		var dir, projectPath, iconPath string
+		icon := func(contents string) *input.StaticFile {
+			return &input.StaticFile{
+				Input:    input.Input{Path: iconPath, IfExistsAction: input.Overwrite},
+				Contents: []byte(contents),
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "kubebuilder-static")
+			Expect(err).NotTo(HaveOccurred())
+			projectPath = filepath.Join(dir, "PROJECT")
+			Expect(ioutil.WriteFile(projectPath, []byte("version: \"2\"\n"), 0600)).To(Succeed())
+			iconPath = filepath.Join(dir, "icon.png")
+			s = &scaffold.Scaffold{BoilerplateOptional: true}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("should record their checksums and overwrite them", func() {
+			Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, icon("v1"))).To(Succeed())
+			project, err := scaffold.LoadProjectFile(projectPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(project.Checksums).To(HaveKeyWithValue(filepath.ToSlash(iconPath),
+				"3bfc269594ef649228e9a74bab00f042efc91d5acc6fbee31a382e80d42388fe"))
+
+			Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, icon("v2"))).To(Succeed())
+			Expect(ioutil.ReadFile(iconPath)).To(Equal([]byte("v2")))
+		})
+
+		It("should keep the files modified by the user", func() {
+			Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, icon("v1"))).To(Succeed())
+			Expect(ioutil.WriteFile(iconPath, []byte("edited"), 0600)).To(Succeed())
+
+			Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, icon("v2"))).To(Succeed())
+			Expect(ioutil.ReadFile(iconPath)).To(Equal([]byte("edited")))
+		})
+
+		It("should keep the files which were not scaffolded", func() {
+			Expect(ioutil.WriteFile(iconPath, []byte("own"), 0600)).To(Succeed())
+			Expect(s.Execute(&model.Universe{}, input.Options{ProjectPath: projectPath}, icon("v1"))).To(Succeed())
+			Expect(ioutil.ReadFile(iconPath)).To(Equal([]byte("own")))
+		})
+	})
+
+	Context("when writing a file fails", func() {
+		var removed []string
+
+		BeforeEach(func() {
+			removed = nil
+			s.RemoveFile = func(path string) error {
+				removed = append(removed, path)
+				delete(out, path)
+				return nil
+			}
+			write := s.GetWriter
+			s.GetWriter = func(path string) (io.Writer, error) {
+				if path == "broken.txt" {
+					return nil, fmt.Errorf("disk full")
+				}
+				return write(path)
+			}
+		})
+
+		It("should remove the files written before", func() {
+			err := s.Execute(&model.Universe{}, input.Options{},
+				&input.RawFile{Input: input.Input{Path: "first.txt"}, Contents: "first"},
+				&input.RawFile{Input: input.Input{Path: "broken.txt"}, Contents: "broken"},
+			)
+			Expect(err).To(HaveOccurred())
+			rollbackErr, ok := err.(*scaffold.RollbackError)
+			Expect(ok).To(BeTrue())
+			Expect(rollbackErr.Err).To(MatchError("disk full"))
+			Expect(rollbackErr.Restored).To(Equal([]string{"broken.txt", "first.txt"}))
+			Expect(removed).To(Equal([]string{"broken.txt", "first.txt"}))
+			Expect(out).NotTo(HaveKey("first.txt"))
+		})
 
+		It("should restore the contents of the overwritten files", func() {
+			s.FileExists = func(path string) bool { return path == "region.txt" }
+			s.ReadFile = func(string) ([]byte, error) { return []byte("original"), nil }
+			err := s.Execute(&model.Universe{}, input.Options{},
+				&regionFile{},
+				&input.RawFile{Input: input.Input{Path: "broken.txt"}, Contents: "broken"},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(out["region.txt"].String()).To(Equal("original"))
+		})
+	})
 })
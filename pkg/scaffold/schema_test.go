@@ -0,0 +1,71 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ = Describe("Schema types", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-schema")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("PROJECT", []byte("version: \"2\"\ndomain: example.com\nrepo: example.com/proj\n"),
+			0600)).To(Succeed())
+		Expect(ioutil.WriteFile("frigate.yaml", []byte(`definitions:
+  Frigate:
+    type: object
+    properties:
+      spec:
+        type: object
+        properties:
+          inner:
+            $ref: '#/definitions/Inner'
+  Inner:
+    type: object
+    properties:
+      name:
+        type: string
+`), 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join("api", "v1"), 0755)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	validate := func() error {
+		api := &API{
+			Resource:   &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			DoResource: true,
+			Schema:     "frigate.yaml",
+		}
+		return api.Validate()
+	}
+
+	It("should name the types of the definitions after the kind", func() {
+		Expect(ioutil.WriteFile(filepath.Join("api", "v1", "sloop_types.go"),
+			[]byte("package v1\n\ntype Inner struct{}\n"), 0600)).To(Succeed())
+		Expect(validate()).To(Succeed())
+	})
+
+	It("should reject the types declared by the other files of the package", func() {
+		Expect(ioutil.WriteFile(filepath.Join("api", "v1", "sloop_types.go"),
+			[]byte("package v1\n\ntype FrigateInner struct{}\n"), 0600)).To(Succeed())
+		Expect(validate()).To(MatchError(ContainSubstring("the type FrigateInner generated from frigate.yaml " +
+			"is already declared in " + filepath.Join("api", "v1", "sloop_types.go"))))
+	})
+})
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+)
+
+// versionMarkerValue prefixes the value of the marker stamping the version of
+// the template of a file, e.g. "# +kubebuilder:scaffold:version=1"
+const versionMarkerValue = "version="
+
+// stampVersion appends the marker of the version of its template to the
+// content of the file at path
+func stampVersion(path string, content []byte, version int) ([]byte, error) {
+	if !marker.Supported(path) {
+		return nil, fmt.Errorf("%s cannot be stamped with the version of its template, "+
+			"its file type does not support markers", path)
+	}
+	stamped := bytes.NewBuffer(content)
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		stamped.WriteString("\n")
+	}
+	fmt.Fprintf(stamped, "%s%d\n", marker.For(path, versionMarkerValue), version)
+	return stamped.Bytes(), nil
+}
+
+// ScaffoldVersion returns the version of the template stamped in the file at
+// path, 0 if the file is not stamped
+func ScaffoldVersion(path string) (int, error) {
+	markers, err := marker.List(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, m := range markers {
+		if strings.HasPrefix(m.Value(), versionMarkerValue) {
+			v, err := strconv.Atoi(strings.TrimPrefix(m.Value(), versionMarkerValue))
+			if err != nil {
+				return 0, fmt.Errorf("invalid version stamped in %s: %v", path, err)
+			}
+			return v, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,45 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scaffold version", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubebuilder-stamp")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should stamp the version of the template at the end of the file", func() {
+		path := filepath.Join(dir, "Makefile")
+		b, err := stampVersion(path, []byte("all: manager"), 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("all: manager\n# +kubebuilder:scaffold:version=2\n"))
+
+		Expect(ioutil.WriteFile(path, b, 0600)).To(Succeed())
+		Expect(ScaffoldVersion(path)).To(Equal(2))
+	})
+
+	It("should return 0 for the files which are not stamped", func() {
+		path := filepath.Join(dir, "main.go")
+		Expect(ioutil.WriteFile(path, []byte("package main\n"), 0600)).To(Succeed())
+		Expect(ScaffoldVersion(path)).To(Equal(0))
+	})
+
+	It("should not stamp the files which cannot hold markers", func() {
+		_, err := stampVersion(filepath.Join(dir, "PROJECT"), []byte("version: \"2\"\n"), 1)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// checksum returns the hex encoded sha256 checksum of the contents
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// staticModified returns whether the existing static file was modified by the
+// user since it was scaffolded, i.e. its contents match neither the checksum
+// recorded in the project file nor the new contents. A file without a
+// recorded checksum was not scaffolded and is considered modified.
+func (s *Scaffold) staticModified(file *model.File) (bool, error) {
+	existing, err := s.ReadFile(file.Path)
+	if err != nil {
+		return false, err
+	}
+	sum := checksum(existing)
+	if sum == checksum([]byte(file.Contents)) {
+		return false, nil
+	}
+	recorded, found := s.Project.Checksums[filepath.ToSlash(file.Path)]
+	return !found || recorded != sum, nil
+}
+
+// recordChecksums records the checksums of the static files whose contents
+// were written in the project file at path, if it exists
+func (s *Scaffold) recordChecksums(path string, files []*model.File) error {
+	checksums := map[string]string{}
+	for _, f := range files {
+		if !f.Static || !s.FileExists(f.Path) {
+			continue
+		}
+		written, err := s.ReadFile(f.Path)
+		if err != nil {
+			return err
+		}
+		// the modified files which were kept keep their previous checksum
+		if sum := checksum([]byte(f.Contents)); checksum(written) == sum {
+			checksums[filepath.ToSlash(f.Path)] = sum
+		}
+	}
+	if len(checksums) == 0 {
+		return nil
+	}
+
+	record := func(p *input.ProjectFile) {
+		if p.Checksums == nil {
+			p.Checksums = map[string]string{}
+		}
+		for path, sum := range checksums {
+			p.Checksums[path] = sum
+		}
+	}
+	record(&s.Project)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	if _, err := updateProjectFile(path, record); err != nil {
+		return fmt.Errorf("error recording the checksums of the static files: %v", err)
+	}
+	return nil
+}
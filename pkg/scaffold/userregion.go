@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+)
+
+var (
+	// userRegionBeginMarker starts a named region of user code which is kept
+	// when the file is scaffolded again
+	userRegionBeginMarker = marker.For(".go", "user-code-begin").String()
+	// userRegionEndMarker ends a named region of user code
+	userRegionEndMarker = marker.For(".go", "user-code-end").String()
+)
+
+// userRegionName returns the name of the region started or ended by the line,
+// if the line is a region marker with the given prefix.
+func userRegionName(line, marker string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, marker+" ") {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+}
+
+// extractUserRegions returns the contents of the user code regions of a file,
+// by region name.
+func extractUserRegions(content []byte) (map[string][]string, error) {
+	regions := map[string][]string{}
+
+	name, inRegion := "", false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !inRegion {
+			if name, inRegion = userRegionName(line, userRegionBeginMarker); inRegion {
+				if _, found := regions[name]; found {
+					return nil, fmt.Errorf("user code region %q is declared more than once", name)
+				}
+				regions[name] = []string{}
+			}
+			continue
+		}
+		if end, ok := userRegionName(line, userRegionEndMarker); ok && end == name {
+			inRegion = false
+			continue
+		}
+		regions[name] = append(regions[name], line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if inRegion {
+		return nil, fmt.Errorf("user code region %q is not closed", name)
+	}
+	return regions, nil
+}
+
+// mergeUserRegions replaces the user code regions of the scaffolded content
+// with the regions of the existing file. Regions which only exist in one of
+// them are left as is.
+func mergeUserRegions(scaffolded, existing []byte) ([]byte, error) {
+	regions, err := extractUserRegions(existing)
+	if err != nil {
+		return nil, err
+	}
+	if len(regions) == 0 {
+		return scaffolded, nil
+	}
+
+	out := &bytes.Buffer{}
+	name, skipping := "", false
+	scanner := bufio.NewScanner(bytes.NewReader(scaffolded))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if skipping {
+			if end, ok := userRegionName(line, userRegionEndMarker); !ok || end != name {
+				continue
+			}
+			skipping = false
+		}
+		fmt.Fprintln(out, line)
+
+		if begin, ok := userRegionName(line, userRegionBeginMarker); ok {
+			if preserved, found := regions[begin]; found {
+				for _, l := range preserved {
+					fmt.Fprintln(out, l)
+				}
+				name, skipping = begin, true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
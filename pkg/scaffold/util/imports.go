@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"sync"
+
+	"golang.org/x/tools/imports"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// importsMu guards the local prefix of golang.org/x/tools/imports, which the
+// version used by kubebuilder only reads from a package variable
+var importsMu sync.Mutex
+
+// FormatImports formats the Go source of the file at path like goimports,
+// grouping the imports whose paths start with one of the comma-separated
+// localPrefix after the third-party ones. The local prefix of the imports
+// package is only set for the call, so it does not leak to the next file.
+func FormatImports(path string, src []byte, localPrefix string) ([]byte, error) {
+	importsMu.Lock()
+	defer importsMu.Unlock()
+	previous := imports.LocalPrefix
+	imports.LocalPrefix = localPrefix
+	defer func() { imports.LocalPrefix = previous }()
+	return imports.Process(path, src, nil)
+}
+
+// ImportsLocalPrefix returns the imports local prefix recorded in the project
+// file at path, which is empty if the file cannot be read.
+func ImportsLocalPrefix(path string) string {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return ""
+	}
+	var p input.ProjectFile
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return ""
+	}
+	return p.ImportsLocalPrefix
+}
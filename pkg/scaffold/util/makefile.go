@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// makefileRule matches the rules of the targets listed by make help, whose
+// names are made of letters, digits, dashes and underscores
+var makefileRule = regexp.MustCompile(`^([a-zA-Z0-9_-]+):([^=].*)?$`)
+
+// AddMakefileTarget appends the fragment defining the target to the Makefile
+// at path, unless the Makefile already defines it. The rules of the fragment
+// keep their ## description, listed by make help; a rule without one is
+// described by the comment line above it, if any.
+func AddMakefileTarget(path, target, fragment string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	content := strings.Replace(string(b), "\r\n", "\n", -1)
+	if strings.HasPrefix(content, target+":") || strings.Contains(content, "\n"+target+":") {
+		return nil
+	}
+
+	fragment = DescribeMakefileTargets(fragment)
+	if !strings.HasSuffix(content, "\n") {
+		fragment = "\n" + fragment
+	}
+	if strings.Contains(string(b), "\r\n") {
+		fragment = strings.Replace(fragment, "\n", "\r\n", -1)
+	}
+	return ioutil.WriteFile(path, append(b, fragment...), info.Mode())
+}
+
+// DescribeMakefileTargets moves the single comment line above the rules of the
+// Makefile fragment without a ## description to their ## description, e.g.
+// "# Build the docs\ndocs:" becomes "docs: ## Build the docs". The rules
+// following a longer comment are left as is.
+func DescribeMakefileTargets(fragment string) string {
+	isComment := func(line string) bool { return strings.HasPrefix(strings.TrimSpace(line), "#") }
+	lines := strings.Split(fragment, "\n")
+	for i := 1; i < len(lines); i++ {
+		if !makefileRule.MatchString(lines[i]) || strings.Contains(lines[i], "##") {
+			continue
+		}
+		comment := strings.TrimSpace(lines[i-1])
+		if !isComment(comment) || i > 1 && isComment(lines[i-2]) {
+			continue
+		}
+		lines[i] = fmt.Sprintf("%s ## %s", lines[i], strings.TrimSpace(strings.TrimLeft(comment, "#")))
+		lines = append(lines[:i-1], lines[i:]...)
+		i--
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDescribeMakefileTargets(t *testing.T) {
+	fragment := `
+# Build the docs
+docs: manifests
+	mkdocs build
+
+# Serve the docs, on the port
+# given by DOCS_PORT
+serve-docs: docs
+	mkdocs serve
+
+# Publish the docs
+publish-docs: docs ## Push the docs to the site
+DOCS_PORT := 8000
+`
+	expected := `
+docs: manifests ## Build the docs
+	mkdocs build
+
+# Serve the docs, on the port
+# given by DOCS_PORT
+serve-docs: docs
+	mkdocs serve
+
+# Publish the docs
+publish-docs: docs ## Push the docs to the site
+DOCS_PORT := 8000
+`
+	if described := DescribeMakefileTargets(fragment); described != expected {
+		t.Errorf("the described fragment is\n%s\nexpected\n%s", described, expected)
+	}
+}
+
+func TestAddMakefileTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "makefile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "Makefile")
+	if err := ioutil.WriteFile(path, []byte("all: manager\r\n\r\nmanager: ## Build manager binary\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := AddMakefileTarget(path, "docs", "\n# Build the docs\ndocs:\n\tmkdocs build\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddMakefileTarget(path, "manager", "\nmanager:\n\tgo build\n"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "all: manager\r\n\r\nmanager: ## Build manager binary\r\n\r\ndocs: ## Build the docs\r\n\tmkdocs build\r\n"
+	if string(b) != expected {
+		t.Errorf("the Makefile is %q, expected %q", b, expected)
+	}
+}
@@ -26,33 +26,43 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
 
+// coreGroups are the groups of the Kubernetes API, by group, with the domain
+// of the group if any
+var coreGroups = map[string]string{
+	"apps":                  "",
+	"admission":             "k8s.io",
+	"admissionregistration": "k8s.io",
+	"auditregistration":     "k8s.io",
+	"apiextensions":         "k8s.io",
+	"authentication":        "k8s.io",
+	"authorization":         "k8s.io",
+	"autoscaling":           "",
+	"batch":                 "",
+	"certificates":          "k8s.io",
+	"coordination":          "k8s.io",
+	"core":                  "",
+	"events":                "k8s.io",
+	"extensions":            "",
+	"imagepolicy":           "k8s.io",
+	"networking":            "k8s.io",
+	"node":                  "k8s.io",
+	"metrics":               "k8s.io",
+	"policy":                "",
+	"rbac.authorization":    "k8s.io",
+	"scheduling":            "k8s.io",
+	"setting":               "k8s.io",
+	"storage":               "k8s.io",
+}
+
+// IsCoreGroup returns true if the group is a group of the Kubernetes API, whose
+// types are in k8s.io/api.
+func IsCoreGroup(group string) bool {
+	_, found := coreGroups[group]
+	return found
+}
+
 func GetResourceInfo(r *resource.Resource, repo, domain string) (resourcePackage, groupDomain string) {
 	// Use the k8s.io/api package for core resources
-	coreGroups := map[string]string{
-		"apps":                  "",
-		"admission":             "k8s.io",
-		"admissionregistration": "k8s.io",
-		"auditregistration":     "k8s.io",
-		"apiextensions":         "k8s.io",
-		"authentication":        "k8s.io",
-		"authorization":         "k8s.io",
-		"autoscaling":           "",
-		"batch":                 "",
-		"certificates":          "k8s.io",
-		"coordination":          "k8s.io",
-		"core":                  "",
-		"events":                "k8s.io",
-		"extensions":            "",
-		"imagepolicy":           "k8s.io",
-		"networking":            "k8s.io",
-		"node":                  "k8s.io",
-		"metrics":               "k8s.io",
-		"policy":                "",
-		"rbac.authorization":    "k8s.io",
-		"scheduling":            "k8s.io",
-		"setting":               "k8s.io",
-		"storage":               "k8s.io",
-	}
 	resourcePath := filepath.Join("api", r.Version, fmt.Sprintf("%s_types.go", strings.ToLower(r.Kind)))
 	if _, err := os.Stat(resourcePath); os.IsNotExist(err) {
 		if domain, found := coreGroups[r.Group]; found {
@@ -68,5 +78,5 @@ func GetResourceInfo(r *resource.Resource, repo, domain string) (resourcePackage
 		}
 		// TODO: need to support '--resource-pkg-path' flag for specifying resourcePath
 	}
-	return path.Join(repo, "api"), r.Group + "." + domain
+	return path.Join(repo, "api"), resource.QualifiedGroup(r.Group, domain)
 }
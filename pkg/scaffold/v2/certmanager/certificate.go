@@ -25,6 +25,10 @@ import (
 // CertManager scaffolds an issuer CR and a certificate CR
 type CertManager struct {
 	input.Input
+
+	// Issuer is the name of an existing Issuer to use instead of scaffolding
+	// a self-signed one
+	Issuer string
 }
 
 // GetInput implements input.File
@@ -36,9 +40,14 @@ func (p *CertManager) GetInput() (input.Input, error) {
 	return p.Input, nil
 }
 
-const certManagerTemplate = `# The following manifests contain a self-signed issuer CR and a certificate CR.
+const certManagerTemplate = `{{- if .Issuer -}}
+# The following manifest contains a certificate CR issued by the {{ .Issuer }} issuer.
+{{- else -}}
+# The following manifests contain a self-signed issuer CR and a certificate CR.
+{{- end }}
 # More document can be found at https://docs.cert-manager.io
 # WARNING: Targets CertManager 0.11 check https://docs.cert-manager.io/en/latest/tasks/upgrading/index.html for breaking changes
+{{- if not .Issuer }}
 apiVersion: cert-manager.io/v1alpha2
 kind: Issuer
 metadata:
@@ -47,6 +56,7 @@ metadata:
 spec:
   selfSigned: {}
 ---
+{{- end }}
 apiVersion: cert-manager.io/v1alpha2
 kind: Certificate
 metadata:
@@ -59,6 +69,10 @@ spec:
   - $(SERVICE_NAME).$(SERVICE_NAMESPACE).svc.cluster.local
   issuerRef:
     kind: Issuer
+{{- if .Issuer }}
+    name: {{ .Issuer }}
+{{- else }}
     name: selfsigned-issuer
+{{- end }}
   secretName: webhook-server-cert # this secret will not be prefixed, since it's not managed by kustomize
 `
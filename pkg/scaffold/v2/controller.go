@@ -17,11 +17,6 @@ limitations under the License.
 package v2
 
 import (
-	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
-
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
@@ -42,6 +37,48 @@ type Controller struct {
 
 	// Is the Group + "." + Domain for the Resource
 	GroupDomain string
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	// Watches are the secondary resources owned by the Resource
+	Watches []*resource.Resource
+
+	// OwnedResources are the Watches with their package information
+	OwnedResources []OwnedResource
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles,
+	// the controller-runtime default is used if 0
+	MaxConcurrentReconciles int
+
+	// GenerationPredicate filters out the updates which do not change the
+	// generation of the objects
+	GenerationPredicate bool
+
+	// Events adds an EventRecorder to the Controller, recording the events of
+	// the reconciled objects with the reasons of the events.go file of its
+	// package
+	Events bool
+
+	// Layout is the layout of the package of the Controller, defaults to flat
+	Layout ControllerLayout
+
+	// Package is the name of the package of the Controller
+	Package string
+}
+
+// OwnedResource is a secondary resource owned by the Resource of a Controller
+type OwnedResource struct {
+	Resource *resource.Resource
+
+	// Package is the package of the Resource
+	Package string
+
+	// Is the Group + "." + Domain for the Resource
+	GroupDomain string
+
+	// Import is false if the package is already imported by the Controller
+	Import bool
 }
 
 // GetInput implements input.File
@@ -49,24 +86,41 @@ func (a *Controller) GetInput() (input.Input, error) {
 
 	a.ResourcePackage, a.GroupDomain = util.GetResourceInfo(a.Resource, a.Repo, a.Domain)
 
+	a.OwnedResources = nil
+	imported := map[string]bool{a.Resource.GroupImportSafe + a.Resource.Version: true}
+	for _, w := range a.Watches {
+		owned := OwnedResource{Resource: w}
+		owned.Package, owned.GroupDomain = util.GetResourceInfo(w, a.Repo, a.Domain)
+		owned.Import = !imported[w.GroupImportSafe+w.Version]
+		imported[w.GroupImportSafe+w.Version] = true
+		a.OwnedResources = append(a.OwnedResources, owned)
+	}
+
 	if a.Plural == "" {
-		a.Plural = flect.Pluralize(strings.ToLower(a.Resource.Kind))
+		a.Plural = a.Resource.Plural()
 	}
 
+	if a.Layout == "" {
+		a.Layout = ControllerLayoutFlat
+	}
+	a.Package = a.Layout.Package(a.Resource.Kind)
 	if a.Path == "" {
-		a.Path = filepath.Join("controllers",
-			strings.ToLower(a.Resource.Kind)+"_controller.go")
+		a.Path = a.Layout.ControllerPath(a.Resource.Kind)
 	}
 
 	a.TemplateBody = controllerTemplate
 
-	a.Input.IfExistsAction = input.Error
+	if a.Force {
+		a.Input.IfExistsAction = input.Overwrite
+	} else {
+		a.Input.IfExistsAction = input.Error
+	}
 	return a.Input, nil
 }
 
 const controllerTemplate = `{{ .Boilerplate }}
 
-package controllers
+package {{ .Package }}
 
 import (
 	"context"
@@ -75,8 +129,17 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+{{- if .MaxConcurrentReconciles }}
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+{{- end }}
+{{- if .GenerationPredicate }}
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+{{- end }}
 
 	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+{{- range .OwnedResources }}{{ if .Import }}
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .Package }}/{{ .Resource.Version }}"
+{{- end }}{{ end }}
 )
 
 // {{ .Resource.Kind }}Reconciler reconciles a {{ .Resource.Kind }} object
@@ -84,16 +147,45 @@ type {{ .Resource.Kind }}Reconciler struct {
 	client.Client
 	Log logr.Logger
 	Scheme *runtime.Scheme
+{{- if .Events }}
+	Recorder EventRecorder
+{{- end }}
 }
 
 // +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }},verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups={{.GroupDomain}},resources={{ .Plural }}/status,verbs=get;update;patch
+{{- range .OwnedResources }}
+// +kubebuilder:rbac:groups={{ .GroupDomain }},resources={{ .Resource.Resource }},verbs=get;list;watch;create;update;patch;delete
+{{- end }}
+{{- if .Events }}
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+{{- end }}
 
 func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+{{- if .Events }}
+	ctx := context.Background()
+	_ = r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
+
+	var obj {{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}
+	if err := r.Get(ctx, req.NamespacedName, &obj); err != nil {
+		// the deleted objects have nothing to reconcile
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// +kubebuilder:scaffold:user-code-begin reconcile
+	// your logic here, record the failures before returning the errors, e.g.
+	// r.Recorder.Warning(&obj, ReasonReconcileFailed, "cannot reconcile: %v", err)
+	// +kubebuilder:scaffold:user-code-end reconcile
+
+	r.Recorder.Normal(&obj, ReasonReconciled, "{{ .Resource.Kind }} reconciled")
+{{- else }}
 	_ = context.Background()
 	_ = r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
 
+	// +kubebuilder:scaffold:user-code-begin reconcile
 	// your logic here
+	// +kubebuilder:scaffold:user-code-end reconcile
+{{- end }}
 
 	return ctrl.Result{}, nil
 }
@@ -101,6 +193,19 @@ func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Resul
 func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}).
+{{- range .OwnedResources }}
+		Owns(&{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}).
+{{- end }}
+{{- if .MaxConcurrentReconciles }}
+		WithOptions(controller.Options{MaxConcurrentReconciles: {{ .MaxConcurrentReconciles }}}).
+{{- end }}
+{{- if .GenerationPredicate }}
+		// only reconcile the updates changing the generation of the objects,
+		// i.e. their spec, and not their status or metadata
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+{{- end }}
+		// +kubebuilder:scaffold:user-code-begin setup
+		// +kubebuilder:scaffold:user-code-end setup
 		Complete(r)
 }
 `
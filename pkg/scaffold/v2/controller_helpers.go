@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &ControllerHelpers{}
+
+// ControllerHelpers scaffolds the helpers.go file of the package of a
+// controller of the per-kind layout, with the finalizer helpers of its kind
+type ControllerHelpers struct {
+	input.Input
+
+	// Resource is the Resource of the controller
+	Resource *resource.Resource
+
+	// Package is the name of the package of the controller
+	Package string
+
+	// Is the Group + "." + Domain for the Resource
+	GroupDomain string
+}
+
+// GetInput implements input.File
+func (h *ControllerHelpers) GetInput() (input.Input, error) {
+	_, h.GroupDomain = util.GetResourceInfo(h.Resource, h.Repo, h.Domain)
+	h.Package = ControllerLayoutPerKind.Package(h.Resource.Kind)
+	if h.Path == "" {
+		h.Path = filepath.Join(ControllerLayoutPerKind.Dir(h.Resource.Kind), "helpers.go")
+	}
+	h.TemplateBody = controllerHelpersTemplate
+	h.Input.IfExistsAction = input.Skip
+	return h.Input, nil
+}
+
+const controllerHelpersTemplate = `{{ .Boilerplate }}
+
+package {{ .Package }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// finalizerName is the finalizer the controller can add to the
+// {{ .Resource.Kind }} objects, to clean up what they own before they are deleted
+const finalizerName = "{{ .GroupDomain }}/finalizer"
+
+// hasFinalizer returns true if the object has the finalizer
+func hasFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// addFinalizer adds the finalizer to the object, if it does not have it yet
+func addFinalizer(obj metav1.Object, finalizer string) {
+	if !hasFinalizer(obj, finalizer) {
+		obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	}
+}
+
+// removeFinalizer removes the finalizer from the object
+func removeFinalizer(obj metav1.Object, finalizer string) {
+	var finalizers []string
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	obj.SetFinalizers(finalizers)
+}
+`
+
+var _ input.File = &ControllerTest{}
+
+// ControllerTest scaffolds the controller_test.go file of the package of a
+// controller of the per-kind layout, run by the suite of the package
+type ControllerTest struct {
+	input.Input
+
+	// Resource is the Resource of the controller
+	Resource *resource.Resource
+
+	// Package is the name of the package of the controller
+	Package string
+
+	// Events sets the EventRecorder of the tested controller
+	Events bool
+}
+
+// GetInput implements input.File
+func (t *ControllerTest) GetInput() (input.Input, error) {
+	t.Package = ControllerLayoutPerKind.Package(t.Resource.Kind)
+	if t.Path == "" {
+		t.Path = filepath.Join(ControllerLayoutPerKind.Dir(t.Resource.Kind), "controller_test.go")
+	}
+	t.TemplateBody = controllerTestTemplate
+	t.Input.IfExistsAction = input.Skip
+	return t.Input, nil
+}
+
+const controllerTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Package }}
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+{{- if .Events }}
+	"k8s.io/client-go/tools/record"
+{{- end }}
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("{{ .Resource.Kind }}Reconciler", func() {
+	var r *{{ .Resource.Kind }}Reconciler
+
+	BeforeEach(func() {
+		r = &{{ .Resource.Kind }}Reconciler{
+			Client: k8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+			Scheme: scheme.Scheme,
+{{- if .Events }}
+			Recorder: EventRecorder{EventRecorder: record.NewFakeRecorder(10)},
+{{- end }}
+		}
+	})
+
+	It("should reconcile a {{ .Resource.Kind }} which does not exist", func() {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{
+{{- if .Resource.Namespaced }}
+			Namespace: "default",
+{{- end }}
+			Name:      "missing",
+		}}
+		_, err := r.Reconcile(req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// TODO(user): add the tests of your controller
+})
+`
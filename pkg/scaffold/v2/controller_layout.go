@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ControllerLayout is how the packages of the controllers are laid out
+type ControllerLayout string
+
+const (
+	// ControllerLayoutFlat scaffolds all the controllers in the controllers
+	// package
+	ControllerLayoutFlat ControllerLayout = "flat"
+
+	// ControllerLayoutPerKind scaffolds the controller of each kind in its own
+	// package under internal/controller, with its helpers and tests
+	ControllerLayoutPerKind ControllerLayout = "per-kind"
+)
+
+// Validate validates the ControllerLayout
+func (l ControllerLayout) Validate() error {
+	switch l {
+	case ControllerLayoutFlat, ControllerLayoutPerKind:
+		return nil
+	}
+	return fmt.Errorf("unknown controller layout %q, should be one of %s, %s",
+		l, ControllerLayoutFlat, ControllerLayoutPerKind)
+}
+
+// Dir returns the directory of the controller of the kind
+func (l ControllerLayout) Dir(kind string) string {
+	if l == ControllerLayoutPerKind {
+		return filepath.Join("internal", "controller", strings.ToLower(kind))
+	}
+	return "controllers"
+}
+
+// ControllerPath returns the path of the file of the controller of the kind
+func (l ControllerLayout) ControllerPath(kind string) string {
+	if l == ControllerLayoutPerKind {
+		return filepath.Join(l.Dir(kind), "controller.go")
+	}
+	return filepath.Join("controllers", strings.ToLower(kind)+"_controller.go")
+}
+
+// Package returns the name of the package of the controller of the kind
+func (l ControllerLayout) Package(kind string) string {
+	if l == ControllerLayoutPerKind {
+		return strings.ToLower(kind)
+	}
+	return "controllers"
+}
+
+// importAlias returns the name the package of the controller of the kind is
+// imported as in main.go
+func (l ControllerLayout) importAlias(kind string) string {
+	if l == ControllerLayoutPerKind {
+		return strings.ToLower(kind) + "controller"
+	}
+	return "controllers"
+}
+
+// projectRoot returns the path from the directory of the controller of the
+// kind to the root of the project, as the arguments of a filepath.Join call
+// of the scaffolded code, e.g. `"..", `
+func (l ControllerLayout) projectRoot(kind string) string {
+	depth := len(strings.Split(filepath.ToSlash(l.Dir(kind)), "/"))
+	return strings.Repeat(`"..", `, depth)
+}
@@ -34,12 +34,31 @@ type ControllerSuiteTest struct {
 
 	// Resource is the resource to scaffold the controller_kind_test.go file for
 	Resource *resource.Resource
+
+	// EnvtestAssets uses the envtest binaries downloaded by the setup-envtest
+	// Makefile target
+	EnvtestAssets bool
+
+	// Layout is the layout of the package of the controller, defaults to flat
+	Layout ControllerLayout
+
+	// Package is the name of the package of the controller
+	Package string
+
+	// ProjectRoot is the path from the package to the root of the project, as
+	// the leading arguments of filepath.Join
+	ProjectRoot string
 }
 
 // GetInput implements input.File
 func (v *ControllerSuiteTest) GetInput() (input.Input, error) {
+	if v.Layout == "" {
+		v.Layout = ControllerLayoutFlat
+	}
+	v.Package = v.Layout.Package(v.Resource.Kind)
+	v.ProjectRoot = v.Layout.projectRoot(v.Resource.Kind)
 	if v.Path == "" {
-		v.Path = filepath.Join("controllers", "suite_test.go")
+		v.Path = filepath.Join(v.Layout.Dir(v.Resource.Kind), "suite_test.go")
 	}
 	v.TemplateBody = controllerSuiteTestTemplate
 	return v.Input, nil
@@ -52,9 +71,12 @@ func (v *ControllerSuiteTest) Validate() error {
 
 const controllerSuiteTestTemplate = `{{ .Boilerplate }}
 
-package controllers
+package {{ .Package }}
 
 import (
+{{- if .EnvtestAssets }}
+	"os"
+{{- end }}
 	"path/filepath"
 	"testing"
 
@@ -65,6 +87,7 @@ import (
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -83,15 +106,22 @@ func TestAPIs(t *testing.T) {
 
 	RunSpecsWithDefaultAndCustomReporters(t,
 	"Controller Suite",
-	[]Reporter{envtest.NewlineReporter{}})
+	[]Reporter{printer.NewlineReporter{}})
 }
 
 var _ = BeforeSuite(func(done Done) {
 	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
 
 	By("bootstrapping test environment")
+{{- if .EnvtestAssets }}
+	// use the binaries downloaded by "make setup-envtest", unless
+	// KUBEBUILDER_ASSETS is already set
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		Expect(os.Setenv("KUBEBUILDER_ASSETS", filepath.Join({{ .ProjectRoot }}"testbin", "bin"))).To(Succeed())
+	}
+{{- end }}
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{filepath.Join({{ .ProjectRoot }}"config", "crd", "bases")},
 	}
 
 	var err error
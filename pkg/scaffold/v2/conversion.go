@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+const storageVersionMarker = "// +kubebuilder:storageversion"
+
+var _ input.File = &ConversionHub{}
+
+// ConversionHub scaffolds the api/<version>/<kind>_conversion.go file which
+// makes the Resource the conversion hub of its kind
+type ConversionHub struct {
+	input.Input
+
+	// Resource is the hub version of the kind
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (h *ConversionHub) GetInput() (input.Input, error) {
+	if h.Path == "" {
+		h.Path = conversionPath(h.Resource)
+	}
+	h.TemplateBody = conversionHubTemplate
+	h.IfExistsAction = input.Skip
+	return h.Input, nil
+}
+
+// Validate validates the values
+func (h *ConversionHub) Validate() error {
+	return h.Resource.Validate()
+}
+
+var _ input.File = &ConversionSpoke{}
+
+// ConversionSpoke scaffolds the api/<version>/<kind>_conversion.go file with
+// the conversions of the Resource from and to the hub version of its kind
+type ConversionSpoke struct {
+	input.Input
+
+	// Resource is the version of the kind to convert
+	Resource *resource.Resource
+
+	// Hub is the hub version of the kind
+	Hub *resource.Resource
+
+	// HubPackage is the go package of the hub version
+	HubPackage string
+}
+
+// GetInput implements input.File
+func (s *ConversionSpoke) GetInput() (input.Input, error) {
+	if s.Path == "" {
+		s.Path = conversionPath(s.Resource)
+	}
+	if s.HubPackage == "" {
+		resPkg, _ := util.GetResourceInfo(s.Hub, s.Repo, s.Domain)
+		s.HubPackage = resPkg + "/" + s.Hub.Version
+	}
+	s.TemplateBody = conversionSpokeTemplate
+	s.IfExistsAction = input.Error
+	return s.Input, nil
+}
+
+// Validate validates the values
+func (s *ConversionSpoke) Validate() error {
+	if err := s.Hub.Validate(); err != nil {
+		return err
+	}
+	return s.Resource.Validate()
+}
+
+func conversionPath(r *resource.Resource) string {
+	return filepath.Join("api", r.Version, fmt.Sprintf("%s_conversion.go", strings.ToLower(r.Kind)))
+}
+
+// AddStorageVersionMarker adds the +kubebuilder:storageversion marker to the
+// type of the kind in the given types file. It returns false if the type
+// declaration is not found, the marker then has to be added by hand.
+func AddStorageVersionMarker(path, kind string) (bool, error) {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return false, err
+	}
+	content := string(b)
+	if strings.Contains(content, storageVersionMarker) {
+		return true, nil
+	}
+
+	typeDecl := strings.Index(content, fmt.Sprintf("\ntype %s struct {", kind))
+	if typeDecl < 0 {
+		return false, nil
+	}
+	root := strings.LastIndex(content[:typeDecl], "// +kubebuilder:object:root=true\n")
+	if root < 0 {
+		return false, nil
+	}
+	at := root + len("// +kubebuilder:object:root=true\n")
+	content = content[:at] + storageVersionMarker + "\n" + content[at:]
+	return true, ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+const conversionHubTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+// Hub marks this type as a conversion hub: the other versions of
+// {{ .Resource.Kind }} are converted from and to {{ .Resource.Version }}, which is the
+// storage version.
+func (*{{ .Resource.Kind }}) Hub() {}
+`
+
+const conversionSpokeTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	{{ .Hub.GroupImportSafe }}{{ .Hub.Version }} "{{ .HubPackage }}"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+var _ conversion.Convertible = &{{ .Resource.Kind }}{}
+
+// ConvertTo converts this {{ .Resource.Kind }} to the hub version ({{ .Hub.Version }}).
+func (src *{{ .Resource.Kind }}) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*{{ .Hub.GroupImportSafe }}{{ .Hub.Version }}.{{ .Resource.Kind }})
+	dst.ObjectMeta = src.ObjectMeta
+
+	// TODO(user): convert the Spec and Status of src to dst
+	return nil
+}
+
+// ConvertFrom converts from the hub version ({{ .Hub.Version }}) to this version.
+func (dst *{{ .Resource.Kind }}) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*{{ .Hub.GroupImportSafe }}{{ .Hub.Version }}.{{ .Resource.Kind }})
+	dst.ObjectMeta = src.ObjectMeta
+
+	// TODO(user): convert the Spec and Status of src to dst
+	return nil
+}
+`
@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -33,16 +30,22 @@ type EnableCAInjectionPatch struct {
 
 	// Resource is the Resource to make the EnableCAInjectionPatch for
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
 }
 
 // GetInput implements input.File
 func (p *EnableCAInjectionPatch) GetInput() (input.Input, error) {
 	if p.Path == "" {
-		plural := flect.Pluralize(strings.ToLower(p.Resource.Kind))
+		plural := p.Resource.Plural()
 		p.Path = filepath.Join("config", "crd", "patches",
 			fmt.Sprintf("cainjection_in_%s.yaml", plural))
 	}
 	p.TemplateBody = EnableCAInjectionPatchTemplate
+	if p.Force {
+		p.IfExistsAction = input.Overwrite
+	}
 	return p.Input, nil
 }
 
@@ -58,5 +61,5 @@ kind: CustomResourceDefinition
 metadata:
   annotations:
     cert-manager.io/inject-ca-from: $(CERTIFICATE_NAMESPACE)/$(CERTIFICATE_NAME)
-  name: {{ .Resource.Resource }}.{{ .Resource.Group }}.{{ .Domain }}
+  name: {{ .Resource.Resource }}.{{ qualifiedGroup .Resource.Group .Domain }}
 `
@@ -19,9 +19,6 @@ package crd
 import (
 	"fmt"
 	"path/filepath"
-	"strings"
-
-	"github.com/gobuffalo/flect"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -33,16 +30,22 @@ type EnableWebhookPatch struct {
 
 	// Resource is the Resource to make the EnableWebhookPatch for
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
 }
 
 // GetInput implements input.File
 func (p *EnableWebhookPatch) GetInput() (input.Input, error) {
 	if p.Path == "" {
-		plural := flect.Pluralize(strings.ToLower(p.Resource.Kind))
+		plural := p.Resource.Plural()
 		p.Path = filepath.Join("config", "crd", "patches",
 			fmt.Sprintf("webhook_in_%s.yaml", plural))
 	}
 	p.TemplateBody = enableWebhookPatchTemplate
+	if p.Force {
+		p.IfExistsAction = input.Overwrite
+	}
 	return p.Input, nil
 }
 
@@ -56,7 +59,7 @@ const enableWebhookPatchTemplate = `# The following patch enables conversion web
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
-  name: {{ .Resource.Resource }}.{{ .Resource.Group }}.{{ .Domain }}
+  name: {{ .Resource.Resource }}.{{ qualifiedGroup .Resource.Group .Domain }}
 spec:
   conversion:
     strategy: Webhook
@@ -18,20 +18,20 @@ package crd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
-
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
 )
 
-const (
-	kustomizeResourceScaffoldMarker         = "# +kubebuilder:scaffold:crdkustomizeresource"
-	kustomizeWebhookPatchScaffoldMarker     = "# +kubebuilder:scaffold:crdkustomizewebhookpatch"
-	kustomizeCAInjectionPatchScaffoldMarker = "# +kubebuilder:scaffold:crdkustomizecainjectionpatch"
+var (
+	kustomizeResourceScaffoldMarker         = marker.For("kustomization.yaml", "crdkustomizeresource").String()
+	kustomizeWebhookPatchScaffoldMarker     = marker.For("kustomization.yaml", "crdkustomizewebhookpatch").String()
+	kustomizeCAInjectionPatchScaffoldMarker = marker.For("kustomization.yaml", "crdkustomizecainjectionpatch").String()
 )
 
 var _ input.File = &Kustomization{}
@@ -61,9 +61,9 @@ func (c *Kustomization) Update() error {
 
 	// TODO(directxman12): not technically valid if something changes from the default
 	// (we'd need to parse the markers)
-	plural := flect.Pluralize(strings.ToLower(c.Resource.Kind))
+	plural := c.Resource.Plural()
 
-	kustomizeResourceCodeFragment := fmt.Sprintf("- bases/%s.%s_%s.yaml\n", c.Resource.Group, c.Domain, plural)
+	kustomizeResourceCodeFragment := fmt.Sprintf("- bases/%s_%s.yaml\n", resource.QualifiedGroup(c.Resource.Group, c.Domain), plural)
 	kustomizeWebhookPatchCodeFragment := fmt.Sprintf("#- patches/webhook_in_%s.yaml\n", plural)
 	kustomizeCAInjectionPatchCodeFragment := fmt.Sprintf("#- patches/cainjection_in_%s.yaml\n", plural)
 
@@ -75,6 +75,35 @@ func (c *Kustomization) Update() error {
 		})
 }
 
+// EnableConversionPatches uncomments the conversion webhook patch of the
+// Resource, which has several versions to convert between, and its
+// cert-manager CA injection patch if caInjection is true.
+func (c *Kustomization) EnableConversionPatches(caInjection bool) error {
+	if c.Path == "" {
+		c.Path = filepath.Join("config", "crd", "kustomization.yaml")
+	}
+	b, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return err
+	}
+	content := string(b)
+	// the kustomization keeps its line endings, LF or CRLF
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+	plural := c.Resource.Plural()
+	patches := []string{"webhook_in_%s.yaml"}
+	if caInjection {
+		patches = append(patches, "cainjection_in_%s.yaml")
+	}
+	for _, patch := range patches {
+		line := "- patches/" + fmt.Sprintf(patch, plural) + eol
+		content = strings.Replace(content, "#"+line, line, 1)
+	}
+	return ioutil.WriteFile(c.Path, []byte(content), 0644)
+}
+
 var kustomizationTemplate = fmt.Sprintf(`# This kustomization.yaml is not intended to be run by itself,
 # since it depends on service name and namespace that are out of this kustomize package.
 # It should be run by config/default
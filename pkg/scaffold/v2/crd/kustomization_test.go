@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestKustomizationUpdateCRLF(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crd-kustomization")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "kustomization.yaml")
+	if err := ioutil.WriteFile(path, []byte(strings.Replace(kustomizationTemplate, "\n", "\r\n", -1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	k := &Kustomization{
+		Input:    input.Input{Path: path, Domain: "example.com"},
+		Resource: &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate"},
+	}
+	if err := k.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.EnableConversionPatches(true); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(b)
+	if strings.Count(content, "\n") != strings.Count(content, "\r\n") {
+		t.Errorf("mixed line endings in:\n%q", content)
+	}
+	for _, line := range []string{
+		"- bases/ship.example.com_frigates.yaml\r\n",
+		"\r\n- patches/webhook_in_frigates.yaml\r\n",
+		"\r\n- patches/cainjection_in_frigates.yaml\r\n",
+	} {
+		if !strings.Contains(content, line) {
+			t.Errorf("expected %q in:\n%s", line, content)
+		}
+	}
+}
@@ -33,6 +33,9 @@ type CRDEditorRole struct {
 
 	// Resource is a resource in the API group
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
 }
 
 // GetInput implements input.File
@@ -42,6 +45,9 @@ func (g *CRDEditorRole) GetInput() (input.Input, error) {
 	}
 
 	g.TemplateBody = crdRoleEditorTemplate
+	if g.Force {
+		g.IfExistsAction = input.Overwrite
+	}
 	return g.Input, nil
 }
 
@@ -57,7 +63,7 @@ metadata:
   name: {{ lower .Resource.Kind }}-editor-role
 rules:
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ qualifiedGroup .Resource.Group .Domain }}
   resources:
   - {{ .Resource.Resource }}
   verbs:
@@ -69,7 +75,7 @@ rules:
   - update
   - watch
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ qualifiedGroup .Resource.Group .Domain }}
   resources:
   - {{ .Resource.Resource }}/status
   verbs:
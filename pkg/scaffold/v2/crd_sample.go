@@ -21,6 +21,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"sigs.k8s.io/yaml"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 )
@@ -33,16 +35,31 @@ type CRDSample struct {
 
 	// Resource is a resource in the API group
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	// Values are the values of the spec of the sample, e.g. those of the file
+	// of create api --sample-values. The spec only has the example field if
+	// nil.
+	Values map[string]interface{}
+
+	// ExampleField is true if the types have the example field, whose value
+	// the Values are merged into
+	ExampleField bool
 }
 
 // GetInput implements input.File
 func (c *CRDSample) GetInput() (input.Input, error) {
 	if c.Path == "" {
-		c.Path = filepath.Join("config", "samples", fmt.Sprintf(
-			"%s_%s_%s.yaml", c.Resource.Group, c.Resource.Version, strings.ToLower(c.Resource.Kind)))
+		c.Path = filepath.Join("config", "samples", c.Resource.SampleFileName())
 	}
 
-	c.IfExistsAction = input.Error
+	if c.Force {
+		c.IfExistsAction = input.Overwrite
+	} else {
+		c.IfExistsAction = input.Error
+	}
 	c.TemplateBody = crdSampleTemplate
 	return c.Input, nil
 }
@@ -52,11 +69,66 @@ func (c *CRDSample) Validate() error {
 	return c.Resource.Validate()
 }
 
-const crdSampleTemplate = `apiVersion: {{ .Resource.Group }}.{{ .Domain }}/{{ .Resource.Version }}
+// Comments returns the comments of the sample telling how kubectl lists it,
+// if the resource has short names, categories or printer columns.
+func (c *CRDSample) Comments() []string {
+	var comments []string
+	names := append(append([]string{}, c.Resource.ShortNames...), c.Resource.Categories...)
+	for _, name := range names {
+		comments = append(comments, fmt.Sprintf("# kubectl get %s lists this sample", name))
+	}
+	if len(c.Resource.PrintColumns) > 0 {
+		columns := []string{"NAME"}
+		for _, column := range c.Resource.PrintColumns {
+			columns = append(columns, column.Name)
+		}
+		comments = append(comments, fmt.Sprintf("# kubectl get prints the columns %s", strings.Join(columns, ", ")))
+	}
+	return comments
+}
+
+// Spec returns the spec of the sample with the Values, indented under the
+// spec key
+func (c *CRDSample) Spec() (string, error) {
+	spec := map[string]interface{}{}
+	if c.ExampleField {
+		spec["foo"] = "bar"
+	}
+	mergeValues(spec, c.Values)
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling the spec of the sample: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// mergeValues merges the values into dst, recursively for the objects
+func mergeValues(dst, values map[string]interface{}) {
+	for k, v := range values {
+		src, srcIsObject := v.(map[string]interface{})
+		existing, dstIsObject := dst[k].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			mergeValues(existing, src)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+const crdSampleTemplate = `{{ range .Comments }}{{ . }}
+{{ end }}apiVersion: {{ qualifiedGroup .Resource.Group .Domain }}/{{ .Resource.Version }}
 kind: {{ .Resource.Kind }}
 metadata:
   name: {{ lower .Resource.Kind }}-sample
 spec:
+{{- if .Values }}
+{{ .Spec }}
+{{- else }}
   # Add fields here
   foo: bar
+{{- end }}
 `
@@ -33,6 +33,9 @@ type CRDViewerRole struct {
 
 	// Resource is a resource in the API group
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
 }
 
 // GetInput implements input.File
@@ -42,6 +45,9 @@ func (g *CRDViewerRole) GetInput() (input.Input, error) {
 	}
 
 	g.TemplateBody = crdRoleViewerTemplate
+	if g.Force {
+		g.IfExistsAction = input.Overwrite
+	}
 	return g.Input, nil
 }
 
@@ -57,7 +63,7 @@ metadata:
   name: {{ lower .Resource.Kind }}-viewer-role
 rules:
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ qualifiedGroup .Resource.Group .Domain }}
   resources:
   - {{ .Resource.Resource }}
   verbs:
@@ -65,7 +71,7 @@ rules:
   - list
   - watch
 - apiGroups:
-  - {{ .Resource.Group }}.{{ .Domain }}
+  - {{ qualifiedGroup .Resource.Group .Domain }}
   resources:
   - {{ .Resource.Resource }}/status
   verbs:
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &DevKustomization{}
+
+// DevKustomization scaffolds the dev overlay, which relaxes the secure
+// defaults of the default overlay for development
+type DevKustomization struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (k *DevKustomization) GetInput() (input.Input, error) {
+	if k.Path == "" {
+		k.Path = filepath.Join("config", "dev", "kustomization.yaml")
+	}
+	k.TemplateBody = devKustomizationTemplate
+	k.Input.IfExistsAction = input.Error
+	return k.Input, nil
+}
+
+const devKustomizationTemplate = `# The dev overlay deploys config/default with relaxed security settings, so
+# that the manager can be debugged: it can write to its root filesystem and
+# its traffic is not restricted by network policies.
+bases:
+- ../default
+
+patchesStrategicMerge:
+- manager_dev_patch.yaml
+- network_policy_dev_patch.yaml
+`
+
+var _ input.File = &ManagerDevPatch{}
+
+// ManagerDevPatch scaffolds the patch of the dev overlay relaxing the security
+// context of the manager
+type ManagerDevPatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (p *ManagerDevPatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "dev", "manager_dev_patch.yaml")
+	}
+	p.TemplateBody = managerDevPatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const managerDevPatchTemplate = `# This patch lets the manager write to its root filesystem
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        securityContext:
+          readOnlyRootFilesystem: false
+`
+
+var _ input.File = &NetworkPolicyDevPatch{}
+
+// NetworkPolicyDevPatch scaffolds the patch of the dev overlay removing the
+// network policy of the manager
+type NetworkPolicyDevPatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (p *NetworkPolicyDevPatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "dev", "network_policy_dev_patch.yaml")
+	}
+	p.TemplateBody = networkPolicyDevPatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const networkPolicyDevPatchTemplate = `# This patch removes the network policy of the manager
+$patch: delete
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-manager-traffic
+  namespace: system
+`
@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// DevTooling is the tool running the local development loop, which rebuilds
+// and redeploys the manager when the code changes
+type DevTooling string
+
+const (
+	// DevToolingNone scaffolds no development loop
+	DevToolingNone DevTooling = "none"
+
+	// DevToolingTilt scaffolds a Tiltfile, see https://tilt.dev
+	DevToolingTilt DevTooling = "tilt"
+
+	// DevToolingSkaffold scaffolds a skaffold.yaml, see https://skaffold.dev
+	DevToolingSkaffold DevTooling = "skaffold"
+)
+
+// Validate validates the DevTooling
+func (t DevTooling) Validate() error {
+	switch t {
+	case DevToolingNone, DevToolingTilt, DevToolingSkaffold:
+		return nil
+	}
+	return fmt.Errorf("unknown dev tooling %q, should be one of %s, %s, %s",
+		t, DevToolingTilt, DevToolingSkaffold, DevToolingNone)
+}
+
+// devSourcePaths are the paths whose changes rebuild the manager image
+var devSourcePaths = []string{"main.go", "go.mod", "go.sum", "api", "controllers", "Dockerfile"}
+
+var _ input.File = &Tiltfile{}
+
+// Tiltfile scaffolds the Tiltfile of the development loop
+type Tiltfile struct {
+	input.Input
+
+	// Overlay is the kustomization deployed by the development loop
+	Overlay string
+
+	// Deps are the paths whose changes rebuild the manager image
+	Deps []string
+}
+
+// GetInput implements input.File
+func (t *Tiltfile) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = "Tiltfile"
+	}
+	if t.Overlay == "" {
+		t.Overlay = "config/default"
+	}
+	if t.Deps == nil {
+		t.Deps = devSourcePaths
+	}
+	t.TemplateBody = tiltfileTemplate
+	return t.Input, nil
+}
+
+const tiltfileTemplate = `# -*- mode: Python -*-
+
+# Local development loop, run it with "tilt up". The manager image is rebuilt
+# with the Makefile and redeployed when the code changes.
+# See https://docs.tilt.dev for more.
+
+custom_build(
+    'controller',
+    'make docker-build IMG=$EXPECTED_REF',
+    deps=[{{ range $i, $dep := .Deps }}{{ if $i }}, {{ end }}'{{ $dep }}'{{ end }}],
+)
+
+# The manifests are rendered from the kustomization, which includes the CRDs
+# added by "kubebuilder create api", and rendered again when it changes.
+k8s_yaml(kustomize('{{ .Overlay }}'))
+`
+
+var _ input.File = &SkaffoldConfig{}
+
+// SkaffoldConfig scaffolds the skaffold.yaml of the development loop
+type SkaffoldConfig struct {
+	input.Input
+
+	// Overlay is the kustomization deployed by the development loop
+	Overlay string
+
+	// Deps are the paths whose changes rebuild the manager image
+	Deps []string
+}
+
+// GetInput implements input.File
+func (s *SkaffoldConfig) GetInput() (input.Input, error) {
+	if s.Path == "" {
+		s.Path = "skaffold.yaml"
+	}
+	if s.Overlay == "" {
+		s.Overlay = "config/default"
+	}
+	if s.Deps == nil {
+		s.Deps = devSourcePaths
+	}
+	s.TemplateBody = skaffoldConfigTemplate
+	return s.Input, nil
+}
+
+const skaffoldConfigTemplate = `# Local development loop, run it with "skaffold dev". The manager image is
+# rebuilt with the Makefile and redeployed when the code changes.
+# See https://skaffold.dev/docs for more.
+apiVersion: skaffold/v2alpha1
+kind: Config
+build:
+  artifacts:
+  - image: controller
+    custom:
+      buildCommand: make docker-build IMG=$IMAGE
+      dependencies:
+        paths:
+{{- range .Deps }}
+        - {{ . }}
+{{- end }}
+  local:
+    push: false
+deploy:
+  # the kustomization includes the CRDs added by "kubebuilder create api"
+  kustomize:
+    paths:
+    - {{ .Overlay }}
+`
@@ -17,7 +17,12 @@ limitations under the License.
 package v2
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
 )
 
 var _ input.File = &Dockerfile{}
@@ -25,6 +30,16 @@ var _ input.File = &Dockerfile{}
 // Dockerfile scaffolds a Dockerfile for building a main
 type Dockerfile struct {
 	input.Input
+
+	// MultiArch cross-compiles the manager for the target platform of the
+	// build instead of linux/amd64
+	MultiArch bool
+
+	// BaseImage is the base image of the manager image, defaults to distroless
+	BaseImage manager.BaseImage
+
+	// GoVersion is the Go release of the builder image, defaults to 1.13
+	GoVersion string
 }
 
 // GetInput implements input.File
@@ -32,12 +47,25 @@ func (c *Dockerfile) GetInput() (input.Input, error) {
 	if c.Path == "" {
 		c.Path = "Dockerfile"
 	}
+	if c.BaseImage == "" {
+		c.BaseImage = manager.BaseImageDistroless
+	}
+	if c.GoVersion == "" {
+		c.GoVersion = "1.13"
+	}
 	c.TemplateBody = dockerfileTemplate
+	c.ScaffoldVersion = dockerfileVersion.Version
 	return c.Input, nil
 }
 
 const dockerfileTemplate = `# Build the manager binary
-FROM golang:1.13 as builder
+{{- if .MultiArch }}
+FROM --platform=${BUILDPLATFORM} golang:{{ .GoVersion }} as builder
+ARG TARGETOS
+ARG TARGETARCH
+{{- else }}
+FROM golang:{{ .GoVersion }} as builder
+{{- end }}
 
 WORKDIR /workspace
 # Copy the Go Modules manifests
@@ -53,7 +81,33 @@ COPY api/ api/
 COPY controllers/ controllers/
 
 # Build
+{{- if .MultiArch }}
+# the GOARCH has no default value so that the binary is built for the platform
+# of the host when building without buildx
+RUN CGO_ENABLED=0 GOOS=${TARGETOS:-linux} GOARCH=${TARGETARCH} GO111MODULE=on go build -a -o manager main.go
+{{- else }}
 RUN CGO_ENABLED=0 GOOS=linux GOARCH=amd64 GO111MODULE=on go build -a -o manager main.go
+{{- end }}
+
+{{- if eq .BaseImage "scratch" }}
+
+# Use scratch as base image to package the manager binary, the CA certificates
+# of the builder are copied to reach TLS endpoints
+FROM scratch
+WORKDIR /
+COPY --from=builder /etc/ssl/certs/ca-certificates.crt /etc/ssl/certs/
+COPY --from=builder /workspace/manager .
+USER 65532:65532
+{{- else if eq .BaseImage "ubi8" }}
+
+# Use the Red Hat Universal Base Image minimal as base image to package the
+# manager binary, it ships the CA certificates
+# Refer to https://catalog.redhat.com/software/containers/ubi8/ubi-minimal for more details
+FROM registry.access.redhat.com/ubi8/ubi-minimal:latest
+WORKDIR /
+COPY --from=builder /workspace/manager .
+USER 65532:65532
+{{- else }}
 
 # Use distroless as minimal base image to package the manager binary
 # Refer to https://github.com/GoogleContainerTools/distroless for more details
@@ -61,6 +115,36 @@ FROM gcr.io/distroless/static:nonroot
 WORKDIR /
 COPY --from=builder /workspace/manager .
 USER nonroot:nonroot
+{{- end }}
 
 ENTRYPOINT ["/manager"]
 `
+
+// controllersCopy is the instruction of the Dockerfile copying the controllers
+// package, the other directories of Go source are copied after it
+const controllersCopy = "COPY controllers/ controllers/"
+
+// AddDockerfileSource adds the instruction copying the dir directory of Go
+// source to the Dockerfile at path, unless it is already copied.
+func AddDockerfileSource(path, dir string) error {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	content := string(b)
+	instruction := fmt.Sprintf("COPY %s/ %s/", dir, dir)
+	if strings.Contains(content, instruction) {
+		return nil
+	}
+	i := strings.Index(content, controllersCopy)
+	if i < 0 {
+		return fmt.Errorf("%s does not copy the controllers package, add %q to it", path, instruction)
+	}
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+	i += len(controllersCopy)
+	content = content[:i] + eol + instruction + content[i:]
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+// Dir is the directory holding the configuration of the API reference docs,
+// which are generated in its api subdirectory
+const Dir = "docs"
+
+// APIPackagesMarker is the marker of the Makefile before which the API
+// packages documented by the docsgen target are added
+var APIPackagesMarker = marker.For("Makefile", "docsgen-packages").String()
+
+var _ input.File = &Config{}
+
+// Config scaffolds the configuration of crd-ref-docs
+type Config struct {
+	input.Input
+
+	// KubernetesVersion is the version of the Kubernetes API reference the
+	// docs link to, defaults to 1.16
+	KubernetesVersion string
+}
+
+// GetInput implements input.File
+func (c *Config) GetInput() (input.Input, error) {
+	if c.Path == "" {
+		c.Path = filepath.Join(Dir, "config.yaml")
+	}
+	if c.KubernetesVersion == "" {
+		c.KubernetesVersion = "1.16"
+	}
+	c.TemplateBody = configTemplate
+	c.Input.IfExistsAction = input.Error
+	return c.Input, nil
+}
+
+const configTemplate = `# Configuration of crd-ref-docs, which generates the API reference docs with
+# make docsgen, see https://github.com/elastic/crd-ref-docs
+processor:
+  # types left out of the docs, as regular expressions
+  ignoreTypes:
+  - "List$"
+  # fields left out of the docs, as regular expressions
+  ignoreFields:
+  - "TypeMeta$"
+render:
+  # version of the Kubernetes API reference the docs link to
+  kubernetesVersion: {{ .KubernetesVersion }}
+`
+
+var _ input.File = &Header{}
+
+// Header scaffolds the header of the API reference docs
+type Header struct {
+	input.Input
+
+	// ProjectName is the name of the project, defaults to the directory name
+	ProjectName string
+}
+
+// GetInput implements input.File
+func (h *Header) GetInput() (input.Input, error) {
+	if h.Path == "" {
+		h.Path = filepath.Join(Dir, "templates", "header.md")
+	}
+	if h.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return input.Input{}, err
+		}
+		h.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	h.TemplateBody = headerTemplate
+	h.Input.IfExistsAction = input.Error
+	return h.Input, nil
+}
+
+const headerTemplate = `# {{ .ProjectName }} API reference
+
+<!-- Generated by make docsgen, edit docs/templates/header.md instead. -->
+
+`
+
+var _ input.File = &Footer{}
+
+// Footer scaffolds the footer of the API reference docs
+type Footer struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (f *Footer) GetInput() (input.Input, error) {
+	if f.Path == "" {
+		f.Path = filepath.Join(Dir, "templates", "footer.md")
+	}
+	f.TemplateBody = footerTemplate
+	f.Input.IfExistsAction = input.Error
+	return f.Input, nil
+}
+
+const footerTemplate = `
+---
+
+_This reference is generated from the API types with [crd-ref-docs](https://github.com/elastic/crd-ref-docs)._
+`
+
+// AddAPIPackage adds the package of the API of the resource to the packages
+// documented by the docsgen target of the Makefile at path
+func AddAPIPackage(path string, r *resource.Resource) error {
+	return internal.InsertStringsInFile(path,
+		map[string][]string{
+			APIPackagesMarker: {fmt.Sprintf("API_DOCS_PACKAGES += ./api/%s\n", r.Version)},
+		})
+}
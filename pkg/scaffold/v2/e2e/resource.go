@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &ResourceTest{}
+
+// ResourceTest scaffolds an e2e test stub which applies the sample of a Resource
+type ResourceTest struct {
+	input.Input
+
+	// Resource is the Resource to test
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (t *ResourceTest) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = filepath.Join(Dir, fmt.Sprintf("%s_test.go", strings.ToLower(t.Resource.Kind)))
+	}
+	t.TemplateBody = resourceTestTemplate
+	t.Input.IfExistsAction = input.Error
+	return t.Input, nil
+}
+
+// Validate validates the values
+func (t *ResourceTest) Validate() error {
+	return t.Resource.Validate()
+}
+
+const resourceTestTemplate = `// +build e2e
+
+{{ .Boilerplate }}
+
+package e2e
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"{{ .Repo }}/test/utils"
+)
+
+var _ = Describe("{{ .Resource.Kind }}", func() {
+	sample := filepath.Join("config", "samples", "{{ .Resource.SampleFileName }}")
+
+	AfterEach(func() {
+		_, _ = utils.Run(exec.Command("kubectl", "delete", "--ignore-not-found", "-f", sample))
+	})
+
+	It("should be created from the sample", func() {
+		_, err := utils.Run(exec.Command("kubectl", "apply", "-f", sample))
+		Expect(err).NotTo(HaveOccurred())
+
+		// TODO(user): check the resources reconciled by the {{ .Resource.Kind }} controller.
+	})
+})
+`
@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Dir is the directory holding the e2e tests of a project
+var Dir = filepath.Join("test", "e2e")
+
+var _ input.File = &SuiteTest{}
+
+// SuiteTest scaffolds the test/e2e/e2e_suite_test.go file which deploys the
+// project on a kind cluster
+type SuiteTest struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (s *SuiteTest) GetInput() (input.Input, error) {
+	if s.Path == "" {
+		s.Path = filepath.Join(Dir, "e2e_suite_test.go")
+	}
+	s.TemplateBody = suiteTestTemplate
+	s.Input.IfExistsAction = input.Error
+	return s.Input, nil
+}
+
+const suiteTestTemplate = `// +build e2e
+
+{{ .Boilerplate }}
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"{{ .Repo }}/test/utils"
+)
+
+// These tests use Ginkgo (BDD-style Go testing framework). Refer to
+// http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+//
+// They build the manager image, load it into a kind cluster and deploy the
+// project with "make deploy". Run them with "make test-e2e".
+
+var (
+	// clusterName is the kind cluster the tests run against
+	clusterName = envOrDefault("KIND_CLUSTER", "kind")
+
+	// image is the manager image built and deployed by the tests
+	image = envOrDefault("IMG", "controller:e2e")
+)
+
+func envOrDefault(key, defaultValue string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return defaultValue
+}
+
+func TestE2E(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "E2E Suite")
+}
+
+var _ = BeforeSuite(func() {
+	By("creating the kind cluster")
+	Expect(utils.CreateKindCluster(clusterName)).To(Succeed())
+
+	By("building the manager image")
+	_, err := utils.Run(exec.Command("make", "docker-build", fmt.Sprintf("IMG=%s", image)))
+	Expect(err).NotTo(HaveOccurred())
+
+	By("loading the manager image on the kind cluster")
+	Expect(utils.LoadImageToKindCluster(clusterName, image)).To(Succeed())
+
+	By("installing the CRDs")
+	_, err = utils.Run(exec.Command("make", "install"))
+	Expect(err).NotTo(HaveOccurred())
+
+	By("deploying the controller-manager")
+	_, err = utils.Run(exec.Command("make", "deploy", fmt.Sprintf("IMG=%s", image)))
+	Expect(err).NotTo(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	By("deleting the kind cluster")
+	Expect(utils.DeleteKindCluster(clusterName)).To(Succeed())
+})
+
+var _ = Describe("controller-manager", func() {
+	It("should run successfully", func() {
+		Eventually(func() error {
+			out, err := utils.Run(exec.Command("kubectl", "get", "pods", "--all-namespaces",
+				"-l", "control-plane=controller-manager",
+				"-o", "jsonpath={.items[*].status.phase}"))
+			if err != nil {
+				return err
+			}
+			if phase := strings.TrimSpace(string(out)); phase != "Running" {
+				return fmt.Errorf("controller-manager pod in %q phase", phase)
+			}
+			return nil
+		}, 2*time.Minute, time.Second).Should(Succeed())
+	})
+})
+`
@@ -0,0 +1,117 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Utils{}
+
+// Utils scaffolds the test/utils/utils.go file with the helpers to run
+// commands and bootstrap kind clusters from the e2e tests
+type Utils struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (u *Utils) GetInput() (input.Input, error) {
+	if u.Path == "" {
+		u.Path = filepath.Join("test", "utils", "utils.go")
+	}
+	u.TemplateBody = utilsTemplate
+	u.Input.IfExistsAction = input.Error
+	return u.Input, nil
+}
+
+const utilsTemplate = `{{ .Boilerplate }}
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo" //nolint:golint
+)
+
+// Run executes the given command from the project root and returns its
+// combined output.
+func Run(cmd *exec.Cmd) ([]byte, error) {
+	dir, err := GetProjectDir()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+
+	command := strings.Join(cmd.Args, " ")
+	fmt.Fprintf(GinkgoWriter, "running: %s\n", command)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("%s failed with error: (%v) %s", command, err, string(output))
+	}
+	return output, nil
+}
+
+// CreateKindCluster creates a kind cluster with the given name, unless it
+// already exists.
+func CreateKindCluster(name string) error {
+	out, err := Run(exec.Command("kind", "get", "clusters"))
+	if err != nil {
+		return err
+	}
+	for _, cluster := range strings.Fields(string(out)) {
+		if cluster == name {
+			return nil
+		}
+	}
+	_, err = Run(exec.Command("kind", "create", "cluster", "--name", name))
+	return err
+}
+
+// DeleteKindCluster deletes the kind cluster with the given name.
+func DeleteKindCluster(name string) error {
+	_, err := Run(exec.Command("kind", "delete", "cluster", "--name", name))
+	return err
+}
+
+// LoadImageToKindCluster loads a local docker image into the kind cluster
+// with the given name.
+func LoadImageToKindCluster(name, image string) error {
+	_, err := Run(exec.Command("kind", "load", "docker-image", image, "--name", name))
+	return err
+}
+
+// GetProjectDir returns the root directory of the project.
+func GetProjectDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	// the tests run from test/e2e
+	if filepath.Base(wd) == "e2e" {
+		return filepath.Join(wd, "..", ".."), nil
+	}
+	return wd, nil
+}
+`
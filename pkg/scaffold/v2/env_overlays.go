@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Environment is a deployment environment of the manager, with its own
+// overlay under config/overlays and its own deploy target
+type Environment struct {
+	// Name is the name of the environment, e.g. dev
+	Name string
+
+	// Replicas is the number of replicas of the manager
+	Replicas int
+
+	// CPU and Memory are the resource limits of the manager
+	CPU, Memory string
+}
+
+// Environments are the environments of the overlays, from development to
+// production
+var Environments = []Environment{
+	{Name: "dev", Replicas: 1, CPU: "200m", Memory: "128Mi"},
+	{Name: "staging", Replicas: 2, CPU: "500m", Memory: "256Mi"},
+	{Name: "prod", Replicas: 3, CPU: "1", Memory: "512Mi"},
+}
+
+// ImageVar returns the Makefile variable of the image of the environment
+func (e Environment) ImageVar() string {
+	return strings.ToUpper(e.Name) + "_IMG"
+}
+
+// Image returns the default image of the environment, the given image with
+// the environment name as tag
+func (e Environment) Image(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return fmt.Sprintf("%s:%s", image, e.Name)
+}
+
+var _ input.File = &EnvKustomization{}
+
+// EnvKustomization scaffolds the overlay of an environment
+type EnvKustomization struct {
+	input.Input
+
+	// Environment is the environment of the overlay
+	Environment Environment
+
+	// Base is the overlay the environment builds on, relative to config,
+	// defaults to default
+	Base string
+}
+
+// GetInput implements input.File
+func (k *EnvKustomization) GetInput() (input.Input, error) {
+	if k.Path == "" {
+		k.Path = filepath.Join("config", "overlays", k.Environment.Name, "kustomization.yaml")
+	}
+	if k.Base == "" {
+		k.Base = "default"
+	}
+	k.TemplateBody = envKustomizationTemplate
+	k.Input.IfExistsAction = input.Error
+	return k.Input, nil
+}
+
+const envKustomizationTemplate = `# The {{ .Environment.Name }} overlay deploys config/{{ .Base }} with the replicas and resource
+# limits of the {{ .Environment.Name }} environment. It is deployed by make deploy-{{ .Environment.Name }}, which
+# sets the image of the manager to ${{ "{" }}{{ .Environment.ImageVar }}{{ "}" }}.
+bases:
+- ../../{{ .Base }}
+
+patchesStrategicMerge:
+- manager_patch.yaml
+`
+
+var _ input.File = &EnvManagerPatch{}
+
+// EnvManagerPatch scaffolds the patch of an environment overlay setting the
+// replicas and resource limits of the manager
+type EnvManagerPatch struct {
+	input.Input
+
+	// Environment is the environment of the overlay
+	Environment Environment
+
+	// LeaderElection indicates whether the manager runs with leader
+	// election, it runs a single replica otherwise
+	LeaderElection bool
+}
+
+// GetInput implements input.File
+func (p *EnvManagerPatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "overlays", p.Environment.Name, "manager_patch.yaml")
+	}
+	if !p.LeaderElection {
+		p.Environment.Replicas = 1
+	}
+	p.TemplateBody = envManagerPatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const envManagerPatchTemplate = `# This patch sets the replicas and resource limits of the manager in the
+# {{ .Environment.Name }} environment.
+{{- if not .LeaderElection }}
+# The manager runs without leader election, so a single replica can run.
+{{- else if gt .Environment.Replicas 1 }}
+# Only the leader of the replicas reconciles, the others take over if it fails.
+{{- end }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  replicas: {{ .Environment.Replicas }}
+  template:
+    spec:
+      containers:
+      - name: manager
+        resources:
+          limits:
+            cpu: {{ .Environment.CPU }}
+            memory: {{ .Environment.Memory }}
+`
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Events{}
+
+// Events scaffolds the events.go file of the controllers, with the typed
+// reasons of their events and the EventRecorder recording them. It is shared
+// by the controllers of its package, and kept if it already exists.
+type Events struct {
+	input.Input
+
+	// Package is the name of the package of the controllers, defaults to
+	// controllers
+	Package string
+}
+
+// GetInput implements input.File
+func (e *Events) GetInput() (input.Input, error) {
+	if e.Path == "" {
+		e.Path = filepath.Join("controllers", "events.go")
+	}
+	if e.Package == "" {
+		e.Package = "controllers"
+	}
+	e.TemplateBody = eventsTemplate
+	e.Input.IfExistsAction = input.Skip
+	return e.Input, nil
+}
+
+const eventsTemplate = `{{ .Boilerplate }}
+
+package {{ .Package }}
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// EventReason is the reason of the events recorded by the controllers, a
+// short UpperCamelCase string
+type EventReason string
+
+const (
+	// ReasonReconciled is the reason of the events recorded when an object is
+	// reconciled
+	ReasonReconciled EventReason = "Reconciled"
+
+	// ReasonReconcileFailed is the reason of the events recorded when an
+	// object cannot be reconciled
+	ReasonReconcileFailed EventReason = "ReconcileFailed"
+
+	// TODO(user): add the reasons of the events of your controllers
+)
+
+// EventRecorder records the events of the objects reconciled by the
+// controllers, with the reasons above
+type EventRecorder struct {
+	record.EventRecorder
+}
+
+// Normal records an event of the object for information
+func (r EventRecorder) Normal(obj runtime.Object, reason EventReason, messageFmt string, args ...interface{}) {
+	r.Eventf(obj, corev1.EventTypeNormal, string(reason), messageFmt, args...)
+}
+
+// Warning records an event of the object for an issue
+func (r EventRecorder) Warning(obj runtime.Object, reason EventReason, messageFmt string, args ...interface{}) {
+	r.Eventf(obj, corev1.EventTypeWarning, string(reason), messageFmt, args...)
+}
+`
@@ -25,7 +25,18 @@ var _ input.File = &GoMod{}
 // GoMod writes a templatefile for go.mod
 type GoMod struct {
 	input.Input
+
+	// GoVersion is the Go release of the go directive, defaults to 1.13
+	GoVersion string
+
 	ControllerRuntimeVersion string
+
+	// Profiling requires the OpenTelemetry modules imported by profiling.go
+	Profiling bool
+
+	// OpenTelemetryVersion is the version of the OpenTelemetry modules,
+	// defaults to OpenTelemetryVersion
+	OpenTelemetryVersion string
 }
 
 // GetInput implements input.File
@@ -33,6 +44,12 @@ func (g *GoMod) GetInput() (input.Input, error) {
 	if g.Path == "" {
 		g.Path = "go.mod"
 	}
+	if g.GoVersion == "" {
+		g.GoVersion = "1.13"
+	}
+	if g.OpenTelemetryVersion == "" {
+		g.OpenTelemetryVersion = OpenTelemetryVersion
+	}
 	g.Input.IfExistsAction = input.Overwrite
 	g.TemplateBody = goModTemplate
 	return g.Input, nil
@@ -41,9 +58,14 @@ func (g *GoMod) GetInput() (input.Input, error) {
 const goModTemplate = `
 module {{ .Repo }}
 
-go 1.13
+go {{ .GoVersion }}
 
 require (
+{{- if .Profiling }}
+	go.opentelemetry.io/otel {{ .OpenTelemetryVersion }}
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc {{ .OpenTelemetryVersion }}
+	go.opentelemetry.io/otel/sdk {{ .OpenTelemetryVersion }}
+{{- end }}
 	sigs.k8s.io/controller-runtime {{ .ControllerRuntimeVersion }}
 )
 `
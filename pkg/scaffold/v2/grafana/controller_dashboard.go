@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &ControllerDashboard{}
+
+// ControllerDashboard scaffolds a Grafana dashboard with the reconcile latency
+// panels of the controller of a Resource
+type ControllerDashboard struct {
+	input.Input
+
+	// Resource is the Resource the controller reconciles
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (d *ControllerDashboard) GetInput() (input.Input, error) {
+	if d.Path == "" {
+		d.Path = filepath.Join(Dir, fmt.Sprintf("%s_controller.json", strings.ToLower(d.Resource.Kind)))
+	}
+	d.TemplateBody = controllerDashboardTemplate
+	d.Input.IfExistsAction = input.Error
+	return d.Input, nil
+}
+
+// Validate validates the values
+func (d *ControllerDashboard) Validate() error {
+	return d.Resource.Validate()
+}
+
+// the controller name used as metric label is the lowercase Kind, which is
+// what the controller-runtime builder defaults to.
+const controllerDashboardTemplate = `{
+  "__inputs": [
+    {
+      "name": "DS_PROMETHEUS",
+      "label": "Prometheus",
+      "type": "datasource",
+      "pluginId": "prometheus"
+    }
+  ],
+  "title": "{{ .Resource.Kind }} Controller",
+  "uid": "{{ lower .Resource.Kind }}-controller",
+  "schemaVersion": 16,
+  "refresh": "30s",
+  "time": {
+    "from": "now-1h",
+    "to": "now"
+  },
+  "panels": [
+    {
+      "title": "{{ .Resource.Kind }} reconcile latency",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 24, "x": 0, "y": 0},
+      "targets": [
+        {
+          "expr": "histogram_quantile(0.50, sum(rate(controller_runtime_reconcile_time_seconds_bucket{controller=\"{{ lower .Resource.Kind }}\"}[5m])) by (le))",
+          "legendFormat": "p50"
+        },
+        {
+          "expr": "histogram_quantile(0.90, sum(rate(controller_runtime_reconcile_time_seconds_bucket{controller=\"{{ lower .Resource.Kind }}\"}[5m])) by (le))",
+          "legendFormat": "p90"
+        },
+        {
+          "expr": "histogram_quantile(0.99, sum(rate(controller_runtime_reconcile_time_seconds_bucket{controller=\"{{ lower .Resource.Kind }}\"}[5m])) by (le))",
+          "legendFormat": "p99"
+        }
+      ]
+    },
+    {
+      "title": "{{ .Resource.Kind }} reconcile results",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 24, "x": 0, "y": 8},
+      "targets": [
+        {
+          "expr": "sum(rate(controller_runtime_reconcile_total{controller=\"{{ lower .Resource.Kind }}\"}[5m])) by (result)",
+          "legendFormat": "{{"{{"}}result{{"}}"}}"
+        }
+      ]
+    }
+  ]
+}
+`
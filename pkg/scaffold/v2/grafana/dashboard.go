@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grafana
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Dir is the directory holding the Grafana dashboards of a project
+var Dir = filepath.Join("config", "grafana")
+
+var _ input.File = &RuntimeDashboard{}
+
+// RuntimeDashboard scaffolds a Grafana dashboard for the controller-runtime metrics
+type RuntimeDashboard struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (d *RuntimeDashboard) GetInput() (input.Input, error) {
+	if d.Path == "" {
+		d.Path = filepath.Join(Dir, "controller-runtime-metrics.json")
+	}
+	d.TemplateBody = runtimeDashboardTemplate
+	d.TemplateEngine = input.RawEngine{}
+	d.Input.IfExistsAction = input.Error
+	return d.Input, nil
+}
+
+const runtimeDashboardTemplate = `{
+  "__inputs": [
+    {
+      "name": "DS_PROMETHEUS",
+      "label": "Prometheus",
+      "type": "datasource",
+      "pluginId": "prometheus"
+    }
+  ],
+  "title": "Controller Runtime Metrics",
+  "uid": "controller-runtime-metrics",
+  "schemaVersion": 16,
+  "refresh": "30s",
+  "time": {
+    "from": "now-1h",
+    "to": "now"
+  },
+  "panels": [
+    {
+      "title": "Reconciliation rate (per controller)",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 12, "x": 0, "y": 0},
+      "targets": [
+        {
+          "expr": "sum(rate(controller_runtime_reconcile_total{job=\"$job\", namespace=\"$namespace\"}[5m])) by (controller, result)",
+          "legendFormat": "{{controller}} {{result}}"
+        }
+      ]
+    },
+    {
+      "title": "Reconciliation errors (per controller)",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 12, "x": 12, "y": 0},
+      "targets": [
+        {
+          "expr": "sum(rate(controller_runtime_reconcile_errors_total{job=\"$job\", namespace=\"$namespace\"}[5m])) by (controller)",
+          "legendFormat": "{{controller}}"
+        }
+      ]
+    },
+    {
+      "title": "Reconciliation time 99th percentile (per controller)",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 12, "x": 0, "y": 8},
+      "targets": [
+        {
+          "expr": "histogram_quantile(0.99, sum(rate(controller_runtime_reconcile_time_seconds_bucket{job=\"$job\", namespace=\"$namespace\"}[5m])) by (controller, le))",
+          "legendFormat": "{{controller}}"
+        }
+      ]
+    },
+    {
+      "title": "Workqueue depth (per queue)",
+      "type": "graph",
+      "datasource": "${DS_PROMETHEUS}",
+      "gridPos": {"h": 8, "w": 12, "x": 12, "y": 8},
+      "targets": [
+        {
+          "expr": "sum(workqueue_depth{job=\"$job\", namespace=\"$namespace\"}) by (name)",
+          "legendFormat": "{{name}}"
+        }
+      ]
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "job",
+        "type": "query",
+        "datasource": "${DS_PROMETHEUS}",
+        "query": "label_values(controller_runtime_reconcile_total, job)",
+        "refresh": 2
+      },
+      {
+        "name": "namespace",
+        "type": "query",
+        "datasource": "${DS_PROMETHEUS}",
+        "query": "label_values(controller_runtime_reconcile_total{job=\"$job\"}, namespace)",
+        "refresh": 2
+      }
+    ]
+  }
+}
+`
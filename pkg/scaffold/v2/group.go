@@ -31,6 +31,13 @@ type Group struct {
 
 	// Resource is a resource in the API group
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	// CodeGenerators adds the markers of conversion-gen and defaulter-gen,
+	// and the localSchemeBuilder their generated code registers with
+	CodeGenerators bool
 }
 
 // GetInput implements input.File
@@ -39,6 +46,9 @@ func (g *Group) GetInput() (input.Input, error) {
 		g.Path = filepath.Join("api", g.Resource.Version, "groupversion_info.go")
 	}
 	g.TemplateBody = groupTemplate
+	if g.Force {
+		g.IfExistsAction = input.Overwrite
+	}
 	return g.Input, nil
 }
 
@@ -49,9 +59,16 @@ func (g *Group) Validate() error {
 
 const groupTemplate = `{{ .Boilerplate }}
 
-// Package {{.Resource.Version}} contains API Schema definitions for the {{ .Resource.GroupImportSafe }} {{.Resource.Version}} API group
+// Package {{.Resource.Version}} contains API Schema definitions for the {{ if .Resource.Group }}{{ .Resource.GroupImportSafe }} {{ end }}{{.Resource.Version}} API group
 // +kubebuilder:object:generate=true
-// +groupName={{ .Resource.Group }}.{{ .Domain }}
+// +groupName={{ qualifiedGroup .Resource.Group .Domain }}
+{{- if .CodeGenerators }}
+// +k8s:defaulter-gen=TypeMeta
+//
+// TODO(user): to generate the conversions of this version to internal (or hub)
+// types with conversion-gen, add a +k8s:conversion-gen=<package> marker with
+// the package of these types.
+{{- end }}
 package {{ .Resource.Version }}
 
 import (
@@ -61,12 +78,17 @@ import (
 
 var (
 	// GroupVersion is group version used to register these objects
-	GroupVersion = schema.GroupVersion{Group: "{{ .Resource.Group }}.{{ .Domain }}", Version: "{{ .Resource.Version }}"}
+	GroupVersion = schema.GroupVersion{Group: "{{ qualifiedGroup .Resource.Group .Domain }}", Version: "{{ .Resource.Version }}"}
 
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
+{{- if .CodeGenerators }}
+
+	// localSchemeBuilder registers the functions generated by conversion-gen
+	localSchemeBuilder = &SchemeBuilder.SchemeBuilder
+{{- end }}
 )
 `
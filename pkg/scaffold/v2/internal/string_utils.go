@@ -18,13 +18,15 @@ package internal
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"golang.org/x/tools/imports"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 )
 
 // insertStrings reads content from given reader and insert string below the
@@ -32,7 +34,14 @@ import (
 // [v1], 'm2': [v2]})
 // v1 will be inserted below the lines containing m1 string and v2 will be inserted
 // below line containing m2 string.
-func insertStrings(r io.Reader, markerAndValues map[string][]string) (io.Reader, error) {
+// The values are inserted with the line endings of the content, LF or CRLF.
+// The map of the caller is left as it is, so it can be reused for other files.
+func insertStrings(r io.Reader, values map[string][]string) (io.Reader, error) {
+	markerAndValues := make(map[string][]string, len(values))
+	for marker, vals := range values {
+		markerAndValues[marker] = append([]string{}, vals...)
+	}
+
 	// reader clone is needed since we will be reading twice from the given reader
 	buf := new(bytes.Buffer)
 	rClone := io.TeeReader(r, buf)
@@ -42,6 +51,16 @@ func insertStrings(r io.Reader, markerAndValues map[string][]string) (io.Reader,
 		return nil, err
 	}
 
+	eol := "\n"
+	if bytes.Contains(buf.Bytes(), []byte("\r\n")) {
+		eol = "\r\n"
+		for _, vals := range markerAndValues {
+			for i, val := range vals {
+				vals[i] = strings.Replace(strings.Replace(val, "\r\n", "\n", -1), "\n", "\r\n", -1)
+			}
+		}
+	}
+
 	out := new(bytes.Buffer)
 
 	scanner := bufio.NewScanner(buf)
@@ -58,7 +77,7 @@ func insertStrings(r io.Reader, markerAndValues map[string][]string) (io.Reader,
 				}
 			}
 		}
-		_, err := out.WriteString(line + "\n")
+		_, err := out.WriteString(line + eol)
 		if err != nil {
 			return nil, err
 		}
@@ -70,6 +89,10 @@ func insertStrings(r io.Reader, markerAndValues map[string][]string) (io.Reader,
 }
 
 func InsertStringsInFile(path string, markerAndValues map[string][]string) error {
+	if err := validateMarkers(path, markerAndValues); err != nil {
+		return err
+	}
+
 	isGoFile := false
 	if ext := filepath.Ext(path); ext == ".go" {
 		isGoFile = true
@@ -97,7 +120,9 @@ func InsertStringsInFile(path string, markerAndValues map[string][]string) error
 
 	formattedContent := content
 	if isGoFile {
-		formattedContent, err = imports.Process(path, content, nil)
+		// the imports are grouped with the local prefix of the project in
+		// the working directory, like the scaffolded files
+		formattedContent, err = util.FormatImports(path, content, util.ImportsLocalPrefix("PROJECT"))
 		if err != nil {
 			return err
 		}
@@ -112,6 +137,28 @@ func InsertStringsInFile(path string, markerAndValues map[string][]string) error
 	return err
 }
 
+// validateMarkers returns an error if one of the markers the values are
+// inserted before is found more than once in the file at path, the values
+// would be inserted twice
+func validateMarkers(path string, markerAndValues map[string][]string) error {
+	if !marker.Supported(path) {
+		return nil
+	}
+	markers, err := marker.List(path)
+	if err != nil {
+		return err
+	}
+	found := make(map[string]bool, len(markers))
+	for _, m := range markers {
+		line := m.String()
+		if _, inserted := markerAndValues[line]; inserted && found[line] {
+			return fmt.Errorf("marker %q is found more than once in %s", line, path)
+		}
+		found[line] = true
+	}
+	return nil
+}
+
 // filterExistingValues removes the single-line values that already exists in
 // the given reader. Multi-line values are ignore currently simply because we
 // don't have a use-case for it.
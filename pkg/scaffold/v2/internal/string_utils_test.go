@@ -18,6 +18,9 @@ package internal
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -76,6 +79,14 @@ v1.AddToScheme(scheme)
 // +kubebuilder:scaffold:apis-add-scheme
 `,
 		},
+		{
+			// CRLF line endings
+			input: "resources:\r\n- bases/a.yaml\r\n# +kubebuilder:scaffold:crdkustomizeresource\r\n",
+			markerNValues: map[string][]string{
+				"# +kubebuilder:scaffold:crdkustomizeresource": {"- bases/a.yaml\n", "- bases/b.yaml\n"},
+			},
+			expected: "resources:\r\n- bases/a.yaml\r\n- bases/b.yaml\r\n# +kubebuilder:scaffold:crdkustomizeresource\r\n",
+		},
 	}
 
 	for _, test := range tests {
@@ -94,3 +105,48 @@ v1.AddToScheme(scheme)
 		}
 	}
 }
+
+func TestInsertStringsInFileDuplicateMarker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "insert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "kustomization.yaml")
+	content := `resources:
+# +kubebuilder:scaffold:crdkustomizeresource
+# +kubebuilder:scaffold:crdkustomizeresource
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = InsertStringsInFile(path, map[string][]string{
+		"# +kubebuilder:scaffold:crdkustomizeresource": {"- bases/ship.example.com_frigates.yaml\n"},
+	})
+	if err == nil {
+		t.Errorf("expected an error inserting before a duplicate marker")
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != content {
+		t.Errorf("file changed to: %s", string(b))
+	}
+}
+
+func TestInsertStringsKeepsValues(t *testing.T) {
+	values := map[string][]string{
+		"# +kubebuilder:scaffold:crdkustomizeresource": {"- bases/a.yaml\n", "- bases/b.yaml\n"},
+	}
+	inputs := []string{
+		"resources:\r\n- bases/a.yaml\r\n# +kubebuilder:scaffold:crdkustomizeresource\r\n",
+		"resources:\n# +kubebuilder:scaffold:crdkustomizeresource\n",
+	}
+	for _, input := range inputs {
+		if _, err := insertStrings(bytes.NewBufferString(input), values); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+	want := []string{"- bases/a.yaml\n", "- bases/b.yaml\n"}
+	if got := values["# +kubebuilder:scaffold:crdkustomizeresource"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("values changed to %q", got)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 var _ input.File = &Kustomize{}
@@ -32,6 +33,21 @@ type Kustomize struct {
 
 	// Prefix to use for name prefix customization
 	Prefix string
+
+	// CertSource is where the webhook server certificates come from
+	CertSource webhook.CertSource
+
+	// WatchNamespacePatch indicates whether to add the patch restricting
+	// the manager to its own namespace
+	WatchNamespacePatch bool
+
+	// NetworkPolicy indicates whether to deploy the network policies of the
+	// manager
+	NetworkPolicy bool
+
+	// ProfilingPatch indicates whether to add the patch enabling the pprof
+	// endpoints of the manager
+	ProfilingPatch bool
 }
 
 // GetInput implements input.File
@@ -47,6 +63,9 @@ func (c *Kustomize) GetInput() (input.Input, error) {
 		}
 		c.Prefix = strings.ToLower(filepath.Base(dir))
 	}
+	if c.CertSource == "" {
+		c.CertSource = webhook.CertSourceCertManager
+	}
 	c.TemplateBody = kustomizeTemplate
 	c.Input.IfExistsAction = input.Error
 	return c.Input, nil
@@ -72,10 +91,16 @@ bases:
 - ../manager
 # [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in crd/kustomization.yaml
 #- ../webhook
+{{- if eq .CertSource "cert-manager" }}
 # [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'. 'WEBHOOK' components are required.
 #- ../certmanager
+{{- end }}
 # [PROMETHEUS] To enable prometheus monitor, uncomment all sections with 'PROMETHEUS'. 
 #- ../prometheus
+{{- if .NetworkPolicy }}
+# Restrict the traffic from and to the manager, the dev overlay removes the network policies.
+- ../network-policy
+{{- end }}
 
 patchesStrategicMerge:
   # Protect the /metrics endpoint by putting it behind auth.
@@ -88,14 +113,28 @@ patchesStrategicMerge:
   # Only one of manager_auth_proxy_patch.yaml and
   # manager_prometheus_metrics_patch.yaml should be enabled.
 #- manager_prometheus_metrics_patch.yaml
+{{- if .WatchNamespacePatch }}
+
+  # Restrict the controller-manager to the namespace it is deployed in.
+- manager_watch_namespace_patch.yaml
+{{- end }}
+{{- if .ProfilingPatch }}
+
+  # Serve the pprof endpoints of the controller-manager and name its traces.
+- manager_profiling_patch.yaml
+{{- end }}
 
 # [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in crd/kustomization.yaml
 #- manager_webhook_patch.yaml
+{{- if eq .CertSource "cert-manager" }}
 
 # [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'.
 # Uncomment 'CERTMANAGER' sections in crd/kustomization.yaml to enable the CA injection in the admission webhooks.
 # 'CERTMANAGER' needs to be enabled to use ca injection
 #- webhookcainjection_patch.yaml
+{{- end }}
+
+{{- if eq .CertSource "cert-manager" }}
 
 # the following config is for teaching kustomize how to do var substitution
 vars:
@@ -126,4 +165,5 @@ vars:
 #    kind: Service
 #    version: v1
 #    name: webhook-service
+{{- end }}
 `
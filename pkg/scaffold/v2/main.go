@@ -19,17 +19,20 @@ package v2
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
-const (
-	apiPkgImportScaffoldMarker    = "// +kubebuilder:scaffold:imports"
-	apiSchemeScaffoldMarker       = "// +kubebuilder:scaffold:scheme"
-	reconcilerSetupScaffoldMarker = "// +kubebuilder:scaffold:builder"
+var (
+	apiPkgImportScaffoldMarker    = marker.For("main.go", "imports").String()
+	apiSchemeScaffoldMarker       = marker.For("main.go", "scheme").String()
+	reconcilerSetupScaffoldMarker = marker.For("main.go", "builder").String()
 )
 
 var _ input.File = &Main{}
@@ -37,6 +40,29 @@ var _ input.File = &Main{}
 // Main scaffolds a main.go to run Controllers
 type Main struct {
 	input.Input
+
+	// WatchNamespace restricts the manager to the namespace set in the
+	// WATCH_NAMESPACE environment variable
+	WatchNamespace bool
+
+	// MetricsBindAddress is the default address of the metrics endpoint,
+	// defaults to :8080
+	MetricsBindAddress string
+
+	// HealthProbePort is the default port of the health and readiness
+	// probes, there are no probes if it is 0
+	HealthProbePort int
+
+	// WebhookPort is the port the webhook server listens on, defaults to 9443
+	WebhookPort int
+
+	// WebhookCertDir is the directory the webhook server reads its
+	// certificates from, the controller-runtime default is kept if empty
+	WebhookCertDir string
+
+	// Profiling serves the pprof endpoints and sets up the trace exporter
+	// defined in profiling.go
+	Profiling bool
 }
 
 // GetInput implements input.File
@@ -44,6 +70,12 @@ func (m *Main) GetInput() (input.Input, error) {
 	if m.Path == "" {
 		m.Path = filepath.Join("main.go")
 	}
+	if m.MetricsBindAddress == "" {
+		m.MetricsBindAddress = ":8080"
+	}
+	if m.WebhookPort == 0 {
+		m.WebhookPort = webhook.DefaultServerPort
+	}
 	m.TemplateBody = mainTemplate
 	return m.Input, nil
 }
@@ -60,53 +92,93 @@ func (m *Main) Update(opts *MainUpdateOptions) error {
 `, opts.Resource.GroupImportSafe, opts.Resource.Version, resPkg, opts.Resource.Version)
 	ctrlImportCodeFragment := fmt.Sprintf(`"%s/controllers"
 `, opts.Project.Repo)
+	ctrlPkg := "controllers"
+	if opts.Layout == ControllerLayoutPerKind {
+		ctrlPkg = opts.Layout.importAlias(opts.Resource.Kind)
+		ctrlImportCodeFragment = fmt.Sprintf(`%s "%s/%s"
+`, ctrlPkg, opts.Project.Repo, filepath.ToSlash(opts.Layout.Dir(opts.Resource.Kind)))
+	}
 	addschemeCodeFragment := fmt.Sprintf(`_ = %s%s.AddToScheme(scheme)
 `, opts.Resource.GroupImportSafe, opts.Resource.Version)
-	reconcilerSetupCodeFragment := fmt.Sprintf(`if err = (&controllers.%sReconciler{
+	recorderCodeFragment := ""
+	if opts.Events {
+		recorderCodeFragment = fmt.Sprintf(`
+		Recorder: %s.EventRecorder{EventRecorder: mgr.GetEventRecorderFor("%s-controller")},`,
+			ctrlPkg, strings.ToLower(opts.Resource.Kind))
+	}
+	reconcilerSetupCodeFragment := fmt.Sprintf(`if err = (&%s.%sReconciler{
 		Client: mgr.GetClient(),
 		Log: ctrl.Log.WithName("controllers").WithName("%s"),
-		Scheme: mgr.GetScheme(),  
+		Scheme: mgr.GetScheme(),  %s
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "%s")
 		os.Exit(1)
 	}
-`, opts.Resource.Kind, opts.Resource.Kind, opts.Resource.Kind)
+`, ctrlPkg, opts.Resource.Kind, opts.Resource.Kind, recorderCodeFragment, opts.Resource.Kind)
 	webhookSetupCodeFragment := fmt.Sprintf(`if err = (&%s%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "%s")
 		os.Exit(1)
 	}
 `, opts.Resource.GroupImportSafe, opts.Resource.Version, opts.Resource.Kind, opts.Resource.Kind)
 
-	if opts.WireResource {
-		err := internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker: {apiImportCodeFragment},
-				apiSchemeScaffoldMarker:    {addschemeCodeFragment},
-			})
-		if err != nil {
-			return err
+	// the fragments of all the wirings are inserted at once, each fragment
+	// once
+	fragments := map[string][]string{}
+	add := func(marker string, values ...string) {
+		for _, v := range values {
+			found := false
+			for _, existing := range fragments[marker] {
+				found = found || existing == v
+			}
+			if !found {
+				fragments[marker] = append(fragments[marker], v)
+			}
 		}
 	}
 
+	if opts.WireResource {
+		add(apiPkgImportScaffoldMarker, apiImportCodeFragment)
+		add(apiSchemeScaffoldMarker, addschemeCodeFragment)
+	}
+
 	if opts.WireController {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				apiSchemeScaffoldMarker:       {addschemeCodeFragment},
-				reconcilerSetupScaffoldMarker: {reconcilerSetupCodeFragment},
-			})
+		add(apiPkgImportScaffoldMarker, apiImportCodeFragment, ctrlImportCodeFragment)
+		add(apiSchemeScaffoldMarker, addschemeCodeFragment)
+		add(reconcilerSetupScaffoldMarker, reconcilerSetupCodeFragment)
+	}
+
+	if opts.WireCoreWebhook {
+		add(apiPkgImportScaffoldMarker, fmt.Sprintf(`"%s/webhook"
+`, opts.Project.Repo))
+		add(reconcilerSetupScaffoldMarker, fmt.Sprintf(`if err = webhook.Setup%sWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "%s")
+		os.Exit(1)
+	}
+`, opts.Resource.Kind, opts.Resource.Kind))
 	}
 
 	if opts.WireWebhook {
-		return internal.InsertStringsInFile(path,
-			map[string][]string{
-				apiPkgImportScaffoldMarker:    {apiImportCodeFragment, ctrlImportCodeFragment},
-				apiSchemeScaffoldMarker:       {addschemeCodeFragment},
-				reconcilerSetupScaffoldMarker: {webhookSetupCodeFragment},
-			})
+		add(apiPkgImportScaffoldMarker, apiImportCodeFragment, ctrlImportCodeFragment)
+		add(apiSchemeScaffoldMarker, addschemeCodeFragment)
+		add(reconcilerSetupScaffoldMarker, webhookSetupCodeFragment)
 	}
 
-	return nil
+	if len(fragments) == 0 {
+		return nil
+	}
+	return internal.InsertStringsInFile(path, fragments)
+}
+
+// AddScheme updates main.go to add the types of an API package of another
+// module to the scheme of the manager.
+func (m *Main) AddScheme(s input.ExternalScheme) error {
+	return internal.InsertStringsInFile("main.go",
+		map[string][]string{
+			apiPkgImportScaffoldMarker: {fmt.Sprintf(`%s "%s"
+`, s.Alias, s.Package)},
+			apiSchemeScaffoldMarker: {fmt.Sprintf(`_ = %s.AddToScheme(scheme)
+`, s.Alias)},
+		})
 }
 
 // MainUpdateOptions contains info required for wiring an API/Controller in
@@ -122,6 +194,15 @@ type MainUpdateOptions struct {
 	WireResource   bool
 	WireController bool
 	WireWebhook    bool
+	// WireCoreWebhook registers the webhooks of a Kubernetes API type,
+	// scaffolded under webhook/
+	WireCoreWebhook bool
+
+	// Events sets the EventRecorder of the controller
+	Events bool
+
+	// Layout is the layout of the package of the controller, defaults to flat
+	Layout ControllerLayout
 }
 
 var mainTemplate = fmt.Sprintf(`{{ .Boilerplate }}
@@ -136,6 +217,9 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+{{- if .HealthProbePort }}
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+{{- end }}
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	%s
@@ -155,20 +239,62 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
-	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+{{- if .HealthProbePort }}
+	var probeAddr string
+{{- end }}
+{{- if .Profiling }}
+	var pprofAddr string
+{{- end }}
+	flag.StringVar(&metricsAddr, "metrics-addr", "{{ .MetricsBindAddress }}", "The address the metric endpoint binds to.")
+{{- if .HealthProbePort }}
+	flag.StringVar(&probeAddr, "health-probe-addr", ":{{ .HealthProbePort }}", "The address the probe endpoint binds to.")
+{{- end }}
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+{{- if .Profiling }}
+	flag.StringVar(&pprofAddr, "pprof-addr", os.Getenv("PPROF_ADDR"),
+		"The address the pprof endpoints bind to, defaults to $PPROF_ADDR. The endpoints are disabled if empty.")
+{{- end }}
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
 		o.Development = true
 	}))
+{{- if .Profiling }}
+
+	// the traces are only exported if OTEL_EXPORTER_OTLP_ENDPOINT is set
+	stopTracing, err := setupTracing()
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer stopTracing()
+{{- end }}
+{{- if .WatchNamespace }}
+
+	// the manager only watches the namespace it is deployed in, which is set
+	// in config/default/manager_watch_namespace_patch.yaml, and by make run
+	watchNamespace := os.Getenv("WATCH_NAMESPACE")
+	if watchNamespace == "" {
+		setupLog.Error(nil, "WATCH_NAMESPACE must be set")
+		os.Exit(1)
+	}
+{{- end }}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
-		Port:               9443, 
+		Port:               {{ .WebhookPort }},
+{{- if .WebhookCertDir }}
+		CertDir:            "{{ .WebhookCertDir }}",
+{{- end }}
+{{- if .HealthProbePort }}
+		HealthProbeBindAddress: probeAddr,
+{{- end }}
+{{- if .WatchNamespace }}
+		Namespace:          watchNamespace,
+{{- end }}
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -177,6 +303,29 @@ func main() {
 
 	%s
 
+	// +kubebuilder:scaffold:user-code-begin setup
+	// +kubebuilder:scaffold:user-code-end setup
+{{- if .Profiling }}
+
+	if pprofAddr != "" {
+		if err := mgr.Add(pprofServer(pprofAddr)); err != nil {
+			setupLog.Error(err, "unable to set up pprof endpoints")
+			os.Exit(1)
+		}
+	}
+{{- end }}
+{{- if .HealthProbePort }}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up health check")
+		os.Exit(1)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+{{- end }}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
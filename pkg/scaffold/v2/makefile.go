@@ -18,6 +18,7 @@ package v2
 
 import (
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/docs"
 )
 
 var _ input.File = &Makefile{}
@@ -29,6 +30,38 @@ type Makefile struct {
 	Image string
 	// Controller tools version to use in the project
 	ControllerToolsVersion string
+	// E2E indicates whether to add the test-e2e target
+	E2E bool
+	// OLM indicates whether to add the bundle targets
+	OLM bool
+	// MultiArch indicates whether to add the docker-buildx target
+	MultiArch bool
+	// EnvtestK8sVersion is the Kubernetes version of the envtest binaries
+	// downloaded by the setup-envtest target, if any
+	EnvtestK8sVersion string
+	// DevOverlay indicates whether to add the deploy-dev target
+	DevOverlay bool
+	// Environments are the environments of the overlays in config/overlays,
+	// which get a deploy target each, replacing the deploy-dev target
+	Environments []Environment
+	// CodeGeneratorVersion is the version of conversion-gen and
+	// defaulter-gen run by the generate target, they are not run if empty
+	CodeGeneratorVersion string
+	// PinnedTools indicates whether to install the tools from the versions
+	// pinned in hack/tools/go.mod instead of using the ones in the PATH, and
+	// to verify the checksums of the envtest binaries, which are recorded
+	// on first use by the envtest-checksum target
+	PinnedTools bool
+	// CRDRefDocsVersion is the version of crd-ref-docs run by the docsgen
+	// target, the target is not added if empty
+	CRDRefDocsVersion string
+	// GeneratedCerts indicates whether the deploy targets pipe the manifests
+	// through hack/certs, which generates the webhook server certificates
+	GeneratedCerts bool
+	// WatchNamespace indicates whether the manager only watches the namespace
+	// set by the WATCH_NAMESPACE environment variable, which the run target
+	// sets
+	WatchNamespace bool
 }
 
 // GetInput implements input.File
@@ -40,15 +73,30 @@ func (c *Makefile) GetInput() (input.Input, error) {
 		c.Image = "controller:latest"
 	}
 	c.TemplateBody = makefileTemplate
+	c.ScaffoldVersion = makefileVersion.Version
 	c.Input.IfExistsAction = input.Error
 	return c.Input, nil
 }
 
-const makefileTemplate = `
+var makefileTemplate = `
+{{- $kustomize := "kustomize" }}{{ $kustomizeDep := "" }}
+{{- if .PinnedTools }}{{ $kustomize = "$(KUSTOMIZE)" }}{{ $kustomizeDep = " kustomize" }}{{ end }}
+{{- $certs := "" }}{{ if .GeneratedCerts }}{{ $certs = " | go run ./hack/certs" }}{{ end }}
 # Image URL to use all building/pushing image targets
 IMG ?= {{ .Image }}
+{{- if .Environments }}
+# Images deployed by the deploy-<environment> targets
+{{- range .Environments }}
+{{ .ImageVar }} ?= {{ .Image $.Image }}
+{{- end }}
+{{- end }}
 # Produce CRDs that work back to Kubernetes 1.11 (no version conversion)
 CRD_OPTIONS ?= "crd:trivialVersions=true"
+{{- if .WatchNamespace }}
+
+# Namespace watched by the manager run by make run
+WATCH_NAMESPACE ?= default
+{{- end }}
 
 # Get the currently used golang install path (in GOPATH/bin, unless GOBIN is set)
 ifeq (,$(shell go env GOBIN))
@@ -56,61 +104,220 @@ GOBIN=$(shell go env GOPATH)/bin
 else
 GOBIN=$(shell go env GOBIN)
 endif
+{{- if .EnvtestK8sVersion }}
+
+# Kubernetes version of the kube-apiserver, etcd and kubectl binaries used by the tests
+ENVTEST_K8S_VERSION ?= {{ .EnvtestK8sVersion }}
+ENVTEST_ASSETS_DIR = $(shell pwd)/testbin
+{{- end }}
+{{- if .PinnedTools }}
+
+# The tools are installed in TOOLS_BIN from the versions pinned in
+# hack/tools/go.mod, the checksums of their modules are recorded in
+# hack/tools/go.sum and verified from then on
+TOOLS_DIR = $(shell pwd)/hack/tools
+TOOLS_BIN = $(shell pwd)/bin/tools
+{{- end }}
 
 all: manager
 
-# Run tests
-test: generate fmt vet manifests
+# The targets described by a ## comment are listed by make help
+help: ## Display the targets of the Makefile
+	@awk 'BEGIN {FS = ":.*## *"; printf "Usage:\n  make \033[36m<target>\033[0m\n\nTargets:\n"} /^[a-zA-Z0-9_-]+:.*##/ { printf "  \033[36m%-20s\033[0m %s\n", $$1, $$2 }' $(MAKEFILE_LIST)
+{{- if .EnvtestK8sVersion }}
+
+test: generate fmt vet manifests setup-envtest ## Run tests
+	KUBEBUILDER_ASSETS=$(ENVTEST_ASSETS_DIR)/bin go test ./... -coverprofile cover.out
+
+setup-envtest: $(ENVTEST_ASSETS_DIR)/$(ENVTEST_K8S_VERSION) ## Download the envtest binaries of ENVTEST_K8S_VERSION
+$(ENVTEST_ASSETS_DIR)/$(ENVTEST_K8S_VERSION):
+	rm -rf $(ENVTEST_ASSETS_DIR) && mkdir -p $(ENVTEST_ASSETS_DIR)
+{{- if .PinnedTools }}
+	@grep -q " $(ENVTEST_ARCHIVE)$$" $(ENVTEST_CHECKSUMS) 2>/dev/null || { \
+		echo "no checksum of $(ENVTEST_ARCHIVE) in $(ENVTEST_CHECKSUMS), record it with make envtest-checksum"; \
+		exit 1; }
+	curl -sSLo $(ENVTEST_ASSETS_DIR)/$(ENVTEST_ARCHIVE) "https://storage.googleapis.com/kubebuilder-tools/$(ENVTEST_ARCHIVE)"
+	cd $(ENVTEST_ASSETS_DIR) && grep " $(ENVTEST_ARCHIVE)$$" $(ENVTEST_CHECKSUMS) | $(SHA256SUM) -c -
+	tar -C $(ENVTEST_ASSETS_DIR) --strip-components=1 -zxf $(ENVTEST_ASSETS_DIR)/$(ENVTEST_ARCHIVE)
+	rm $(ENVTEST_ASSETS_DIR)/$(ENVTEST_ARCHIVE)
+{{- else }}
+	curl -sSL "https://storage.googleapis.com/kubebuilder-tools/kubebuilder-tools-$(ENVTEST_K8S_VERSION)-$(shell go env GOOS)-$(shell go env GOARCH).tar.gz" | \
+		tar -C $(ENVTEST_ASSETS_DIR) --strip-components=1 -zx
+{{- end }}
+	touch $@
+{{- if .PinnedTools }}
+
+# setup-envtest verifies the envtest binaries of each Kubernetes version and
+# platform against their checksum in ENVTEST_CHECKSUMS, and fails if there is
+# none. kubebuilder does not ship these checksums: envtest-checksum records
+# the checksum of the archive it downloads, which is trust on first use.
+# Check the recorded checksum against a trusted source, then commit
+# ENVTEST_CHECKSUMS to verify the next downloads.
+ENVTEST_ARCHIVE = kubebuilder-tools-$(ENVTEST_K8S_VERSION)-$(shell go env GOOS)-$(shell go env GOARCH).tar.gz
+ENVTEST_CHECKSUMS = $(TOOLS_DIR)/envtest.sha256
+SHA256SUM ?= $(shell command -v sha256sum || echo shasum -a 256)
+
+envtest-checksum: ## Record the checksum of the envtest binaries of ENVTEST_K8S_VERSION, trusting this download
+	@! grep -q " $(ENVTEST_ARCHIVE)$$" $(ENVTEST_CHECKSUMS) 2>/dev/null || { \
+		echo "the checksum of $(ENVTEST_ARCHIVE) is already recorded in $(ENVTEST_CHECKSUMS)"; \
+		exit 1; }
+	mkdir -p $(ENVTEST_ASSETS_DIR)
+	curl -sSLo $(ENVTEST_ASSETS_DIR)/$(ENVTEST_ARCHIVE) "https://storage.googleapis.com/kubebuilder-tools/$(ENVTEST_ARCHIVE)"
+	cd $(ENVTEST_ASSETS_DIR) && $(SHA256SUM) $(ENVTEST_ARCHIVE) >> $(ENVTEST_CHECKSUMS)
+	rm $(ENVTEST_ASSETS_DIR)/$(ENVTEST_ARCHIVE)
+{{- end }}
+{{- else }}
+
+test: generate fmt vet manifests ## Run tests
 	go test ./... -coverprofile cover.out
+{{- end }}
+{{- if .E2E }}
+
+test-e2e: ## Run e2e tests against a kind cluster
+	go test -tags e2e ./test/e2e/ -v -ginkgo.v
+{{- end }}
 
-# Build manager binary
-manager: generate fmt vet
+manager: generate fmt vet ## Build manager binary
 	go build -o bin/manager main.go
 
-# Run against the configured Kubernetes cluster in ~/.kube/config
-run: generate fmt vet manifests
-	go run ./main.go
+run: generate fmt vet manifests ## Run against the configured Kubernetes cluster in ~/.kube/config
+	{{ if .WatchNamespace }}WATCH_NAMESPACE=$(WATCH_NAMESPACE) {{ end }}go run ./main.go
+
+install: manifests{{ $kustomizeDep }} ## Install CRDs into a cluster
+	{{ $kustomize }} build config/crd | kubectl apply -f -
 
-# Install CRDs into a cluster
-install: manifests
-	kustomize build config/crd | kubectl apply -f -
+uninstall: manifests{{ $kustomizeDep }} ## Uninstall CRDs from a cluster
+	{{ $kustomize }} build config/crd | kubectl delete -f -
 
-# Uninstall CRDs from a cluster
-uninstall: manifests
-	kustomize build config/crd | kubectl delete -f -
+deploy: manifests{{ $kustomizeDep }} ## Deploy controller in the configured Kubernetes cluster in ~/.kube/config
+	cd config/manager && {{ $kustomize }} edit set image controller=${IMG}
+	{{ $kustomize }} build config/default{{ $certs }} | kubectl apply -f -
+{{- range .Environments }}
 
-# Deploy controller in the configured Kubernetes cluster in ~/.kube/config
-deploy: manifests
-	cd config/manager && kustomize edit set image controller=${IMG}
-	kustomize build config/default | kubectl apply -f -
+# The settings of the {{ .Name }} environment are in config/overlays/{{ .Name }}
+deploy-{{ .Name }}: manifests{{ $kustomizeDep }} ## Deploy controller with the settings of the {{ .Name }} environment
+	cd config/manager && {{ $kustomize }} edit set image controller=${{ "{" }}{{ .ImageVar }}{{ "}" }}
+	{{ $kustomize }} build config/overlays/{{ .Name }}{{ $certs }} | kubectl apply -f -
+{{- end }}
+{{- if and .DevOverlay (not .Environments) }}
 
-# Generate manifests e.g. CRD, RBAC etc.
-manifests: controller-gen
+deploy-dev: manifests{{ $kustomizeDep }} ## Deploy controller with the relaxed security settings of the dev overlay
+	cd config/manager && {{ $kustomize }} edit set image controller=${IMG}
+	{{ $kustomize }} build config/dev{{ $certs }} | kubectl apply -f -
+{{- end }}
+
+manifests: controller-gen ## Generate manifests e.g. CRD, RBAC etc.
 	$(CONTROLLER_GEN) $(CRD_OPTIONS) rbac:roleName=manager-role webhook paths="./..." output:crd:artifacts:config=config/crd/bases
 
-# Run go fmt against code
-fmt:
+fmt: ## Run go fmt against code
 	go fmt ./...
 
-# Run go vet against code
-vet:
+vet: ## Run go vet against code
 	go vet ./...
+{{- if .CodeGeneratorVersion }}
+
+generate: controller-gen conversion-gen defaulter-gen ## Generate code
+	$(CONTROLLER_GEN) object:headerFile=./hack/boilerplate.go.txt paths="./..."
+	$(CONVERSION_GEN) --go-header-file=./hack/boilerplate.go.txt --input-dirs=$(CODE_GEN_DIRS) \
+		--output-base=. --output-file-base=zz_generated.conversion
+	$(DEFAULTER_GEN) --go-header-file=./hack/boilerplate.go.txt --input-dirs=$(CODE_GEN_DIRS) \
+		--output-base=. --output-file-base=zz_generated.defaults
+{{- else }}
 
-# Generate code
-generate: controller-gen
+generate: controller-gen ## Generate code
 	$(CONTROLLER_GEN) object:headerFile=./hack/boilerplate.go.txt paths="./..."
+{{- end }}
 
-# Build the docker image
-docker-build: test
+{{- if .CRDRefDocsVersion }}
+
+# API packages documented by the docsgen target, create api adds the package
+# of each new API version
+API_DOCS_PACKAGES =
+` + docs.APIPackagesMarker + `
+
+# Generate the API reference docs of each package of API_DOCS_PACKAGES in
+# docs/api, between the header and the footer of docs/templates
+docsgen: crd-ref-docs ## Generate the API reference docs
+	mkdir -p docs/api
+	@set -e ; for pkg in $(API_DOCS_PACKAGES) ; do \
+		tmp=$$(mktemp -d) ;\
+		$(CRD_REF_DOCS) --config=docs/config.yaml --renderer=markdown --source-path=$$pkg --output-path=$$tmp ;\
+		cat docs/templates/header.md $$tmp/out.md docs/templates/footer.md > docs/api/$$(basename $$pkg).md ;\
+		rm -rf $$tmp ;\
+	done
+{{- end }}
+
+docker-build: test ## Build the docker image
 	docker build . -t ${IMG}
 
-# Push the docker image
-docker-push:
+docker-push: ## Push the docker image
 	docker push ${IMG}
+{{- if .MultiArch }}
+
+# Platforms to build the multi-arch image for
+PLATFORMS ?= linux/amd64,linux/arm64,linux/ppc64le,linux/s390x
+
+docker-buildx: test ## Build the image for all the PLATFORMS and push it as a manifest list
+	docker buildx inspect manager-builder > /dev/null 2>&1 || docker buildx create --name manager-builder
+	docker buildx build --builder manager-builder --platform=$(PLATFORMS) --push -t ${IMG} .
+{{- end }}
+{{- if .OLM }}
+
+# Bundle image URL to use in the bundle targets
+BUNDLE_IMG ?= controller-bundle:0.0.1
+
+bundle: manifests{{ $kustomizeDep }} ## Add the CRDs to the OLM bundle manifests
+	{{ $kustomize }} build config/crd > bundle/manifests/crds.yaml
+
+bundle-build: bundle ## Build the OLM bundle image
+	docker build -f bundle.Dockerfile -t ${BUNDLE_IMG} .
+{{- end }}
+
+{{- if .PinnedTools }}
+
+tools: controller-gen kustomize{{ if .CodeGeneratorVersion }} conversion-gen defaulter-gen{{ end }}
+{{- if .CRDRefDocsVersion }} crd-ref-docs{{ end }} ## Install all the tools
+
+# Record the checksums of the modules of the tools
+$(TOOLS_DIR)/go.sum: $(TOOLS_DIR)/go.mod
+	cd $(TOOLS_DIR) && go mod tidy
+	touch $@
+
+CONTROLLER_GEN = $(TOOLS_BIN)/controller-gen
+controller-gen: $(CONTROLLER_GEN) ## Install controller-gen
+$(CONTROLLER_GEN): $(TOOLS_DIR)/go.sum
+	cd $(TOOLS_DIR) && GOBIN=$(TOOLS_BIN) go install sigs.k8s.io/controller-tools/cmd/controller-gen
+
+KUSTOMIZE = $(TOOLS_BIN)/kustomize
+kustomize: $(KUSTOMIZE) ## Install kustomize
+$(KUSTOMIZE): $(TOOLS_DIR)/go.sum
+	cd $(TOOLS_DIR) && GOBIN=$(TOOLS_BIN) go install sigs.k8s.io/kustomize/kustomize/v3
+{{- if .CodeGeneratorVersion }}
 
-# find or download controller-gen
-# download controller-gen if necessary
-controller-gen:
+# API packages which conversion-gen and defaulter-gen generate code for, only
+# the packages with +k8s:conversion-gen or +k8s:defaulter-gen markers get any
+CODE_GEN_DIRS ?= $(shell find ./api -mindepth 1 -type d | paste -sd, -)
+
+CONVERSION_GEN = $(TOOLS_BIN)/conversion-gen
+conversion-gen: $(CONVERSION_GEN) ## Install conversion-gen
+$(CONVERSION_GEN): $(TOOLS_DIR)/go.sum
+	cd $(TOOLS_DIR) && GOBIN=$(TOOLS_BIN) go install k8s.io/code-generator/cmd/conversion-gen
+
+DEFAULTER_GEN = $(TOOLS_BIN)/defaulter-gen
+defaulter-gen: $(DEFAULTER_GEN) ## Install defaulter-gen
+$(DEFAULTER_GEN): $(TOOLS_DIR)/go.sum
+	cd $(TOOLS_DIR) && GOBIN=$(TOOLS_BIN) go install k8s.io/code-generator/cmd/defaulter-gen
+{{- end }}
+{{- if .CRDRefDocsVersion }}
+
+CRD_REF_DOCS = $(TOOLS_BIN)/crd-ref-docs
+crd-ref-docs: $(CRD_REF_DOCS) ## Install crd-ref-docs
+$(CRD_REF_DOCS): $(TOOLS_DIR)/go.sum
+	cd $(TOOLS_DIR) && GOBIN=$(TOOLS_BIN) go install github.com/elastic/crd-ref-docs
+{{- end }}
+{{- else }}
+
+controller-gen: ## Download controller-gen if necessary
 ifeq (, $(shell which controller-gen))
 	@{ \
 	set -e ;\
@@ -124,4 +331,58 @@ CONTROLLER_GEN=$(GOBIN)/controller-gen
 else
 CONTROLLER_GEN=$(shell which controller-gen)
 endif
+{{- if .CodeGeneratorVersion }}
+
+# API packages which conversion-gen and defaulter-gen generate code for, only
+# the packages with +k8s:conversion-gen or +k8s:defaulter-gen markers get any
+CODE_GEN_DIRS ?= $(shell find ./api -mindepth 1 -type d | paste -sd, -)
+
+conversion-gen: ## Download conversion-gen if necessary
+ifeq (, $(shell which conversion-gen))
+	@{ \
+	set -e ;\
+	CONVERSION_GEN_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CONVERSION_GEN_TMP_DIR ;\
+	go mod init tmp ;\
+	go get k8s.io/code-generator/cmd/conversion-gen@{{ .CodeGeneratorVersion }} ;\
+	rm -rf $$CONVERSION_GEN_TMP_DIR ;\
+	}
+CONVERSION_GEN=$(GOBIN)/conversion-gen
+else
+CONVERSION_GEN=$(shell which conversion-gen)
+endif
+
+defaulter-gen: ## Download defaulter-gen if necessary
+ifeq (, $(shell which defaulter-gen))
+	@{ \
+	set -e ;\
+	DEFAULTER_GEN_TMP_DIR=$$(mktemp -d) ;\
+	cd $$DEFAULTER_GEN_TMP_DIR ;\
+	go mod init tmp ;\
+	go get k8s.io/code-generator/cmd/defaulter-gen@{{ .CodeGeneratorVersion }} ;\
+	rm -rf $$DEFAULTER_GEN_TMP_DIR ;\
+	}
+DEFAULTER_GEN=$(GOBIN)/defaulter-gen
+else
+DEFAULTER_GEN=$(shell which defaulter-gen)
+endif
+{{- end }}
+{{- if .CRDRefDocsVersion }}
+
+crd-ref-docs: ## Download crd-ref-docs if necessary
+ifeq (, $(shell which crd-ref-docs))
+	@{ \
+	set -e ;\
+	CRD_REF_DOCS_TMP_DIR=$$(mktemp -d) ;\
+	cd $$CRD_REF_DOCS_TMP_DIR ;\
+	go mod init tmp ;\
+	go get github.com/elastic/crd-ref-docs@{{ .CRDRefDocsVersion }} ;\
+	rm -rf $$CRD_REF_DOCS_TMP_DIR ;\
+	}
+CRD_REF_DOCS=$(GOBIN)/crd-ref-docs
+else
+CRD_REF_DOCS=$(shell which crd-ref-docs)
+endif
+{{- end }}
+{{- end }}
 `
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	// DefaultMetricsBindAddress is the address the metrics endpoint binds to
+	DefaultMetricsBindAddress = ":8080"
+
+	// PprofPort is the port of the pprof endpoints of the deployed manager,
+	// when the project is scaffolded with profiling
+	PprofPort = 6060
+
+	// webhookPort is the port of the webhook server
+	webhookPort = 9443
+	// authProxyPort is the port of the kube-rbac-proxy in front of the metrics
+	authProxyPort = 8443
+)
+
+// BindAddressPort returns the port of a host:port bind address.
+func BindAddressPort(address string) (int, error) {
+	_, p, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bind address %q, should be host:port, e.g. :8080", address)
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port in bind address %q", address)
+	}
+	if err := ValidatePort(port); err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+// ValidateWebhookPort checks that the port can be used by the webhook server
+// of the manager, which is proxied by kube-rbac-proxy on 8443.
+func ValidateWebhookPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d is out of range", port)
+	}
+	if port == authProxyPort {
+		return fmt.Errorf("port %d is already used by the metrics auth proxy", port)
+	}
+	return nil
+}
+
+// ValidatePort checks that the port can be used by the manager, which also
+// serves the webhooks on 9443 and is proxied by kube-rbac-proxy on 8443.
+func ValidatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d is out of range", port)
+	}
+	switch port {
+	case webhookPort:
+		return fmt.Errorf("port %d is already used by the webhook server", port)
+	case authProxyPort:
+		return fmt.Errorf("port %d is already used by the metrics auth proxy", port)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+)
+
+// BaseImage is the base image of the manager image
+type BaseImage string
+
+const (
+	// BaseImageDistroless runs the manager on distroless as its nonroot user
+	BaseImageDistroless BaseImage = "distroless"
+
+	// BaseImageScratch runs the manager on an empty image, with the CA
+	// certificates copied from the builder image
+	BaseImageScratch BaseImage = "scratch"
+
+	// BaseImageUBI8 runs the manager on the Red Hat Universal Base Image 8
+	// minimal image
+	BaseImageUBI8 BaseImage = "ubi8"
+)
+
+// Validate validates the BaseImage
+func (b BaseImage) Validate() error {
+	switch b {
+	case BaseImageDistroless, BaseImageScratch, BaseImageUBI8:
+		return nil
+	}
+	return fmt.Errorf("unknown base image %q, should be one of %s, %s, %s",
+		b, BaseImageDistroless, BaseImageScratch, BaseImageUBI8)
+}
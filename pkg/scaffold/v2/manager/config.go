@@ -29,6 +29,16 @@ type Config struct {
 	input.Input
 	// Image is controller manager image name
 	Image string
+	// BaseImage is the base image of the manager image, the security
+	// context of the manager depends on its user
+	BaseImage BaseImage
+	// LeaderElection enables leader election for the manager
+	LeaderElection bool
+	// HealthProbePort is the port of the liveness and readiness probes of
+	// the manager, there are no probes if it is 0
+	HealthProbePort int
+	// SecureDefaults hardens the security context of the manager
+	SecureDefaults bool
 }
 
 // GetInput implements input.File
@@ -64,13 +74,59 @@ spec:
       labels:
         control-plane: controller-manager
     spec:
+{{- if .SecureDefaults }}
+      securityContext:
+        runAsNonRoot: true
+{{- if eq .BaseImage "ubi8" }}
+        # the user ID is not set so that it can be assigned by OpenShift
+{{- else }}
+        runAsUser: 65532
+{{- end }}
+        seccompProfile:
+          type: RuntimeDefault
+{{- else if eq .BaseImage "scratch" }}
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: 65532
+{{- else if eq .BaseImage "ubi8" }}
+      # the user ID is not set so that it can be assigned by OpenShift
+      securityContext:
+        runAsNonRoot: true
+{{- end }}
       containers:
       - command:
         - /manager
+{{- if .LeaderElection }}
         args:
         - --enable-leader-election
+{{- end }}
         image: {{ .Image }}
         name: manager
+{{- if .SecureDefaults }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+{{- end }}
+{{- if .HealthProbePort }}
+        ports:
+        - containerPort: {{ .HealthProbePort }}
+          name: health
+        livenessProbe:
+          httpGet:
+            path: /healthz
+            port: health
+          initialDelaySeconds: 15
+          periodSeconds: 20
+        readinessProbe:
+          httpGet:
+            path: /readyz
+            port: health
+          initialDelaySeconds: 5
+          periodSeconds: 10
+{{- end }}
         resources:
           limits:
             cpu: 100m
@@ -28,6 +28,13 @@ var _ input.File = &KustomizeAuthProxyPatch{}
 // prometheus metrics for manager Pod.
 type KustomizeAuthProxyPatch struct {
 	input.Input
+
+	// MetricsPort is the port the metrics endpoint of the manager binds to,
+	// defaults to 8080
+	MetricsPort int
+
+	// LeaderElection enables leader election for the manager
+	LeaderElection bool
 }
 
 // GetInput implements input.File
@@ -35,6 +42,9 @@ func (c *KustomizeAuthProxyPatch) GetInput() (input.Input, error) {
 	if c.Path == "" {
 		c.Path = filepath.Join("config", "default", "manager_auth_proxy_patch.yaml")
 	}
+	if c.MetricsPort == 0 {
+		c.MetricsPort = 8080
+	}
 	c.TemplateBody = kustomizeAuthProxyPatchTemplate
 	c.Input.IfExistsAction = input.Error
 	return c.Input, nil
@@ -55,7 +65,7 @@ spec:
         image: gcr.io/kubebuilder/kube-rbac-proxy:v0.4.1
         args:
         - "--secure-listen-address=0.0.0.0:8443"
-        - "--upstream=http://127.0.0.1:8080/"
+        - "--upstream=http://127.0.0.1:{{ .MetricsPort }}/"
         - "--logtostderr=true"
         - "--v=10"
         ports:
@@ -63,6 +73,8 @@ spec:
           name: https
       - name: manager
         args:
-        - "--metrics-addr=127.0.0.1:8080"
+        - "--metrics-addr=127.0.0.1:{{ .MetricsPort }}"
+{{- if .LeaderElection }}
         - "--enable-leader-election"
+{{- end }}
 `
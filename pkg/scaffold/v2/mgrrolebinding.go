@@ -27,6 +27,9 @@ var _ input.File = &ManagerRoleBinding{}
 // ManagerRoleBinding scaffolds the config/rbac/role_binding.yaml file
 type ManagerRoleBinding struct {
 	input.Input
+
+	// Namespaced binds the manager role in the namespace of the manager only
+	Namespaced bool
 }
 
 // GetInput implements input.File
@@ -38,10 +41,19 @@ func (r *ManagerRoleBinding) GetInput() (input.Input, error) {
 	return r.Input, nil
 }
 
+// a RoleBinding to the generated manager-role ClusterRole only grants its
+// rules in the namespace of the RoleBinding.
 const managerBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+{{- if .Namespaced }}
+kind: RoleBinding
+metadata:
+  name: manager-rolebinding
+  namespace: system
+{{- else }}
 kind: ClusterRoleBinding
 metadata:
   name: manager-rolebinding
+{{- end }}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Dir is the directory of the network policies of the manager
+var Dir = filepath.Join("config", "network-policy")
+
+var _ input.File = &ManagerPolicy{}
+
+// ManagerPolicy scaffolds the NetworkPolicy restricting the traffic from and
+// to the manager
+type ManagerPolicy struct {
+	input.Input
+
+	// MetricsPort is the port of the metrics endpoint of the manager, which
+	// the auth proxy forwards to
+	MetricsPort int
+
+	// HealthProbePort is the port of the health probes of the manager, there
+	// are no probes if it is 0
+	HealthProbePort int
+
+	// WebhookPort is the port of the webhook server of the manager, defaults
+	// to 9443
+	WebhookPort int
+
+	// PprofPort is the port of the pprof endpoints of the manager, there are
+	// no pprof endpoints if it is 0
+	PprofPort int
+}
+
+// GetInput implements input.File
+func (p *ManagerPolicy) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join(Dir, "allow-manager-traffic.yaml")
+	}
+	if p.MetricsPort == 0 {
+		p.MetricsPort = 8080
+	}
+	if p.WebhookPort == 0 {
+		p.WebhookPort = webhook.DefaultServerPort
+	}
+	p.TemplateBody = managerPolicyTemplate
+	p.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const managerPolicyTemplate = `# This NetworkPolicy only allows the traffic the manager needs: the webhook
+# requests, the metrics scraped by Prometheus from the namespaces labeled
+# metrics: enabled, DNS and the requests to the Kubernetes API server.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: allow-manager-traffic
+  namespace: system
+  labels:
+    control-plane: controller-manager
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Ingress
+  - Egress
+  ingress:
+  # webhook server
+  - ports:
+    - port: {{ .WebhookPort }}
+      protocol: TCP
+{{- if .HealthProbePort }}
+  # health probes
+  - ports:
+    - port: {{ .HealthProbePort }}
+      protocol: TCP
+{{- end }}
+{{- if .PprofPort }}
+  # pprof endpoints, reached through the API server proxy
+  - ports:
+    - port: {{ .PprofPort }}
+      protocol: TCP
+{{- end }}
+  # metrics, served by the auth proxy on 8443 or by the manager directly
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          metrics: enabled
+    ports:
+    - port: 8443
+      protocol: TCP
+    - port: {{ .MetricsPort }}
+      protocol: TCP
+  egress:
+  # DNS
+  - ports:
+    - port: 53
+      protocol: UDP
+    - port: 53
+      protocol: TCP
+  # Kubernetes API server
+  - ports:
+    - port: 443
+      protocol: TCP
+    - port: 6443
+      protocol: TCP
+`
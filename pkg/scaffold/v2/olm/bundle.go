@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Annotations{}
+
+// Annotations scaffolds the metadata/annotations.yaml file of the OLM bundle
+type Annotations struct {
+	input.Input
+
+	// ProjectName is the name of the operator package, defaults to the
+	// directory name
+	ProjectName string
+}
+
+// GetInput implements input.File
+func (a *Annotations) GetInput() (input.Input, error) {
+	if a.Path == "" {
+		a.Path = filepath.Join(Dir, "metadata", "annotations.yaml")
+	}
+	if a.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return input.Input{}, err
+		}
+		a.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	a.TemplateBody = annotationsTemplate
+	a.Input.IfExistsAction = input.Error
+	return a.Input, nil
+}
+
+const annotationsTemplate = `annotations:
+  operators.operatorframework.io.bundle.mediatype.v1: registry+v1
+  operators.operatorframework.io.bundle.manifests.v1: manifests/
+  operators.operatorframework.io.bundle.metadata.v1: metadata/
+  operators.operatorframework.io.bundle.package.v1: {{ .ProjectName }}
+  operators.operatorframework.io.bundle.channels.v1: alpha
+  operators.operatorframework.io.bundle.channel.default.v1: alpha
+`
+
+var _ input.File = &Dockerfile{}
+
+// Dockerfile scaffolds the bundle.Dockerfile which builds the bundle image
+type Dockerfile struct {
+	input.Input
+
+	// ProjectName is the name of the operator package, defaults to the
+	// directory name
+	ProjectName string
+}
+
+// GetInput implements input.File
+func (d *Dockerfile) GetInput() (input.Input, error) {
+	if d.Path == "" {
+		d.Path = "bundle.Dockerfile"
+	}
+	if d.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return input.Input{}, err
+		}
+		d.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	d.TemplateBody = dockerfileTemplate
+	d.Input.IfExistsAction = input.Error
+	return d.Input, nil
+}
+
+const dockerfileTemplate = `FROM scratch
+
+LABEL operators.operatorframework.io.bundle.mediatype.v1=registry+v1
+LABEL operators.operatorframework.io.bundle.manifests.v1=manifests/
+LABEL operators.operatorframework.io.bundle.metadata.v1=metadata/
+LABEL operators.operatorframework.io.bundle.package.v1={{ .ProjectName }}
+LABEL operators.operatorframework.io.bundle.channels.v1=alpha
+LABEL operators.operatorframework.io.bundle.channel.default.v1=alpha
+
+COPY bundle/manifests /manifests/
+COPY bundle/metadata /metadata/
+`
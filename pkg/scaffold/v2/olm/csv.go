@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package olm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+// Dir is the directory holding the OLM bundle of a project
+const Dir = "bundle"
+
+var ownedCRDsScaffoldMarker = marker.For("csv.yaml", "csvownedcrds").String()
+
+var _ input.File = &CSV{}
+
+// CSV scaffolds the ClusterServiceVersion of the OLM bundle
+type CSV struct {
+	input.Input
+
+	// ProjectName is the name of the operator package, defaults to the
+	// directory name
+	ProjectName string
+
+	// Resource is the Resource to add to the owned CRDs on Update
+	Resource *resource.Resource
+}
+
+func (c *CSV) setDefaults() error {
+	if c.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		c.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	if c.Path == "" {
+		c.Path = filepath.Join(Dir, "manifests", fmt.Sprintf("%s.clusterserviceversion.yaml", c.ProjectName))
+	}
+	return nil
+}
+
+// GetInput implements input.File
+func (c *CSV) GetInput() (input.Input, error) {
+	if err := c.setDefaults(); err != nil {
+		return input.Input{}, err
+	}
+	c.TemplateBody = csvTemplate
+	c.Input.IfExistsAction = input.Error
+	return c.Input, nil
+}
+
+// Update adds the CRD of the Resource to the owned CRDs of the ClusterServiceVersion
+func (c *CSV) Update() error {
+	if err := c.setDefaults(); err != nil {
+		return err
+	}
+
+	plural := c.Resource.Plural()
+	name := fmt.Sprintf("%s.%s", plural, resource.QualifiedGroup(c.Resource.Group, c.Domain))
+
+	// multi-line values are not deduplicated by InsertStringsInFile
+	b, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(b), fmt.Sprintf("- name: %s\n      kind: %s\n      version: %s\n",
+		name, c.Resource.Kind, c.Resource.Version)) {
+		return nil
+	}
+
+	ownedCRDCodeFragment := fmt.Sprintf(`    - name: %s
+      kind: %s
+      version: %s
+      displayName: %s
+      description: %s is the Schema for the %s API
+`, name, c.Resource.Kind, c.Resource.Version, c.Resource.Kind, c.Resource.Kind, plural)
+
+	return internal.InsertStringsInFile(c.Path,
+		map[string][]string{
+			ownedCRDsScaffoldMarker: {ownedCRDCodeFragment},
+		})
+}
+
+var csvTemplate = fmt.Sprintf(`apiVersion: operators.coreos.com/v1alpha1
+kind: ClusterServiceVersion
+metadata:
+  name: {{ .ProjectName }}.v0.0.1
+  namespace: placeholder
+  annotations:
+    alm-examples: '[]'
+    capabilities: Basic Install
+spec:
+  displayName: {{ .ProjectName }}
+  description: "TODO(user): describe the operator."
+  version: 0.0.1
+  maturity: alpha
+  provider:
+    name: "TODO(user)"
+  installModes:
+  - type: OwnNamespace
+    supported: true
+  - type: SingleNamespace
+    supported: true
+  - type: MultiNamespace
+    supported: false
+  - type: AllNamespaces
+    supported: true
+  install:
+    strategy: deployment
+    spec:
+      # TODO(user): copy the manager Deployment and its RBAC rules from the
+      # output of "kustomize build config/default".
+      clusterPermissions: []
+      deployments: []
+  customresourcedefinitions:
+    owned:
+    %s
+`, ownedCRDsScaffoldMarker)
@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Dir is the directory of the ValidatingAdmissionPolicy manifests
+var Dir = filepath.Join("config", "policy")
+
+var policiesScaffoldMarker = marker.For("kustomization.yaml", "policies").String()
+
+// Validation is a CEL validation rule of a ValidatingAdmissionPolicy
+type Validation struct {
+	// Expression is the CEL expression, which is true for a valid object
+	Expression string
+
+	// Message is the message of the denied requests
+	Message string
+
+	// Commented indicates whether the rule is a commented out example
+	Commented bool
+}
+
+var _ input.File = &Policy{}
+
+// Policy scaffolds the ValidatingAdmissionPolicy of a Resource and its
+// binding, an alternative to a validating webhook which needs no webhook
+// server
+type Policy struct {
+	input.Input
+
+	// Resource is the Resource validated by the policy
+	Resource *resource.Resource
+
+	// SpecFields are the fields of the <kind>Spec struct of the Resource, the
+	// CEL rules are stubs checking them
+	SpecFields []webhook.SpecField
+
+	// FailurePolicy is how the API server handles the errors evaluating the
+	// rules, fail or ignore, defaults to fail
+	FailurePolicy string
+
+	// APIGroup is the API group of the Resource, empty for the core group
+	APIGroup string
+
+	// Validations are the CEL rules of the policy
+	Validations []Validation
+}
+
+// GetInput implements input.File
+func (p *Policy) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join(Dir, p.FileName())
+	}
+	if p.FailurePolicy == "" {
+		p.FailurePolicy = "fail"
+	}
+	_, groupDomain := util.GetResourceInfo(p.Resource, p.Repo, p.Domain)
+	if p.Resource.Group != "core" {
+		p.APIGroup = groupDomain
+	}
+	p.Validations = Validations(p.Resource.Kind, p.SpecFields)
+	p.TemplateBody = policyTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+// FileName returns the name of the policy file in Dir
+func (p *Policy) FileName() string {
+	return fmt.Sprintf("%s_policy.yaml", strings.ToLower(p.Resource.Kind))
+}
+
+var celIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validations returns the stubs of the CEL rules of the spec fields: the
+// strings and numbers cannot be set to their zero value, and the other fields
+// get a commented out rule requiring them.
+func Validations(kind string, fields []webhook.SpecField) []Validation {
+	var validations []Validation
+	for _, f := range fields {
+		if !celIdentifier.MatchString(f.JSONName) {
+			continue
+		}
+		field := "object.spec." + f.JSONName
+		switch f.ZeroCheck {
+		case `== ""`:
+			validations = append(validations, Validation{
+				Expression: fmt.Sprintf("!has(%s) || %s != ''", field, field),
+				Message:    fmt.Sprintf("spec.%s cannot be empty", f.JSONName),
+			})
+		case "== 0":
+			validations = append(validations, Validation{
+				Expression: fmt.Sprintf("!has(%s) || %s != 0", field, field),
+				Message:    fmt.Sprintf("spec.%s cannot be 0", f.JSONName),
+			})
+		default:
+			validations = append(validations, Validation{
+				Expression: fmt.Sprintf("has(%s)", field),
+				Message:    fmt.Sprintf("spec.%s is required", f.JSONName),
+				Commented:  true,
+			})
+		}
+	}
+	// a policy needs at least one rule
+	for _, v := range validations {
+		if !v.Commented {
+			return validations
+		}
+	}
+	return append([]Validation{{
+		Expression: "true",
+		Message:    fmt.Sprintf("TODO(user): replace with the rules of the %s objects", kind),
+	}}, validations...)
+}
+
+const policyTemplate = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: {{ lower .Resource.Kind }}-policy
+spec:
+  failurePolicy: {{ title .FailurePolicy }}
+  matchConstraints:
+    resourceRules:
+    - apiGroups: ["{{ .APIGroup }}"]
+      apiVersions: ["{{ .Resource.Version }}"]
+      operations: ["CREATE", "UPDATE"]
+      resources: ["{{ .Resource.Resource }}"]
+  # TODO(user): write the rules of the {{ .Resource.Kind }} objects, see
+  # https://kubernetes.io/docs/reference/access-authn-authz/validating-admission-policy/
+  validations:
+{{- range .Validations }}
+{{- if .Commented }}
+  # - expression: "{{ .Expression }}"
+  #   message: "{{ .Message }}"
+{{- else }}
+  - expression: "{{ .Expression }}"
+    message: "{{ .Message }}"
+{{- end }}
+{{- end }}
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: {{ lower .Resource.Kind }}-policy
+spec:
+  policyName: {{ lower .Resource.Kind }}-policy
+  validationActions: [Deny]
+`
+
+var _ input.File = &Kustomization{}
+
+// Kustomization scaffolds the kustomization of the policies
+type Kustomization struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (k *Kustomization) GetInput() (input.Input, error) {
+	if k.Path == "" {
+		k.Path = filepath.Join(Dir, "kustomization.yaml")
+	}
+	k.TemplateBody = fmt.Sprintf(kustomizationTemplate, policiesScaffoldMarker)
+	k.Input.IfExistsAction = input.Error
+	return k.Input, nil
+}
+
+// AddPolicy adds the policy file to the resources of the kustomization
+func (k *Kustomization) AddPolicy(p *Policy) error {
+	if k.Path == "" {
+		k.Path = filepath.Join(Dir, "kustomization.yaml")
+	}
+	return internal.InsertStringsInFile(k.Path,
+		map[string][]string{
+			policiesScaffoldMarker: {fmt.Sprintf("- %s\n", p.FileName())},
+		})
+}
+
+const kustomizationTemplate = `# This kustomization.yaml is not intended to be run by itself,
+# since it depends on the namePrefix of config/default. Add ../policy to the
+# bases of config/default/kustomization.yaml to deploy the policies.
+resources:
+%s
+
+configurations:
+- kustomizeconfig.yaml
+`
+
+var _ input.File = &KustomizeConfig{}
+
+// KustomizeConfig scaffolds the kustomize configuration of the policies
+type KustomizeConfig struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (c *KustomizeConfig) GetInput() (input.Input, error) {
+	if c.Path == "" {
+		c.Path = filepath.Join(Dir, "kustomizeconfig.yaml")
+	}
+	c.TemplateBody = kustomizeConfigTemplate
+	c.Input.IfExistsAction = input.Error
+	return c.Input, nil
+}
+
+const kustomizeConfigTemplate = `# the following config is for teaching kustomize to prefix the policy names
+# the bindings refer to, like the policies
+nameReference:
+- kind: ValidatingAdmissionPolicy
+  group: admissionregistration.k8s.io
+  fieldSpecs:
+  - kind: ValidatingAdmissionPolicyBinding
+    group: admissionregistration.k8s.io
+    path: spec/policyName
+`
@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+)
+
+const (
+	// OpenTelemetryVersion is the version of the OpenTelemetry modules
+	// required by profiling.go
+	OpenTelemetryVersion = "v1.0.0"
+
+	// OpenTelemetryMinGoVersion is the oldest Go release supported by
+	// OpenTelemetryVersion
+	OpenTelemetryMinGoVersion = "1.15"
+)
+
+var _ input.File = &Profiling{}
+
+// Profiling scaffolds the profiling.go file serving the pprof endpoints and
+// setting up the OpenTelemetry trace exporter of the manager
+type Profiling struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (p *Profiling) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = "profiling.go"
+	}
+	p.TemplateBody = profilingTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const profilingTemplate = `{{ .Boilerplate }}
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// pprofServer returns a runnable serving the pprof endpoints at addr until the
+// manager stops.
+func pprofServer(addr string) manager.Runnable {
+	return manager.RunnableFunc(func(stop <-chan struct{}) error {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		server := &http.Server{Addr: addr, Handler: mux}
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- server.ListenAndServe()
+		}()
+		select {
+		case err := <-errs:
+			return err
+		case <-stop:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return server.Shutdown(ctx)
+		}
+	})
+}
+
+// setupTracing installs an OpenTelemetry tracer provider exporting the spans
+// over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set. The exporter is configured
+// with the standard OTEL_* environment variables, see
+// https://opentelemetry.io/docs/specs/otel/protocol/exporter/
+// The returned function flushes the pending spans.
+func setupTracing() (func(), error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func() {}, nil
+	}
+	exporter, err := otlptracegrpc.New(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	otel.SetTracerProvider(provider)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			setupLog.Error(err, "unable to flush the traces")
+		}
+	}, nil
+}
+`
+
+var _ input.File = &ManagerProfilingPatch{}
+
+// ManagerProfilingPatch scaffolds the patch enabling the pprof endpoints and
+// naming the traces of the manager
+type ManagerProfilingPatch struct {
+	input.Input
+
+	// Port is the port of the pprof endpoints
+	Port int
+}
+
+// GetInput implements input.File
+func (p *ManagerProfilingPatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "default", "manager_profiling_patch.yaml")
+	}
+	if p.Port == 0 {
+		p.Port = manager.PprofPort
+	}
+	p.TemplateBody = managerProfilingPatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const managerProfilingPatchTemplate = `# This patch serves the pprof endpoints of the manager, exposed by
+# config/rbac/pprof_service.yaml, and names its traces. The traces are only
+# exported if OTEL_EXPORTER_OTLP_ENDPOINT is set, uncomment it to send them to
+# an OpenTelemetry collector.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        ports:
+        - containerPort: {{ .Port }}
+          name: pprof
+          protocol: TCP
+        env:
+        - name: PPROF_ADDR
+          value: ":{{ .Port }}"
+        - name: OTEL_SERVICE_NAME
+          value: controller-manager
+        #- name: OTEL_EXPORTER_OTLP_ENDPOINT
+        #  value: http://otel-collector.observability:4317
+`
+
+var _ input.File = &PprofService{}
+
+// PprofService scaffolds the config/rbac/pprof_service.yaml file
+type PprofService struct {
+	input.Input
+
+	// Port is the port of the pprof endpoints
+	Port int
+}
+
+// GetInput implements input.File
+func (s *PprofService) GetInput() (input.Input, error) {
+	if s.Path == "" {
+		s.Path = filepath.Join("config", "rbac", "pprof_service.yaml")
+	}
+	if s.Port == 0 {
+		s.Port = manager.PprofPort
+	}
+	s.TemplateBody = pprofServiceTemplate
+	s.Input.IfExistsAction = input.Error
+	return s.Input, nil
+}
+
+const pprofServiceTemplate = `# The pprof endpoints of the manager can be reached through the API server
+# proxy by the users bound to the pprof-reader ClusterRole, e.g.
+#   kubectl get --raw /api/v1/namespaces/<prefix>-system/services/<prefix>-controller-manager-pprof-service:pprof/proxy/debug/pprof/heap > heap.out
+#   go tool pprof heap.out
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: controller-manager-pprof-service
+  namespace: system
+spec:
+  ports:
+  - name: pprof
+    port: {{ .Port }}
+    targetPort: pprof
+  selector:
+    control-plane: controller-manager
+`
+
+var _ input.File = &PprofReaderRole{}
+
+// PprofReaderRole scaffolds the config/rbac/pprof_reader_role.yaml file
+type PprofReaderRole struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (r *PprofReaderRole) GetInput() (input.Input, error) {
+	if r.Path == "" {
+		r.Path = filepath.Join("config", "rbac", "pprof_reader_role.yaml")
+	}
+	r.TemplateBody = pprofReaderRoleTemplate
+	r.Input.IfExistsAction = input.Error
+	return r.Input, nil
+}
+
+const pprofReaderRoleTemplate = `# Bind this ClusterRole to the users profiling the manager, either through the
+# API server proxy of the pprof service or with kubectl port-forward.
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: pprof-reader
+rules:
+- apiGroups: [""]
+  resources:
+  - services/proxy
+  verbs: ["get"]
+- apiGroups: [""]
+  resources:
+  - pods/portforward
+  verbs: ["create"]
+`
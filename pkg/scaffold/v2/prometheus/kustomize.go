@@ -24,6 +24,9 @@ import (
 // Kustomization scaffolds the kustomizaiton in the prometheus folder
 type Kustomization struct {
 	input.Input
+
+	// Rules indicates whether the alerting rules should be deployed
+	Rules bool
 }
 
 // GetInput implements input.File
@@ -37,4 +40,7 @@ func (p *Kustomization) GetInput() (input.Input, error) {
 
 const kustomizationTemplate = `resources:
 - monitor.yaml
+{{- if .Rules }}
+- rules.yaml
+{{- end }}
 `
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prometheus
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &PrometheusRule{}
+
+// PrometheusRule scaffolds the alerting rules for the controller-runtime metrics
+type PrometheusRule struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (p *PrometheusRule) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "prometheus", "rules.yaml")
+	}
+	p.TemplateBody = rulesTemplate
+	p.TemplateEngine = input.RawEngine{}
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const rulesTemplate = `
+# Prometheus alerting rules for the controller-runtime metrics
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: controller-manager-rules
+  namespace: system
+spec:
+  groups:
+  - name: controller-runtime.rules
+    rules:
+    - alert: ReconcileErrorsHigh
+      expr: sum(rate(controller_runtime_reconcile_errors_total[5m])) by (controller) > 0.1
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: "Controller {{ $labels.controller }} is failing to reconcile"
+        description: "More than 10% of the reconciles of {{ $labels.controller }} returned errors in the last 15 minutes."
+    - alert: ReconcileLatencyHigh
+      expr: histogram_quantile(0.99, sum(rate(controller_runtime_reconcile_time_seconds_bucket[5m])) by (controller, le)) > 5
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: "Controller {{ $labels.controller }} reconciles slowly"
+        description: "The 99th percentile reconcile time of {{ $labels.controller }} is above 5 seconds."
+    - alert: WorkqueueDepthHigh
+      expr: sum(workqueue_depth) by (name) > 100
+      for: 15m
+      labels:
+        severity: warning
+      annotations:
+        summary: "Workqueue {{ $labels.name }} is backing up"
+        description: "The workqueue {{ $labels.name }} has held more than 100 items for 15 minutes."
+`
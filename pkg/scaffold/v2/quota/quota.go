@@ -0,0 +1,258 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Dir is the directory of the ResourceQuota and LimitRange of the manager
+// namespace
+var Dir = filepath.Join("config", "quota")
+
+// Settings are the resources of the manager namespace. The empty quantities
+// are left out of the manifests.
+type Settings struct {
+	// RequestsCPU and RequestsMemory are the maximum sums of the requests of
+	// the pods of the namespace
+	RequestsCPU    string
+	RequestsMemory string
+
+	// LimitsCPU and LimitsMemory are the maximum sums of the limits of the
+	// pods of the namespace
+	LimitsCPU    string
+	LimitsMemory string
+
+	// Pods is the maximum number of pods of the namespace, unlimited if 0
+	Pods int
+
+	// LimitRange indicates whether to scaffold the LimitRange setting the
+	// default requests and limits of the containers. Without it, the
+	// containers without requests and limits are rejected by the quota.
+	LimitRange bool
+
+	// DefaultCPU and DefaultMemory are the default limits of the containers
+	DefaultCPU    string
+	DefaultMemory string
+
+	// DefaultRequestCPU and DefaultRequestMemory are the default requests of
+	// the containers
+	DefaultRequestCPU    string
+	DefaultRequestMemory string
+}
+
+// quantity matches the Kubernetes quantities, e.g. 500m, 2, 1.5Gi or 1e3
+var quantity = regexp.MustCompile(`^[+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)(Ki|Mi|Gi|Ti|Pi|Ei|n|u|m|k|M|G|T|P|E|[eE][+-]?[0-9]+)?$`)
+
+// Validate checks the settings
+func (s *Settings) Validate() error {
+	for name, q := range map[string]string{
+		"requests cpu":           s.RequestsCPU,
+		"requests memory":        s.RequestsMemory,
+		"limits cpu":             s.LimitsCPU,
+		"limits memory":          s.LimitsMemory,
+		"default cpu":            s.DefaultCPU,
+		"default memory":         s.DefaultMemory,
+		"default request cpu":    s.DefaultRequestCPU,
+		"default request memory": s.DefaultRequestMemory,
+	} {
+		if q != "" && !quantity.MatchString(q) {
+			return fmt.Errorf("%s must be a quantity, e.g. 500m or 1Gi (was %s)", name, q)
+		}
+	}
+	if s.Pods < 0 {
+		return fmt.Errorf("pods cannot be negative (was %d)", s.Pods)
+	}
+	if s.RequestsCPU == "" && s.RequestsMemory == "" && s.LimitsCPU == "" && s.LimitsMemory == "" && s.Pods == 0 {
+		return fmt.Errorf("the quota needs at least one of the requests, limits and pods")
+	}
+	return nil
+}
+
+var _ input.File = &ResourceQuota{}
+
+// ResourceQuota scaffolds the ResourceQuota of the manager namespace
+type ResourceQuota struct {
+	input.Input
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	Settings Settings
+}
+
+// GetInput implements input.File
+func (q *ResourceQuota) GetInput() (input.Input, error) {
+	if q.Path == "" {
+		q.Path = filepath.Join(Dir, "resource_quota.yaml")
+	}
+	q.TemplateBody = resourceQuotaTemplate
+	if q.Force {
+		q.Input.IfExistsAction = input.Overwrite
+	} else {
+		q.Input.IfExistsAction = input.Error
+	}
+	return q.Input, nil
+}
+
+const resourceQuotaTemplate = `apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: manager-quota
+spec:
+  hard:
+{{- with .Settings }}
+{{- if .RequestsCPU }}
+    requests.cpu: "{{ .RequestsCPU }}"
+{{- end }}
+{{- if .RequestsMemory }}
+    requests.memory: "{{ .RequestsMemory }}"
+{{- end }}
+{{- if .LimitsCPU }}
+    limits.cpu: "{{ .LimitsCPU }}"
+{{- end }}
+{{- if .LimitsMemory }}
+    limits.memory: "{{ .LimitsMemory }}"
+{{- end }}
+{{- if .Pods }}
+    pods: "{{ .Pods }}"
+{{- end }}
+{{- end }}
+`
+
+var _ input.File = &LimitRange{}
+
+// LimitRange scaffolds the LimitRange setting the default requests and limits
+// of the containers of the manager namespace
+type LimitRange struct {
+	input.Input
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	Settings Settings
+}
+
+// GetInput implements input.File
+func (l *LimitRange) GetInput() (input.Input, error) {
+	if l.Path == "" {
+		l.Path = filepath.Join(Dir, "limit_range.yaml")
+	}
+	l.TemplateBody = limitRangeTemplate
+	if l.Force {
+		l.Input.IfExistsAction = input.Overwrite
+	} else {
+		l.Input.IfExistsAction = input.Error
+	}
+	return l.Input, nil
+}
+
+const limitRangeTemplate = `apiVersion: v1
+kind: LimitRange
+metadata:
+  name: manager-limits
+spec:
+  limits:
+  - type: Container
+{{- with .Settings }}
+{{- if or .DefaultCPU .DefaultMemory }}
+    default:
+{{- if .DefaultCPU }}
+      cpu: "{{ .DefaultCPU }}"
+{{- end }}
+{{- if .DefaultMemory }}
+      memory: "{{ .DefaultMemory }}"
+{{- end }}
+{{- end }}
+{{- if or .DefaultRequestCPU .DefaultRequestMemory }}
+    defaultRequest:
+{{- if .DefaultRequestCPU }}
+      cpu: "{{ .DefaultRequestCPU }}"
+{{- end }}
+{{- if .DefaultRequestMemory }}
+      memory: "{{ .DefaultRequestMemory }}"
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+var _ input.File = &Kustomization{}
+
+// Kustomization scaffolds the kustomization of the quota
+type Kustomization struct {
+	input.Input
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	// LimitRange indicates whether the LimitRange is scaffolded
+	LimitRange bool
+}
+
+// GetInput implements input.File
+func (k *Kustomization) GetInput() (input.Input, error) {
+	if k.Path == "" {
+		k.Path = filepath.Join(Dir, "kustomization.yaml")
+	}
+	k.TemplateBody = kustomizationTemplate
+	if k.Force {
+		k.Input.IfExistsAction = input.Overwrite
+	} else {
+		k.Input.IfExistsAction = input.Error
+	}
+	return k.Input, nil
+}
+
+const kustomizationTemplate = `# The quota and the default resources of the containers of the namespace of
+# the manager, the namespace is set by config/default.
+resources:
+- resource_quota.yaml
+{{- if .LimitRange }}
+- limit_range.yaml
+{{- end }}
+`
+
+// defaultBase is the base of the quota in config/default/kustomization.yaml
+const defaultBase = "- ../quota\n"
+
+// AddToDefault adds the quota to the bases of the kustomization of the
+// default overlay at path, after the manager. It returns false if the bases
+// of the kustomization have no manager to add it after.
+func AddToDefault(path string) (bool, error) {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return false, err
+	}
+	content := string(b)
+	if strings.Contains(content, defaultBase) {
+		return true, nil
+	}
+	const manager = "- ../manager\n"
+	i := strings.Index(content, manager)
+	if i < 0 {
+		return false, nil
+	}
+	i += len(manager)
+	content = content[:i] + defaultBase + content[i:]
+	return true, ioutil.WriteFile(path, []byte(content), 0644)
+}
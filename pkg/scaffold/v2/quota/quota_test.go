@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		valid    bool
+	}{
+		{Settings{Pods: 10}, true},
+		{Settings{RequestsCPU: "500m", RequestsMemory: "1.5Gi", LimitsCPU: "2", LimitsMemory: "1e9"}, true},
+		{Settings{}, false},
+		{Settings{Pods: -1}, false},
+		{Settings{Pods: 10, LimitsCPU: "2 cpus"}, false},
+		{Settings{Pods: 10, DefaultMemory: "128MB"}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.settings.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() of %+v returned %v", tt.settings, err)
+		}
+	}
+}
+
+func TestAddToDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kustomization.yaml")
+
+	if err := ioutil.WriteFile(path, []byte("bases:\n- ../crd\n- ../manager\n#- ../webhook\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if added, err := AddToDefault(path); err != nil || !added {
+			t.Fatalf("AddToDefault() returned %v, %v", added, err)
+		}
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "bases:\n- ../crd\n- ../manager\n- ../quota\n#- ../webhook\n"; string(b) != expected {
+		t.Errorf("the kustomization is\n%s\nexpected\n%s", b, expected)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("bases:\n- ../crd\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if added, err := AddToDefault(path); err != nil || added {
+		t.Errorf("AddToDefault() without the manager returned %v, %v", added, err)
+	}
+}
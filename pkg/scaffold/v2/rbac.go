@@ -27,6 +27,10 @@ var _ input.File = &KustomizeRBAC{}
 // KustomizeRBAC scaffolds the Kustomization file in rbac folder.
 type KustomizeRBAC struct {
 	input.Input
+
+	// Profiling indicates whether to deploy the pprof service and the
+	// pprof-reader role
+	Profiling bool
 }
 
 // GetInput implements input.File
@@ -50,4 +54,9 @@ const kustomizeRBACTemplate = `resources:
 - auth_proxy_service.yaml
 - auth_proxy_role.yaml
 - auth_proxy_role_binding.yaml
+{{- if .Profiling }}
+# The pprof endpoints of the manager and the role of the users profiling it.
+- pprof_service.yaml
+- pprof_reader_role.yaml
+{{- end }}
 `
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+var kustomizeSampleScaffoldMarker = marker.For("kustomization.yaml", "samplekustomizeresource").String()
+
+var _ input.File = &SamplesKustomization{}
+
+// SamplesKustomization scaffolds the config/samples/kustomization.yaml file
+// aggregating the samples of the resources, e.g. to create them all with
+// kubectl apply -k config/samples
+type SamplesKustomization struct {
+	input.Input
+
+	// Resource is the resource whose sample is added by Update
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (k *SamplesKustomization) GetInput() (input.Input, error) {
+	if k.Path == "" {
+		k.Path = filepath.Join("config", "samples", "kustomization.yaml")
+	}
+	k.TemplateBody = samplesKustomizationTemplate
+	k.IfExistsAction = input.Error
+	return k.Input, nil
+}
+
+// Update adds the sample of the Resource to the kustomization
+func (k *SamplesKustomization) Update() error {
+	if k.Path == "" {
+		k.Path = filepath.Join("config", "samples", "kustomization.yaml")
+	}
+	return internal.InsertStringsInFile(k.Path, map[string][]string{
+		kustomizeSampleScaffoldMarker: {fmt.Sprintf("- %s\n", k.Resource.SampleFileName())},
+	})
+}
+
+var samplesKustomizationTemplate = fmt.Sprintf(`# The samples of the resources of the project, which kubectl apply -k creates
+resources:
+%s
+`, kustomizeSampleScaffoldMarker)
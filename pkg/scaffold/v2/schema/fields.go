@@ -0,0 +1,324 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// fieldType is a type of the fields defined with ParseField
+type fieldType struct {
+	goType string
+	schema Schema
+
+	// pkg is the package of the type, besides metav1
+	pkg string
+}
+
+// fieldTypes are the types of the fields defined with ParseField, by name
+var fieldTypes = map[string]fieldType{
+	"string":   {goType: "string", schema: Schema{Type: "string"}},
+	"int32":    {goType: "int32", schema: Schema{Type: "integer"}},
+	"int64":    {goType: "int64", schema: Schema{Type: "integer", Format: "int64"}},
+	"bool":     {goType: "bool", schema: Schema{Type: "boolean"}},
+	"quantity": {goType: "resource.Quantity", schema: Schema{Type: "number"}, pkg: quantityPackage},
+	"time":     {goType: "metav1.Time", schema: Schema{Type: "string", Format: "date-time"}},
+	"object": {goType: "runtime.RawExtension", schema: Schema{Type: "object", PreserveUnknownFields: true},
+		pkg: runtimePackage},
+}
+
+// Field is a field of the Spec of a kind defined in a single line, see
+// ParseField
+type Field struct {
+	// Name is the Go name of the field, e.g. Replicas
+	Name string
+
+	// JSONName is the name of the field in the json tag, e.g. replicas
+	JSONName string
+
+	// Type is the Go type of the field
+	Type string
+
+	// Required is true if the field cannot be omitted
+	Required bool
+
+	// Schema holds the constraints and the default of the field
+	Schema *Schema
+
+	// pkg is the package of the type of the field, besides metav1
+	pkg string
+}
+
+var fieldName = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// ParseField parses a field defined as <name>:<type>[:<options>], e.g.
+// "Replicas:int32:min=1,max=10,default=3".
+//
+// The type is string, int32, int64, bool, quantity or time, or a slice
+// ([]<type>) or a map with string keys (map[string]<type>) of them, or
+// object, an arbitrary object whose unknown fields are not pruned, see
+// EmbedResource. The options are separated by commas:
+//   - required: the field cannot be omitted, it is optional otherwise
+//   - min, max: the bounds of the integers
+//   - minLength, maxLength, pattern, format: the constraints of the strings
+//   - enum: the values, separated by semicolons, of the strings and integers
+//   - minItems, maxItems: the bounds of the length of the slices
+//   - default: the default value of the field, which cannot be a slice or a map
+//
+// The slices and maps only have the required, minItems and maxItems options,
+// and the objects the required option.
+func ParseField(s string) (*Field, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid field %q, must be <name>:<type>[:<options>]", s)
+	}
+	f := &Field{Name: parts[0]}
+	if !fieldName.MatchString(f.Name) {
+		return nil, fmt.Errorf("invalid field name %q, must be an exported Go name, e.g. Replicas", f.Name)
+	}
+	f.JSONName = jsonName(f.Name)
+
+	typ := parts[1]
+	switch {
+	case typ == "[]object" || typ == "map[string]object":
+		return nil, fmt.Errorf("invalid field type %q, the objects cannot be in a slice or a map, whose "+
+			"items would be pruned", typ)
+	case strings.HasPrefix(typ, "[]"):
+		t, found := fieldTypes[strings.TrimPrefix(typ, "[]")]
+		if !found {
+			return nil, unknownFieldType(typ)
+		}
+		f.Schema = &Schema{Type: "array", Items: &t.schema}
+		f.Type, f.pkg = "[]"+t.goType, t.pkg
+	case strings.HasPrefix(typ, "map[string]"):
+		t, found := fieldTypes[strings.TrimPrefix(typ, "map[string]")]
+		if !found {
+			return nil, unknownFieldType(typ)
+		}
+		f.Schema = &Schema{Type: "object", AdditionalProperties: &t.schema}
+		f.Type, f.pkg = "map[string]"+t.goType, t.pkg
+	default:
+		t, found := fieldTypes[typ]
+		if !found {
+			return nil, unknownFieldType(typ)
+		}
+		f.Schema = &t.schema
+		f.Type, f.pkg = t.goType, t.pkg
+	}
+
+	if len(parts) < 3 || parts[2] == "" {
+		return f, nil
+	}
+	for _, option := range strings.Split(parts[2], ",") {
+		if err := f.setOption(option); err != nil {
+			return nil, fmt.Errorf("invalid option %q of field %s: %v", option, f.Name, err)
+		}
+	}
+	return f, nil
+}
+
+// EmbedResource makes the field an embedded Kubernetes object, whose
+// apiVersion, kind and metadata are validated by the API server, e.g. the
+// template of the objects a controller creates. Only the object fields can
+// embed a resource.
+func (f *Field) EmbedResource() error {
+	if f.Type != fieldTypes["object"].goType {
+		return fmt.Errorf("field %s is a %s, only the object fields can embed a resource", f.Name, f.Type)
+	}
+	f.Schema.EmbeddedResource = true
+	return nil
+}
+
+// setOption sets an option of the field
+func (f *Field) setOption(option string) error {
+	kv := strings.SplitN(option, "=", 2)
+	key := strings.TrimSpace(kv[0])
+	if key == "required" && len(kv) == 1 {
+		f.Required = true
+		return nil
+	}
+	if len(kv) < 2 {
+		return fmt.Errorf("must be <option>=<value>")
+	}
+	value := strings.TrimSpace(kv[1])
+
+	s := f.Schema
+	isString := f.Type == "string"
+	isInteger := s.Type == "integer"
+	switch key {
+	case "min", "max":
+		if !isInteger {
+			return fmt.Errorf("only the integers have bounds")
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		bound := float64(v)
+		if key == "min" {
+			s.Minimum = &bound
+		} else {
+			s.Maximum = &bound
+		}
+	case "minLength", "maxLength":
+		if !isString {
+			return fmt.Errorf("only the strings have a length")
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if key == "minLength" {
+			s.MinLength = &v
+		} else {
+			s.MaxLength = &v
+		}
+	case "pattern", "format":
+		if !isString {
+			return fmt.Errorf("only the strings have a %s", key)
+		}
+		if key == "pattern" {
+			s.Pattern = value
+		} else {
+			s.Format = value
+		}
+	case "enum":
+		if !isString && !isInteger {
+			return fmt.Errorf("only the strings and integers have an enum")
+		}
+		for _, v := range strings.Split(value, ";") {
+			e, err := scalarValue(s, v)
+			if err != nil {
+				return err
+			}
+			s.Enum = append(s.Enum, e)
+		}
+	case "minItems", "maxItems":
+		if s.Type != "array" {
+			return fmt.Errorf("only the slices have items")
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		if key == "minItems" {
+			s.MinItems = &v
+		} else {
+			s.MaxItems = &v
+		}
+	case "default":
+		if s.Type == "array" || s.Type == "object" {
+			return fmt.Errorf("the slices, maps and objects cannot have a default")
+		}
+		d, err := scalarValue(s, value)
+		if err != nil {
+			return err
+		}
+		s.Default = d
+	default:
+		return fmt.Errorf("unknown option")
+	}
+	return nil
+}
+
+// scalarValue parses a value of a scalar schema, as decoded from JSON
+func scalarValue(s *Schema, value string) (interface{}, error) {
+	switch s.Type {
+	case "integer":
+		v, err := strconv.ParseInt(value, 10, 64)
+		return float64(v), err
+	case "boolean":
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
+
+func unknownFieldType(typ string) error {
+	types := make([]string, 0, len(fieldTypes))
+	for t := range fieldTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return fmt.Errorf("unknown field type %q, must be one of %s, or a slice or map of them",
+		typ, strings.Join(types, ", "))
+}
+
+// jsonName returns the json name of a Go name, with its leading initialism
+// in lower case, e.g. apiKey for APIKey
+func jsonName(name string) string {
+	r := []rune(name)
+	for i := range r {
+		if !unicode.IsUpper(r[i]) {
+			break
+		}
+		// the last upper case letter of an initialism starts the next word
+		if i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) {
+			break
+		}
+		r[i] = unicode.ToLower(r[i])
+	}
+	return string(r)
+}
+
+// FieldTypes returns the Types of a kind whose Spec has the given fields, in
+// order
+func FieldTypes(fields []*Field) (*Types, error) {
+	imports := map[string]bool{}
+	seen := map[string]bool{}
+	var b strings.Builder
+	for i, f := range fields {
+		if seen[f.Name] {
+			return nil, fmt.Errorf("field %s is defined more than once", f.Name)
+		}
+		seen[f.Name] = true
+		if f.pkg != "" {
+			imports[f.pkg] = true
+		}
+		fieldMarkers, err := markers(f.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name, err)
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		typ, tag := f.Type, f.JSONName
+		if !f.Required {
+			b.WriteString("\t// +optional\n")
+			tag += ",omitempty"
+			if typ == "metav1.Time" {
+				typ = "*" + typ
+			}
+		}
+		for _, m := range fieldMarkers {
+			fmt.Fprintf(&b, "\t// %s\n", m)
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", f.Name, typ, tag)
+	}
+
+	t := &Types{SpecFields: b.String()}
+	for pkg := range imports {
+		t.Imports = append(t.Imports, pkg)
+	}
+	sort.Strings(t.Imports)
+	return t, nil
+}
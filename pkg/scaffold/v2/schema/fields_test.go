@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldTypes(t *testing.T) {
+	var fields []*Field
+	for _, f := range []string{
+		"Replicas:int32:min=1,max=10,default=3",
+		"APIKey:string:required,minLength=8",
+		"Size:string:enum=Small;Large,default=Small",
+		"Memory:quantity",
+		"Hosts:[]string:minItems=1",
+	} {
+		field, err := ParseField(f)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", f, err)
+		}
+		fields = append(fields, field)
+	}
+	types, err := FieldTypes(fields)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	expected := "\t// +optional\n" +
+		"\t// +kubebuilder:validation:Minimum=1\n" +
+		"\t// +kubebuilder:validation:Maximum=10\n" +
+		"\t// +kubebuilder:default=3\n" +
+		"\tReplicas int32 `json:\"replicas,omitempty\"`\n" +
+		"\n" +
+		"\t// +kubebuilder:validation:MinLength=8\n" +
+		"\tAPIKey string `json:\"apiKey\"`\n" +
+		"\n" +
+		"\t// +optional\n" +
+		"\t// +kubebuilder:validation:Enum=Small;Large\n" +
+		"\t// +kubebuilder:default=\"Small\"\n" +
+		"\tSize string `json:\"size,omitempty\"`\n" +
+		"\n" +
+		"\t// +optional\n" +
+		"\tMemory resource.Quantity `json:\"memory,omitempty\"`\n" +
+		"\n" +
+		"\t// +optional\n" +
+		"\t// +kubebuilder:validation:MinItems=1\n" +
+		"\tHosts []string `json:\"hosts,omitempty\"`\n"
+	if types.SpecFields != expected {
+		t.Errorf("got spec fields:\n%s\nwanted:\n%s", types.SpecFields, expected)
+	}
+	if !reflect.DeepEqual(types.Imports, []string{quantityPackage}) {
+		t.Errorf("got imports %v", types.Imports)
+	}
+}
+
+func TestParseFieldErrors(t *testing.T) {
+	for _, f := range []string{
+		"Replicas",
+		"replicas:int32",
+		"Replicas:float",
+		"Replicas:int32:min=one",
+		"Replicas:int32:minLength=1",
+		"Name:string:max=1",
+		"Hosts:[]string:default=a",
+		"Labels:map[string]string:minItems=1",
+		"Name:string:unknown=1",
+		"Templates:[]object",
+		"Template:object:default=a",
+	} {
+		if _, err := ParseField(f); err == nil {
+			t.Errorf("expected an error parsing %q", f)
+		}
+	}
+}
+
+func TestEmbedResource(t *testing.T) {
+	template, err := ParseField("Template:object:required")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := template.EmbedResource(); err != nil {
+		t.Fatalf("error %v", err)
+	}
+	types, err := FieldTypes([]*Field{template})
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	expected := "\t// +kubebuilder:pruning:PreserveUnknownFields\n" +
+		"\t// +kubebuilder:validation:EmbeddedResource\n" +
+		"\tTemplate runtime.RawExtension `json:\"template\"`\n"
+	if types.SpecFields != expected {
+		t.Errorf("got spec fields:\n%s\nwanted:\n%s", types.SpecFields, expected)
+	}
+	if !reflect.DeepEqual(types.Imports, []string{runtimePackage}) {
+		t.Errorf("got imports %v", types.Imports)
+	}
+
+	name, err := ParseField("Name:string")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	if err := name.EmbedResource(); err == nil {
+		t.Errorf("expected an error embedding a resource in a string")
+	}
+}
+
+func TestJSONName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Replicas": "replicas",
+		"APIKey":   "apiKey",
+		"URL":      "url",
+		"ImageURL": "imageURL",
+	} {
+		if got := jsonName(name); got != expected {
+			t.Errorf("json name of %s: got %s, wanted %s", name, got, expected)
+		}
+	}
+}
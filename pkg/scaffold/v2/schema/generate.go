@@ -0,0 +1,447 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+)
+
+const (
+	intstrPackage   = "k8s.io/apimachinery/pkg/util/intstr"
+	quantityPackage = "k8s.io/apimachinery/pkg/api/resource"
+	runtimePackage  = "k8s.io/apimachinery/pkg/runtime"
+)
+
+// Types are the Go types generated for the Spec and Status of a kind
+type Types struct {
+	// Imports are the packages the types use, besides metav1
+	Imports []string
+
+	// SpecFields and StatusFields are the fields of the Spec and Status
+	// structs
+	SpecFields, StatusFields string
+
+	// Decls are the declarations of the types of the fields
+	Decls string
+
+	// Names are the names of the types declared by Decls, which must not be
+	// declared by the other files of the package
+	Names []string
+}
+
+// Generate generates the Go types of the Spec and Status of the kind from its
+// schema in the document. The spec and status properties of the schema of the
+// kind are the Spec and Status, or the whole schema is the Spec if it has
+// neither.
+//
+// The objects with properties are structs, the objects with
+// additionalProperties maps and the other objects runtime.RawExtension. The
+// integers are int32 unless their format is int64, and the numbers are
+// resource.Quantity since controller-gen does not support floats. The
+// constraints and the scalar defaults of the properties are validation and
+// default markers, and the properties which are not required are optional.
+// The types of the schemas referred to with $ref are named after the kind and
+// the name of the schema, so that the types of the kinds of a package do not
+// collide.
+func Generate(kind string, d *Document) (*Types, error) {
+	kindSchema, err := d.KindSchema(kind)
+	if err != nil {
+		return nil, err
+	}
+	if _, kindSchema, err = d.resolve(kindSchema); err != nil {
+		return nil, err
+	}
+
+	g := &generator{doc: d, kind: kind, imports: map[string]bool{}, declared: map[string]*Schema{}}
+	// the types of the kind are declared by the types file
+	for _, name := range []string{kind, kind + "List", kind + "Spec", kind + "Status"} {
+		g.declared[name] = nil
+	}
+	spec, status := kindSchema.Properties["spec"], kindSchema.Properties["status"]
+	if spec == nil && status == nil {
+		spec = kindSchema
+	}
+
+	t := &Types{}
+	if spec != nil {
+		if t.SpecFields, err = g.structFields(kind+"Spec", spec); err != nil {
+			return nil, fmt.Errorf("error generating the spec of %s: %v", kind, err)
+		}
+	}
+	if status != nil {
+		if t.StatusFields, err = g.structFields(kind+"Status", status); err != nil {
+			return nil, fmt.Errorf("error generating the status of %s: %v", kind, err)
+		}
+	}
+	t.Decls = strings.Join(g.decls, "\n")
+	for name, s := range g.declared {
+		if s != nil {
+			t.Names = append(t.Names, name)
+		}
+	}
+	sort.Strings(t.Names)
+	for pkg := range g.imports {
+		t.Imports = append(t.Imports, pkg)
+	}
+	sort.Strings(t.Imports)
+	return t, nil
+}
+
+type generator struct {
+	doc *Document
+
+	// kind is the kind the types are generated for
+	kind string
+
+	// imports are the imported packages
+	imports map[string]bool
+
+	// decls are the type declarations, in the order they are generated, and
+	// declared the schemas of their names, nil for the types of the kind
+	decls    []string
+	declared map[string]*Schema
+}
+
+// declare records that the type of the given name is declared for the
+// schema. It returns false if the type is already declared for the schema,
+// and an error if it is declared for another one.
+func (g *generator) declare(name string, s *Schema) (bool, error) {
+	declared, found := g.declared[name]
+	if !found {
+		g.declared[name] = s
+		return true, nil
+	}
+	if declared == nil {
+		return false, fmt.Errorf("type %s is one of the types of the kind %s", name, g.kind)
+	}
+	if declared != s {
+		return false, fmt.Errorf("type %s is generated for two different schemas", name)
+	}
+	return false, nil
+}
+
+// structFields returns the fields of the struct of the given name generated
+// from an object schema
+func (g *generator) structFields(name string, s *Schema) (string, error) {
+	_, s, err := g.doc.resolve(s)
+	if err != nil {
+		return "", err
+	}
+
+	props := make([]string, 0, len(s.Properties))
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	sort.Strings(props)
+
+	var b strings.Builder
+	for i, p := range props {
+		ps := s.Properties[p]
+		fieldName := goName(p)
+		if fieldName == "" {
+			return "", fmt.Errorf("property %q has no valid Go name", p)
+		}
+		required := contains(s.Required, p)
+
+		typ, err := g.goType(name+fieldName, fmt.Sprintf("the %s of %s", p, name), ps)
+		if err != nil {
+			return "", fmt.Errorf("property %s: %v", p, err)
+		}
+		// the optional structs are pointers, so that they can be omitted
+		if !required && (g.isStruct(ps) || typ == "metav1.Time") {
+			typ = "*" + typ
+		}
+		markers, err := g.fieldMarkers(ps)
+		if err != nil {
+			return "", fmt.Errorf("property %s: %v", p, err)
+		}
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeComment(&b, "\t", ps.Description)
+		if !required {
+			b.WriteString("\t// +optional\n")
+		}
+		for _, m := range markers {
+			fmt.Fprintf(&b, "\t// %s\n", m)
+		}
+		tag := p
+		if !required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", fieldName, typ, tag)
+	}
+	return b.String(), nil
+}
+
+// goType returns the Go type of a schema, declaring the structs and the named
+// types it needs under the given name. The structs defining what is
+// described by of are documented as such if the schema has no description.
+func (g *generator) goType(name, of string, s *Schema) (string, error) {
+	refName, s, err := g.doc.resolve(s)
+	if err != nil {
+		return "", err
+	}
+	if refName != "" {
+		return g.declareNamed(g.kind+goName(refName), refName, s)
+	}
+
+	switch {
+	case s.IntOrString:
+		g.imports[intstrPackage] = true
+		return "intstr.IntOrString", nil
+	case s.Type == "string" && s.Format == "date-time":
+		return "metav1.Time", nil
+	case s.Type == "string":
+		return "string", nil
+	case s.Type == "integer" && s.Format == "int64":
+		return "int64", nil
+	case s.Type == "integer":
+		return "int32", nil
+	case s.Type == "number":
+		g.imports[quantityPackage] = true
+		return "resource.Quantity", nil
+	case s.Type == "boolean":
+		return "bool", nil
+	case s.Type == "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array has no items")
+		}
+		item, err := g.goType(flect.Singularize(name), "an item of "+of, s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case len(s.Properties) > 0:
+		return g.declareStruct(name, of, "", s)
+	case s.AdditionalProperties != nil:
+		value, err := g.goType(name+"Value", "a value of "+of, s.AdditionalProperties)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + value, nil
+	case s.Type == "object" || s.PreserveUnknownFields:
+		g.imports[runtimePackage] = true
+		return "runtime.RawExtension", nil
+	}
+	return "", fmt.Errorf("unsupported type %q", s.Type)
+}
+
+// declareStruct declares the struct of an object schema, which is the schema
+// ref refers to if it is not empty
+func (g *generator) declareStruct(name, of, ref string, s *Schema) (string, error) {
+	if first, err := g.declare(name, s); err != nil || !first {
+		return name, err
+	}
+
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+	fields, err := g.structFields(name, s)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	writeComment(&b, "", typeDescription(name, of, ref, s))
+	fmt.Fprintf(&b, "type %s struct {\n%s}\n", name, fields)
+	g.decls[i] = b.String()
+	return name, nil
+}
+
+// declareNamed declares the type of a schema referred to with $ref, a struct
+// for an object schema and else a type whose validation markers are those of
+// the schema
+func (g *generator) declareNamed(name, ref string, s *Schema) (string, error) {
+	if len(s.Properties) > 0 {
+		return g.declareStruct(name, "", ref, s)
+	}
+	if first, err := g.declare(name, s); err != nil || !first {
+		return name, err
+	}
+
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+	typ, err := g.goType(name+"Item", name, s)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	markers, err := markers(s)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	var b strings.Builder
+	writeComment(&b, "", typeDescription(name, "", ref, s))
+	for _, m := range markers {
+		fmt.Fprintf(&b, "// %s\n", m)
+	}
+	fmt.Fprintf(&b, "type %s %s\n", name, typ)
+	g.decls[i] = b.String()
+	return name, nil
+}
+
+// isStruct returns true if the Go type of the schema is a struct
+func (g *generator) isStruct(s *Schema) bool {
+	_, s, err := g.doc.resolve(s)
+	return err == nil && len(s.Properties) > 0
+}
+
+// fieldMarkers returns the validation markers of a field, which are those of
+// its named type instead if it has one
+func (g *generator) fieldMarkers(s *Schema) ([]string, error) {
+	if s.Ref != "" {
+		return nil, nil
+	}
+	return markers(s)
+}
+
+// markers returns the validation markers of the constraints of a schema
+func markers(s *Schema) ([]string, error) {
+	var m []string
+	if len(s.Enum) > 0 {
+		values := make([]string, 0, len(s.Enum))
+		for _, v := range s.Enum {
+			values = append(values, enumValue(v))
+		}
+		m = append(m, "+kubebuilder:validation:Enum="+strings.Join(values, ";"))
+	}
+	if s.Pattern != "" {
+		pattern := "`" + s.Pattern + "`"
+		if strings.Contains(s.Pattern, "`") {
+			pattern = strconv.Quote(s.Pattern)
+		}
+		m = append(m, "+kubebuilder:validation:Pattern="+pattern)
+	}
+	if s.Type == "string" && s.Format != "" && s.Format != "date-time" {
+		m = append(m, "+kubebuilder:validation:Format="+s.Format)
+	}
+	// the bounds of resource.Quantity cannot be validated
+	if s.Type == "integer" {
+		for _, b := range []struct {
+			marker    string
+			bound     *float64
+			exclusive bool
+		}{
+			{"Minimum", s.Minimum, s.ExclusiveMinimum},
+			{"Maximum", s.Maximum, s.ExclusiveMaximum},
+		} {
+			if b.bound == nil {
+				continue
+			}
+			if *b.bound != math.Trunc(*b.bound) {
+				return nil, fmt.Errorf("%s %v of an integer is not an integer", strings.ToLower(b.marker), *b.bound)
+			}
+			m = append(m, fmt.Sprintf("+kubebuilder:validation:%s=%d", b.marker, int64(*b.bound)))
+			if b.exclusive {
+				m = append(m, fmt.Sprintf("+kubebuilder:validation:Exclusive%s=true", b.marker))
+			}
+		}
+	}
+	for _, b := range []struct {
+		marker string
+		bound  *int64
+	}{
+		{"MinLength", s.MinLength}, {"MaxLength", s.MaxLength},
+		{"MinItems", s.MinItems}, {"MaxItems", s.MaxItems},
+	} {
+		if b.bound != nil {
+			m = append(m, fmt.Sprintf("+kubebuilder:validation:%s=%d", b.marker, *b.bound))
+		}
+	}
+	if s.PreserveUnknownFields {
+		m = append(m, "+kubebuilder:pruning:PreserveUnknownFields")
+	}
+	if s.EmbeddedResource {
+		m = append(m, "+kubebuilder:validation:EmbeddedResource")
+	}
+	// the defaults of the objects and arrays are left to the user
+	switch d := s.Default.(type) {
+	case string:
+		m = append(m, "+kubebuilder:default="+strconv.Quote(d))
+	case float64, bool:
+		m = append(m, "+kubebuilder:default="+enumValue(d))
+	}
+	return m, nil
+}
+
+var simpleEnumValue = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// enumValue returns an enum value as written in the Enum marker, quoted
+// unless it is a number, a boolean or a simple string
+func enumValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		if simpleEnumValue.MatchString(v) {
+			return v
+		}
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// goName returns the exported Go name of a property or a schema name, e.g.
+// MaxSize for maxSize or max-size
+func goName(name string) string {
+	name = flect.Pascalize(nonIdentifier.ReplaceAllString(name, " "))
+	name = strings.Replace(name, " ", "", -1)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return ""
+	}
+	return name
+}
+
+// typeDescription returns the description of a declared type, which defaults
+// to what it defines or to the schema ref refers to
+func typeDescription(name, of, ref string, s *Schema) string {
+	switch {
+	case s.Description != "":
+		return s.Description
+	case of != "":
+		return fmt.Sprintf("%s defines %s", name, of)
+	}
+	return fmt.Sprintf("%s is generated from the schema %s", name, ref)
+}
+
+// writeComment writes a description as a Go comment
+func writeComment(b *strings.Builder, indent, description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	for _, l := range strings.Split(description, "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight("// "+l, " \t"))
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const frigateSchema = `
+components:
+  schemas:
+    Frigate:
+      type: object
+      properties:
+        spec:
+          type: object
+          required: [name]
+          properties:
+            name:
+              description: Name of the frigate
+              type: string
+              minLength: 1
+            replicas:
+              type: integer
+              minimum: 0
+              exclusiveMinimum: true
+              default: 3
+            size:
+              $ref: '#/components/schemas/Size'
+            labels:
+              type: object
+              additionalProperties:
+                type: string
+        status:
+          type: object
+          properties:
+            armedAt:
+              type: string
+              format: date-time
+    Size:
+      type: string
+      enum: [Small, Large]
+`
+
+func TestGenerate(t *testing.T) {
+	d, err := Parse([]byte(frigateSchema))
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	types, err := Generate("Frigate", d)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	for _, expected := range []string{
+		"// +optional\n\tLabels map[string]string `json:\"labels,omitempty\"`",
+		"// Name of the frigate\n\t// +kubebuilder:validation:MinLength=1\n\tName string `json:\"name\"`",
+		"// +optional\n\t// +kubebuilder:validation:Minimum=0\n\t" +
+			"// +kubebuilder:validation:ExclusiveMinimum=true\n\t// +kubebuilder:default=3\n\tReplicas int32 `json:\"replicas,omitempty\"`",
+		"Size FrigateSize `json:\"size,omitempty\"`",
+	} {
+		if !strings.Contains(types.SpecFields, expected) {
+			t.Errorf("spec fields %q do not contain %q", types.SpecFields, expected)
+		}
+	}
+	if expected := "ArmedAt *metav1.Time `json:\"armedAt,omitempty\"`"; !strings.Contains(types.StatusFields, expected) {
+		t.Errorf("status fields %q do not contain %q", types.StatusFields, expected)
+	}
+	expected := "// FrigateSize is generated from the schema Size\n// +kubebuilder:validation:Enum=Small;Large\n" +
+		"type FrigateSize string"
+	if !strings.Contains(types.Decls, expected) {
+		t.Errorf("declarations %q do not contain %q", types.Decls, expected)
+	}
+	if names := []string{"FrigateSize"}; !reflect.DeepEqual(types.Names, names) {
+		t.Errorf("expected the names %v, got %v", names, types.Names)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, schema := range []string{
+		"type: object\nproperties:\n  spec:\n    $ref: '#/definitions/Missing'\n",
+		"type: object\nproperties:\n  spec:\n    type: object\n    properties:\n" +
+			"      replicas:\n        type: integer\n        minimum: 0.5\n",
+		// the type of the Spec definition would be the FrigateSpec of the kind
+		"type: object\nproperties:\n  spec:\n    type: object\n    properties:\n" +
+			"      inner:\n        $ref: '#/definitions/Spec'\n" +
+			"definitions:\n  Spec:\n    type: object\n    properties:\n      name:\n        type: string\n",
+	} {
+		d, err := Parse([]byte(schema))
+		if err != nil {
+			t.Fatalf("error %v", err)
+		}
+		if _, err := Generate("Frigate", d); err == nil {
+			t.Errorf("expected an error generating the types of %q", schema)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema generates the Go types of an API, with their validation
+// markers, from an OpenAPI or JSON schema.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Schema is the part of an OpenAPI v3 or JSON schema the types are generated
+// from
+type Schema struct {
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+
+	// AdditionalProperties is the schema of the values of a map, it is nil
+	// if additionalProperties is not a schema
+	AdditionalProperties *Schema `json:"-"`
+
+	Enum             []interface{} `json:"enum,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum bool          `json:"-"`
+	ExclusiveMaximum bool          `json:"-"`
+	MinLength        *int64        `json:"minLength,omitempty"`
+	MaxLength        *int64        `json:"maxLength,omitempty"`
+	MinItems         *int64        `json:"minItems,omitempty"`
+	MaxItems         *int64        `json:"maxItems,omitempty"`
+	Default          interface{}   `json:"default,omitempty"`
+
+	IntOrString           bool `json:"x-kubernetes-int-or-string,omitempty"`
+	PreserveUnknownFields bool `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	EmbeddedResource      bool `json:"x-kubernetes-embedded-resource,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, for the fields whose type
+// differs between OpenAPI v3 and JSON schema
+func (s *Schema) UnmarshalJSON(b []byte) error {
+	type plain Schema
+	aux := struct {
+		*plain
+		AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+		ExclusiveMinimum     json.RawMessage `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     json.RawMessage `json:"exclusiveMaximum,omitempty"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.AdditionalProperties) > 0 && aux.AdditionalProperties[0] == '{' {
+		s.AdditionalProperties = &Schema{}
+		if err := json.Unmarshal(aux.AdditionalProperties, s.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+	// exclusiveMinimum and exclusiveMaximum are booleans in OpenAPI v3, and
+	// the bounds themselves in JSON schema
+	var err error
+	if s.ExclusiveMinimum, err = exclusiveBound(aux.ExclusiveMinimum, &s.Minimum); err != nil {
+		return fmt.Errorf("invalid exclusiveMinimum: %v", err)
+	}
+	if s.ExclusiveMaximum, err = exclusiveBound(aux.ExclusiveMaximum, &s.Maximum); err != nil {
+		return fmt.Errorf("invalid exclusiveMaximum: %v", err)
+	}
+	return nil
+}
+
+func exclusiveBound(raw json.RawMessage, bound **float64) (bool, error) {
+	if len(raw) == 0 {
+		return false, nil
+	}
+	var exclusive bool
+	if err := json.Unmarshal(raw, &exclusive); err == nil {
+		return exclusive, nil
+	}
+	var v float64
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return false, err
+	}
+	*bound = &v
+	return true, nil
+}
+
+// Document is a schema file. It is either the schema of the kind, e.g. the
+// openAPIV3Schema of a CRD, or a document holding the schemas of several
+// types, e.g. an OpenAPI v3 document with the schema of the kind in
+// components.schemas, or an OpenAPI v2 or JSON schema document with the
+// schema of the kind in definitions.
+type Document struct {
+	// Root is the schema at the root of the file
+	Root *Schema
+
+	// Named are the schemas of the types of the file, by name, which are
+	// referred to with $ref
+	Named map[string]*Schema
+}
+
+// Load reads the schema file at the given path, in YAML or JSON
+func Load(path string) (*Document, error) {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	d, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the schema file %s: %v", path, err)
+	}
+	return d, nil
+}
+
+// Parse parses a schema file, in YAML or JSON
+func Parse(b []byte) (*Document, error) {
+	d := &Document{Root: &Schema{}}
+	if err := yaml.Unmarshal(b, d.Root); err != nil {
+		return nil, err
+	}
+	types := struct {
+		Components struct {
+			Schemas map[string]*Schema `json:"schemas"`
+		} `json:"components"`
+		Definitions map[string]*Schema `json:"definitions"`
+	}{}
+	if err := yaml.Unmarshal(b, &types); err != nil {
+		return nil, err
+	}
+	d.Named = map[string]*Schema{}
+	for name, s := range types.Definitions {
+		d.Named[name] = s
+	}
+	for name, s := range types.Components.Schemas {
+		d.Named[name] = s
+	}
+	return d, nil
+}
+
+// KindSchema returns the schema of the kind: the named schema of the kind,
+// ignoring the case, or else the root schema.
+func (d *Document) KindSchema(kind string) (*Schema, error) {
+	for name, s := range d.Named {
+		if strings.EqualFold(name, kind) {
+			return s, nil
+		}
+	}
+	if d.Root.Type == "" && len(d.Root.Properties) == 0 && d.Root.Ref == "" {
+		return nil, fmt.Errorf("no schema of %s found, the file must be the schema of the kind or define it "+
+			"in components.schemas or definitions", kind)
+	}
+	return d.Root, nil
+}
+
+// resolve returns the schema referred to by the $ref of the schema, with the
+// name of its type, or the schema itself if it has no $ref
+func (d *Document) resolve(s *Schema) (string, *Schema, error) {
+	if s.Ref == "" {
+		return "", s, nil
+	}
+	name := s.Ref
+	for _, prefix := range []string{"#/components/schemas/", "#/definitions/"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	named, found := d.Named[name]
+	if !found {
+		return "", nil, fmt.Errorf("unresolved reference %s, only the schemas of the file can be referred to", s.Ref)
+	}
+	return name, named, nil
+}
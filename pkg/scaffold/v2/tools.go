@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &ToolsGoMod{}
+
+// ToolsGoMod scaffolds the go.mod of the tools module in hack/tools, which
+// pins the versions of the tools installed by the Makefile
+type ToolsGoMod struct {
+	input.Input
+
+	// GoVersion is the Go release of the go directive, defaults to 1.13
+	GoVersion string
+
+	ControllerToolsVersion string
+	KustomizeVersion       string
+
+	// CodeGeneratorVersion is the version of conversion-gen and
+	// defaulter-gen, they are not pinned if empty
+	CodeGeneratorVersion string
+
+	// CRDRefDocsVersion is the version of crd-ref-docs, it is not pinned
+	// if empty
+	CRDRefDocsVersion string
+}
+
+// GetInput implements input.File
+func (t *ToolsGoMod) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = filepath.Join("hack", "tools", "go.mod")
+	}
+	if t.GoVersion == "" {
+		t.GoVersion = "1.13"
+	}
+	t.TemplateBody = toolsGoModTemplate
+	t.Input.IfExistsAction = input.Error
+	return t.Input, nil
+}
+
+const toolsGoModTemplate = `
+module {{ .Repo }}/hack/tools
+
+go {{ .GoVersion }}
+
+require (
+{{- if .CRDRefDocsVersion }}
+	github.com/elastic/crd-ref-docs {{ .CRDRefDocsVersion }}
+{{- end }}
+{{- if .CodeGeneratorVersion }}
+	k8s.io/code-generator {{ .CodeGeneratorVersion }}
+{{- end }}
+	sigs.k8s.io/controller-tools {{ .ControllerToolsVersion }}
+	sigs.k8s.io/kustomize/kustomize/v3 {{ .KustomizeVersion }}
+)
+`
+
+var _ input.File = &ToolsGo{}
+
+// ToolsGo scaffolds the tools.go file importing the tools of the tools
+// module, so that go mod tidy keeps them in its go.mod
+type ToolsGo struct {
+	input.Input
+
+	// CodeGenerators indicates whether to import conversion-gen and
+	// defaulter-gen
+	CodeGenerators bool
+
+	// APIDocs indicates whether to import crd-ref-docs
+	APIDocs bool
+}
+
+// GetInput implements input.File
+func (t *ToolsGo) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = filepath.Join("hack", "tools", "tools.go")
+	}
+	t.TemplateBody = toolsGoTemplate
+	t.Input.IfExistsAction = input.Error
+	return t.Input, nil
+}
+
+const toolsGoTemplate = `// +build tools
+
+{{ .Boilerplate }}
+
+// Package tools pins the versions of the tools installed by the Makefile
+package tools
+
+import (
+{{- if .APIDocs }}
+	_ "github.com/elastic/crd-ref-docs"
+{{- end }}
+{{- if .CodeGenerators }}
+	_ "k8s.io/code-generator/cmd/conversion-gen"
+	_ "k8s.io/code-generator/cmd/defaulter-gen"
+{{- end }}
+	_ "sigs.k8s.io/controller-tools/cmd/controller-gen"
+	_ "sigs.k8s.io/kustomize/kustomize/v3"
+)
+`
@@ -23,6 +23,7 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/schema"
 )
 
 var _ input.File = &Types{}
@@ -33,6 +34,17 @@ type Types struct {
 
 	// Resource is the resource to scaffold the types_test.go file for
 	Resource *resource.Resource
+
+	// Force overwrites the file if it already exists
+	Force bool
+
+	// Schema are the types generated from a schema file, the Spec has an
+	// example field if nil
+	Schema *schema.Types
+
+	// PreserveUnknownFields are the fields of the kind, spec and/or status,
+	// whose unknown fields are not pruned by the API server
+	PreserveUnknownFields []string
 }
 
 // GetInput implements input.File
@@ -42,7 +54,11 @@ func (t *Types) GetInput() (input.Input, error) {
 			fmt.Sprintf("%s_types.go", strings.ToLower(t.Resource.Kind)))
 	}
 	t.TemplateBody = typesTemplate
-	t.IfExistsAction = input.Error
+	if t.Force {
+		t.IfExistsAction = input.Overwrite
+	} else {
+		t.IfExistsAction = input.Error
+	}
 	return t.Input, nil
 }
 
@@ -51,12 +67,50 @@ func (t *Types) Validate() error {
 	return t.Resource.Validate()
 }
 
+// ResourceMarker returns the +kubebuilder:resource marker of the type, if the
+// resource is cluster scoped, has a custom plural, short names or categories.
+func (t *Types) ResourceMarker() string {
+	var args []string
+	if t.Resource.HasCustomPlural() {
+		args = append(args, "path="+t.Resource.Plural())
+	}
+	if !t.Resource.Namespaced {
+		args = append(args, "scope=Cluster")
+	}
+	if len(t.Resource.ShortNames) > 0 {
+		args = append(args, "shortName="+strings.Join(t.Resource.ShortNames, ";"))
+	}
+	if len(t.Resource.Categories) > 0 {
+		args = append(args, "categories="+strings.Join(t.Resource.Categories, ";"))
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return "// +kubebuilder:resource:" + strings.Join(args, ",")
+}
+
+// PreservesUnknownFields returns true if the unknown fields of the given field
+// of the kind, spec or status, are not pruned
+func (t *Types) PreservesUnknownFields(field string) bool {
+	for _, f := range t.PreserveUnknownFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
 const typesTemplate = `{{ .Boilerplate }}
 
 package {{ .Resource.Version }}
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+{{- if .Schema }}
+{{- range .Schema.Imports }}
+	"{{ . }}"
+{{- end }}
+{{- end }}
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -66,26 +120,56 @@ import (
 type {{.Resource.Kind}}Spec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .Schema }}
+{{- with .Schema.SpecFields }}
+
+{{ . }}
+{{- end }}
+{{- else }}
 
 	// Foo is an example field of {{.Resource.Kind}}. Edit {{.Resource.Kind}}_types.go to remove/update
 	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- end }}
 }
 
 // {{.Resource.Kind}}Status defines the observed state of {{.Resource.Kind}}
 type {{.Resource.Kind}}Status struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .Schema }}
+{{- with .Schema.StatusFields }}
+
+{{ . }}
+{{- end }}
+{{- end }}
 }
+{{- if .Schema }}
+{{- with .Schema.Decls }}
+
+{{ . }}
+{{- end }}
+{{- end }}
 
 // +kubebuilder:object:root=true
-{{ if not .Resource.Namespaced }} // +kubebuilder:resource:scope=Cluster {{ end }}
+{{- with .ResourceMarker }}
+{{ . }}
+{{- end }}
+{{- range .Resource.PrintColumns }}
+{{ .Marker }}
+{{- end }}
 
 // {{.Resource.Kind}} is the Schema for the {{ .Resource.Resource }} API
 type {{.Resource.Kind}} struct {
 	metav1.TypeMeta   ` + "`" + `json:",inline"` + "`" + `
 	metav1.ObjectMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
-
+{{ if .PreservesUnknownFields "spec" }}
+	// +kubebuilder:pruning:PreserveUnknownFields
+{{- end }}
 	Spec   {{.Resource.Kind}}Spec   ` + "`" + `json:"spec,omitempty"` + "`" + `
+{{- if .PreservesUnknownFields "status" }}
+
+	// +kubebuilder:pruning:PreserveUnknownFields
+{{- end }}
 	Status {{.Resource.Kind}}Status ` + "`" + `json:"status,omitempty"` + "`" + `
 }
 
@@ -0,0 +1,38 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// The versions of the templates stamping the files they scaffold. A change of
+// a template which the projects scaffolded before it should get bumps its
+// version, with a migration describing the change.
+var (
+	makefileVersion = input.TemplateVersion{Path: "Makefile", Version: 2, Migrations: map[int]string{
+		2: "describe the targets with a ## comment on their line and add the help target listing them",
+	}}
+	dockerfileVersion = input.TemplateVersion{Path: "Dockerfile", Version: 1}
+)
+
+// TemplateVersions are the current versions of the templates stamping the
+// files they scaffold
+var TemplateVersions = []input.TemplateVersion{
+	makefileVersion,
+	dockerfileVersion,
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &ManagerWatchNamespacePatch{}
+
+// ManagerWatchNamespacePatch scaffolds the patch setting WATCH_NAMESPACE to
+// the namespace of the manager
+type ManagerWatchNamespacePatch struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (p *ManagerWatchNamespacePatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "default", "manager_watch_namespace_patch.yaml")
+	}
+	p.TemplateBody = managerWatchNamespacePatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+const managerWatchNamespacePatchTemplate = `# This patch restricts the manager to the namespace it is deployed in
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        env:
+        - name: WATCH_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+`
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &BootstrapRBAC{}
+
+// BootstrapRBAC scaffolds the permissions of the init container injecting the
+// CA bundle in the webhook configurations and the conversion webhooks of the
+// CRDs with the webhook-bootstrap certificate source
+type BootstrapRBAC struct {
+	input.Input
+}
+
+// BootstrapRBACPath is the path of the BootstrapRBAC
+var BootstrapRBACPath = filepath.Join("config", "webhook", "bootstrap_rbac.yaml")
+
+// GetInput implements input.File
+func (r *BootstrapRBAC) GetInput() (input.Input, error) {
+	if r.Path == "" {
+		r.Path = BootstrapRBACPath
+	}
+	r.TemplateBody = bootstrapRBACTemplate
+	r.Input.IfExistsAction = input.Error
+	return r.Input, nil
+}
+
+const bootstrapRBACTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: webhook-bootstrap-role
+rules:
+- apiGroups:
+  - admissionregistration.k8s.io
+  resources:
+  - mutatingwebhookconfigurations
+  - validatingwebhookconfigurations
+  verbs:
+  - get
+  - patch
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions
+  verbs:
+  - get
+  - list
+  - patch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: webhook-bootstrap-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: webhook-bootstrap-role
+subjects:
+- kind: ServiceAccount
+  name: default
+  namespace: system
+`
@@ -0,0 +1,313 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ input.File = &Certs{}
+
+// Certs scaffolds the hack/certs command of the generated certificate source,
+// which generates the serving certificates of the webhook server and patches
+// the caBundle of the webhook configurations in the manifests piped through it
+// by make deploy
+type Certs struct {
+	input.Input
+}
+
+// GetInput implements input.File
+func (c *Certs) GetInput() (input.Input, error) {
+	if c.Path == "" {
+		c.Path = filepath.Join("hack", "certs", "main.go")
+	}
+	c.TemplateBody = certsTemplate
+	c.Input.IfExistsAction = input.Error
+	return c.Input, nil
+}
+
+const certsTemplate = `{{ .Boilerplate }}
+
+// Command certs generates the serving certificates of the webhook server for
+// the manifests read from stdin, and writes the manifests to stdout with the
+// webhook-server-cert secret added and the caBundle of the webhook
+// configurations and conversion webhooks set, e.g.
+//
+//   kustomize build config/default | go run ./hack/certs | kubectl apply -f -
+//
+// The CA is generated once in the -dir directory and reused from then on, the
+// serving certificate is signed for the webhook services of the manifests on
+// each run.
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	dir        = flag.String("dir", filepath.Join("bin", "webhook-certs"), "directory of the CA certificate and key")
+	secretName = flag.String("secret", "webhook-server-cert", "name of the secret of the serving certificate")
+	validity   = flag.Duration("validity", 10*365*24*time.Hour, "validity of the generated certificates")
+)
+
+// documentSeparator separates the documents of a YAML stream
+var documentSeparator = regexp.MustCompile("(?m)^---\\s*$")
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "certs: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	in, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	var objs []map[string]interface{}
+	for _, doc := range documentSeparator.Split(string(in), -1) {
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return err
+		}
+		if len(obj) > 0 {
+			objs = append(objs, obj)
+		}
+	}
+
+	// the client configurations of the webhooks, and the namespaces and DNS
+	// names of the services they call
+	var clientConfigs []map[string]interface{}
+	var namespaces, hosts []string
+	seen := map[string]bool{}
+	for _, obj := range objs {
+		for _, c := range webhookClientConfigs(obj) {
+			clientConfigs = append(clientConfigs, c)
+			svc, _ := c["service"].(map[string]interface{})
+			name, _ := svc["name"].(string)
+			namespace, _ := svc["namespace"].(string)
+			if name == "" || namespace == "" {
+				continue
+			}
+			host := fmt.Sprintf("%s.%s.svc", name, namespace)
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host, host+".cluster.local")
+			}
+			if !seen[namespace] {
+				seen[namespace] = true
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+
+	if len(hosts) > 0 {
+		caCert, caKey, err := loadOrCreateCA()
+		if err != nil {
+			return err
+		}
+		cert, key, err := servingCert(caCert, caKey, hosts)
+		if err != nil {
+			return err
+		}
+		caBundle := base64.StdEncoding.EncodeToString(encodeCert(caCert))
+		for _, c := range clientConfigs {
+			c["caBundle"] = caBundle
+		}
+		for _, namespace := range namespaces {
+			objs = append(objs, secret(namespace, cert, key, encodeCert(caCert)))
+		}
+	}
+
+	var out bytes.Buffer
+	for i, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(b)
+	}
+	_, err = os.Stdout.Write(out.Bytes())
+	return err
+}
+
+// webhookClientConfigs returns the client configurations of the webhooks of
+// the webhook configurations, and of the conversion webhook of the CRDs
+func webhookClientConfigs(obj map[string]interface{}) []map[string]interface{} {
+	var configs []map[string]interface{}
+	switch obj["kind"] {
+	case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+		webhooks, _ := obj["webhooks"].([]interface{})
+		for _, w := range webhooks {
+			w, _ := w.(map[string]interface{})
+			if c, ok := w["clientConfig"].(map[string]interface{}); ok {
+				configs = append(configs, c)
+			}
+		}
+	case "CustomResourceDefinition":
+		spec, _ := obj["spec"].(map[string]interface{})
+		conversion, _ := spec["conversion"].(map[string]interface{})
+		if c, ok := conversion["webhookClientConfig"].(map[string]interface{}); ok {
+			configs = append(configs, c)
+		}
+	}
+	return configs
+}
+
+// loadOrCreateCA loads the CA from the directory, or creates it there
+func loadOrCreateCA() (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certPath, keyPath := filepath.Join(*dir, "ca.crt"), filepath.Join(*dir, "ca.key")
+	certPEM, certErr := ioutil.ReadFile(certPath)
+	keyPEM, keyErr := ioutil.ReadFile(keyPath)
+	if certErr == nil && keyErr == nil {
+		certBlock, _ := pem.Decode(certPEM)
+		keyBlock, _ := pem.Decode(keyPEM)
+		if certBlock == nil || keyBlock == nil {
+			return nil, nil, fmt.Errorf("invalid CA in %s, remove it to generate a new one", *dir)
+		}
+		cert, err := x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cert, key, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "webhook-ca"},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := sign(template, nil, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		return nil, nil, err
+	}
+	if err := ioutil.WriteFile(certPath, encodeCert(cert), 0600); err != nil {
+		return nil, nil, err
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// servingCert returns the PEM encoded serving certificate and key of the
+// hosts, signed by the CA
+func servingCert(caCert *x509.Certificate, caKey *ecdsa.PrivateKey, hosts []string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: hosts[0]},
+		DNSNames:    hosts,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	cert, err := sign(template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeCert(cert), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// sign signs the certificate template with the key of the parent, it is self
+// signed if parent is nil
+func sign(template, parent *x509.Certificate, pub *ecdsa.PublicKey, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(*validity)
+	if parent == nil {
+		parent = template
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+func encodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+// secret returns the secret of the serving certificate mounted by the manager
+func secret(namespace string, cert, key, ca []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      *secretName,
+			"namespace": namespace,
+		},
+		"type": "kubernetes.io/tls",
+		"data": map[string]interface{}{
+			"tls.crt": base64.StdEncoding.EncodeToString(cert),
+			"tls.key": base64.StdEncoding.EncodeToString(key),
+			"ca.crt":  base64.StdEncoding.EncodeToString(ca),
+		},
+	}
+}
+`
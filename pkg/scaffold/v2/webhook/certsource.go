@@ -0,0 +1,77 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	// DefaultServerPort is the port the webhook server of the manager
+	// listens on
+	DefaultServerPort = 9443
+
+	// DefaultCertDir is the directory the webhook server of the manager
+	// reads its serving certificates from
+	DefaultCertDir = "/tmp/k8s-webhook-server/serving-certs"
+)
+
+// ValidateCertDir checks that the certificate directory is an absolute path,
+// which can be written in the scaffolded Go code and manifests.
+func ValidateCertDir(dir string) error {
+	if !path.IsAbs(dir) {
+		return fmt.Errorf("certificate directory must be an absolute path (was %q)", dir)
+	}
+	if strings.ContainsAny(dir, " \t\n\"'`\\$") {
+		return fmt.Errorf("certificate directory cannot contain spaces, quotes or shell characters (was %q)", dir)
+	}
+	return nil
+}
+
+// CertSource is where the serving certificates of the webhook server come from
+type CertSource string
+
+const (
+	// CertSourceCertManager uses cert-manager to issue the certificates and
+	// inject the CA bundle
+	CertSourceCertManager CertSource = "cert-manager"
+
+	// CertSourceBootstrap generates a self-signed certificate in an init
+	// container of the manager and injects the CA bundle from there
+	CertSourceBootstrap CertSource = "webhook-bootstrap"
+
+	// CertSourceManual expects the webhook-server-cert secret and the CA
+	// bundle to be provided by the user
+	CertSourceManual CertSource = "manual"
+
+	// CertSourceGenerated generates the certificate with the hack/certs
+	// command of the project in make deploy, which adds the
+	// webhook-server-cert secret and the CA bundle to the deployed manifests
+	CertSourceGenerated CertSource = "generated"
+)
+
+// Validate validates the CertSource
+func (s CertSource) Validate() error {
+	switch s {
+	case CertSourceCertManager, CertSourceBootstrap, CertSourceManual, CertSourceGenerated:
+		return nil
+	}
+	return fmt.Errorf("unknown certificate source %q, should be one of %s, %s, %s, %s",
+		s, CertSourceCertManager, CertSourceBootstrap, CertSourceManual, CertSourceGenerated)
+}
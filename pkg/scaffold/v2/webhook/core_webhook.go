@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+// CoreDir is the go package of the webhooks for the Kubernetes API types
+const CoreDir = "webhook"
+
+var _ input.File = &CoreWebhook{}
+
+// CoreWebhook scaffolds the admission webhooks of a type of the Kubernetes
+// API, e.g. Pod, which can not implement webhook.Defaulter or
+// webhook.Validator like the types of the project
+type CoreWebhook struct {
+	input.Input
+
+	// Resource is the Kubernetes API resource to make the webhooks for
+	Resource *resource.Resource
+
+	// ResourcePackage is the go package of the type of the Resource
+	ResourcePackage string
+
+	// APIGroup is the API group of the Resource, empty for the core group
+	APIGroup string
+
+	// GroupDomainWithDash is the group of the Resource with dashes, used in
+	// the paths of the webhooks
+	GroupDomainWithDash string
+
+	// Defaulting indicates whether to scaffold the mutating webhook
+	Defaulting bool
+
+	// Validating indicates whether to scaffold the validating webhook
+	Validating bool
+
+	// Settings are the settings of the webhooks
+	Settings
+}
+
+// GetInput implements input.File
+func (w *CoreWebhook) GetInput() (input.Input, error) {
+	var groupDomain string
+	w.ResourcePackage, groupDomain = util.GetResourceInfo(w.Resource, w.Repo, w.Domain)
+	w.GroupDomainWithDash = strings.Replace(groupDomain, ".", "-", -1)
+	w.Settings.setDefaults()
+	if w.Resource.Group != "core" {
+		w.APIGroup = groupDomain
+	}
+
+	if w.Path == "" {
+		w.Path = filepath.Join(CoreDir, fmt.Sprintf("%s_webhook.go", strings.ToLower(w.Resource.Kind)))
+	}
+	w.TemplateBody = coreWebhookTemplate
+	w.IfExistsAction = input.Error
+	return w.Input, nil
+}
+
+// Validate validates the values
+func (w *CoreWebhook) Validate() error {
+	if !util.IsCoreGroup(w.Resource.Group) {
+		return fmt.Errorf("%s is not a group of the Kubernetes API", w.Resource.Group)
+	}
+	if !w.Defaulting && !w.Validating {
+		return fmt.Errorf("at least one of the defaulting and validating webhooks is required")
+	}
+	if err := w.Settings.Validate(); err != nil {
+		return err
+	}
+	return w.Resource.Validate()
+}
+
+const coreWebhookTemplate = `{{ .Boilerplate }}
+
+package webhook
+
+import (
+	"context"
+{{- if .Defaulting }}
+	"encoding/json"
+{{- end }}
+	"net/http"
+
+	{{ .Resource.GroupImportSafe }}{{ .Resource.Version }} "{{ .ResourcePackage }}/{{ .Resource.Version }}"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging the {{ .Resource.Kind }} webhooks.
+var {{ lower .Resource.Kind }}log = logf.Log.WithName("{{ lower .Resource.Kind }}-resource")
+
+// Setup{{ .Resource.Kind }}WebhookWithManager registers the webhooks for {{ .Resource.Resource }} with the manager.
+func Setup{{ .Resource.Kind }}WebhookWithManager(mgr ctrl.Manager) error {
+{{- if .Defaulting }}
+	mgr.GetWebhookServer().Register("/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}",
+		&webhook.Admission{Handler: &{{ .Resource.Kind }}Defaulter{}})
+{{- end }}
+{{- if .Validating }}
+	mgr.GetWebhookServer().Register("/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }}",
+		&webhook.Admission{Handler: &{{ .Resource.Kind }}Validator{}})
+{{- end }}
+	return nil
+}
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+{{- if .Defaulting }}
+
+// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy={{ .FailurePolicy }},groups="{{ .APIGroup }}",resources={{ .Resource.Resource }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
+
+// {{ .Resource.Kind }}Defaulter defaults {{ .Resource.Resource }}
+type {{ .Resource.Kind }}Defaulter struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &{{ .Resource.Kind }}Defaulter{}
+var _ admission.DecoderInjector = &{{ .Resource.Kind }}Defaulter{}
+
+// Handle implements admission.Handler
+func (d *{{ .Resource.Kind }}Defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+	if err := d.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	{{ lower .Resource.Kind }}log.Info("default", "name", obj.Name)
+
+	// TODO(user): fill in your defaulting logic.
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (d *{{ .Resource.Kind }}Defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+{{- end }}
+{{- if .Validating }}
+
+// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
+// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy={{ .FailurePolicy }},groups="{{ .APIGroup }}",resources={{ .Resource.Resource }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
+
+// {{ .Resource.Kind }}Validator validates {{ .Resource.Resource }}
+type {{ .Resource.Kind }}Validator struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &{{ .Resource.Kind }}Validator{}
+var _ admission.DecoderInjector = &{{ .Resource.Kind }}Validator{}
+
+// Handle implements admission.Handler
+func (v *{{ .Resource.Kind }}Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &{{ .Resource.GroupImportSafe }}{{ .Resource.Version }}.{{ .Resource.Kind }}{}
+	if err := v.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	{{ lower .Resource.Kind }}log.Info("validate", "name", obj.Name)
+
+	// TODO(user): fill in your validation logic, e.g. return admission.Denied("reason").
+	return admission.Allowed("")
+}
+
+// InjectDecoder implements admission.DecoderInjector
+func (v *{{ .Resource.Kind }}Validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+{{- end }}
+`
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+var _ input.File = &FuzzTest{}
+
+// FuzzTest scaffolds the fuzz test of the validating webhook of a Resource,
+// which feeds it random objects to catch the panics of the validation logic.
+// It is built with the fuzz tag, and run by the test-fuzz Makefile target.
+type FuzzTest struct {
+	input.Input
+
+	// Resource is the Resource to test the validating webhook of
+	Resource *resource.Resource
+}
+
+// GetInput implements input.File
+func (t *FuzzTest) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = filepath.Join("api", t.Resource.Version,
+			fmt.Sprintf("%s_webhook_fuzz_test.go", strings.ToLower(t.Resource.Kind)))
+	}
+	t.TemplateBody = fuzzTestTemplate
+	return t.Input, nil
+}
+
+// Validate validates the values
+func (t *FuzzTest) Validate() error {
+	return t.Resource.Validate()
+}
+
+const fuzzTestTemplate = `{{ .Boilerplate }}
+
+// +build fuzz
+
+package {{ .Resource.Version }}
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+)
+
+// TestFuzz{{ .Resource.Kind }}Validation feeds random objects to the validating
+// webhook of {{ .Resource.Kind }}, and fails if the validation panics. It is run by
+// "make test-fuzz": FUZZ_ITERATIONS sets how many objects are validated, and
+// the FUZZ_SEED of a failed run reproduces it.
+func TestFuzz{{ .Resource.Kind }}Validation(t *testing.T) {
+	iterations := 1000
+	if s := os.Getenv("FUZZ_ITERATIONS"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			t.Fatalf("invalid FUZZ_ITERATIONS: %v", err)
+		}
+		iterations = n
+	}
+	seed := time.Now().UnixNano()
+	if s := os.Getenv("FUZZ_SEED"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid FUZZ_SEED: %v", err)
+		}
+		seed = n
+	}
+
+	f := fuzz.New().NilChance(0.2).RandSource(rand.NewSource(seed))
+	for i := 0; i < iterations; i++ {
+		obj, old := &{{ .Resource.Kind }}{}, &{{ .Resource.Kind }}{}
+		f.Fuzz(obj)
+		f.Fuzz(old)
+
+		check := func(name string, validate func() error) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s panicked, reproduce with FUZZ_SEED=%d: %v\nobject: %#v", name, seed, r, obj)
+				}
+			}()
+			// the objects are random, only the panics fail the test
+			_ = validate()
+		}
+		check("ValidateCreate", obj.ValidateCreate)
+		check("ValidateUpdate", func() error { return obj.ValidateUpdate(old) })
+		check("ValidateDelete", obj.ValidateDelete)
+	}
+}
+`
+
+// fuzzTarget is the Makefile target running the fuzz tests
+const fuzzTarget = `
+# Run the fuzz tests of the validating webhooks, FUZZ_SEED=<seed> reproduces a
+# failed run
+FUZZ_ITERATIONS ?= 1000
+test-fuzz: generate fmt vet ## Run the fuzz tests of the validating webhooks
+	FUZZ_ITERATIONS=$(FUZZ_ITERATIONS) go test -tags fuzz -run Fuzz ./api/... -v
+`
+
+// AddFuzzTarget adds the test-fuzz target running the fuzz tests to the
+// Makefile at path, unless it already has it.
+func AddFuzzTarget(path string) error {
+	return util.AddMakefileTarget(path, "test-fuzz", fuzzTarget)
+}
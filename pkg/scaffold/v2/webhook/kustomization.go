@@ -27,6 +27,9 @@ var _ input.File = &Kustomization{}
 // Kustomization scaffolds the Kustomization file in manager folder.
 type Kustomization struct {
 	input.Input
+
+	// CertSource is where the webhook server certificates come from
+	CertSource CertSource
 }
 
 // GetInput implements input.File
@@ -34,6 +37,9 @@ func (c *Kustomization) GetInput() (input.Input, error) {
 	if c.Path == "" {
 		c.Path = filepath.Join("config", "webhook", "kustomization.yaml")
 	}
+	if c.CertSource == "" {
+		c.CertSource = CertSourceCertManager
+	}
 	c.TemplateBody = KustomizeWebhookTemplate
 	c.Input.IfExistsAction = input.Error
 	return c.Input, nil
@@ -42,6 +48,9 @@ func (c *Kustomization) GetInput() (input.Input, error) {
 const KustomizeWebhookTemplate = `resources:
 - manifests.yaml
 - service.yaml
+{{- if eq .CertSource "webhook-bootstrap" }}
+- bootstrap_rbac.yaml
+{{- end }}
 
 configurations:
 - kustomizeconfig.yaml
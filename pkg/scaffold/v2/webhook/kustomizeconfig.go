@@ -42,6 +42,8 @@ func (c *KustomizeConfigWebhook) GetInput() (input.Input, error) {
 const KustomizeConfigWebhookTemplate = `# the following config is for teaching kustomize where to look at when substituting vars.
 # It requires kustomize v2.1.0 or newer to work properly.
 nameReference:
+# the webhook Service, with the name prefix of config/default and renamed by
+# service_patch.yaml if any, in the clientConfig.service of the webhook configurations
 - kind: Service
   version: v1
   fieldSpecs:
@@ -52,6 +54,8 @@ nameReference:
     group: admissionregistration.k8s.io
     path: webhooks/clientConfig/service/name
 
+# the namespace of config/default, which is the namespace of the webhook Service,
+# in the clientConfig.service of the webhook configurations
 namespace:
 - kind: MutatingWebhookConfiguration
   group: admissionregistration.k8s.io
@@ -0,0 +1,214 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/manager"
+)
+
+// ServiceName is the name of the webhook Service in config/webhook, which
+// controller-gen writes in the clientConfig of the webhook configurations
+const ServiceName = "webhook-service"
+
+// Server are the settings of the webhook server of the manager and of the
+// Service in front of it, which can be changed after init. The settings
+// left empty are not changed.
+type Server struct {
+	// Port is the port the webhook server listens on
+	Port int
+
+	// CertDir is the directory the webhook server reads its certificates
+	// from
+	CertDir string
+
+	// ServiceName is the name of the webhook Service, before the name prefix
+	// of config/default
+	ServiceName string
+
+	// ServiceNamespace is the namespace of the webhook Service, which is the
+	// namespace of the manager set in config/default
+	ServiceNamespace string
+}
+
+// Validate checks the settings
+func (s *Server) Validate() error {
+	if s.Port != 0 {
+		if err := manager.ValidateWebhookPort(s.Port); err != nil {
+			return fmt.Errorf("invalid webhook port: %v", err)
+		}
+	}
+	if s.CertDir != "" {
+		if err := ValidateCertDir(s.CertDir); err != nil {
+			return err
+		}
+	}
+	if s.ServiceName != "" {
+		if errs := resource.IsDNS1123Label(s.ServiceName); len(errs) > 0 {
+			return fmt.Errorf("invalid webhook service name %q: %s", s.ServiceName, strings.Join(errs, ", "))
+		}
+	}
+	if s.ServiceNamespace != "" {
+		if errs := resource.IsDNS1123Label(s.ServiceNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid webhook service namespace %q: %s",
+				s.ServiceNamespace, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// IsSet returns true if any of the settings is set
+func (s *Server) IsSet() bool {
+	return s.Port != 0 || s.CertDir != "" || s.ServiceName != "" || s.ServiceNamespace != ""
+}
+
+var _ input.File = &ServicePatch{}
+
+// ServicePatch scaffolds the JSON patch of the webhook Service setting the
+// port it forwards to and renaming it. The original name is replaced by the
+// new one in the clientConfig.service of the webhook configurations by the
+// nameReference of KustomizeConfigWebhook.
+type ServicePatch struct {
+	input.Input
+
+	// Port is the port of the webhook server the Service forwards to
+	Port int
+
+	// Name is the name of the Service, it is not renamed if empty
+	Name string
+}
+
+// GetInput implements input.File
+func (p *ServicePatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = ServicePatchPath
+	}
+	if p.Port == 0 {
+		p.Port = DefaultServerPort
+	}
+	if p.Name == ServiceName {
+		p.Name = ""
+	}
+	p.TemplateBody = servicePatchTemplate
+	p.Input.IfExistsAction = input.Overwrite
+	return p.Input, nil
+}
+
+// ServicePatchPath is the path of the ServicePatch
+var ServicePatchPath = filepath.Join("config", "webhook", "service_patch.yaml")
+
+// jsonPatchOp is an operation of a JSON patch
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// ReadServicePatch returns the port and name set by the ServicePatch at
+// path. The port is 0 and the name webhook-service when they are not set,
+// or when there is no patch.
+func ReadServicePatch(path string) (int, string, error) {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if os.IsNotExist(err) {
+		return 0, ServiceName, nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	var ops []jsonPatchOp
+	if err := yaml.Unmarshal(b, &ops); err != nil {
+		return 0, "", fmt.Errorf("error reading %s: %v", path, err)
+	}
+	port, name := 0, ServiceName
+	for _, op := range ops {
+		switch op.Path {
+		case "/spec/ports/0/targetPort":
+			port, err = strconv.Atoi(fmt.Sprint(op.Value))
+			if err != nil {
+				return 0, "", fmt.Errorf("invalid target port in %s: %v", path, op.Value)
+			}
+		case "/metadata/name":
+			name = fmt.Sprint(op.Value)
+		}
+	}
+	return port, name, nil
+}
+
+// AddToKustomization adds the patch to the patchesJson6902 of the
+// kustomization of config/webhook, unless it is already there.
+func (p *ServicePatch) AddToKustomization() error {
+	path := filepath.Join("config", "webhook", "kustomization.yaml")
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	// the kustomization keeps its line endings, LF or CRLF
+	eol := "\n"
+	if strings.Contains(string(b), "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(strings.TrimRight(string(b), eol), eol)
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "path: "+filepath.Base(ServicePatchPath) {
+			return nil
+		}
+	}
+
+	target := []string{
+		"- target:",
+		"    version: v1",
+		"    kind: Service",
+		"    name: " + ServiceName,
+		"  path: " + filepath.Base(ServicePatchPath),
+	}
+	// the patch is added at the end of the patches, or in a new section
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "patchesJson6902:" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && (strings.HasPrefix(lines[end], "-") || strings.HasPrefix(lines[end], " ")) {
+			end++
+		}
+		lines = append(lines[:end], append(target, lines[end:]...)...)
+		return ioutil.WriteFile(path, []byte(strings.Join(lines, eol)+eol), 0644)
+	}
+	lines = append(append(lines, "", "patchesJson6902:"), target...)
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, eol)+eol), 0644)
+}
+
+const servicePatchTemplate = `# This patch sets the port the webhook Service forwards to{{ if .Name }} and renames it{{ end }}.
+# The vars and the patches of the Service still refer to it as webhook-service.
+- op: replace
+  path: /spec/ports/0/targetPort
+  value: {{ .Port }}
+{{- if .Name }}
+- op: replace
+  path: /metadata/name
+  value: {{ .Name }}
+{{- end }}
+`
@@ -27,6 +27,9 @@ var _ input.File = &Service{}
 // Service scaffolds the Service file in manager folder.
 type Service struct {
 	input.Input
+
+	// Port is the port the webhook server listens on
+	Port int
 }
 
 // GetInput implements input.File
@@ -34,6 +37,9 @@ func (c *Service) GetInput() (input.Input, error) {
 	if c.Path == "" {
 		c.Path = filepath.Join("config", "webhook", "service.yaml")
 	}
+	if c.Port == 0 {
+		c.Port = DefaultServerPort
+	}
 	c.TemplateBody = ServiceTemplate
 	c.Input.IfExistsAction = input.Error
 	return c.Input, nil
@@ -48,7 +54,7 @@ metadata:
 spec:
   ports:
     - port: 443
-      targetPort: 9443
+      targetPort: {{ .Port }}
   selector:
     control-plane: controller-manager
 `
@@ -0,0 +1,205 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+// FailurePolicies are the failure policies of the admission webhooks, as
+// written in the webhook markers
+var FailurePolicies = []string{"fail", "ignore"}
+
+// SideEffectsClasses are the side effects classes of the admission webhooks
+var SideEffectsClasses = []string{"None", "NoneOnDryRun", "Some", "Unknown"}
+
+// MaxTimeoutSeconds is the maximum timeout of the admission webhooks
+const MaxTimeoutSeconds = 30
+
+// Settings are the settings of the admission webhooks of a Resource. The
+// failure policy is set in the webhook markers, the side effects and timeout
+// are not supported by the markers of the controller-gen version of the
+// projects and are set with a SettingsPatch.
+type Settings struct {
+	// FailurePolicy is how the API server handles the errors calling the
+	// webhooks, fail or ignore. Defaults to fail.
+	FailurePolicy string
+
+	// SideEffects is the side effects class of the webhooks, unset if empty
+	SideEffects string
+
+	// TimeoutSeconds is how long the API server waits for the webhooks, the
+	// API server default is used if 0
+	TimeoutSeconds int
+}
+
+// Validate checks the settings
+func (s *Settings) Validate() error {
+	if s.FailurePolicy != "" && !contains(FailurePolicies, s.FailurePolicy) {
+		return fmt.Errorf("failure policy must be one of %s (was %s)",
+			strings.Join(FailurePolicies, ", "), s.FailurePolicy)
+	}
+	if s.SideEffects != "" && !contains(SideEffectsClasses, s.SideEffects) {
+		return fmt.Errorf("side effects must be one of %s (was %s)",
+			strings.Join(SideEffectsClasses, ", "), s.SideEffects)
+	}
+	if s.TimeoutSeconds < 0 || s.TimeoutSeconds > MaxTimeoutSeconds {
+		return fmt.Errorf("timeout must be between 1 and %d seconds (was %d)", MaxTimeoutSeconds, s.TimeoutSeconds)
+	}
+	return nil
+}
+
+// NeedsPatch returns true if the settings are set with a SettingsPatch
+func (s *Settings) NeedsPatch() bool {
+	return s.SideEffects != "" || s.TimeoutSeconds != 0
+}
+
+func (s *Settings) setDefaults() {
+	if s.FailurePolicy == "" {
+		s.FailurePolicy = "fail"
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+var _ input.File = &SettingsPatch{}
+
+// SettingsPatch scaffolds the patch setting the side effects and timeout of
+// the admission webhooks of a Resource in the manifests generated from the
+// webhook markers
+type SettingsPatch struct {
+	input.Input
+	Settings
+
+	// Resource is the Resource of the webhooks
+	Resource *resource.Resource
+
+	// Defaulting indicates whether the mutating webhook is patched
+	Defaulting bool
+
+	// Validating indicates whether the validating webhook is patched
+	Validating bool
+}
+
+// GetInput implements input.File
+func (p *SettingsPatch) GetInput() (input.Input, error) {
+	if p.Path == "" {
+		p.Path = filepath.Join("config", "webhook", p.FileName())
+	}
+	p.TemplateBody = settingsPatchTemplate
+	p.Input.IfExistsAction = input.Error
+	return p.Input, nil
+}
+
+// FileName returns the name of the patch file in config/webhook
+func (p *SettingsPatch) FileName() string {
+	return fmt.Sprintf("%s_webhook_patch.yaml", strings.ToLower(p.Resource.Kind))
+}
+
+// Validate validates the values
+func (p *SettingsPatch) Validate() error {
+	if !p.Defaulting && !p.Validating {
+		return fmt.Errorf("the webhook settings patch of %s requires a defaulting or validating webhook",
+			p.Resource.Kind)
+	}
+	return p.Settings.Validate()
+}
+
+// AddToKustomization adds the patch to the patchesStrategicMerge of the
+// kustomization of config/webhook, unless it is already there.
+func (p *SettingsPatch) AddToKustomization() error {
+	path := filepath.Join("config", "webhook", "kustomization.yaml")
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	entry := "- " + p.FileName()
+	// the kustomization keeps its line endings, LF or CRLF
+	eol := "\n"
+	if strings.Contains(string(b), "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(strings.TrimRight(string(b), eol), eol)
+	for _, l := range lines {
+		if strings.TrimSpace(l) == entry {
+			return nil
+		}
+	}
+
+	// the patch is added at the end of the patches, or in a new section
+	for i, l := range lines {
+		if strings.TrimSpace(l) != "patchesStrategicMerge:" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.HasPrefix(lines[end], "-") {
+			end++
+		}
+		lines = append(lines[:end], append([]string{entry}, lines[end:]...)...)
+		return ioutil.WriteFile(path, []byte(strings.Join(lines, eol)+eol), 0644)
+	}
+	lines = append(lines, "", "patchesStrategicMerge:", entry)
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, eol)+eol), 0644)
+}
+
+const settingsPatchTemplate = `# This patch sets the side effects and timeout of the webhooks of {{ .Resource.Kind }},
+# which cannot be set with the webhook markers of controller-gen yet.
+{{- if .Defaulting }}
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: mutating-webhook-configuration
+webhooks:
+- name: m{{ lower .Resource.Kind }}.kb.io
+{{- if .SideEffects }}
+  sideEffects: {{ .SideEffects }}
+{{- end }}
+{{- if .TimeoutSeconds }}
+  timeoutSeconds: {{ .TimeoutSeconds }}
+{{- end }}
+{{- end }}
+{{- if and .Defaulting .Validating }}
+---
+{{- end }}
+{{- if .Validating }}
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validating-webhook-configuration
+webhooks:
+- name: v{{ lower .Resource.Kind }}.kb.io
+{{- if .SideEffects }}
+  sideEffects: {{ .SideEffects }}
+{{- end }}
+{{- if .TimeoutSeconds }}
+  timeoutSeconds: {{ .TimeoutSeconds }}
+{{- end }}
+{{- end }}
+`
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		settings Settings
+		valid    bool
+	}{
+		{Settings{}, true},
+		{Settings{FailurePolicy: "ignore", SideEffects: "NoneOnDryRun", TimeoutSeconds: 30}, true},
+		{Settings{FailurePolicy: "Fail"}, false},
+		{Settings{SideEffects: "none"}, false},
+		{Settings{TimeoutSeconds: -1}, false},
+		{Settings{TimeoutSeconds: 31}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.settings.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() of %+v returned %v", tt.settings, err)
+		}
+	}
+}
+
+func TestSettingsPatchAddToKustomization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-settings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd) // nolint: errcheck
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join("config", "webhook", "kustomization.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// the kustomization keeps its line endings
+	for _, eol := range []string{"\n", "\r\n"} {
+		content := strings.Replace("resources:\n- manifests.yaml\n", "\n", eol, -1)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, kind := range []string{"Frigate", "Sloop", "Frigate"} {
+			p := &SettingsPatch{Resource: &resource.Resource{Kind: kind}}
+			if err := p.AddToKustomization(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := strings.Replace(`resources:
+- manifests.yaml
+
+patchesStrategicMerge:
+- frigate_webhook_patch.yaml
+- sloop_webhook_patch.yaml
+`, "\n", eol, -1)
+		if string(b) != expected {
+			t.Errorf("expected kustomization:\n%q\ngot:\n%q", expected, b)
+		}
+	}
+}
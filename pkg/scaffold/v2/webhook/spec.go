@@ -0,0 +1,193 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SpecField describes a field of the <Kind>Spec struct of a Resource.
+type SpecField struct {
+	// Name is the Go name of the field
+	Name string
+
+	// JSONName is the serialized name of the field
+	JSONName string
+
+	// ZeroCheck is the comparison that is true when the field is unset,
+	// e.g. `== ""`. It is empty when the type has no simple zero check.
+	ZeroCheck string
+
+	// line is where the field, including its doc comment, starts
+	line int
+
+	// defaulted is true when the doc comment already has a default marker
+	defaulted bool
+
+	// required is true when the json tag has no omitempty option
+	required bool
+}
+
+// ParseSpecFields returns the fields of the <kind>Spec struct defined in the
+// given types file.
+func ParseSpecFields(path, kind string) ([]SpecField, error) {
+	src, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	return parseSpecFields(path, src, kind)
+}
+
+func parseSpecFields(path string, src []byte, kind string) ([]SpecField, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := findStruct(f, kind+"Spec")
+	if spec == nil {
+		return nil, fmt.Errorf("type %sSpec not found in %s", kind, path)
+	}
+
+	var fields []SpecField
+	for _, field := range spec.Fields.List {
+		// embedded fields are inlined and have no single value to default
+		if len(field.Names) == 0 {
+			continue
+		}
+		jsonName := field.Names[0].Name
+		required := true
+		if field.Tag != nil {
+			tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+			options := strings.Split(tag, ",")
+			if name := options[0]; name == "-" {
+				continue
+			} else if name != "" {
+				jsonName = name
+			}
+			for _, option := range options[1:] {
+				if option == "omitempty" {
+					required = false
+				}
+			}
+		}
+		start := field.Pos()
+		defaulted := false
+		if field.Doc != nil {
+			start = field.Doc.Pos()
+			defaulted = strings.Contains(field.Doc.Text(), "+kubebuilder:default")
+		}
+		fields = append(fields, SpecField{
+			Name:      field.Names[0].Name,
+			JSONName:  jsonName,
+			ZeroCheck: zeroCheck(field.Type),
+			line:      fset.Position(start).Line,
+			defaulted: defaulted,
+			required:  required,
+		})
+	}
+	return fields, nil
+}
+
+// findStruct returns the struct type with the given name declared in f.
+func findStruct(f *ast.File, name string) *ast.StructType {
+	var found *ast.StructType
+	ast.Inspect(f, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != name {
+			return found == nil
+		}
+		if st, ok := ts.Type.(*ast.StructType); ok {
+			found = st
+		}
+		return false
+	})
+	return found
+}
+
+// zeroCheck returns the comparison against the zero value of the given type.
+func zeroCheck(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType:
+		return "== nil"
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return `== ""`
+		case "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64",
+			"float32", "float64":
+			return "== 0"
+		}
+	}
+	return ""
+}
+
+// defaultMarkerStub is the comment added above every Spec field to hint where
+// the default value of a field is declared.
+func defaultMarkerStub(field SpecField) string {
+	return fmt.Sprintf(`// TODO(user): set a default for %s with a "+kubebuilder:default=<value>" marker.`, field.JSONName)
+}
+
+// AddDefaultMarkerStubs adds a default marker stub above every optional field
+// of the <kind>Spec struct of the given types file that has no default marker
+// yet. The stub is kept in its own comment group, so it is not part of the
+// field documentation.
+func AddDefaultMarkerStubs(path, kind string) error {
+	src, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+
+	fields, err := parseSpecFields(path, src, kind)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(src), "\n")
+	// insert from the bottom so the line numbers of the fields remain valid
+	sort.Slice(fields, func(i, j int) bool { return fields[i].line > fields[j].line })
+	for _, field := range fields {
+		// a required field is never defaulted by the API server, and a
+		// defaulted one needs no reminder
+		if field.required || field.defaulted {
+			continue
+		}
+		stub := defaultMarkerStub(field)
+		if strings.Contains(string(src), stub) {
+			continue
+		}
+		i := field.line - 1
+		lines = append(lines[:i], append([]string{stub, ""}, lines[i:]...)...)
+	}
+
+	out, err := format.Source([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, out, os.ModePerm)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const typesSource = `package v1
+
+type EmbeddedSpec struct{}
+
+// FrigateSpec defines the desired state of Frigate
+type FrigateSpec struct {
+	EmbeddedSpec ` + "`json:\",inline\"`" + `
+
+	// Name of the frigate
+	Name string ` + "`json:\"name,omitempty\"`" + `
+	Replicas *int32 ` + "`json:\"replicas\"`" + `
+	Size int64
+	Armed bool ` + "`json:\"armed\"`" + `
+	Ignored string ` + "`json:\"-\"`" + `
+	// +kubebuilder:default=3
+	Guns int32 ` + "`json:\"guns,omitempty\"`" + `
+	Crew []string ` + "`json:\"crew,omitempty\"`" + `
+}
+`
+
+func TestParseSpecFields(t *testing.T) {
+	fields, err := parseSpecFields("frigate_types.go", []byte(typesSource), "Frigate")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	expected := []SpecField{
+		{Name: "Name", JSONName: "name", ZeroCheck: `== ""`},
+		{Name: "Replicas", JSONName: "replicas", ZeroCheck: "== nil", required: true},
+		{Name: "Size", JSONName: "Size", ZeroCheck: "== 0", required: true},
+		{Name: "Armed", JSONName: "armed", ZeroCheck: "", required: true},
+		{Name: "Guns", JSONName: "guns", ZeroCheck: "== 0", defaulted: true},
+		{Name: "Crew", JSONName: "crew", ZeroCheck: "== nil"},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("got %d fields and wanted %d: %v", len(fields), len(expected), fields)
+	}
+	for i, field := range fields {
+		field.line = 0
+		if field != expected[i] {
+			t.Errorf("got: %v and wanted: %v", field, expected[i])
+		}
+	}
+
+	if _, err := parseSpecFields("frigate_types.go", []byte(typesSource), "Kraken"); err == nil {
+		t.Errorf("expected an error for a missing Spec type")
+	}
+}
+
+func TestAddDefaultMarkerStubs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubebuilder-webhook-spec")
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "frigate_types.go")
+	if err := ioutil.WriteFile(path, []byte(typesSource), 0600); err != nil {
+		t.Fatalf("error %v", err)
+	}
+
+	// running it twice must not duplicate the stubs
+	for i := 0; i < 2; i++ {
+		if err := AddDefaultMarkerStubs(path, "Frigate"); err != nil {
+			t.Fatalf("error %v", err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error %v", err)
+	}
+	content := string(b)
+
+	for name, want := range map[string]int{
+		"name": 1, "crew": 1,
+		// required fields
+		"replicas": 0, "Size": 0, "armed": 0,
+		// fields with a default marker
+		"guns": 0,
+	} {
+		stub := `// TODO(user): set a default for ` + name + ` with a "+kubebuilder:default=<value>" marker.`
+		if n := strings.Count(content, stub); n != want {
+			t.Errorf("got %d stubs for %s and wanted %d in:\n%s", n, name, want, content)
+		}
+	}
+
+	// the stub must not be merged into the doc comment of the field
+	if !strings.Contains(content, "marker.\n\n\t// Name of the frigate\n") {
+		t.Errorf("stub for name is not separated from its doc comment:\n%s", content)
+	}
+}
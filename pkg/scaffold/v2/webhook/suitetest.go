@@ -0,0 +1,324 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/marker"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/internal"
+)
+
+var webhookSetupScaffoldMarker = marker.For("suite_test.go", "webhook").String()
+
+var _ input.File = &SuiteTest{}
+
+// SuiteTest scaffolds the webhook_suite_test.go file, which runs the webhooks
+// of an API version against the API server of envtest
+type SuiteTest struct {
+	input.Input
+
+	// Resource is a resource of the API version to test the webhooks of
+	Resource *resource.Resource
+
+	// EnvtestAssets uses the envtest binaries downloaded by the setup-envtest
+	// Makefile target
+	EnvtestAssets bool
+}
+
+// GetInput implements input.File
+func (s *SuiteTest) GetInput() (input.Input, error) {
+	if s.Path == "" {
+		s.Path = filepath.Join("api", s.Resource.Version, "webhook_suite_test.go")
+	}
+	s.TemplateBody = suiteTestTemplate
+	return s.Input, nil
+}
+
+// Validate validates the values
+func (s *SuiteTest) Validate() error {
+	return s.Resource.Validate()
+}
+
+// Update registers the webhooks of the resource with the manager of the suite.
+func (s *SuiteTest) Update() error {
+	setupCodeFragment := fmt.Sprintf(`err = (&%s{}).SetupWebhookWithManager(mgr)
+Expect(err).NotTo(HaveOccurred())
+
+`, s.Resource.Kind)
+
+	return internal.InsertStringsInFile(s.Path,
+		map[string][]string{
+			webhookSetupScaffoldMarker: {setupCodeFragment},
+		})
+}
+
+const suiteTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests use Ginkgo (BDD-style Go testing framework). Refer to
+// http://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+
+// webhookHost is the address the webhook server of the tests listens on, and
+// which the API server of envtest calls.
+const webhookHost = "127.0.0.1"
+
+var k8sClient client.Client
+var testEnv *envtest.Environment
+var certDir string
+var stopCh chan struct{}
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecsWithDefaultAndCustomReporters(t,
+	"Webhook Suite",
+	[]Reporter{printer.NewlineReporter{}})
+}
+
+var _ = BeforeSuite(func(done Done) {
+	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
+
+	By("bootstrapping test environment")
+{{- if .EnvtestAssets }}
+	// use the binaries downloaded by "make setup-envtest", unless
+	// KUBEBUILDER_ASSETS is already set
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		Expect(os.Setenv("KUBEBUILDER_ASSETS", filepath.Join("..", "..", "testbin", "bin"))).To(Succeed())
+	}
+{{- end }}
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join("..", "..", "config", "crd", "bases")},
+	}
+
+	cfg, err := testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cfg).ToNot(BeNil())
+
+	scheme := runtime.NewScheme()
+	err = clientgoscheme.AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+	err = AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(k8sClient).ToNot(BeNil())
+
+	By("starting the webhook server")
+	certDir, err = ioutil.TempDir("", "webhook-certs")
+	Expect(err).NotTo(HaveOccurred())
+	caBundle, err := writeServingCert(certDir)
+	Expect(err).NotTo(HaveOccurred())
+	port, err := freePort()
+	Expect(err).NotTo(HaveOccurred())
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		Host:               webhookHost,
+		Port:               port,
+		CertDir:            certDir,
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	// +kubebuilder:scaffold:webhook
+
+	stopCh = make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		err := mgr.Start(stopCh)
+		Expect(err).NotTo(HaveOccurred())
+	}()
+
+	addr := net.JoinHostPort(webhookHost, strconv.Itoa(port))
+	Eventually(func() error {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, 10*time.Second).Should(Succeed())
+
+	By("installing the webhook configurations")
+	// the configurations are generated by "make manifests". The API server
+	// may take a moment to start calling the webhooks once they are installed.
+	err = installWebhookConfigurations(filepath.Join("..", "..", "config", "webhook", "manifests.yaml"),
+		"https://"+addr, caBundle)
+	Expect(err).NotTo(HaveOccurred())
+
+	close(done)
+}, 60)
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	close(stopCh)
+	err := testEnv.Stop()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(os.RemoveAll(certDir)).To(Succeed())
+})
+
+// writeServingCert writes a self-signed serving certificate for webhookHost
+// to dir, and returns it to use as the CA bundle of the webhooks.
+func writeServingCert(dir string) ([]byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: webhookHost},
+		IPAddresses:           []net.IP{net.ParseIP(webhookHost)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.crt"), cert, 0600); err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.key"), keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// freePort returns a port of webhookHost which nothing listens on.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(webhookHost, "0"))
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// installWebhookConfigurations creates the webhook configurations of the given
+// manifests, calling the webhook server at url instead of the webhook service.
+func installWebhookConfigurations(path, url string, caBundle []byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%v, run \"make manifests\" to generate the webhook configurations", err)
+	}
+	defer f.Close()
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		typeMeta := &metav1.TypeMeta{}
+		if err := decodeManifest(doc, typeMeta); err != nil {
+			return err
+		}
+		var obj runtime.Object
+		switch typeMeta.Kind {
+		case "MutatingWebhookConfiguration":
+			config := &admissionv1beta1.MutatingWebhookConfiguration{}
+			if err := decodeManifest(doc, config); err != nil {
+				return err
+			}
+			for i := range config.Webhooks {
+				config.Webhooks[i].ClientConfig = localClientConfig(config.Webhooks[i].ClientConfig, url, caBundle)
+			}
+			obj = config
+		case "ValidatingWebhookConfiguration":
+			config := &admissionv1beta1.ValidatingWebhookConfiguration{}
+			if err := decodeManifest(doc, config); err != nil {
+				return err
+			}
+			for i := range config.Webhooks {
+				config.Webhooks[i].ClientConfig = localClientConfig(config.Webhooks[i].ClientConfig, url, caBundle)
+			}
+			obj = config
+		default:
+			continue
+		}
+		if err := k8sClient.Create(context.Background(), obj); err != nil {
+			return err
+		}
+	}
+}
+
+func decodeManifest(doc []byte, into interface{}) error {
+	return utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(doc), len(doc)).Decode(into)
+}
+
+// localClientConfig returns the client config calling the path of the webhook
+// service at url.
+func localClientConfig(config admissionv1beta1.WebhookClientConfig, url string, caBundle []byte) admissionv1beta1.WebhookClientConfig {
+	if config.Service != nil && config.Service.Path != nil {
+		url += *config.Service.Path
+	}
+	return admissionv1beta1.WebhookClientConfig{URL: &url, CABundle: caBundle}
+}
+`
@@ -17,11 +17,15 @@ limitations under the License.
 package webhook
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"path/filepath"
 	"strings"
 
-	"github.com/gobuffalo/flect"
+	"golang.org/x/tools/go/ast/astutil"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
@@ -48,6 +52,17 @@ type Webhook struct {
 	Defaulting bool
 	// If scaffold the validating webhook
 	Validating bool
+
+	// Settings are the settings of the defaulting and validating webhooks
+	Settings
+
+	// DefaultingFields are the Spec fields the defaulting webhook has TODOs
+	// for. They are read from the types file of the Resource if unset.
+	DefaultingFields []SpecField
+
+	// Existing is the content of the webhook file of the Resource the
+	// defaulting and validating webhooks are added to, see AddTo
+	Existing string
 }
 
 // GetInput implements input.File
@@ -56,16 +71,26 @@ func (a *Webhook) GetInput() (input.Input, error) {
 	_, a.GroupDomain = util.GetResourceInfo(a.Resource, a.Repo, a.Domain)
 
 	a.GroupDomainWithDash = strings.Replace(a.GroupDomain, ".", "-", -1)
+	a.Settings.setDefaults()
 
 	if a.Plural == "" {
-		a.Plural = flect.Pluralize(strings.ToLower(a.Resource.Kind))
+		a.Plural = a.Resource.Plural()
 	}
 
 	if a.Path == "" {
 		a.Path = filepath.Join("api", a.Resource.Version,
 			fmt.Sprintf("%s_webhook.go", strings.ToLower(a.Resource.Kind)))
 	}
+	if a.Defaulting && a.DefaultingFields == nil {
+		// the types file does not exist for core types, in which case
+		// the defaulting webhook is scaffolded without per-field TODOs
+		a.DefaultingFields, _ = ParseSpecFields(a.TypesPath(), a.Resource.Kind)
+	}
+
 	webhookTemplate := WebhookTemplate
+	if a.Existing != "" {
+		webhookTemplate = "{{ .Existing }}"
+	}
 	if a.Defaulting {
 		webhookTemplate = webhookTemplate + DefaultingWebhookTemplate
 	}
@@ -75,14 +100,62 @@ func (a *Webhook) GetInput() (input.Input, error) {
 
 	a.TemplateBody = webhookTemplate
 	a.Input.IfExistsAction = input.Error
+	if a.Existing != "" {
+		a.Input.IfExistsAction = input.Overwrite
+	}
 	return a.Input, nil
 }
 
+// AddTo makes the webhook extend the existing webhook file of the Resource,
+// e.g. the one with the conversion webhook only scaffolded for the hub
+// version of a kind, with the defaulting and validating webhooks. It fails if
+// the file already has one of them.
+func (a *Webhook) AddTo(path string, existing []byte) error {
+	if a.Defaulting && bytes.Contains(existing, []byte(fmt.Sprintf("webhook.Defaulter = &%s{}", a.Resource.Kind))) {
+		return fmt.Errorf("the defaulting webhook of %s already exists in %s", a.Resource.Kind, path)
+	}
+	if a.Validating && bytes.Contains(existing, []byte(fmt.Sprintf("webhook.Validator = &%s{}", a.Resource.Kind))) {
+		return fmt.Errorf("the validating webhook of %s already exists in %s", a.Resource.Kind, path)
+	}
+
+	// the imports of the webhooks, the unused ones are removed when the
+	// file is formatted
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, existing, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	astutil.AddImport(fset, f, "k8s.io/apimachinery/pkg/runtime")
+	astutil.AddImport(fset, f, "sigs.k8s.io/controller-runtime/pkg/webhook")
+	out := &bytes.Buffer{}
+	if err := format.Node(out, fset, f); err != nil {
+		return fmt.Errorf("error formatting %s: %v", path, err)
+	}
+
+	a.Path = path
+	a.Existing = out.String()
+	return nil
+}
+
 // Validate validates the values
 func (g *Webhook) Validate() error {
+	if err := g.Settings.Validate(); err != nil {
+		return err
+	}
 	return g.Resource.Validate()
 }
 
+// TypesPath returns the path of the types file of the Resource.
+func (a *Webhook) TypesPath() string {
+	return filepath.Join("api", a.Resource.Version, fmt.Sprintf("%s_types.go", strings.ToLower(a.Resource.Kind)))
+}
+
+// UpdateTypes adds the default marker stubs for the Spec fields to the types
+// file of the Resource.
+func (a *Webhook) UpdateTypes() error {
+	return AddDefaultMarkerStubs(a.TypesPath(), a.Resource.Kind)
+}
+
 const (
 	WebhookTemplate = `{{ .Boilerplate }}
 
@@ -108,7 +181,7 @@ func (r *{{.Resource.Kind}}) SetupWebhookWithManager(mgr ctrl.Manager) error {
 `
 
 	DefaultingWebhookTemplate = `
-// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy=fail,groups={{ .GroupDomain }},resources={{ .Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy={{ .FailurePolicy }},groups={{ .GroupDomain }},resources={{ .Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
 
 var _ webhook.Defaulter = &{{ .Resource.Kind }}{}
 
@@ -117,12 +190,23 @@ func (r *{{ .Resource.Kind }}) Default() {
 	{{ lower .Resource.Kind }}log.Info("default", "name", r.Name)
 
 	// TODO(user): fill in your defaulting logic.
+{{- range .DefaultingFields }}
+
+	// TODO(user): default {{ .Name }}, e.g.
+	{{- if .ZeroCheck }}
+	// if r.Spec.{{ .Name }} {{ .ZeroCheck }} {
+	// 	r.Spec.{{ .Name }} = ...
+	// }
+	{{- else }}
+	// r.Spec.{{ .Name }} = ...
+	{{- end }}
+{{- end }}
 }
 `
 
 	ValidatingWebhookTemplate = `
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy=fail,groups={{ .GroupDomain }},resources={{ .Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy={{ .FailurePolicy }},groups={{ .GroupDomain }},resources={{ .Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
 
 var _ webhook.Validator = &{{ .Resource.Kind }}{}
 
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+var _ input.File = &WebhookTest{}
+
+// WebhookTest scaffolds the tests of the defaulting and validating webhooks
+// of a Resource, which run in the suite scaffolded by SuiteTest
+type WebhookTest struct {
+	input.Input
+
+	// Resource is the Resource to test the webhooks of
+	Resource *resource.Resource
+
+	// Namespaced is true if the objects of the Resource are namespaced
+	Namespaced bool
+
+	// If test the defaulting webhook
+	Defaulting bool
+	// If test the validating webhook
+	Validating bool
+}
+
+// GetInput implements input.File
+func (t *WebhookTest) GetInput() (input.Input, error) {
+	if t.Path == "" {
+		t.Path = filepath.Join("api", t.Resource.Version,
+			fmt.Sprintf("%s_webhook_test.go", strings.ToLower(t.Resource.Kind)))
+	}
+	t.TemplateBody = webhookTestTemplate
+	return t.Input, nil
+}
+
+// Validate validates the values
+func (t *WebhookTest) Validate() error {
+	return t.Resource.Validate()
+}
+
+const webhookTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("{{ .Resource.Kind }} webhook", func() {
+	It("should admit a valid {{ .Resource.Kind }}", func() {
+		obj := &{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "{{ lower .Resource.Kind }}-sample",
+{{- if .Namespaced }}
+				Namespace: "default",
+{{- end }}
+			},
+		}
+		// TODO(user): set the fields a valid {{ .Resource.Kind }} requires.
+
+		Expect(k8sClient.Create(context.Background(), obj)).To(Succeed())
+{{- if .Defaulting }}
+
+		// TODO(user): check the fields set by the defaulting webhook, e.g.
+		// Expect(obj.Spec.Foo).To(Equal("bar"))
+{{- end }}
+
+		Expect(k8sClient.Delete(context.Background(), obj)).To(Succeed())
+	})
+{{- if .Validating }}
+
+	// TODO(user): check that the validating webhook rejects an invalid
+	// {{ .Resource.Kind }}, e.g. with
+	// Expect(k8sClient.Create(context.Background(), obj)).NotTo(Succeed())
+{{- end }}
+})
+`
@@ -17,14 +17,30 @@ limitations under the License.
 package v2
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
 )
 
 // CRDWebhookPatch scaffolds a CRDWebhookPatch for a Resource
 type ManagerWebhookPatch struct {
 	input.Input
+
+	// CertSource is where the webhook server certificates come from
+	CertSource webhook.CertSource
+
+	// Prefix is the kustomize name prefix of the project, used to find the
+	// webhook service and configurations with the webhook-bootstrap source
+	Prefix string
+
+	// Port is the port the webhook server listens on
+	Port int
+
+	// CertDir is the directory the webhook server reads its certificates from
+	CertDir string
 }
 
 // GetInput implements input.File
@@ -32,11 +48,37 @@ func (p *ManagerWebhookPatch) GetInput() (input.Input, error) {
 	if p.Path == "" {
 		p.Path = filepath.Join("config", "default", "manager_webhook_patch.yaml")
 	}
+	if p.CertSource == "" {
+		p.CertSource = webhook.CertSourceCertManager
+	}
+	if p.Port == 0 {
+		p.Port = webhook.DefaultServerPort
+	}
+	if p.CertDir == "" {
+		p.CertDir = webhook.DefaultCertDir
+	}
+	if p.Prefix == "" {
+		// use directory name as prefix
+		dir, err := os.Getwd()
+		if err != nil {
+			return input.Input{}, err
+		}
+		p.Prefix = strings.ToLower(filepath.Base(dir))
+	}
 	p.TemplateBody = ManagerWebhookPatchTemplate
 	return p.Input, nil
 }
 
-const ManagerWebhookPatchTemplate = `apiVersion: apps/v1
+const ManagerWebhookPatchTemplate = `
+{{- if eq .CertSource "manual" -}}
+# The webhook-server-cert secret must be created in the manager namespace,
+# and the caBundle of the webhook configurations set to the CA that signed it.
+{{ end -}}
+{{- if eq .CertSource "generated" -}}
+# The webhook-server-cert secret and the caBundle of the webhook configurations
+# are generated by hack/certs in make deploy.
+{{ end -}}
+apiVersion: apps/v1
 kind: Deployment
 metadata:
   name: controller-manager
@@ -44,19 +86,87 @@ metadata:
 spec:
   template:
     spec:
+{{- if eq .CertSource "webhook-bootstrap" }}
+      initContainers:
+      # generates a self-signed certificate for the webhook service
+      - name: cert-bootstrap
+        image: alpine/openssl
+        command: ["/bin/sh", "-c"]
+        args:
+        - |
+          set -e
+          cd {{ .CertDir }}
+          host="${SERVICE_NAME}.${POD_NAMESPACE}.svc"
+          openssl req -x509 -newkey rsa:2048 -nodes -days 3650 -subj "/CN=webhook-ca" -keyout ca.key -out ca.crt
+          openssl req -newkey rsa:2048 -nodes -subj "/CN=${host}" -keyout tls.key -out tls.csr
+          echo "subjectAltName=DNS:${host},DNS:${host}.cluster.local" > ext.cnf
+          openssl x509 -req -in tls.csr -CA ca.crt -CAkey ca.key -CAcreateserial -days 3650 -extfile ext.cnf -out tls.crt
+        env:
+        - name: SERVICE_NAME
+          value: {{ .Prefix }}-webhook-service
+        - name: POD_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+        volumeMounts:
+        - mountPath: {{ .CertDir }}
+          name: cert
+      # injects the CA of the certificate in the webhook configurations and in
+      # the conversion webhooks of the CRDs
+      - name: cabundle-inject
+        image: bitnami/kubectl
+        command: ["/bin/sh", "-c"]
+        args:
+        - |
+          set -e
+          ca_bundle="` + "`" + `base64 -w0 {{ .CertDir }}/ca.crt` + "`" + `"
+          crds="customresourcedefinitions.v1beta1.apiextensions.k8s.io"
+          filter="?(@.spec.conversion.webhookClientConfig.service.name=='${SERVICE_NAME}')"
+          for crd in ` + "`" + `kubectl get ${crds} -o jsonpath="{.items[${filter}].metadata.name}"` + "`" + `; do
+            kubectl patch ${crds} ${crd} --type=json \
+              -p "[{\"op\": \"add\", \"path\": \"/spec/conversion/webhookClientConfig/caBundle\", \"value\": \"${ca_bundle}\"}]"
+          done
+          for config in mutatingwebhookconfiguration/{{ .Prefix }}-mutating-webhook-configuration \
+            validatingwebhookconfiguration/{{ .Prefix }}-validating-webhook-configuration; do
+            # the configuration may not exist if the project has no such webhooks
+            names=""
+            for i in ` + "`" + `seq 30` + "`" + `; do
+              names="` + "`" + `kubectl get ${config} --ignore-not-found -o jsonpath='{.webhooks[*].name}'` + "`" + `"
+              [ -n "${names}" ] && break
+              sleep 2
+            done
+            i=0
+            for name in ${names}; do
+              kubectl patch ${config} --type=json \
+                -p "[{\"op\": \"add\", \"path\": \"/webhooks/${i}/clientConfig/caBundle\", \"value\": \"${ca_bundle}\"}]"
+              i=` + "`" + `expr ${i} + 1` + "`" + `
+            done
+          done
+        env:
+        - name: SERVICE_NAME
+          value: {{ .Prefix }}-webhook-service
+        volumeMounts:
+        - mountPath: {{ .CertDir }}
+          name: cert
+          readOnly: true
+{{- end }}
       containers:
       - name: manager
         ports:
-        - containerPort: 9443
+        - containerPort: {{ .Port }}
           name: webhook-server
           protocol: TCP
         volumeMounts:
-        - mountPath: /tmp/k8s-webhook-server/serving-certs
+        - mountPath: {{ .CertDir }}
           name: cert
           readOnly: true
       volumes:
       - name: cert
+{{- if eq .CertSource "webhook-bootstrap" }}
+        emptyDir: {}
+{{- else }}
         secret:
           defaultMode: 420
           secretName: webhook-server-cert
+{{- end }}
 `
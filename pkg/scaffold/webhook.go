@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Webhook contains the configuration for generating the defaulting,
+// validating and conversion webhooks of a resource of the project.
+type Webhook struct {
+	Resource *resource.Resource
+
+	// Defaulting, Validating and Conversion indicate which webhooks to
+	// scaffold, at least one of them must be set
+	Defaulting bool
+	Validating bool
+	Conversion bool
+
+	// Settings are the settings of the defaulting and validating webhooks
+	Settings webhook.Settings
+
+	// Server are the settings of the webhook server and of its Service to
+	// change, see UpdateWebhookServer
+	Server webhook.Server
+
+	project *input.ProjectFile
+}
+
+// Validate validates whether the webhooks can be scaffolded.
+func (w *Webhook) Validate() error {
+	if err := w.setDefaults(); err != nil {
+		return err
+	}
+	if err := w.validate(); err != nil {
+		return invalidInput(err)
+	}
+	return nil
+}
+
+// validate checks the webhooks against the project, whose errors are invalid
+// inputs
+func (w *Webhook) validate() error {
+	if w.project.Version != project.Version2 {
+		return fmt.Errorf("webhooks are only supported for project version %s", project.Version2)
+	}
+	if !w.Defaulting && !w.Validating && !w.Conversion {
+		return fmt.Errorf("at least one of the defaulting, validating and conversion webhooks is required")
+	}
+	if err := w.Settings.Validate(); err != nil {
+		return err
+	}
+	if w.Settings.NeedsPatch() && !w.Defaulting && !w.Validating {
+		return fmt.Errorf("the side effects and timeout only apply to the defaulting and validating webhooks")
+	}
+	if err := w.Server.Validate(); err != nil {
+		return err
+	}
+	return w.Resource.Validate()
+}
+
+func (w *Webhook) setDefaults() error {
+	if w.project == nil {
+		p, err := LoadProjectFile("PROJECT")
+		if err != nil {
+			return err
+		}
+		w.project = &p
+	}
+	if w.Resource.Resource == "" {
+		w.Resource.Resource = RecordedPlural(w.project, w.Resource)
+	}
+	if w.Resource.Resource == "" {
+		w.Resource.Resource = resource.Pluralize(w.Resource.Kind)
+	}
+	return nil
+}
+
+// Scaffold scaffolds the webhooks, wires them in main.go and scaffolds the
+// tests of the defaulting and validating webhooks.
+func (w *Webhook) Scaffold() error {
+	if err := w.setDefaults(); err != nil {
+		return err
+	}
+	// the webhooks added to an existing webhook file are already wired
+	_, err := os.Stat(w.path())
+	wired := err == nil
+	if err := w.scaffoldFiles(&Scaffold{}, &model.Universe{}); err != nil {
+		return err
+	}
+	if wired {
+		return UpdateWebhookServer(w.Server)
+	}
+
+	err = (&scaffoldv2.Main{}).Update(
+		&scaffoldv2.MainUpdateOptions{
+			Project:        w.project,
+			WireResource:   false,
+			WireController: false,
+			WireWebhook:    true,
+			Resource:       w.Resource,
+		})
+	if err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+	return UpdateWebhookServer(w.Server)
+}
+
+// scaffoldFiles scaffolds the webhooks and their tests with s in the universe
+// u, everything but their wiring in main.go
+func (w *Webhook) scaffoldFiles(s *Scaffold, u *model.Universe) error {
+	r := w.Resource
+
+	webhookPath := w.path()
+	logging.Infof("%s", webhookPath)
+	if w.Conversion {
+		logging.Infof(`Webhook server has been set up for you.
+You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
+	}
+	webhookScaffolder := &webhook.Webhook{
+		Resource:   r,
+		Defaulting: w.Defaulting,
+		Validating: w.Validating,
+		Settings:   w.Settings,
+	}
+	// the webhook file may already exist with other webhooks of the
+	// resource, e.g. the conversion webhook of the hub version of a kind
+	if existing, err := ioutil.ReadFile(webhookPath); err == nil && (w.Defaulting || w.Validating) {
+		if err := webhookScaffolder.AddTo(webhookPath, existing); err != nil {
+			return err
+		}
+	}
+	if err := s.Execute(u, input.Options{}, webhookScaffolder); err != nil {
+		return fmt.Errorf("error scaffolding webhook: %v", err)
+	}
+	if err := WebhookSettingsPatch(r, w.Settings, w.Defaulting, w.Validating); err != nil {
+		return err
+	}
+
+	if w.Defaulting {
+		if _, err := os.Stat(webhookScaffolder.TypesPath()); err == nil {
+			if err := webhookScaffolder.UpdateTypes(); err != nil {
+				return fmt.Errorf("error adding default markers to %s: %v", webhookScaffolder.TypesPath(), err)
+			}
+		}
+	}
+
+	if w.Defaulting || w.Validating {
+		return WebhookTests(r, w.Defaulting, w.Validating)
+	}
+	return nil
+}
+
+// path returns the path of the webhook file of the resource
+func (w *Webhook) path() string {
+	return filepath.Join("api", w.Resource.Version, fmt.Sprintf("%s_webhook.go", strings.ToLower(w.Resource.Kind)))
+}
+
+// WebhookSettingsPatch scaffolds the patch setting the side effects and
+// timeout of the defaulting and validating webhooks of the resource in
+// config/webhook, if any of them is set.
+func WebhookSettingsPatch(r *resource.Resource, settings webhook.Settings, defaulting, validating bool) error {
+	if !settings.NeedsPatch() {
+		return nil
+	}
+	patch := &webhook.SettingsPatch{
+		Resource:   r,
+		Settings:   settings,
+		Defaulting: defaulting,
+		Validating: validating,
+	}
+	logging.Infof("%s", filepath.Join("config", "webhook", patch.FileName()))
+	if err := (&Scaffold{}).Execute(&model.Universe{}, input.Options{}, patch); err != nil {
+		return fmt.Errorf("error scaffolding the webhook settings patch: %v", err)
+	}
+	if err := patch.AddToKustomization(); err != nil {
+		return fmt.Errorf("error adding the webhook settings patch to config/webhook/kustomization.yaml: %v", err)
+	}
+	return nil
+}
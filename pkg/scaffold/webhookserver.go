@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/networkpolicy"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var (
+	mainPortRegexp    = regexp.MustCompile(`(?m)^([ \t]+Port:[ \t]+)(\d+),$`)
+	mainCertDirRegexp = regexp.MustCompile(`(?m)^[ \t]+CertDir:[ \t]+"([^"]*)",\n`)
+	namespaceRegexp   = regexp.MustCompile(`(?m)^#? ?namespace: .*$`)
+)
+
+var (
+	managerWebhookPatchPath = filepath.Join("config", "default", "manager_webhook_patch.yaml")
+	defaultKustomization    = filepath.Join("config", "default", "kustomization.yaml")
+	managerPolicyPath       = filepath.Join(networkpolicy.Dir, "allow-manager-traffic.yaml")
+)
+
+// UpdateWebhookServer changes the settings of the webhook server of the
+// manager and of its Service in an initialized project. The port is changed in
+// main.go, the manager webhook patch, the network policy and the ServicePatch
+// of the webhook Service, the certificate directory in main.go and the manager
+// webhook patch. The Service is renamed by the ServicePatch, and its namespace
+// is the namespace of the manager set in config/default.
+func UpdateWebhookServer(s webhook.Server) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if !s.IsSet() {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile("main.go")
+	if err != nil {
+		return err
+	}
+	main := b
+	ports := mainPortRegexp.FindAllSubmatch(main, -1)
+	if len(ports) != 1 {
+		return fmt.Errorf("cannot find the port of the webhook server in the options of the manager in main.go")
+	}
+	port, _ := strconv.Atoi(string(ports[0][2]))
+	certDir := webhook.DefaultCertDir
+	if m := mainCertDirRegexp.FindSubmatch(main); m != nil {
+		certDir = string(m[1])
+	}
+	patchPort, serviceName, err := webhook.ReadServicePatch(webhook.ServicePatchPath)
+	if err != nil {
+		return err
+	}
+	if patchPort == 0 {
+		patchPort = port
+	}
+
+	if s.Port != 0 && s.Port != port {
+		err := replaceInFile(managerWebhookPatchPath, regexp.MustCompile(
+			fmt.Sprintf(`(?m)^([ \t]+- containerPort: )%d$`, port)), fmt.Sprintf("${1}%d", s.Port))
+		if err != nil {
+			return err
+		}
+		// the ingress of the webhook server is the first one of the policy
+		if _, err := os.Stat(managerPolicyPath); err == nil {
+			err := replaceInFile(managerPolicyPath, regexp.MustCompile(
+				fmt.Sprintf(`(# webhook server\n[ \t]+- ports:\n[ \t]+- port: )%d\n`, port)),
+				fmt.Sprintf("${1}%d\n", s.Port))
+			if err != nil {
+				return err
+			}
+		}
+		main = mainPortRegexp.ReplaceAll(main, []byte(fmt.Sprintf("${1}%d,", s.Port)))
+		patchPort = s.Port
+	}
+
+	if s.CertDir != "" && s.CertDir != certDir {
+		b, err := ioutil.ReadFile(managerWebhookPatchPath) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		b = []byte(strings.Replace(string(b), certDir, s.CertDir, -1))
+		if err := ioutil.WriteFile(managerWebhookPatchPath, b, 0644); err != nil {
+			return err
+		}
+		// main.go only sets the certificate directory if it is not the
+		// controller-runtime default
+		main = mainCertDirRegexp.ReplaceAll(main, nil)
+		if s.CertDir != webhook.DefaultCertDir {
+			main = mainPortRegexp.ReplaceAll(main, []byte(fmt.Sprintf("$0\n\t\tCertDir: %q,", s.CertDir)))
+		}
+	}
+
+	if !bytes.Equal(main, b) {
+		formatted, err := format.Source(main)
+		if err != nil {
+			return fmt.Errorf("error formatting main.go: %v", err)
+		}
+		if err := ioutil.WriteFile("main.go", formatted, 0644); err != nil {
+			return err
+		}
+	}
+
+	if s.ServiceName != "" && s.ServiceName != serviceName {
+		b, err := ioutil.ReadFile(managerWebhookPatchPath) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		// the webhook-bootstrap init container signs the certificate for the
+		// prefixed name of the Service
+		if bytes.Contains(b, []byte("name: SERVICE_NAME")) {
+			err := replaceInFile(managerWebhookPatchPath, regexp.MustCompile(
+				fmt.Sprintf(`(name: SERVICE_NAME\n[ \t]+value: \S+-)%s\n`, regexp.QuoteMeta(serviceName))),
+				fmt.Sprintf("${1}%s\n", s.ServiceName))
+			if err != nil {
+				return err
+			}
+		}
+		serviceName = s.ServiceName
+	}
+
+	if s.Port != 0 || s.ServiceName != "" {
+		patch := &webhook.ServicePatch{Port: patchPort, Name: serviceName}
+		logging.Infof("%s", webhook.ServicePatchPath)
+		if err := (&Scaffold{}).Execute(&model.Universe{}, input.Options{}, patch); err != nil {
+			return fmt.Errorf("error scaffolding the webhook service patch: %v", err)
+		}
+		if err := patch.AddToKustomization(); err != nil {
+			return fmt.Errorf("error adding the webhook service patch to config/webhook/kustomization.yaml: %v", err)
+		}
+	}
+
+	if s.ServiceNamespace != "" {
+		b, err := ioutil.ReadFile(defaultKustomization) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		namespace := "namespace: " + s.ServiceNamespace
+		// the namespace may be commented out
+		if loc := namespaceRegexp.FindIndex(b); loc != nil {
+			b = append(append(append([]byte{}, b[:loc[0]]...), namespace...), b[loc[1]:]...)
+		} else {
+			b = append([]byte(namespace+"\n\n"), b...)
+		}
+		if err := ioutil.WriteFile(defaultKustomization, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceInFile replaces the matches of re in the file at path, which must
+// have one.
+func replaceInFile(path string, re *regexp.Regexp, repl string) error {
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	if !re.Match(b) {
+		return fmt.Errorf("cannot find %s in %s", re, path)
+	}
+	return ioutil.WriteFile(path, re.ReplaceAll(b, []byte(repl)), 0644)
+}
@@ -0,0 +1,116 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+var _ = Describe("UpdateWebhookServer", func() {
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-webhook-server")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(dir, "proj"), 0750)).To(Succeed())
+		Expect(os.Chdir(filepath.Join(dir, "proj"))).To(Succeed())
+
+		p := &V2Project{
+			Project: project.Project{ProjectFile: input.ProjectFile{
+				Version: project.Version2,
+				Domain:  "example.com",
+				Repo:    "example.com/proj",
+			}},
+			Boilerplate:    project.Boilerplate{License: "none"},
+			CertSource:     webhook.CertSourceBootstrap,
+			SecureDefaults: true,
+		}
+		Expect(p.Validate()).To(Succeed())
+		Expect(p.Scaffold()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	read := func(path ...string) string {
+		b, err := ioutil.ReadFile(filepath.Join(path...))
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("should change the port and certificate directory of the manager", func() {
+		Expect(UpdateWebhookServer(webhook.Server{Port: 9444, CertDir: "/certs"})).To(Succeed())
+
+		main := read("main.go")
+		Expect(main).To(ContainSubstring("Port:               9444,\n"))
+		Expect(main).To(ContainSubstring(`CertDir:            "/certs",`))
+		patch := read("config", "default", "manager_webhook_patch.yaml")
+		Expect(patch).To(ContainSubstring("- containerPort: 9444\n"))
+		Expect(patch).To(ContainSubstring("- mountPath: /certs\n"))
+		Expect(patch).NotTo(ContainSubstring(webhook.DefaultCertDir))
+		Expect(read("config", "network-policy", "allow-manager-traffic.yaml")).To(ContainSubstring("- port: 9444\n"))
+
+		port, name, err := webhook.ReadServicePatch(webhook.ServicePatchPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(port).To(Equal(9444))
+		Expect(name).To(Equal(webhook.ServiceName))
+		Expect(read("config", "webhook", "service.yaml")).To(ContainSubstring("targetPort: 9443\n"))
+
+		By("dropping the certificate directory of main.go when it is the default again")
+		Expect(UpdateWebhookServer(webhook.Server{CertDir: webhook.DefaultCertDir})).To(Succeed())
+		Expect(read("main.go")).NotTo(ContainSubstring("CertDir"))
+		Expect(read("config", "default", "manager_webhook_patch.yaml")).NotTo(ContainSubstring("/certs"))
+	})
+
+	It("should rename the service with a patch and keep its port", func() {
+		Expect(UpdateWebhookServer(webhook.Server{Port: 9444})).To(Succeed())
+		Expect(UpdateWebhookServer(webhook.Server{ServiceName: "proj-webhooks"})).To(Succeed())
+		Expect(UpdateWebhookServer(webhook.Server{ServiceName: "proj-webhooks"})).To(Succeed())
+
+		port, name, err := webhook.ReadServicePatch(webhook.ServicePatchPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(port).To(Equal(9444))
+		Expect(name).To(Equal("proj-webhooks"))
+		Expect(read("config", "default", "manager_webhook_patch.yaml")).To(
+			ContainSubstring("- name: SERVICE_NAME\n          value: proj-proj-webhooks\n"))
+		Expect(read("config", "webhook", "kustomization.yaml")).To(HaveSuffix(`configurations:
+- kustomizeconfig.yaml
+
+patchesJson6902:
+- target:
+    version: v1
+    kind: Service
+    name: webhook-service
+  path: service_patch.yaml
+`))
+	})
+
+	It("should set the namespace of config/default", func() {
+		Expect(UpdateWebhookServer(webhook.Server{ServiceNamespace: "webhooks"})).To(Succeed())
+
+		kustomization := read("config", "default", "kustomization.yaml")
+		Expect(kustomization).To(HavePrefix("# Adds namespace to all resources.\nnamespace: webhooks\n"))
+		Expect(kustomization).To(ContainSubstring("\nnamePrefix: proj-\n"))
+		_, err := os.Stat(webhook.ServicePatchPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should reject invalid settings", func() {
+		Expect(UpdateWebhookServer(webhook.Server{Port: 8443})).NotTo(Succeed())
+		Expect(UpdateWebhookServer(webhook.Server{CertDir: "certs"})).NotTo(Succeed())
+		Expect(UpdateWebhookServer(webhook.Server{ServiceName: "Webhooks"})).NotTo(Succeed())
+		Expect(UpdateWebhookServer(webhook.Server{ServiceNamespace: "web_hooks"})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// WebhookTests scaffolds the tests of the defaulting and validating webhooks
+// of the resource, and the envtest suite running the webhooks of its version
+// if it does not exist yet. The validating webhook also gets a fuzz test, run
+// by the test-fuzz target added to the Makefile.
+func WebhookTests(r *resource.Resource, defaulting, validating bool) error {
+	w := &webhook.Webhook{Resource: r}
+	suite := &webhook.SuiteTest{Resource: r, EnvtestAssets: envtestEnabled()}
+	test := &webhook.WebhookTest{
+		Resource:   r,
+		Namespaced: !clusterScoped(w.TypesPath()),
+		Defaulting: defaulting,
+		Validating: validating,
+	}
+	files := []input.File{suite, test}
+	if validating {
+		files = append(files, &webhook.FuzzTest{Resource: r})
+	}
+	if err := (&Scaffold{}).Execute(&model.Universe{}, input.Options{}, files...); err != nil {
+		return fmt.Errorf("error scaffolding webhook tests: %v", err)
+	}
+	if err := suite.Update(); err != nil {
+		return fmt.Errorf("error updating %s: %v", suite.Path, err)
+	}
+	if validating {
+		if err := webhook.AddFuzzTarget("Makefile"); err != nil {
+			return fmt.Errorf("error adding the test-fuzz target to the Makefile: %v", err)
+		}
+	}
+	return nil
+}
+
+// clusterScoped returns true if the types file at path marks the resource as
+// cluster scoped.
+func clusterScoped(path string) bool {
+	b, err := ioutil.ReadFile(path)
+	return err == nil && bytes.Contains(b, []byte("scope=Cluster"))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffold
+
+import (
+	"fmt"
+	"go/token"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	scaffoldv2 "sigs.k8s.io/kubebuilder/pkg/scaffold/v2"
+)
+
+var (
+	apiVersionRegexp = regexp.MustCompile(`^v[1-9][0-9]*((alpha|beta)[1-9][0-9]*)?$`)
+	nonAlnumRegexp   = regexp.MustCompile(`[^a-z0-9]`)
+)
+
+// DefaultSchemeAlias returns the alias an API package is imported as in
+// main.go by default: the name of its group and its version, like the APIs of
+// the project, e.g. certmanagerv1alpha2 for
+// github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2.
+func DefaultSchemeAlias(pkg string) string {
+	pkg = strings.TrimSuffix(pkg, "/")
+	alias := path.Base(pkg)
+	if apiVersionRegexp.MatchString(alias) {
+		alias = path.Base(path.Dir(pkg)) + alias
+	}
+	return nonAlnumRegexp.ReplaceAllString(strings.ToLower(alias), "")
+}
+
+// ValidateExternalScheme validates the API package and its alias
+func ValidateExternalScheme(s input.ExternalScheme) error {
+	if s.Package == "" || strings.ContainsAny(s.Package, " \t\"\\") ||
+		strings.HasPrefix(s.Package, "/") || strings.HasPrefix(s.Package, ".") {
+		return fmt.Errorf("invalid package %q, it must be a Go import path", s.Package)
+	}
+	if !token.IsIdentifier(s.Alias) || s.Alias == "_" {
+		return fmt.Errorf("invalid alias %q, it must be a Go identifier", s.Alias)
+	}
+	return nil
+}
+
+// WireScheme adds the types of an API package of another module to the scheme
+// of the manager in main.go, and records the package in the project file so
+// that it is wired again when the project is regenerated. The alias defaults
+// to DefaultSchemeAlias. Wiring a package again does nothing, but a package
+// is not wired under two aliases, nor two packages under the same alias.
+func WireScheme(s input.ExternalScheme) error {
+	if s.Alias == "" {
+		s.Alias = DefaultSchemeAlias(s.Package)
+	}
+	if err := ValidateExternalScheme(s); err != nil {
+		return err
+	}
+
+	p, err := LoadProjectFile("PROJECT")
+	if err != nil {
+		return err
+	}
+	for _, wired := range p.ExternalSchemes {
+		if wired.Alias == s.Alias && wired.Package != s.Package {
+			return fmt.Errorf("alias %s is already used by %s, set another alias", s.Alias, wired.Package)
+		}
+		if wired.Package == s.Package && wired.Alias != s.Alias {
+			return fmt.Errorf("package %s is already wired as %s", s.Package, wired.Alias)
+		}
+	}
+
+	if err := checkMainAlias("main.go", s); err != nil {
+		return err
+	}
+	if err := (&scaffoldv2.Main{}).AddScheme(s); err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	_, err = updateProjectFile("PROJECT", func(p *input.ProjectFile) {
+		for _, wired := range p.ExternalSchemes {
+			if wired == s {
+				return
+			}
+		}
+		p.ExternalSchemes = append(p.ExternalSchemes, s)
+	})
+	return err
+}
+
+// mainIdentifiers are the names declared or imported by the scaffolded main.go
+var mainIdentifiers = map[string]bool{
+	"ctrl": true, "clientgoscheme": true, "runtime": true, "scheme": true, "setupLog": true,
+	"os": true, "flag": true, "controllers": true, "webhook": true, "healthz": true,
+}
+
+// checkMainAlias checks that the alias is not already used in main.go by
+// another import or declaration.
+func checkMainAlias(path string, s input.ExternalScheme) error {
+	if mainIdentifiers[s.Alias] {
+		return fmt.Errorf("alias %s is used by main.go, set another alias", s.Alias)
+	}
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	prefix := s.Alias + ` "`
+	for _, l := range strings.Split(string(b), "\n") {
+		l = strings.TrimSpace(l)
+		if strings.HasPrefix(l, prefix) && l != fmt.Sprintf(`%s "%s"`, s.Alias, s.Package) {
+			return fmt.Errorf("alias %s is already imported in main.go, set another alias", s.Alias)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package scaffold
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+var _ = Describe("WireScheme", func() {
+	const certManager = "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1alpha2"
+
+	var dir, wd string
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-wire")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile("PROJECT", []byte("version: \"2\"\nrepo: example.com/proj\n"), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile("main.go", []byte(`package main
+
+import (
+	"os"
+	// +kubebuilder:scaffold:imports
+)
+
+func init() {
+	// +kubebuilder:scaffold:scheme
+}
+`), 0600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should derive the alias from the group and version of the package", func() {
+		Expect(DefaultSchemeAlias(certManager)).To(Equal("certmanagerv1alpha2"))
+		Expect(DefaultSchemeAlias("k8s.io/api/apps/v1")).To(Equal("appsv1"))
+		Expect(DefaultSchemeAlias("example.com/my-operator/api")).To(Equal("api"))
+	})
+
+	It("should add the package to main.go and the project file once", func() {
+		Expect(WireScheme(input.ExternalScheme{Package: certManager})).To(Succeed())
+		Expect(WireScheme(input.ExternalScheme{Package: certManager})).To(Succeed())
+
+		b, err := ioutil.ReadFile(filepath.Join(dir, "main.go"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring(`certmanagerv1alpha2 "` + certManager + `"`))
+		Expect(string(b)).To(ContainSubstring("_ = certmanagerv1alpha2.AddToScheme(scheme)"))
+
+		p, err := LoadProjectFile("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.ExternalSchemes).To(Equal([]input.ExternalScheme{{Package: certManager, Alias: "certmanagerv1alpha2"}}))
+	})
+
+	It("should reject aliases which are already used", func() {
+		Expect(WireScheme(input.ExternalScheme{Package: "k8s.io/api/apps/v1"})).To(Succeed())
+		Expect(WireScheme(input.ExternalScheme{Package: "example.com/apps/v1"})).NotTo(Succeed())
+		Expect(WireScheme(input.ExternalScheme{Package: "k8s.io/api/apps/v1", Alias: "k8sappsv1"})).NotTo(Succeed())
+		Expect(WireScheme(input.ExternalScheme{Package: "example.com/apps/v1", Alias: "os"})).NotTo(Succeed())
+		Expect(WireScheme(input.ExternalScheme{Package: "example.com/apps/v1", Alias: "not-valid"})).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scaffolder is the Go API of the kubebuilder scaffolding, for the
+// programs embedding kubebuilder, e.g. IDEs, web UIs or operator hubs.
+//
+// InitProject, CreateAPI and CreateWebhook scaffold like the init, create api
+// and create webhook commands, without the command line: nothing is prompted,
+// make and the hooks of the PROJECT file are not run, and the errors are
+// returned. Only project version 2 is supported.
+//
+// The project is scaffolded in the directory of its Target, either on the
+// filesystem of the OS or on the injected afero.Fs of the Target. The
+// scaffolding uses the working directory of the process, so the calls change
+// it while they run and do not run concurrently. The output of the
+// scaffolding goes through pkg/logging, see logging.SetOutput.
+package scaffolder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/project"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/v2/webhook"
+)
+
+// Target is where a project is scaffolded
+type Target struct {
+	// Dir is the directory of the project, the working directory if empty.
+	// Its name is the name of the project.
+	Dir string
+
+	// Fs is the filesystem the project is read from and written to, the
+	// filesystem of the OS if nil. The project is scaffolded in a temporary
+	// directory, and only written to Fs if the scaffolding succeeds.
+	Fs afero.Fs
+}
+
+// InitOptions are the options of InitProject
+type InitOptions struct {
+	Target
+
+	// Domain is the domain of the API groups, e.g. my.domain
+	Domain string
+
+	// Repo is the Go module of the project, e.g. github.com/user/repo
+	Repo string
+
+	// License is the license of the boilerplate, apache2 or none, defaults
+	// to apache2
+	License string
+
+	// Owner is the copyright owner of the boilerplate
+	Owner string
+
+	// GoVersion is the Go release of the go directive of go.mod, defaults
+	// to scaffold.DefaultGoVersion
+	GoVersion string
+
+	// CertSource is where the webhook server certificates come from,
+	// defaults to cert-manager
+	CertSource string
+
+	// FetchDeps fetches the dependencies of the project once it is
+	// scaffolded
+	FetchDeps bool
+}
+
+// InitProject scaffolds a new project in the directory of the target.
+func InitProject(o InitOptions) error {
+	if o.Repo == "" {
+		return fmt.Errorf("the repo of the project is required")
+	}
+	if o.Domain != "" {
+		if errs := resource.IsDNS1123Subdomain(o.Domain); len(errs) > 0 {
+			return fmt.Errorf("domain %q is invalid: (%s)", o.Domain, strings.Join(errs, ", "))
+		}
+	}
+	license := o.License
+	if license == "" {
+		license = "apache2"
+	}
+	p := &scaffold.V2Project{
+		Project: project.Project{ProjectFile: input.ProjectFile{
+			Version: project.Version2,
+			Domain:  o.Domain,
+			Repo:    o.Repo,
+		}},
+		Boilerplate: project.Boilerplate{License: license, Owner: o.Owner},
+		GoVersion:   o.GoVersion,
+		CertSource:  webhook.CertSource(o.CertSource),
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	return o.run(func() error {
+		if _, err := os.Stat("PROJECT"); err == nil {
+			return fmt.Errorf("failed to initialize project: %w", scaffold.ErrProjectExists)
+		}
+		if err := p.Scaffold(); err != nil {
+			return fmt.Errorf("error scaffolding project: %v", err)
+		}
+		if o.FetchDeps {
+			_, err := p.EnsureDependencies()
+			return err
+		}
+		return nil
+	})
+}
+
+// APIOptions are the options of CreateAPI
+type APIOptions struct {
+	Target
+
+	// Group, Version and Kind are the group, version and kind of the API
+	Group   string
+	Version string
+	Kind    string
+
+	// Plural is the plural of the kind, only needed when the one computed
+	// from the kind is wrong
+	Plural string
+
+	// ClusterScoped makes the resource cluster scoped instead of namespaced
+	ClusterScoped bool
+
+	// Resource and Controller indicate whether to scaffold the types and
+	// the controller of the API
+	Resource   bool
+	Controller bool
+
+	// Fields are the fields of the Spec of the types, with the syntax of
+	// create api --field, e.g. Replicas:int32:min=1,max=10
+	Fields []string
+
+	// Plugins transform the files of the API before they are written
+	Plugins []scaffold.Plugin
+}
+
+// CreateAPI scaffolds an API in the project of the target.
+func CreateAPI(o APIOptions) error {
+	return o.run(func() error {
+		api := &scaffold.API{
+			Plugins: o.Plugins,
+			Resource: &resource.Resource{
+				Group:                      o.Group,
+				Version:                    o.Version,
+				Kind:                       o.Kind,
+				Resource:                   o.Plural,
+				Namespaced:                 !o.ClusterScoped,
+				CreateExampleReconcileBody: true,
+			},
+			DoResource:   o.Resource,
+			DoController: o.Controller,
+			Fields:       o.Fields,
+		}
+		if err := api.Validate(); err != nil {
+			return err
+		}
+		return api.Scaffold()
+	})
+}
+
+// WebhookOptions are the options of CreateWebhook
+type WebhookOptions struct {
+	Target
+
+	// Group, Version and Kind are the group, version and kind of the API of
+	// the project the webhooks are for
+	Group   string
+	Version string
+	Kind    string
+
+	// Defaulting, Validating and Conversion indicate which webhooks to
+	// scaffold, at least one of them must be set
+	Defaulting bool
+	Validating bool
+	Conversion bool
+}
+
+// CreateWebhook scaffolds the webhooks of an API in the project of the
+// target.
+func CreateWebhook(o WebhookOptions) error {
+	return o.run(func() error {
+		w := &scaffold.Webhook{
+			Resource:   &resource.Resource{Group: o.Group, Version: o.Version, Kind: o.Kind},
+			Defaulting: o.Defaulting,
+			Validating: o.Validating,
+			Conversion: o.Conversion,
+		}
+		if err := w.Validate(); err != nil {
+			return err
+		}
+		return w.Scaffold()
+	})
+}
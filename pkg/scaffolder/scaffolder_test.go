@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolder
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/pkg/logging"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/resource"
+)
+
+func TestScaffoldInFs(t *testing.T) {
+	logging.SetOutput(ioutil.Discard, ioutil.Discard)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := afero.NewMemMapFs()
+	target := Target{Dir: "/projects/guestbook", Fs: fs}
+	err = InitProject(InitOptions{Target: target, Domain: "example.org", Repo: "example.org/guestbook"})
+	if err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	err = CreateAPI(APIOptions{Target: target, Group: "webapp", Version: "v1", Kind: "Guestbook",
+		Resource: true, Controller: true, Fields: []string{"Replicas:int32:min=1"}})
+	if err != nil {
+		t.Fatalf("CreateAPI: %v", err)
+	}
+	err = CreateWebhook(WebhookOptions{Target: target, Group: "webapp", Version: "v1", Kind: "Guestbook",
+		Defaulting: true, Validating: true})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	if cwd, _ := os.Getwd(); cwd != wd {
+		t.Errorf("the working directory is %s, expected %s", cwd, wd)
+	}
+	for path, content := range map[string]string{
+		"PROJECT":                             "kind: Guestbook",
+		"config/default/kustomization.yaml":   "namePrefix: guestbook-",
+		"api/v1/guestbook_types.go":           "Replicas int32",
+		"api/v1/guestbook_webhook.go":         "webhook.Validator",
+		"controllers/guestbook_controller.go": "GuestbookReconciler",
+		"main.go":                             "SetupWebhookWithManager",
+	} {
+		b, err := afero.ReadFile(fs, "/projects/guestbook/"+path)
+		if err != nil {
+			t.Errorf("%s was not written to the filesystem: %v", path, err)
+			continue
+		}
+		if !strings.Contains(string(b), content) {
+			t.Errorf("%s does not contain %q", path, content)
+		}
+	}
+
+	// a failed call writes nothing
+	before, _ := afero.ReadFile(fs, "/projects/guestbook/PROJECT")
+	err = CreateAPI(APIOptions{Target: target, Group: "webapp", Version: "v1", Kind: "Guestbook", Resource: true})
+	if !errors.Is(err, scaffold.ErrResourceExists) {
+		t.Errorf("CreateAPI of an existing API returned %v, expected %v", err, scaffold.ErrResourceExists)
+	}
+	if after, _ := afero.ReadFile(fs, "/projects/guestbook/PROJECT"); string(after) != string(before) {
+		t.Errorf("the PROJECT file was changed by the failed call")
+	}
+
+	err = InitProject(InitOptions{Target: target, Repo: "example.org/guestbook"})
+	if !errors.Is(err, scaffold.ErrProjectExists) {
+		t.Errorf("InitProject of an existing project returned %v, expected %v", err, scaffold.ErrProjectExists)
+	}
+}
+
+func TestCreateAPIWithWebhooks(t *testing.T) {
+	logging.SetOutput(ioutil.Discard, ioutil.Discard)
+	fs := afero.NewMemMapFs()
+	target := Target{Dir: "/projects/fleet", Fs: fs}
+	if err := InitProject(InitOptions{Target: target, Domain: "example.org", Repo: "example.org/fleet"}); err != nil {
+		t.Fatalf("InitProject: %v", err)
+	}
+	err := target.run(func() error {
+		api := &scaffold.API{
+			Resource:     &resource.Resource{Group: "ship", Version: "v1", Kind: "Frigate", Namespaced: true},
+			DoResource:   true,
+			DoController: true,
+			Webhooks:     &scaffold.Webhook{Defaulting: true, Validating: true},
+		}
+		if err := api.Validate(); err != nil {
+			return err
+		}
+		return api.Scaffold()
+	})
+	if err != nil {
+		t.Fatalf("scaffolding the API with its webhooks: %v", err)
+	}
+
+	b, err := afero.ReadFile(fs, "/projects/fleet/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, setup := range []string{"(&controllers.FrigateReconciler{", "(&shipv1.Frigate{}).SetupWebhookWithManager"} {
+		if n := strings.Count(string(b), setup); n != 1 {
+			t.Errorf("main.go contains %q %d times, expected once", setup, n)
+		}
+	}
+	if exists, _ := afero.Exists(fs, "/projects/fleet/api/v1/frigate_webhook.go"); !exists {
+		t.Errorf("the webhooks were not scaffolded")
+	}
+}
+
+func TestTargetValidation(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	if err := InitProject(InitOptions{Target: Target{Fs: fs}, Repo: "example.org/guestbook"}); err == nil {
+		t.Errorf("InitProject in a filesystem without a directory succeeded")
+	}
+	if err := InitProject(InitOptions{Target: Target{Dir: "/guestbook", Fs: fs}}); err == nil {
+		t.Errorf("InitProject without a repo succeeded")
+	}
+	if err := InitProject(InitOptions{Target: Target{Dir: "/guestbook", Fs: fs}, Repo: "example.org/guestbook",
+		Domain: "Example_org"}); err == nil {
+		t.Errorf("InitProject with an invalid domain succeeded")
+	}
+}
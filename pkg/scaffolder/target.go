@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// workingDir serializes the calls, which change the working directory of
+// the process
+var workingDir sync.Mutex
+
+// run runs scaffold in the directory of the project. With an injected
+// filesystem, the project is copied to a temporary directory named after it,
+// and the files scaffolded there are written back to the filesystem.
+func (t Target) run(scaffold func() error) error {
+	workingDir.Lock()
+	defer workingDir.Unlock()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	dir := t.Dir
+	var copied map[string]bool
+	if t.Fs != nil {
+		name := filepath.Base(filepath.Clean(t.Dir))
+		if t.Dir == "" || name == "." || name == string(filepath.Separator) {
+			return fmt.Errorf("the directory of the project is required with a filesystem, its name is the " +
+				"name of the project")
+		}
+		tmp, err := ioutil.TempDir("", "kubebuilder-scaffolder")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp) // nolint: errcheck
+		dir = filepath.Join(tmp, name)
+		if copied, err = copyFromFs(t.Fs, t.Dir, dir); err != nil {
+			return fmt.Errorf("error copying the project from the filesystem: %v", err)
+		}
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := os.Chdir(dir); err != nil {
+			return err
+		}
+		defer os.Chdir(wd) // nolint: errcheck
+	}
+
+	if err := scaffold(); err != nil {
+		return err
+	}
+	if t.Fs != nil {
+		if err := copyToFs(dir, t.Fs, t.Dir, copied); err != nil {
+			return fmt.Errorf("error writing the project to the filesystem: %v", err)
+		}
+	}
+	return nil
+}
+
+// copyFromFs copies the files under root of fs to dir, and returns their
+// paths relative to root
+func copyFromFs(fs afero.Fs, root, dir string) (map[string]bool, error) {
+	copied := map[string]bool{}
+	if exists, err := afero.DirExists(fs, root); err != nil || !exists {
+		return copied, err
+	}
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		b, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		copied[rel] = true
+		return ioutil.WriteFile(target, b, info.Mode().Perm())
+	})
+	return copied, err
+}
+
+// copyToFs writes the files of dir under root of fs, and removes the copied
+// files which no longer exist in dir
+func copyToFs(dir string, fs afero.Fs, root string, copied map[string]bool) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		delete(copied, rel)
+		b, err := ioutil.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(root, rel)
+		if err := fs.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return afero.WriteFile(fs, target, b, info.Mode().Perm())
+	})
+	if err != nil {
+		return err
+	}
+	for rel := range copied {
+		if err := fs.Remove(filepath.Join(root, rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
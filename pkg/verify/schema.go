@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// junctors are the keywords whose schemas may only hold value validations in
+// a structural schema
+var junctors = []string{"allOf", "anyOf", "oneOf", "not"}
+
+// verifyCRDs checks that the schemas of the CRDs are structural, which is
+// required by the v1 CRDs and for pruning and defaulting.
+func verifyCRDs(objects []Object) []Problem {
+	var problems []Problem
+	for _, o := range objects {
+		if o.kind() != "CustomResourceDefinition" {
+			continue
+		}
+		schemas := map[string]Object{}
+		if s := o.obj("spec", "validation", "openAPIV3Schema"); s != nil {
+			schemas["spec.validation.openAPIV3Schema"] = s
+		}
+		for i, v := range o.list("spec", "versions") {
+			if s := v.obj("schema", "openAPIV3Schema"); s != nil {
+				schemas[fmt.Sprintf("spec.versions[%d].schema.openAPIV3Schema", i)] = s
+			}
+		}
+
+		paths := make([]string, 0, len(schemas))
+		for path := range schemas {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			for _, msg := range structuralErrors(schemas[path]) {
+				problems = append(problems, Problem{Object: o.id(), Message: path + msg})
+			}
+		}
+	}
+	return problems
+}
+
+// structuralErrors returns the reasons why the root schema is not structural.
+func structuralErrors(root Object) []string {
+	errs := checkSchema(root, "", false)
+
+	// metadata may only restrict the name and generateName at the root
+	if metadata := root.obj("properties", "metadata"); metadata != nil {
+		for key := range metadata {
+			if key != "type" && key != "properties" {
+				errs = append(errs, fmt.Sprintf(".properties.metadata: %s is not allowed, only the name and "+
+					"generateName can be restricted", key))
+			}
+		}
+		for field := range metadata.obj("properties") {
+			if field != "name" && field != "generateName" {
+				errs = append(errs, fmt.Sprintf(".properties.metadata.properties.%s: only the name and "+
+					"generateName can be restricted", field))
+			}
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// checkSchema checks the schema at the given path relative to the root, and
+// its sub-schemas. Within a junctor, schemas may only specify value
+// validations.
+func checkSchema(s Object, path string, inJunctor bool) []string {
+	var errs []string
+
+	if inJunctor {
+		for key := range s {
+			if key == "type" || key == "description" || key == "default" || key == "nullable" ||
+				key == "additionalProperties" || strings.HasPrefix(key, "x-kubernetes-") {
+				errs = append(errs, fmt.Sprintf("%s: %s is not allowed within %s", path, key,
+					strings.Join(junctors, ", ")))
+			}
+		}
+	} else if s.str("type") == "" && s.get("x-kubernetes-int-or-string") != true &&
+		s.get("x-kubernetes-preserve-unknown-fields") != true {
+		errs = append(errs, fmt.Sprintf("%s: type must be set", path))
+	}
+
+	if _, ok := s["$ref"]; ok {
+		errs = append(errs, fmt.Sprintf("%s: $ref is not allowed", path))
+	}
+	if s.get("properties") != nil && s.get("additionalProperties") != nil {
+		errs = append(errs, fmt.Sprintf("%s: properties and additionalProperties are mutually exclusive",
+			path))
+	}
+
+	properties := s.obj("properties")
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, checkSchema(properties.obj(name), path+".properties."+name, inJunctor)...)
+	}
+	if items := s.obj("items"); items != nil {
+		errs = append(errs, checkSchema(items, path+".items", inJunctor)...)
+	}
+	if additional := s.obj("additionalProperties"); additional != nil {
+		errs = append(errs, checkSchema(additional, path+".additionalProperties", inJunctor)...)
+	}
+
+	for _, junctor := range junctors {
+		if junctor == "not" {
+			if not := s.obj("not"); not != nil {
+				errs = append(errs, checkSchema(not, path+".not", true)...)
+			}
+			continue
+		}
+		for i, sub := range s.list(junctor) {
+			errs = append(errs, checkSchema(sub, fmt.Sprintf("%s.%s[%d]", path, junctor, i), true)...)
+		}
+	}
+	return errs
+}
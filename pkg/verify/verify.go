@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verify checks the rendered manifests of a project for problems which
+// would only show up once they are applied to a cluster.
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Problem is a problem found in the manifests
+type Problem struct {
+	// Object is the object with the problem, as kind/namespace/name
+	Object string
+
+	// Message describes the problem
+	Message string
+
+	// Warning is true if the problem may be fixed at runtime, e.g. a CA
+	// bundle injected by the manager
+	Warning bool
+}
+
+func (p Problem) String() string {
+	level := "error"
+	if p.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", level, p.Object, p.Message)
+}
+
+// Object is a manifest, as decoded from YAML
+type Object map[string]interface{}
+
+// Parse splits and decodes the YAML documents of the manifests.
+func Parse(manifests []byte) ([]Object, error) {
+	var objects []Object
+
+	doc := &bytes.Buffer{}
+	flush := func() error {
+		if strings.TrimSpace(doc.String()) == "" {
+			return nil
+		}
+		o := Object{}
+		if err := yaml.Unmarshal(doc.Bytes(), &o); err != nil {
+			return err
+		}
+		if len(o) > 0 {
+			objects = append(objects, o)
+		}
+		doc.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(manifests))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(manifests)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "---") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		fmt.Fprintln(doc, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// Verify returns the problems found in the given objects, sorted by object.
+func Verify(objects []Object) []Problem {
+	var problems []Problem
+	problems = append(problems, verifyCRDs(objects)...)
+	problems = append(problems, verifyWebhooks(objects)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Object < problems[j].Object
+	})
+	return problems
+}
+
+func (o Object) kind() string {
+	return o.str("kind")
+}
+
+func (o Object) name() string {
+	return o.str("metadata", "name")
+}
+
+func (o Object) namespace() string {
+	return o.str("metadata", "namespace")
+}
+
+// id returns kind/namespace/name, or kind/name for cluster scoped objects
+func (o Object) id() string {
+	if ns := o.namespace(); ns != "" {
+		return fmt.Sprintf("%s/%s/%s", o.kind(), ns, o.name())
+	}
+	return fmt.Sprintf("%s/%s", o.kind(), o.name())
+}
+
+// get returns the value at the given path, or nil if there is none
+func (o Object) get(path ...string) interface{} {
+	var current interface{} = map[string]interface{}(o)
+	for _, field := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[field]
+	}
+	return current
+}
+
+func (o Object) str(path ...string) string {
+	s, _ := o.get(path...).(string)
+	return s
+}
+
+func (o Object) obj(path ...string) Object {
+	m, _ := o.get(path...).(map[string]interface{})
+	return Object(m)
+}
+
+func (o Object) list(path ...string) []Object {
+	items, _ := o.get(path...).([]interface{})
+	var objects []Object
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, Object(m))
+		}
+	}
+	return objects
+}
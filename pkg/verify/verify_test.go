@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+const manifests = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  annotations:
+    cert-manager.io/inject-ca-from: project-system/project-serving-cert
+  name: captains.crew.testproject.org
+spec:
+  conversion:
+    strategy: Webhook
+    webhookClientConfig:
+      caBundle: Cg==
+      service:
+        name: project-webhook-service
+        namespace: project-system
+        path: /convert
+  validation:
+    openAPIV3Schema:
+      type: object
+      properties:
+        metadata:
+          type: object
+        spec:
+          type: object
+          properties:
+            foo:
+              type: string
+              anyOf:
+              - minLength: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: project-webhook-service
+  namespace: project-system
+spec:
+  ports:
+  - port: 443
+    targetPort: 9443
+  selector:
+    control-plane: controller-manager
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: project-controller-manager
+  namespace: project-system
+spec:
+  template:
+    metadata:
+      labels:
+        control-plane: controller-manager
+    spec:
+      containers:
+      - name: manager
+        ports:
+        - containerPort: 9443
+          name: webhook-server
+      volumes:
+      - name: cert
+        secret:
+          secretName: webhook-server-cert
+---
+apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: project-serving-cert
+  namespace: project-system
+spec:
+  dnsNames:
+  - project-webhook-service.project-system.svc
+  secretName: webhook-server-cert
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  annotations:
+    cert-manager.io/inject-ca-from: project-system/project-serving-cert
+  name: project-mutating-webhook-configuration
+webhooks:
+- clientConfig:
+    service:
+      name: project-webhook-service
+      namespace: project-system
+      path: /mutate-crew-testproject-org-v1-captain
+  name: mcaptain.kb.io
+`
+
+func verifyManifests(t *testing.T, manifests string) []Problem {
+	objects, err := Parse([]byte(manifests))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return Verify(objects)
+}
+
+func TestVerifyValid(t *testing.T) {
+	if problems := verifyManifests(t, manifests); len(problems) != 0 {
+		t.Errorf("expected no problem, got %v", problems)
+	}
+}
+
+func TestVerifyProblems(t *testing.T) {
+	for name, test := range map[string]struct {
+		old, new string
+		problem  string
+	}{
+		"missing type": {
+			old:     "            foo:\n              type: string\n",
+			new:     "            foo:\n              description: foo\n",
+			problem: "error: CustomResourceDefinition/captains.crew.testproject.org: spec.validation.openAPIV3Schema.properties.spec.properties.foo: type must be set",
+		},
+		"type in junctor": {
+			old:     "              - minLength: 1",
+			new:     "              - type: string",
+			problem: "foo.anyOf[0]: type is not allowed within allOf, anyOf, oneOf, not",
+		},
+		"restricted metadata": {
+			old:     "        metadata:\n          type: object\n",
+			new:     "        metadata:\n          type: object\n          properties:\n            labels:\n              type: object\n",
+			problem: ".properties.metadata.properties.labels: only the name and generateName can be restricted",
+		},
+		"missing service": {
+			old:     "  name: project-webhook-service\n  namespace: project-system\nspec:",
+			new:     "  name: webhook-service\n  namespace: project-system\nspec:",
+			problem: "webhooks[0] (mcaptain.kb.io) points to the service project-system/project-webhook-service which is not in the manifests",
+		},
+		"wrong target port": {
+			old:     "    targetPort: 9443",
+			new:     "    targetPort: 8443",
+			problem: "the port 443 of the service project-system/project-webhook-service of conversion webhook is not a container port of Deployment/project-system/project-controller-manager",
+		},
+		"missing certificate": {
+			old:     "  name: project-serving-cert\n",
+			new:     "  name: serving-cert\n",
+			problem: "the CA is injected from the certificate project-system/project-serving-cert which is not in the manifests",
+		},
+		"wrong certificate host": {
+			old:     "  - project-webhook-service.project-system.svc",
+			new:     "  - webhook-service.project-system.svc",
+			problem: "the certificate project-system/project-serving-cert is not valid for project-webhook-service.project-system.svc",
+		},
+		"secret not mounted": {
+			old:     "          secretName: webhook-server-cert",
+			new:     "          secretName: other-cert",
+			problem: "the secret webhook-server-cert of the certificate project-system/project-serving-cert is not mounted",
+		},
+		"no CA": {
+			old:     "    cert-manager.io/inject-ca-from: project-system/project-serving-cert\n  name: project-mutating",
+			new:     "    foo: bar\n  name: project-mutating",
+			problem: "warning: MutatingWebhookConfiguration/project-mutating-webhook-configuration: webhooks[0] (mcaptain.kb.io) has no caBundle",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if !strings.Contains(manifests, test.old) {
+				t.Fatalf("the manifests do not contain %q", test.old)
+			}
+			problems := verifyManifests(t, strings.Replace(manifests, test.old, test.new, 1))
+			for _, p := range problems {
+				if strings.Contains(p.String(), test.problem) {
+					return
+				}
+			}
+			t.Errorf("expected a problem containing %q, got %v", test.problem, problems)
+		})
+	}
+}
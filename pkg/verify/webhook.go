@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// injectCAAnnotation is the annotation of the objects whose CA bundle is
+// injected by cert-manager, set to the namespace/name of the Certificate
+const injectCAAnnotation = "cert-manager.io/inject-ca-from"
+
+// verifyWebhooks checks that the webhook configurations and conversion
+// webhooks point to a Service backed by the manager, and that their CA bundle
+// matches the certificate served by the manager.
+func verifyWebhooks(objects []Object) []Problem {
+	var problems []Problem
+	seen := map[Problem]bool{}
+	add := func(ps ...Problem) {
+		for _, p := range ps {
+			if !seen[p] {
+				seen[p] = true
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	for _, o := range objects {
+		switch o.kind() {
+		case "MutatingWebhookConfiguration", "ValidatingWebhookConfiguration":
+			for i, w := range o.list("webhooks") {
+				where := fmt.Sprintf("webhooks[%d] (%s)", i, w.str("name"))
+				add(verifyClientConfig(objects, o, where, w.obj("clientConfig"))...)
+			}
+		case "CustomResourceDefinition":
+			if o.str("spec", "conversion", "strategy") != "Webhook" {
+				continue
+			}
+			clientConfig := o.obj("spec", "conversion", "webhookClientConfig")
+			if clientConfig == nil {
+				clientConfig = o.obj("spec", "conversion", "webhook", "clientConfig")
+			}
+			add(verifyClientConfig(objects, o, "conversion webhook", clientConfig)...)
+		}
+	}
+	return problems
+}
+
+// verifyClientConfig checks the client config of a webhook of the owner.
+func verifyClientConfig(objects []Object, owner Object, where string, clientConfig Object) []Problem {
+	problem := func(warning bool, format string, args ...interface{}) []Problem {
+		return []Problem{{Object: owner.id(), Message: fmt.Sprintf(format, args...), Warning: warning}}
+	}
+
+	if clientConfig.str("url") != "" {
+		// the webhook is not served by the project
+		return nil
+	}
+	ref := clientConfig.obj("service")
+	if ref == nil {
+		return problem(false, "%s has neither a service nor a url", where)
+	}
+	svcNamespace, svcName := ref.str("namespace"), ref.str("name")
+	service := find(objects, "Service", svcNamespace, svcName)
+	if service == nil {
+		return problem(false, "%s points to the service %s/%s which is not in the manifests",
+			where, svcNamespace, svcName)
+	}
+
+	port := ref.get("port")
+	if port == nil {
+		port = 443
+	}
+
+	var problems []Problem
+	deployment := selectedDeployment(objects, service)
+	if deployment == nil {
+		problems = append(problems, problem(false, "the service %s/%s of %s does not select any Deployment",
+			svcNamespace, svcName, where)...)
+	} else if !servesPort(deployment, service, port) {
+		problems = append(problems, problem(false, "the port %v of the service %s/%s of %s is not a container "+
+			"port of %s", port, svcNamespace, svcName, where, deployment.id())...)
+	}
+
+	from := owner.str("metadata", "annotations", injectCAAnnotation)
+	if from == "" {
+		if clientConfig.str("caBundle") == "" {
+			problems = append(problems, problem(true, "%s has no caBundle and no %s annotation, "+
+				"the CA bundle must be set at runtime", where, injectCAAnnotation)...)
+		}
+		return problems
+	}
+
+	parts := strings.SplitN(from, "/", 2)
+	if len(parts) != 2 {
+		return append(problems, problem(false, "the %s annotation %q should be namespace/name",
+			injectCAAnnotation, from)...)
+	}
+	certificate := find(objects, "Certificate", parts[0], parts[1])
+	if certificate == nil {
+		return append(problems, problem(false, "the CA is injected from the certificate %s which is not in "+
+			"the manifests", from)...)
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", svcName, svcNamespace)
+	if !contains(certificate.get("spec", "dnsNames"), host) {
+		problems = append(problems, problem(false, "the certificate %s is not valid for %s, the host of %s",
+			from, host, where)...)
+	}
+	secret := certificate.str("spec", "secretName")
+	if deployment != nil && !mountsSecret(deployment, secret) {
+		problems = append(problems, problem(false, "the secret %s of the certificate %s is not mounted by %s",
+			secret, from, deployment.id())...)
+	}
+	return problems
+}
+
+// find returns the object of the given kind, namespace and name, if any
+func find(objects []Object, kind, namespace, name string) Object {
+	for _, o := range objects {
+		if o.kind() == kind && o.namespace() == namespace && o.name() == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// selectedDeployment returns the Deployment whose pods are selected by the
+// service, if any
+func selectedDeployment(objects []Object, service Object) Object {
+	selector := service.obj("spec", "selector")
+	if len(selector) == 0 {
+		return nil
+	}
+	for _, o := range objects {
+		if o.kind() != "Deployment" || o.namespace() != service.namespace() {
+			continue
+		}
+		labels := o.obj("spec", "template", "metadata", "labels")
+		matches := true
+		for k, v := range selector {
+			if labels[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return o
+		}
+	}
+	return nil
+}
+
+// servesPort returns true if the given port of the service targets a
+// container port of the deployment.
+func servesPort(deployment, service Object, port interface{}) bool {
+	for _, p := range service.list("spec", "ports") {
+		if fmt.Sprint(p.get("port")) != fmt.Sprint(port) {
+			continue
+		}
+		target := p.get("targetPort")
+		if target == nil {
+			target = p.get("port")
+		}
+		for _, c := range deployment.list("spec", "template", "spec", "containers") {
+			for _, cp := range c.list("ports") {
+				if fmt.Sprint(cp.get("containerPort")) == fmt.Sprint(target) || cp.str("name") == fmt.Sprint(target) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// mountsSecret returns true if the deployment has a volume for the secret
+func mountsSecret(deployment Object, secret string) bool {
+	for _, v := range deployment.list("spec", "template", "spec", "volumes") {
+		if v.str("secret", "secretName") == secret {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list interface{}, s string) bool {
+	items, _ := list.([]interface{})
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
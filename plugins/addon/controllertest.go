@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// ChannelsTest adds a test checking that each version of the stable channel
+// has a manifest for the declarative reconciler, when the controller is
+// scaffolded.
+func ChannelsTest(u *model.Universe) error {
+	kind := strings.ToLower(u.Resource.Kind)
+	controllerPath := filepath.Join("controllers", kind+"_controller.go")
+	if !hasFile(u, controllerPath) {
+		return nil
+	}
+
+	data := struct {
+		*model.Universe
+		PackageName string
+	}{u, getPackageName(u)}
+	contents, err := RunTemplate("channels-test", channelsTestTemplate, data, DefaultTemplateFunctions())
+	if err != nil {
+		return err
+	}
+
+	_, err = AddFile(u, &model.File{
+		Path:           filepath.Join("controllers", kind+"_channels_test.go"),
+		Contents:       contents,
+		IfExistsAction: input.Skip,
+	})
+	return err
+}
+
+// hasFile returns true if the universe has a file at path
+func hasFile(u *model.Universe, path string) bool {
+	for _, f := range u.Files {
+		if f.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+const channelsTestTemplate = `{{ .Boilerplate }}
+
+package controllers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+// channel is a channel of the declarative pattern, which lists the versions
+// of the manifests of the addon
+type channel struct {
+	Manifests []struct {
+		Version string ` + "`" + `json:"version"` + "`" + `
+	} ` + "`" + `json:"manifests"` + "`" + `
+}
+
+var _ = Describe("{{ .Resource.Kind }} channels", func() {
+	// the declarative reconciler loads the channels from the working
+	// directory of the manager, which is the root of the project
+	channels := filepath.Join("..", "channels")
+
+	It("should have a manifest for each version of the stable channel", func() {
+		b, err := ioutil.ReadFile(filepath.Join(channels, "stable"))
+		Expect(err).NotTo(HaveOccurred())
+
+		stable := channel{}
+		Expect(yaml.Unmarshal(b, &stable)).To(Succeed())
+		Expect(stable.Manifests).NotTo(BeEmpty())
+		for _, m := range stable.Manifests {
+			path := filepath.Join(channels, "packages", "{{ .PackageName }}", m.Version, "manifest.yaml")
+			_, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred(), "version %s of the stable channel has no manifest", m.Version)
+		}
+	})
+})
+`
@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/util"
+)
+
+const packageChannelsTarget = "package-channels"
+
+const packageChannelsMakefile = `
+package-channels: ## Package the channels and the manifests of the addon, e.g. to publish them
+	mkdir -p bin
+	tar -czf bin/channels.tar.gz channels
+`
+
+// managerCopyLine is the line of the Dockerfile copying the manager binary in
+// the final image
+const managerCopyLine = "COPY --from=builder /workspace/manager .\n"
+
+const channelsCopy = `# The channels and the manifests of the addon, loaded by the declarative
+# reconciler from the working directory
+COPY channels/ channels/
+`
+
+// PackageChannels adds the package-channels target to the Makefile, and copies
+// the channels in the manager image built by the Dockerfile, unless they
+// already do.
+func PackageChannels() error {
+	if _, err := os.Stat("Makefile"); err == nil {
+		if err := util.AddMakefileTarget("Makefile", packageChannelsTarget, packageChannelsMakefile); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return updateFile("Dockerfile", func(content string) string {
+		if strings.Contains(content, "COPY channels/") {
+			return content
+		}
+		return strings.Replace(content, managerCopyLine, managerCopyLine+channelsCopy, 1)
+	})
+}
+
+// updateFile replaces the content of the file at path by the result of update,
+// if the file exists.
+func updateFile(path string, update func(string) string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	updated := update(string(b))
+	if updated == string(b) {
+		return nil
+	}
+	return ioutil.WriteFile(path, []byte(updated), info.Mode())
+}
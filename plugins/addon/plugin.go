@@ -2,17 +2,34 @@ package addon
 
 import (
 	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold"
 )
 
+// PluginKey is the key of the addon plugin in the plugins section of the
+// PROJECT file, recorded when an API is created with the addon pattern
+const PluginKey = "addon.kubebuilder.io"
+
 type Plugin struct {
 }
 
+// Pattern returns the addon pattern of create api --pattern
+func Pattern() scaffold.Pattern {
+	return scaffold.Pattern{
+		Name: "addon",
+		Key:  PluginKey,
+		NewPlugins: func() []scaffold.Plugin {
+			return []scaffold.Plugin{&Plugin{}}
+		},
+	}
+}
+
 func (p *Plugin) Pipe(u *model.Universe) error {
 	functions := []PluginFunc{
 		ExampleManifest,
 		ExampleChannel,
 		ReplaceController,
 		ReplaceTypes,
+		ChannelsTest,
 	}
 
 	for _, fn := range functions {
@@ -24,3 +41,8 @@ func (p *Plugin) Pipe(u *model.Universe) error {
 
 	return nil
 }
+
+// PostScaffold implements scaffold.PostScaffolder
+func (p *Plugin) PostScaffold(u *model.Universe) error {
+	return PackageChannels()
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientstub
+
+const typeScriptTemplate = `// {{ .Kind }} is the {{ .GroupDomain }}/{{ .Version }} {{ .Kind }} resource.
+// EDIT THIS FILE! It was scaffolded from the initial {{ .Kind }} types, keep it
+// in sync with api/{{ .Version }}/{{ lower .Kind }}_types.go and openapi.json.
+
+export const apiVersion = "{{ .GroupDomain }}/{{ .Version }}";
+export const kind = "{{ .Kind }}";
+export const plural = "{{ .Plural }}";
+
+// {{ .Kind }}Spec defines the desired state of {{ .Kind }}
+export interface {{ .Kind }}Spec {
+  // Foo is an example field of {{ .Kind }}
+  foo?: string;
+}
+
+// {{ .Kind }}Status defines the observed state of {{ .Kind }}
+export interface {{ .Kind }}Status {}
+
+// {{ .Kind }} is the Schema for the {{ .Plural }} API
+export interface {{ .Kind }} {
+  apiVersion: typeof apiVersion;
+  kind: typeof kind;
+  metadata?: { [key: string]: unknown };
+  spec?: {{ .Kind }}Spec;
+  status?: {{ .Kind }}Status;
+}
+`
+
+const pythonTemplate = `# {{ .Kind }} is the {{ .GroupDomain }}/{{ .Version }} {{ .Kind }} resource.
+# EDIT THIS FILE! It was scaffolded from the initial {{ .Kind }} types, keep it
+# in sync with api/{{ .Version }}/{{ lower .Kind }}_types.go and openapi.json.
+
+from dataclasses import dataclass, field
+from typing import Any, Dict, Optional
+
+API_VERSION = "{{ .GroupDomain }}/{{ .Version }}"
+KIND = "{{ .Kind }}"
+PLURAL = "{{ .Plural }}"
+
+
+@dataclass
+class {{ .Kind }}Spec:
+    """{{ .Kind }}Spec defines the desired state of {{ .Kind }}"""
+
+    # Foo is an example field of {{ .Kind }}
+    foo: Optional[str] = None
+
+
+@dataclass
+class {{ .Kind }}Status:
+    """{{ .Kind }}Status defines the observed state of {{ .Kind }}"""
+
+
+@dataclass
+class {{ .Kind }}:
+    """{{ .Kind }} is the Schema for the {{ .Plural }} API"""
+
+    metadata: Dict[str, Any] = field(default_factory=dict)
+    spec: {{ .Kind }}Spec = field(default_factory={{ .Kind }}Spec)
+    status: {{ .Kind }}Status = field(default_factory={{ .Kind }}Status)
+
+    def to_dict(self) -> Dict[str, Any]:
+        """Returns the object as sent to the Kubernetes API."""
+        return {
+            "apiVersion": API_VERSION,
+            "kind": KIND,
+            "metadata": self.metadata,
+            "spec": {k: v for k, v in vars(self.spec).items() if v is not None},
+        }
+`
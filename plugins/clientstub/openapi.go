@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clientstub
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+)
+
+type schema map[string]interface{}
+
+// openAPISchema returns the OpenAPI v3 document of the scaffolded types of
+// the resource.
+func openAPISchema(r *model.Resource) (string, error) {
+	apiVersion := r.GroupDomain + "/" + r.Version
+	ref := func(name string) schema {
+		return schema{"$ref": "#/components/schemas/" + name}
+	}
+
+	doc := schema{
+		"openapi": "3.0.0",
+		"info": schema{
+			"title":   r.Kind + " " + apiVersion,
+			"version": r.Version,
+		},
+		"paths": schema{},
+		"components": schema{
+			"schemas": schema{
+				r.Kind: schema{
+					"description": r.Kind + " is the Schema for the " + r.Plural + " API",
+					"type":        "object",
+					"properties": schema{
+						"apiVersion": schema{"type": "string", "enum": []string{apiVersion}},
+						"kind":       schema{"type": "string", "enum": []string{r.Kind}},
+						"metadata":   schema{"type": "object"},
+						"spec":       ref(r.Kind + "Spec"),
+						"status":     ref(r.Kind + "Status"),
+					},
+					"x-kubernetes-group-version-kind": []schema{
+						{"group": r.GroupDomain, "version": r.Version, "kind": r.Kind},
+					},
+				},
+				r.Kind + "Spec": schema{
+					"description": r.Kind + "Spec defines the desired state of " + r.Kind,
+					"type":        "object",
+					"properties": schema{
+						"foo": schema{
+							"description": "Foo is an example field of " + r.Kind,
+							"type":        "string",
+						},
+					},
+				},
+				r.Kind + "Status": schema{
+					"description": r.Kind + "Status defines the observed state of " + r.Kind,
+					"type":        "object",
+				},
+			},
+		},
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clientstub is a plugin which scaffolds client stubs of the API for
+// non-Go consumers: the OpenAPI v3 schema of the kind and a typed model.
+package clientstub
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/scaffold/input"
+)
+
+// Language is the language of the typed model of a client stub
+type Language string
+
+const (
+	// TypeScript scaffolds TypeScript interfaces
+	TypeScript Language = "typescript"
+
+	// Python scaffolds Python dataclasses
+	Python Language = "python"
+)
+
+// Validate validates the Language
+func (l Language) Validate() error {
+	switch l {
+	case TypeScript, Python:
+		return nil
+	}
+	return fmt.Errorf("unknown client stub language %q, should be one of %s, %s", l, TypeScript, Python)
+}
+
+// Dir is the directory holding the client stubs of a project
+const Dir = "clients"
+
+// Plugin scaffolds the client stub of the resource under
+// clients/<group>/<version>/<kind>, when the API types are scaffolded.
+type Plugin struct {
+	Language Language
+}
+
+// Pipe implements scaffold.Plugin
+func (p *Plugin) Pipe(u *model.Universe) error {
+	if err := p.Language.Validate(); err != nil {
+		return err
+	}
+	if u.Resource == nil || !scaffoldsTypes(u) {
+		return nil
+	}
+
+	dir := filepath.Join(Dir, u.Resource.Group, u.Resource.Version, strings.ToLower(u.Resource.Kind))
+
+	schema, err := openAPISchema(u.Resource)
+	if err != nil {
+		return err
+	}
+	u.Files = append(u.Files, &model.File{
+		Path:           filepath.Join(dir, "openapi.json"),
+		Contents:       schema,
+		IfExistsAction: input.Skip,
+	})
+
+	name, body := "model.ts", typeScriptTemplate
+	if p.Language == Python {
+		name, body = "model.py", pythonTemplate
+	}
+	contents, err := runTemplate(name, body, u.Resource)
+	if err != nil {
+		return err
+	}
+	u.Files = append(u.Files, &model.File{
+		Path:           filepath.Join(dir, name),
+		Contents:       contents,
+		IfExistsAction: input.Skip,
+	})
+	return nil
+}
+
+// scaffoldsTypes returns true if the API types of the resource are part of
+// the files being scaffolded
+func scaffoldsTypes(u *model.Universe) bool {
+	types := filepath.Join("api", u.Resource.Version, strings.ToLower(u.Resource.Kind)+"_types.go")
+	for _, f := range u.Files {
+		if f.Path == types {
+			return true
+		}
+	}
+	return false
+}
+
+func runTemplate(name, body string, data interface{}) (string, error) {
+	t, err := template.New(name).Funcs(template.FuncMap{"lower": strings.ToLower}).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("error building template %s: %v", name, err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("error rendering template %s: %v", name, err)
+	}
+	return b.String(), nil
+}
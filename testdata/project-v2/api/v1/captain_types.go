@@ -28,6 +28,8 @@ type CaptainSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// TODO(user): set a default for foo with a "+kubebuilder:default=<value>" marker.
+
 	// Foo is an example field of Captain. Edit Captain_types.go to remove/update
 	Foo string `json:"foo,omitempty"`
 }
@@ -43,6 +43,11 @@ func (r *Captain) Default() {
 	captainlog.Info("default", "name", r.Name)
 
 	// TODO(user): fill in your defaulting logic.
+
+	// TODO(user): default Foo, e.g.
+	// if r.Spec.Foo == "" {
+	// 	r.Spec.Foo = ...
+	// }
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
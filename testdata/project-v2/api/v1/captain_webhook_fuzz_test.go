@@ -0,0 +1,73 @@
+//go:build fuzz
+// +build fuzz
+
+/*
+Copyright 2019 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+)
+
+// TestFuzzCaptainValidation feeds random objects to the validating
+// webhook of Captain, and fails if the validation panics. It is run by
+// "make test-fuzz": FUZZ_ITERATIONS sets how many objects are validated, and
+// the FUZZ_SEED of a failed run reproduces it.
+func TestFuzzCaptainValidation(t *testing.T) {
+	iterations := 1000
+	if s := os.Getenv("FUZZ_ITERATIONS"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			t.Fatalf("invalid FUZZ_ITERATIONS: %v", err)
+		}
+		iterations = n
+	}
+	seed := time.Now().UnixNano()
+	if s := os.Getenv("FUZZ_SEED"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			t.Fatalf("invalid FUZZ_SEED: %v", err)
+		}
+		seed = n
+	}
+
+	f := fuzz.New().NilChance(0.2).RandSource(rand.NewSource(seed))
+	for i := 0; i < iterations; i++ {
+		obj, old := &Captain{}, &Captain{}
+		f.Fuzz(obj)
+		f.Fuzz(old)
+
+		check := func(name string, validate func() error) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("%s panicked, reproduce with FUZZ_SEED=%d: %v\nobject: %#v", name, seed, r, obj)
+				}
+			}()
+			// the objects are random, only the panics fail the test
+			_ = validate()
+		}
+		check("ValidateCreate", obj.ValidateCreate)
+		check("ValidateUpdate", func() error { return obj.ValidateUpdate(old) })
+		check("ValidateDelete", obj.ValidateDelete)
+	}
+}
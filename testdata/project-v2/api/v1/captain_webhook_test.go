@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Captain webhook", func() {
+	It("should admit a valid Captain", func() {
+		obj := &Captain{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "captain-sample",
+				Namespace: "default",
+			},
+		}
+		// TODO(user): set the fields a valid Captain requires.
+
+		Expect(k8sClient.Create(context.Background(), obj)).To(Succeed())
+
+		// TODO(user): check the fields set by the defaulting webhook, e.g.
+		// Expect(obj.Spec.Foo).To(Equal("bar"))
+
+		Expect(k8sClient.Delete(context.Background(), obj)).To(Succeed())
+	})
+
+	// TODO(user): check that the validating webhook rejects an invalid
+	// Captain, e.g. with
+	// Expect(k8sClient.Create(context.Background(), obj)).NotTo(Succeed())
+})
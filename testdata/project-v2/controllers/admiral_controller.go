@@ -41,7 +41,9 @@ func (r *AdmiralReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
 	_ = r.Log.WithValues("admiral", req.NamespacedName)
 
+	// +kubebuilder:scaffold:user-code-begin reconcile
 	// your logic here
+	// +kubebuilder:scaffold:user-code-end reconcile
 
 	return ctrl.Result{}, nil
 }
@@ -49,5 +51,7 @@ func (r *AdmiralReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 func (r *AdmiralReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&crewv1.Admiral{}).
+		// +kubebuilder:scaffold:user-code-begin setup
+		// +kubebuilder:scaffold:user-code-end setup
 		Complete(r)
 }
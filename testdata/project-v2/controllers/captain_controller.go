@@ -41,7 +41,9 @@ func (r *CaptainReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	_ = context.Background()
 	_ = r.Log.WithValues("captain", req.NamespacedName)
 
+	// +kubebuilder:scaffold:user-code-begin reconcile
 	// your logic here
+	// +kubebuilder:scaffold:user-code-end reconcile
 
 	return ctrl.Result{}, nil
 }
@@ -49,5 +51,7 @@ func (r *CaptainReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 func (r *CaptainReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&crewv1.Captain{}).
+		// +kubebuilder:scaffold:user-code-begin setup
+		// +kubebuilder:scaffold:user-code-end setup
 		Complete(r)
 }
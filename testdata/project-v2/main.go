@@ -109,6 +109,9 @@ func main() {
 	}
 	// +kubebuilder:scaffold:builder
 
+	// +kubebuilder:scaffold:user-code-begin setup
+	// +kubebuilder:scaffold:user-code-end setup
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")